@@ -26,6 +26,7 @@ Additional information can be found in our Developer Guide:
 import Photos from "pages/photos.vue";
 import Albums from "pages/albums.vue";
 import AlbumPhotos from "pages/album/photos.vue";
+import SharedPhotos from "pages/shared/photos.vue";
 import Places from "pages/places.vue";
 import Browse from "pages/files/browse.vue";
 import Errors from "pages/files/errors.vue";
@@ -183,6 +184,20 @@ export default [
     component: AlbumPhotos,
     meta: { collName: "Folders", collRoute: "folders", auth: true },
   },
+  {
+    name: "photo_shared",
+    path: "/photos/:uid/:slug",
+    component: SharedPhotos,
+    meta: { title: siteTitle, auth: true },
+    props: { shareType: "photo" },
+  },
+  {
+    name: "label_shared",
+    path: "/labels/:uid/:slug",
+    component: SharedPhotos,
+    meta: { title: siteTitle, auth: true },
+    props: { shareType: "label" },
+  },
   {
     name: "unsorted",
     path: "/unsorted",
@@ -80,7 +80,7 @@ export default class Session {
     // Say hello.
     if (shared && shared.token) {
       this.config.progress(80);
-      this.redeemToken(shared.token).finally(() => {
+      this.redeemShare(shared).finally(() => {
         this.config.progress(99);
         if (shared.uri) {
           window.location = shared.uri;
@@ -277,10 +277,22 @@ export default class Session {
     }
   }
 
-  login(name, password, token) {
+  login(name, password, token, passcode) {
     this.deleteId();
 
-    return Api.post("session", { name, password, token }).then((resp) => {
+    return Api.post("session", { name, password, token, passcode }).then((resp) => {
+      const reload = this.config.getLanguage() !== resp.data?.config?.settings?.ui?.language;
+      this.setResp(resp);
+      this.sendClientInfo();
+      return Promise.resolve(reload);
+    });
+  }
+
+  // Completes a Single Sign-On login with a verification code if two-factor authentication is enabled.
+  loginOidc(token, passcode) {
+    this.deleteId();
+
+    return Api.post("oidc/passcode", { token, passcode }).then((resp) => {
       const reload = this.config.getLanguage() !== resp.data?.config?.settings?.ui?.language;
       this.setResp(resp);
       this.sendClientInfo();
@@ -305,17 +317,59 @@ export default class Session {
     }
   }
 
-  redeemToken(token) {
+  redeemToken(token, password) {
     if (!token) {
       return Promise.reject();
     }
 
-    return Api.post("session", { token }).then((resp) => {
+    const values = { token };
+
+    if (password) {
+      values.password = password;
+    }
+
+    return Api.post("session", values).then((resp) => {
       this.setResp(resp);
       this.sendClientInfo();
     });
   }
 
+  // Redeems a share token and asks for the password first if the link is protected.
+  redeemShare(shared) {
+    const form = document.getElementById("share-password");
+
+    if (!shared.password || !form) {
+      return this.redeemToken(shared.token);
+    }
+
+    const error = form.querySelector(".error");
+
+    form.style.setProperty("display", "block");
+    form.elements.password.focus();
+
+    return new Promise((resolve) => {
+      form.addEventListener("submit", (ev) => {
+        ev.preventDefault();
+        error.textContent = "";
+
+        this.redeemToken(shared.token, form.elements.password.value)
+          .then(() => {
+            form.style.setProperty("display", "none");
+            resolve();
+          })
+          .catch((err) => {
+            if (err && err.response && err.response.data && err.response.data.error) {
+              error.textContent = err.response.data.error;
+            } else {
+              error.textContent = "Invalid password";
+            }
+
+            form.elements.password.select();
+          });
+      });
+    });
+  }
+
   onLogout(noRedirect) {
     this.deleteId();
     if (noRedirect !== true) {
@@ -16,6 +16,8 @@ require (
 	github.com/esimov/pigo v1.4.6
 	github.com/gin-contrib/gzip v0.0.6
 	github.com/gin-gonic/gin v1.8.1
+	github.com/go-asn1-ber/asn1-ber v1.5.4
+	github.com/go-ldap/ldap/v3 v3.4.4
 	github.com/go-playground/validator/v10 v10.11.1 // indirect
 	github.com/golang/geo v0.0.0-20210211234256-740aa86cb551
 	github.com/google/open-location-code/go v0.0.0-20221010173056-817c0086479a
@@ -74,11 +76,20 @@ require (
 	github.com/goccy/go-json v0.9.11 // indirect
 	github.com/mattn/go-isatty v0.0.16 // indirect
 	github.com/zitadel/oidc v1.9.1
-	golang.org/x/oauth2 v0.0.0-20221006150949-b44042a4b9c1 // indirect
+	golang.org/x/oauth2 v0.0.0-20221006150949-b44042a4b9c1
 	google.golang.org/protobuf v1.28.1 // indirect
 )
 
-require github.com/gabriel-vasile/mimetype v1.4.1
+require (
+	github.com/coreos/go-oidc/v3 v3.4.0
+	github.com/eclipse/paho.mqtt.golang v1.4.2
+	github.com/gabriel-vasile/mimetype v1.4.1
+	github.com/klauspost/compress v1.15.12
+	github.com/mochi-co/mqtt v1.3.2
+	github.com/pkg/sftp v1.13.5
+	github.com/robfig/cron/v3 v3.0.1
+	gopkg.in/square/go-jose.v2 v2.6.0
+)
 
 require (
 	cloud.google.com/go/compute v1.10.0 // indirect
@@ -93,6 +104,7 @@ require (
 	github.com/Azure/go-autorest/autorest/validation v0.3.1 // indirect
 	github.com/Azure/go-autorest/logger v0.2.1 // indirect
 	github.com/Azure/go-autorest/tracing v0.6.0 // indirect
+	github.com/Azure/go-ntlmssp v0.0.0-20220621081337-cb9428e4ac1e // indirect
 	github.com/aws/aws-sdk-go v1.44.115 // indirect
 	github.com/cenkalti/backoff/v4 v4.1.3 // indirect
 	github.com/cloudflare/cloudflare-go v0.52.0 // indirect
@@ -120,6 +132,7 @@ require (
 	github.com/hashicorp/go-retryablehttp v0.7.1 // indirect
 	github.com/jmespath/go-jmespath v0.4.0 // indirect
 	github.com/json-iterator/go v1.1.12 // indirect
+	github.com/kr/fs v0.1.0 // indirect
 	github.com/leodido/go-urn v1.2.1 // indirect
 	github.com/mandykoh/go-parallel v0.1.0 // indirect
 	github.com/miekg/dns v1.1.50 // indirect
@@ -130,6 +143,7 @@ require (
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rivo/uniseg v0.2.0 // indirect
+	github.com/rs/xid v1.4.0 // indirect
 	github.com/russross/blackfriday/v2 v2.1.0 // indirect
 	github.com/softlayer/softlayer-go v1.0.6 // indirect
 	github.com/softlayer/xmlrpc v0.0.0-20200409220501-5f089df7cb7e // indirect
@@ -147,7 +161,6 @@ require (
 	google.golang.org/grpc v1.50.0 // indirect
 	gopkg.in/asn1-ber.v1 v1.0.0-20181015200546-f715ec2f112d // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
-	gopkg.in/square/go-jose.v2 v2.6.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
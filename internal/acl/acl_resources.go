@@ -45,6 +45,9 @@ var Resources = ACL{
 	ResourceLogs: Roles{
 		RoleAdmin: GrantFullAccess,
 	},
+	ResourceAudit: Roles{
+		RoleAdmin: GrantFullAccess,
+	},
 	ResourceSettings: Roles{
 		RoleAdmin:   GrantFullAccess,
 		RoleVisitor: Grant{AccessOwn: true, ActionView: true},
@@ -64,6 +67,15 @@ var Resources = ACL{
 	ResourceUsers: Roles{
 		RoleAdmin: GrantFullAccess,
 	},
+	ResourceWebhooks: Roles{
+		RoleAdmin: GrantFullAccess,
+	},
+	ResourceJobs: Roles{
+		RoleAdmin: GrantFullAccess,
+	},
+	ResourceBackups: Roles{
+		RoleAdmin: GrantFullAccess,
+	},
 	ResourceConfig: Roles{
 		RoleAdmin: GrantFullAccess,
 	},
@@ -0,0 +1,43 @@
+package acl
+
+import (
+	"sort"
+	"strings"
+)
+
+// Report returns the permissions granted to the specified roles by resource, for use in reports.
+func (acl ACL) Report(roles []Role) (rows [][]string, cols []string) {
+	cols = []string{"Role", "Resource", "Permissions"}
+
+	resources := make([]Resource, 0, len(acl))
+
+	for resource := range acl {
+		resources = append(resources, resource)
+	}
+
+	sort.Slice(resources, func(i, j int) bool { return resources[i] < resources[j] })
+
+	for _, role := range roles {
+		for _, resource := range resources {
+			grant, ok := acl[resource][role]
+
+			if !ok {
+				continue
+			}
+
+			perms := make([]string, 0, len(grant))
+
+			for perm, granted := range grant {
+				if granted {
+					perms = append(perms, string(perm))
+				}
+			}
+
+			sort.Strings(perms)
+
+			rows = append(rows, []string{role.String(), resource.String(), strings.Join(perms, ", ")})
+		}
+	}
+
+	return rows, cols
+}
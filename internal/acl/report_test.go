@@ -0,0 +1,24 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACL_Report(t *testing.T) {
+	acl := ACL{
+		ResourcePhotos: Roles{RoleAdmin: GrantFullAccess, RoleVisitor: Grant{ActionView: true, AccessShared: true, ActionDelete: false}},
+		ResourceAlbums: Roles{RoleAdmin: GrantFullAccess},
+	}
+
+	rows, cols := acl.Report([]Role{RoleVisitor})
+
+	assert.Equal(t, []string{"Role", "Resource", "Permissions"}, cols)
+	assert.Equal(t, [][]string{{"visitor", "photos", "access_shared, view"}}, rows)
+
+	rows, _ = acl.Report([]Role{RoleAdmin, RoleVisitor})
+	assert.Len(t, rows, 3)
+	assert.Equal(t, "albums", rows[0][1])
+	assert.Equal(t, "photos", rows[1][1])
+}
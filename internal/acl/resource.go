@@ -14,6 +14,7 @@ const (
 	ResourcePlaces    Resource = "places"
 	ResourceLabels    Resource = "labels"
 	ResourceLogs      Resource = "logs"
+	ResourceAudit     Resource = "audit"
 	ResourceConfig    Resource = "config"
 	ResourceSettings  Resource = "settings"
 	ResourcePassword  Resource = "password"
@@ -24,6 +25,9 @@ const (
 	ResourceShares    Resource = "shares"
 	ResourceVideos    Resource = "videos"
 	ResourceFeedback  Resource = "feedback"
+	ResourceWebhooks  Resource = "webhooks"
+	ResourceJobs      Resource = "jobs"
+	ResourceBackups   Resource = "backups"
 )
 
 // Resource represents a resource for which roles can be granted Permission.
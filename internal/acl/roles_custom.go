@@ -0,0 +1,130 @@
+package acl
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// BuiltInRoles specifies the roles that cannot be redefined.
+var BuiltInRoles = []Role{RoleAdmin, RoleVisitor, RoleDefault}
+
+// CustomRoles represents additional roles and the permissions granted to them by resource.
+type CustomRoles map[Role]Grants
+
+// rolesFile represents the YAML file format for custom roles, for example:
+//
+//	Roles:
+//	  contributor:
+//	    config: [access_own]
+//	    photos: [access_library, search, view, upload, download]
+//	    labels: [access_library, search, view, create, update]
+type rolesFile struct {
+	Roles map[string]map[string][]string `yaml:"Roles"`
+}
+
+// LoadRoles reads and validates custom roles from a YAML file.
+func LoadRoles(fileName string) (CustomRoles, error) {
+	data, err := os.ReadFile(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseRoles(data)
+}
+
+// ParseRoles parses and validates custom roles in YAML format.
+func ParseRoles(data []byte) (CustomRoles, error) {
+	f := rolesFile{}
+
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, err
+	}
+
+	result := make(CustomRoles, len(f.Roles))
+
+	for name, resources := range f.Roles {
+		role := Role(clean.Role(name))
+
+		if role == RoleUnknown || role.NotEqual(name) {
+			return nil, fmt.Errorf("invalid role name %s", clean.LogQuote(name))
+		}
+
+		for _, r := range BuiltInRoles {
+			if role == r {
+				return nil, fmt.Errorf("role %s cannot be redefined", role)
+			}
+		}
+
+		if _, ok := result[role]; ok {
+			return nil, fmt.Errorf("role %s is defined more than once", role)
+		}
+
+		grants := make(Grants, len(resources))
+
+		for res, perms := range resources {
+			resource := Resource(strings.ToLower(res))
+
+			if resource != ResourceDefault && !ValidResources[resource] {
+				return nil, fmt.Errorf("role %s: invalid resource %s", role, clean.Log(res))
+			}
+
+			grant := make(Grant, len(perms))
+
+			for _, p := range perms {
+				perm := Permission(strings.ToLower(strings.ReplaceAll(strings.TrimSpace(p), " ", "_")))
+
+				if !ValidPermissions[perm] {
+					return nil, fmt.Errorf("role %s: invalid permission %s for %s", role, clean.Log(p), resource)
+				}
+
+				grant[perm] = true
+			}
+
+			grants[resource] = grant
+		}
+
+		result[role] = grants
+	}
+
+	return result, nil
+}
+
+// Roles returns the custom role names in alphabetical order.
+func (roles CustomRoles) Roles() []Role {
+	result := make([]Role, 0, len(roles))
+
+	for role := range roles {
+		result = append(result, role)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+
+	return result
+}
+
+// Apply adds the custom roles to the valid user roles and grants their permissions in the access control list.
+func (roles CustomRoles) Apply(acl ACL) {
+	for role, grants := range roles {
+		ValidRoles[role.String()] = role
+
+		// Remove permissions granted previously.
+		for resource := range acl {
+			delete(acl[resource], role)
+		}
+
+		for resource, grant := range grants {
+			if acl[resource] == nil {
+				acl[resource] = Roles{}
+			}
+
+			acl[resource][role] = grant
+		}
+	}
+}
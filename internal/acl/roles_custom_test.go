@@ -0,0 +1,97 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadRoles(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		roles, err := LoadRoles("testdata/roles.yml")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []Role{"contributor"}, roles.Roles())
+
+		grants := roles["contributor"]
+		assert.Len(t, grants, 4)
+		assert.True(t, grants[ResourcePhotos].Allow(ActionUpload))
+		assert.False(t, grants[ResourcePhotos].Allow(ActionDelete))
+		assert.True(t, grants[ResourceLabels].Allow(ActionUpdate))
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := LoadRoles("testdata/missing.yml")
+		assert.Error(t, err)
+	})
+}
+
+func TestParseRoles(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		roles, err := ParseRoles([]byte(""))
+		assert.NoError(t, err)
+		assert.Len(t, roles, 0)
+	})
+	t.Run("PermissionNames", func(t *testing.T) {
+		roles, err := ParseRoles([]byte("Roles:\n  editor:\n    Albums: [Full Access]\n"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, roles["editor"][ResourceAlbums].Allow(ActionDelete))
+	})
+	t.Run("InvalidResource", func(t *testing.T) {
+		_, err := ParseRoles([]byte("Roles:\n  editor:\n    pictures: [view]\n"))
+		assert.EqualError(t, err, "role editor: invalid resource pictures")
+	})
+	t.Run("InvalidPermission", func(t *testing.T) {
+		_, err := ParseRoles([]byte("Roles:\n  editor:\n    photos: [fly]\n"))
+		assert.EqualError(t, err, "role editor: invalid permission fly for photos")
+	})
+	t.Run("BuiltInRole", func(t *testing.T) {
+		_, err := ParseRoles([]byte("Roles:\n  Admin:\n    photos: [view]\n"))
+		assert.EqualError(t, err, "role admin cannot be redefined")
+	})
+	t.Run("InvalidName", func(t *testing.T) {
+		_, err := ParseRoles([]byte("Roles:\n  \"foo bar\":\n    photos: [view]\n"))
+		assert.Error(t, err)
+	})
+	t.Run("UnknownField", func(t *testing.T) {
+		_, err := ParseRoles([]byte("Groups:\n  editor: {}\n"))
+		assert.Error(t, err)
+	})
+}
+
+func TestCustomRoles_Apply(t *testing.T) {
+	roles, err := ParseRoles([]byte("Roles:\n  reviewer:\n    config: [access_own]\n    photos: [view, rate]\n"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acl := ACL{
+		ResourcePhotos: Roles{RoleAdmin: GrantFullAccess},
+		ResourceAlbums: Roles{RoleAdmin: GrantFullAccess},
+	}
+
+	defer delete(ValidRoles, "reviewer")
+
+	roles.Apply(acl)
+
+	assert.Equal(t, Role("reviewer"), ValidRoles["reviewer"])
+	assert.True(t, acl.Allow(ResourcePhotos, "reviewer", ActionRate))
+	assert.True(t, acl.Allow(ResourceConfig, "reviewer", AccessOwn))
+	assert.False(t, acl.Allow(ResourcePhotos, "reviewer", ActionDelete))
+	assert.False(t, acl.Allow(ResourceAlbums, "reviewer", ActionView))
+	assert.True(t, acl.Allow(ResourcePhotos, RoleAdmin, ActionDelete))
+
+	// Permissions that are no longer granted are removed.
+	roles["reviewer"] = Grants{ResourceAlbums: Grant{ActionView: true}}
+	roles.Apply(acl)
+
+	assert.False(t, acl.Allow(ResourcePhotos, "reviewer", ActionRate))
+	assert.True(t, acl.Allow(ResourceAlbums, "reviewer", ActionView))
+}
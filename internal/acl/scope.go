@@ -0,0 +1,167 @@
+package acl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ScopeAll is the scope string that does not restrict access.
+const ScopeAll = "*"
+
+// ValidResources lists the resources that can be used in scopes.
+var ValidResources = map[Resource]bool{
+	ResourcePhotos:    true,
+	ResourceFavorites: true,
+	ResourceAlbums:    true,
+	ResourcePeople:    true,
+	ResourceMoments:   true,
+	ResourceCalendar:  true,
+	ResourcePlaces:    true,
+	ResourceLabels:    true,
+	ResourceLogs:      true,
+	ResourceAudit:     true,
+	ResourceConfig:    true,
+	ResourceSettings:  true,
+	ResourcePassword:  true,
+	ResourceUsers:     true,
+	ResourceServices:  true,
+	ResourceFiles:     true,
+	ResourceFolders:   true,
+	ResourceShares:    true,
+	ResourceVideos:    true,
+	ResourceFeedback:  true,
+	ResourceWebhooks:  true,
+	ResourceJobs:      true,
+	ResourceBackups:   true,
+}
+
+// ValidPermissions lists the permissions that can be used in scopes.
+var ValidPermissions = map[Permission]bool{
+	FullAccess:      true,
+	AccessShared:    true,
+	AccessLibrary:   true,
+	AccessPrivate:   true,
+	AccessOwn:       true,
+	AccessAll:       true,
+	ActionSearch:    true,
+	ActionView:      true,
+	ActionUpload:    true,
+	ActionCreate:    true,
+	ActionUpdate:    true,
+	ActionDownload:  true,
+	ActionShare:     true,
+	ActionDelete:    true,
+	ActionRate:      true,
+	ActionReact:     true,
+	ActionManage:    true,
+	ActionSubscribe: true,
+}
+
+// Scope limits access to a subset of resources and permissions, e.g. for access tokens.
+// An empty scope does not restrict access.
+type Scope map[Resource]Grant
+
+// ParseScope parses a space-separated list of resources with optional comma-separated
+// permissions, e.g. "photos:view,download albums", and returns the corresponding Scope.
+func ParseScope(s string) (Scope, error) {
+	scope := Scope{}
+
+	s = strings.TrimSpace(strings.ToLower(s))
+
+	if s == "" || s == ScopeAll {
+		return scope, nil
+	}
+
+	for _, item := range strings.Fields(s) {
+		name, perms, _ := strings.Cut(item, ":")
+		resource := Resource(name)
+
+		if !ValidResources[resource] {
+			return Scope{}, fmt.Errorf("invalid resource %s", name)
+		}
+
+		grant, ok := scope[resource]
+
+		if !ok {
+			grant = Grant{}
+			scope[resource] = grant
+		}
+
+		if perms == "" || perms == ScopeAll {
+			grant[FullAccess] = true
+			continue
+		}
+
+		for _, p := range strings.Split(perms, ",") {
+			perm := Permission(strings.TrimSpace(p))
+
+			if !ValidPermissions[perm] {
+				return Scope{}, fmt.Errorf("invalid permission %s", p)
+			}
+
+			grant[perm] = true
+		}
+	}
+
+	return scope, nil
+}
+
+// Unrestricted checks if the scope does not restrict access.
+func (scope Scope) Unrestricted() bool {
+	return len(scope) == 0
+}
+
+// Allow checks whether the scope includes the permission for the specified resource.
+func (scope Scope) Allow(resource Resource, perm Permission) bool {
+	if scope.Unrestricted() {
+		return true
+	} else if grant, ok := scope[resource]; ok {
+		return grant.Allow(perm)
+	}
+
+	return false
+}
+
+// AllowAny checks whether the scope includes any of the permissions for the specified resource.
+func (scope Scope) AllowAny(resource Resource, perms Permissions) bool {
+	for i := range perms {
+		if scope.Allow(resource, perms[i]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns the scope in its normalized string representation.
+func (scope Scope) String() string {
+	if scope.Unrestricted() {
+		return ScopeAll
+	}
+
+	items := make([]string, 0, len(scope))
+
+	for resource, grant := range scope {
+		if grant[FullAccess] {
+			items = append(items, resource.String())
+			continue
+		}
+
+		perms := make([]string, 0, len(grant))
+
+		for perm, allow := range grant {
+			if allow {
+				perms = append(perms, string(perm))
+			}
+		}
+
+		sort.Strings(perms)
+
+		items = append(items, fmt.Sprintf("%s:%s", resource, strings.Join(perms, ",")))
+	}
+
+	sort.Strings(items)
+
+	return strings.Join(items, " ")
+}
@@ -0,0 +1,62 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScope(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		scope, err := ParseScope("")
+		assert.NoError(t, err)
+		assert.True(t, scope.Unrestricted())
+		assert.Equal(t, "*", scope.String())
+	})
+	t.Run("All", func(t *testing.T) {
+		scope, err := ParseScope(" * ")
+		assert.NoError(t, err)
+		assert.True(t, scope.Unrestricted())
+	})
+	t.Run("PhotosAlbums", func(t *testing.T) {
+		scope, err := ParseScope("Photos:view,download albums")
+		assert.NoError(t, err)
+		assert.False(t, scope.Unrestricted())
+		assert.Equal(t, "albums photos:download,view", scope.String())
+	})
+	t.Run("InvalidResource", func(t *testing.T) {
+		scope, err := ParseScope("photos foo:view")
+		assert.EqualError(t, err, "invalid resource foo")
+		assert.True(t, scope.Unrestricted())
+	})
+	t.Run("InvalidPermission", func(t *testing.T) {
+		_, err := ParseScope("photos:view,fly")
+		assert.EqualError(t, err, "invalid permission fly")
+	})
+}
+
+func TestScope_Allow(t *testing.T) {
+	scope, err := ParseScope("photos:view,download albums")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, scope.Allow(ResourcePhotos, ActionView))
+	assert.False(t, scope.Allow(ResourcePhotos, ActionDelete))
+	assert.True(t, scope.Allow(ResourceAlbums, ActionDelete))
+	assert.False(t, scope.Allow(ResourceUsers, ActionView))
+	assert.True(t, Scope{}.Allow(ResourceUsers, ActionDelete))
+}
+
+func TestScope_AllowAny(t *testing.T) {
+	scope, err := ParseScope("photos:view")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, scope.AllowAny(ResourcePhotos, Permissions{ActionDelete, ActionView}))
+	assert.False(t, scope.AllowAny(ResourcePhotos, Permissions{ActionDelete, ActionUpdate}))
+	assert.False(t, scope.AllowAny(ResourcePhotos, Permissions{}))
+}
@@ -49,6 +49,10 @@ func GetAlbum(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortAlbumAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		id := clean.UID(c.Param("uid"))
 		a, err := query.AlbumByUID(id)
 
@@ -134,6 +138,10 @@ func UpdateAlbum(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortAlbumAccess(c, s, clean.UID(c.Param("uid"))) || AbortAlbumPerm(c, s, clean.UID(c.Param("uid")), entity.PermEdit) {
+			return
+		}
+
 		uid := clean.UID(c.Param("uid"))
 		a, err := query.AlbumByUID(uid)
 
@@ -188,6 +196,10 @@ func DeleteAlbum(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortAlbumAccess(c, s, clean.UID(c.Param("uid"))) || AbortAlbumPerm(c, s, clean.UID(c.Param("uid")), entity.PermAll) {
+			return
+		}
+
 		id := clean.UID(c.Param("uid"))
 
 		a, err := query.AlbumByUID(id)
@@ -217,6 +229,8 @@ func DeleteAlbum(router *gin.RouterGroup) {
 
 		// PublishAlbumEvent(EntityDeleted, id, c)
 
+		event.AuditInfo([]string{ClientIP(c), "session %s", "album %s", "deleted"}, s.RefID, a.AlbumUID)
+
 		UpdateClientConfig()
 
 		SaveAlbumAsYaml(a)
@@ -242,6 +256,10 @@ func LikeAlbum(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortAlbumAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		id := clean.UID(c.Param("uid"))
 		a, err := query.AlbumByUID(id)
 
@@ -280,6 +298,10 @@ func DislikeAlbum(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortAlbumAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		id := clean.UID(c.Param("uid"))
 		a, err := query.AlbumByUID(id)
 
@@ -314,6 +336,10 @@ func CloneAlbums(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortAlbumAccess(c, s, clean.UID(c.Param("uid"))) || AbortAlbumPerm(c, s, clean.UID(c.Param("uid")), entity.PermUpload) {
+			return
+		}
+
 		a, err := query.AlbumByUID(clean.UID(c.Param("uid")))
 
 		if err != nil {
@@ -331,6 +357,11 @@ func CloneAlbums(router *gin.RouterGroup) {
 		var added []entity.PhotoAlbum
 
 		for _, uid := range f.Albums {
+			// Skip albums the user may not access.
+			if !search.AlbumAccess(uid, s) {
+				continue
+			}
+
 			cloneAlbum, err := query.AlbumByUID(uid)
 
 			if err != nil {
@@ -371,6 +402,10 @@ func AddPhotosToAlbum(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortAlbumAccess(c, s, clean.UID(c.Param("uid"))) || AbortAlbumPerm(c, s, clean.UID(c.Param("uid")), entity.PermUpload) {
+			return
+		}
+
 		var f form.Selection
 
 		if err := c.BindJSON(&f); err != nil {
@@ -387,7 +422,7 @@ func AddPhotosToAlbum(router *gin.RouterGroup) {
 		}
 
 		// Fetch selection from index.
-		photos, err := query.SelectedPhotos(f)
+		photos, err := query.UserSelectedPhotos(f, s)
 
 		if err != nil {
 			log.Errorf("album: %s", err)
@@ -426,6 +461,10 @@ func RemovePhotosFromAlbum(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortAlbumAccess(c, s, clean.UID(c.Param("uid"))) || AbortAlbumPerm(c, s, clean.UID(c.Param("uid")), entity.PermEdit) {
+			return
+		}
+
 		var f form.Selection
 
 		if err := c.BindJSON(&f); err != nil {
@@ -0,0 +1,211 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/i18n"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// AlbumUser represents a user with whom an album has been shared.
+type AlbumUser struct {
+	UserUID     string     `json:"UserUID"`
+	UserName    string     `json:"UserName"`
+	DisplayName string     `json:"DisplayName,omitempty"`
+	Level       string     `json:"Level"`
+	Perm        uint       `json:"Perm"`
+	Comment     string     `json:"Comment,omitempty"`
+	ExpiresAt   *time.Time `json:"ExpiresAt,omitempty"`
+	CreatedAt   time.Time  `json:"CreatedAt"`
+}
+
+// AlbumUsers returns the users with whom the album has been shared.
+func AlbumUsers(albumUid string) []AlbumUser {
+	shares := entity.FindShareUsers(albumUid)
+	result := make([]AlbumUser, 0, len(shares))
+
+	for _, share := range shares {
+		u := entity.FindUserByUID(share.UserUID)
+
+		if u == nil {
+			continue
+		}
+
+		result = append(result, AlbumUser{
+			UserUID:     u.UserUID,
+			UserName:    u.Name(),
+			DisplayName: u.DisplayName,
+			Level:       share.Level(),
+			Perm:        share.Perm,
+			Comment:     share.Comment,
+			ExpiresAt:   share.ExpiresAt,
+			CreatedAt:   share.CreatedAt,
+		})
+	}
+
+	return result
+}
+
+// GetAlbumUsers returns the users with whom an album has been shared as JSON.
+//
+// GET /api/v1/albums/:uid/users
+func GetAlbumUsers(router *gin.RouterGroup) {
+	router.GET("/albums/:uid/users", func(c *gin.Context) {
+		s := Auth(c, acl.ResourceAlbums, acl.ActionShare)
+
+		if s.Abort(c) {
+			return
+		}
+
+		uid := clean.UID(c.Param("uid"))
+
+		if AbortAlbumAccess(c, s, uid) || AbortAlbumPerm(c, s, uid, entity.PermShare) {
+			return
+		}
+
+		c.JSON(http.StatusOK, AlbumUsers(uid))
+	})
+}
+
+// ShareAlbumWithUser shares an album with another user, or changes the share level if it has already been shared.
+//
+// POST /api/v1/albums/:uid/users
+func ShareAlbumWithUser(router *gin.RouterGroup) {
+	router.POST("/albums/:uid/users", func(c *gin.Context) {
+		// Albums cannot be shared with other users in public mode.
+		if get.Config().Public() {
+			Abort(c, http.StatusForbidden, i18n.ErrPublic)
+			return
+		}
+
+		s := Auth(c, acl.ResourceAlbums, acl.ActionShare)
+
+		if s.Abort(c) {
+			return
+		}
+
+		uid := clean.UID(c.Param("uid"))
+
+		if AbortAlbumAccess(c, s, uid) || AbortAlbumPerm(c, s, uid, entity.PermShare) {
+			return
+		}
+
+		var f form.AlbumShare
+
+		if err := c.BindJSON(&f); err != nil {
+			AbortBadRequest(c)
+			return
+		}
+
+		// Check share level, e.g. viewer, contributor, or editor.
+		level := strings.ToLower(strings.TrimSpace(f.Level))
+
+		if level == "" {
+			level = entity.ShareViewer
+		}
+
+		perm, ok := entity.ShareLevels[level]
+
+		if !ok {
+			AbortBadRequest(c)
+			return
+		}
+
+		a, err := query.AlbumByUID(uid)
+
+		if err != nil {
+			AbortAlbumNotFound(c)
+			return
+		}
+
+		// Find the user to share the album with.
+		var u *entity.User
+
+		if f.UserUID != "" {
+			u = entity.FindUserByUID(clean.UID(f.UserUID))
+		} else {
+			u = entity.FindUserByName(f.UserName)
+		}
+
+		if u == nil || !u.IsRegistered() {
+			Abort(c, http.StatusNotFound, i18n.ErrUserNotFound)
+			return
+		} else if u.UserUID == s.User().UserUID {
+			AbortBadRequest(c)
+			return
+		}
+
+		// Add share or update the existing share level.
+		if m := entity.FindUserShare(entity.UserShare{UserUID: u.UserUID, ShareUID: a.AlbumUID}); m == nil {
+			m = entity.NewUserShare(u.UserUID, a.AlbumUID, perm, nil)
+			m.Comment = txt.Clip(f.Comment, txt.ClipComment)
+
+			if err = m.Create(); err != nil {
+				log.Errorf("album: %s", err)
+				AbortSaveFailed(c)
+				return
+			}
+		} else if err = m.Updates(entity.Values{"perm": perm, "comment": txt.Clip(f.Comment, txt.ClipComment), "updated_at": entity.TimeStamp()}); err != nil {
+			log.Errorf("album: %s", err)
+			AbortSaveFailed(c)
+			return
+		}
+
+		// Apply changes with the next request of the user.
+		entity.FlushUserSessions(u.UserUID)
+
+		event.AuditInfo([]string{ClientIP(c), "session %s", "album %s", "shared with %s as %s"}, s.RefID, a.AlbumUID, clean.Log(u.Name()), level)
+
+		c.JSON(http.StatusOK, AlbumUsers(a.AlbumUID))
+	})
+}
+
+// RevokeAlbumUser stops sharing an album with another user.
+//
+// DELETE /api/v1/albums/:uid/users/:user
+func RevokeAlbumUser(router *gin.RouterGroup) {
+	router.DELETE("/albums/:uid/users/:user", func(c *gin.Context) {
+		s := Auth(c, acl.ResourceAlbums, acl.ActionShare)
+
+		if s.Abort(c) {
+			return
+		}
+
+		uid := clean.UID(c.Param("uid"))
+
+		if AbortAlbumAccess(c, s, uid) || AbortAlbumPerm(c, s, uid, entity.PermShare) {
+			return
+		}
+
+		m := entity.FindUserShare(entity.UserShare{UserUID: clean.UID(c.Param("user")), ShareUID: uid})
+
+		if m == nil {
+			AbortEntityNotFound(c)
+			return
+		}
+
+		if err := m.Delete(); err != nil {
+			log.Errorf("album: %s", err)
+			AbortDeleteFailed(c)
+			return
+		}
+
+		// Apply changes with the next request of the user.
+		entity.FlushUserSessions(m.UserUID)
+
+		event.AuditInfo([]string{ClientIP(c), "session %s", "album %s", "access revoked for %s"}, s.RefID, uid, m.UserUID)
+
+		c.JSON(http.StatusOK, AlbumUsers(uid))
+	})
+}
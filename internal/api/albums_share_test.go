@@ -0,0 +1,71 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	"github.com/photoprism/photoprism/internal/config"
+)
+
+func TestGetAlbumUsers(t *testing.T) {
+	t.Run("Ok", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetAlbumUsers(router)
+		r := PerformRequest(app, "GET", "/api/v1/albums/at9lxuqxpogaaba9/users")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "alice", gjson.Get(r.Body.String(), "0.UserName").String())
+		assert.Equal(t, "editor", gjson.Get(r.Body.String(), "0.Level").String())
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetAlbumUsers(router)
+		r := PerformRequest(app, "GET", "/api/v1/albums/xxx/users")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+}
+
+func TestShareAlbumWithUser(t *testing.T) {
+	t.Run("PublicMode", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		ShareAlbumWithUser(router)
+		r := PerformRequestWithBody(app, "POST", "/api/v1/albums/at9lxuqxpogaaba8/users", `{"UserName": "bob"}`)
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+	t.Run("ShareAndRevoke", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		ShareAlbumWithUser(router)
+		RevokeAlbumUser(router)
+		sessId := AuthenticateUser(app, router, "alice", "Alice123!")
+
+		r := AuthenticatedRequestWithBody(app, "POST", "/api/v1/albums/at9lxuqxpogaaba8/users", `{"UserName": "bob", "Level": "contributor"}`, sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "bob", gjson.Get(r.Body.String(), "0.UserName").String())
+		assert.Equal(t, "contributor", gjson.Get(r.Body.String(), "0.Level").String())
+
+		r = AuthenticatedRequestWithBody(app, "POST", "/api/v1/albums/at9lxuqxpogaaba8/users", `{"UserUID": "uqxc08w3d0ej2283", "Level": "Editor"}`, sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, int64(1), gjson.Get(r.Body.String(), "#").Int())
+		assert.Equal(t, "editor", gjson.Get(r.Body.String(), "0.Level").String())
+
+		r = AuthenticatedRequestWithBody(app, "POST", "/api/v1/albums/at9lxuqxpogaaba8/users", `{"UserName": "bob", "Level": "owner"}`, sessId)
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+
+		r = AuthenticatedRequestWithBody(app, "POST", "/api/v1/albums/at9lxuqxpogaaba8/users", `{"UserName": "nobody"}`, sessId)
+		assert.Equal(t, http.StatusNotFound, r.Code)
+
+		r = AuthenticatedRequestWithBody(app, "POST", "/api/v1/albums/at9lxuqxpogaaba8/users", `{"UserName": "alice"}`, sessId)
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+
+		r = AuthenticatedRequest(app, "DELETE", "/api/v1/albums/at9lxuqxpogaaba8/users/uqxc08w3d0ej2283", sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, int64(0), gjson.Get(r.Body.String(), "#").Int())
+
+		r = AuthenticatedRequest(app, "DELETE", "/api/v1/albums/at9lxuqxpogaaba8/users/uqxc08w3d0ej2283", sessId)
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+}
@@ -0,0 +1,298 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	gc "github.com/patrickmn/go-cache"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/rnd"
+)
+
+// sseBufferSize specifies how many events are kept in memory so that clients can resume after reconnecting.
+var sseBufferSize = 1000
+
+// ssePingInterval specifies how often a comment is sent to keep idle connections open.
+var ssePingInterval = 15 * time.Second
+
+// sseEvent represents a buffered event.
+type sseEvent struct {
+	ID    uint64
+	Topic string
+	Data  event.Data
+}
+
+// sseRing is a bounded in-memory buffer of recent events.
+type sseRing struct {
+	events []sseEvent
+	lastId uint64
+	notify chan struct{}
+	mutex  sync.RWMutex
+}
+
+// sseEvents buffers the events that are streamed to clients.
+var sseEvents = &sseRing{notify: make(chan struct{})}
+
+// sseRecorder ensures that events are only recorded once.
+var sseRecorder sync.Once
+
+// Add appends an event to the buffer, drops the oldest event if it is full, and wakes up waiting clients.
+func (r *sseRing) Add(topic string, data event.Data) uint64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.lastId++
+	r.events = append(r.events, sseEvent{ID: r.lastId, Topic: topic, Data: data})
+
+	if n := len(r.events); n > sseBufferSize {
+		r.events = append([]sseEvent(nil), r.events[n-sseBufferSize:]...)
+	}
+
+	close(r.notify)
+	r.notify = make(chan struct{})
+
+	return r.lastId
+}
+
+// LastID returns the id of the most recent event.
+func (r *sseRing) LastID() uint64 {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.lastId
+}
+
+// Since returns the buffered events after the specified id, along with a channel
+// that is closed as soon as another event is added.
+func (r *sseRing) Since(id uint64) ([]sseEvent, <-chan struct{}) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if len(r.events) == 0 || id >= r.lastId {
+		return nil, r.notify
+	}
+
+	// Event ids are consecutive, so the offset can be calculated.
+	first := r.events[0].ID
+
+	if id < first {
+		id = first - 1
+	}
+
+	result := make([]sseEvent, r.lastId-id)
+	copy(result, r.events[id-first+1:])
+
+	return result, r.notify
+}
+
+// sseRecord adds published events to the buffer.
+func sseRecord() {
+	s := event.Subscribe(wsTopics...)
+
+	for msg := range s.Receiver {
+		sseEvents.Add(msg.Topic(), msg.Fields)
+	}
+}
+
+// sseTokenExpires specifies how long a stream token can be used to subscribe to events.
+var sseTokenExpires = time.Minute
+
+// sseTokens maps single-use stream tokens to session ids.
+var sseTokens = gc.New(sseTokenExpires, time.Minute)
+var sseTokenMutex = sync.Mutex{}
+
+// sseTakeToken returns the session id for a stream token and removes the token, so that
+// only one of several concurrent requests can use it. It returns an empty string otherwise.
+func sseTakeToken(token string) (sessId string) {
+	sseTokenMutex.Lock()
+	defer sseTokenMutex.Unlock()
+
+	if cached, found := sseTokens.Get(token); found {
+		sessId, _ = cached.(string)
+		sseTokens.Delete(token)
+	}
+
+	return sessId
+}
+
+// sseSession returns the client session, or nil if the client is not authenticated, along with a function
+// that finds it again to check if it is still valid. Since browsers cannot set custom headers for event
+// streams, a short-lived stream token may be passed as query parameter instead of the session id.
+func sseSession(c *gin.Context) (*entity.Session, func() *entity.Session) {
+	refresh := func() *entity.Session {
+		return AuthSession(c)
+	}
+
+	if token := clean.ID(c.Query("token")); token != "" {
+		sessId := sseTakeToken(token)
+
+		if sessId == "" {
+			return nil, refresh
+		}
+
+		refresh = func() *entity.Session {
+			return Session(sessId)
+		}
+	}
+
+	return refresh(), refresh
+}
+
+// sseScope checks if the session scope allows subscribing to the event topic.
+func sseScope(scope acl.Scope, topic string) bool {
+	if scope.Unrestricted() {
+		return true
+	}
+
+	// The resource is the first sub-channel, e.g. "photos.updated", or
+	// the third for user and session events, e.g. "user.*.albums.created".
+	ch := strings.Split(topic, ".")
+
+	switch len(ch) {
+	case 2:
+		return scope.Allow(acl.Resource(ch[0]), acl.ActionSubscribe)
+	case 4:
+		return scope.Allow(acl.Resource(ch[2]), acl.ActionSubscribe)
+	default:
+		return false
+	}
+}
+
+// sseLastEventID returns the id of the last event received by the client before reconnecting.
+func sseLastEventID(c *gin.Context) (uint64, bool) {
+	s := strings.TrimSpace(c.GetHeader("Last-Event-ID"))
+
+	if s == "" {
+		return 0, false
+	}
+
+	id, err := strconv.ParseUint(s, 10, 64)
+
+	return id, err == nil
+}
+
+// sseWrite sends an event to the client.
+func sseWrite(c *gin.Context, id uint64, topic string, data event.Data) error {
+	payload, err := json.Marshal(data)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", id, topic, payload)
+
+	return err
+}
+
+// ServerSentEvents registers the /events endpoint for streaming events to clients that cannot use a WebSocket.
+// Events are filtered and named in the same way, and clients can resume by sending the Last-Event-ID header.
+//
+// GET /api/v1/events
+func ServerSentEvents(router *gin.RouterGroup) {
+	if router == nil {
+		return
+	}
+
+	sseRecorder.Do(func() {
+		go sseRecord()
+	})
+
+	router.GET("/events", func(c *gin.Context) {
+		s, refresh := sseSession(c)
+
+		if s == nil || s.User() == nil {
+			event.AuditWarn([]string{ClientIP(c), "unauthenticated", "subscribe events as unknown user", "denied"})
+			AbortUnauthorized(c)
+			return
+		}
+
+		user := *s.User()
+		sid := s.ID
+		scope := s.Scope()
+
+		event.AuditInfo([]string{ClientIP(c), "session %s", "subscribe events as %s", "granted"}, s.RefID, user.AclRole().String())
+
+		// Resume after the last event received by the client if it is still buffered.
+		lastId, resume := sseLastEventID(c)
+
+		if !resume || lastId > sseEvents.LastID() {
+			lastId = sseEvents.LastID()
+		}
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+		c.Writer.Flush()
+
+		ping := time.NewTicker(ssePingInterval)
+		defer ping.Stop()
+
+		for {
+			events, next := sseEvents.Since(lastId)
+
+			for _, ev := range events {
+				lastId = ev.ID
+
+				// Send the event only to authorized recipients.
+				if !sseScope(scope, ev.Topic) {
+					continue
+				} else if topic, ok := wsRecipient(ev.Topic, user, sid); !ok {
+					continue
+				} else if err := sseWrite(c, ev.ID, topic, ev.Data); err != nil {
+					return
+				}
+			}
+
+			c.Writer.Flush()
+
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case <-next:
+			case <-ping.C:
+				// Stop streaming if the session has expired in the meantime.
+				if s = refresh(); s == nil {
+					return
+				} else if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+					return
+				}
+			}
+		}
+	})
+}
+
+// CreateEventsToken returns a short-lived, single-use token for subscribing to events with
+// the EventSource API in browsers, which cannot send the session id as request header.
+//
+// POST /api/v1/events/token
+func CreateEventsToken(router *gin.RouterGroup) {
+	router.POST("/events/token", func(c *gin.Context) {
+		s := AuthSession(c)
+
+		if s == nil || s.User() == nil {
+			AbortUnauthorized(c)
+			return
+		} else if s.AuthMethod == entity.AuthMethodToken {
+			// Clients with an access token can send it as request header.
+			AbortForbidden(c)
+			return
+		}
+
+		token := rnd.Base62(32)
+		sseTokens.SetDefault(token, s.ID)
+
+		c.JSON(http.StatusOK, gin.H{"token": token, "expires": int(sseTokenExpires.Seconds())})
+	})
+}
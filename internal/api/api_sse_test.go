@@ -0,0 +1,234 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/event"
+)
+
+// PerformStreamRequest executes a streaming API request that is cancelled after the specified duration.
+func PerformStreamRequest(r http.Handler, path string, d time.Duration, header map[string]string) *httptest.ResponseRecorder {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, "GET", path, nil)
+
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestSseRing(t *testing.T) {
+	defer func(n int) { sseBufferSize = n }(sseBufferSize)
+	sseBufferSize = 3
+
+	r := &sseRing{notify: make(chan struct{})}
+
+	events, next := r.Since(0)
+	assert.Empty(t, events)
+
+	id := r.Add("photos.updated", event.Data{"n": 1})
+	assert.Equal(t, uint64(1), id)
+
+	select {
+	case <-next:
+	default:
+		t.Fatal("waiting clients should be notified")
+	}
+
+	r.Add("photos.updated", event.Data{"n": 2})
+	r.Add("photos.updated", event.Data{"n": 3})
+	r.Add("photos.updated", event.Data{"n": 4})
+
+	assert.Equal(t, uint64(4), r.LastID())
+	assert.Len(t, r.events, 3)
+
+	t.Run("Resume", func(t *testing.T) {
+		events, _ := r.Since(2)
+		if assert.Len(t, events, 2) {
+			assert.Equal(t, uint64(3), events[0].ID)
+			assert.Equal(t, uint64(4), events[1].ID)
+		}
+	})
+	t.Run("Dropped", func(t *testing.T) {
+		events, _ := r.Since(0)
+		if assert.Len(t, events, 3) {
+			assert.Equal(t, uint64(2), events[0].ID)
+		}
+	})
+	t.Run("UpToDate", func(t *testing.T) {
+		events, _ := r.Since(4)
+		assert.Empty(t, events)
+	})
+}
+
+func TestSseScope(t *testing.T) {
+	scope, err := acl.ParseScope("photos:subscribe albums:view")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, sseScope(acl.Scope{}, "labels.updated"))
+	assert.True(t, sseScope(scope, "photos.updated"))
+	assert.True(t, sseScope(scope, "user.uqxetse3cy5eo9z2.photos.created"))
+	assert.False(t, sseScope(scope, "albums.updated"))
+	assert.False(t, sseScope(scope, "session.sess6ey1ykya3qmw.notify.info"))
+	assert.False(t, sseScope(scope, "photos"))
+}
+
+func TestCreateEventsToken(t *testing.T) {
+	app, router, conf := NewApiTest()
+	conf.SetAuthMode(config.AuthModePasswd)
+	defer conf.SetAuthMode(config.AuthModePublic)
+
+	ServerSentEvents(router)
+	CreateEventsToken(router)
+
+	sessId := AuthenticateUser(app, router, "alice", "Alice123!")
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		r := PerformRequest(app, "POST", "/api/v1/events/token")
+		assert.Equal(t, http.StatusUnauthorized, r.Code)
+	})
+	t.Run("SessionQuery", func(t *testing.T) {
+		r := PerformRequest(app, "GET", "/api/v1/events?session="+sessId)
+		assert.Equal(t, http.StatusUnauthorized, r.Code)
+	})
+	t.Run("Subscribe", func(t *testing.T) {
+		r := AuthenticatedRequest(app, "POST", "/api/v1/events/token", sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+
+		token := gjson.Get(r.Body.String(), "token").String()
+		assert.Len(t, token, 32)
+
+		r = PerformStreamRequest(app, "/api/v1/events?token="+token, 100*time.Millisecond, nil)
+		assert.Equal(t, http.StatusOK, r.Code)
+
+		// Tokens can only be used once.
+		r = PerformStreamRequest(app, "/api/v1/events?token="+token, 100*time.Millisecond, nil)
+		assert.Equal(t, http.StatusUnauthorized, r.Code)
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		r := AuthenticatedRequest(app, "POST", "/api/v1/events/token", sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+
+		token := gjson.Get(r.Body.String(), "token").String()
+
+		var wg sync.WaitGroup
+		var subscribed int32
+
+		// Only one of several concurrent requests may use the token.
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				if PerformStreamRequest(app, "/api/v1/events?token="+token, 100*time.Millisecond, nil).Code == http.StatusOK {
+					atomic.AddInt32(&subscribed, 1)
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, int32(1), subscribed)
+	})
+}
+
+func TestSseTakeToken(t *testing.T) {
+	t.Run("Concurrent", func(t *testing.T) {
+		sseTokens.SetDefault("sse-take-token", "sess-take-token")
+
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		var taken int32
+
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+				<-start
+
+				if sseTakeToken("sse-take-token") == "sess-take-token" {
+					atomic.AddInt32(&taken, 1)
+				}
+			}()
+		}
+
+		close(start)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), taken)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		assert.Equal(t, "", sseTakeToken("sse-unknown-token"))
+	})
+}
+
+func TestServerSentEvents(t *testing.T) {
+	t.Run("RouterNil", func(t *testing.T) {
+		app, _, _ := NewApiTest()
+		ServerSentEvents(nil)
+		r := PerformRequest(app, "GET", "/api/v1/events")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+	t.Run("Unauthorized", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		ServerSentEvents(router)
+		r := PerformRequest(app, "GET", "/api/v1/events")
+		assert.Equal(t, http.StatusUnauthorized, r.Code)
+	})
+	t.Run("Public", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		ServerSentEvents(router)
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			sseEvents.Add("photos.updated", event.Data{"uid": "pt9jtdre2lvl0yh7"})
+			sseEvents.Add("session.sess6ey1ykya3qmw.notify.info", event.Data{"message": "Hello"})
+		}()
+
+		r := PerformStreamRequest(app, "/api/v1/events", 500*time.Millisecond, nil)
+
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "text/event-stream", r.Header().Get("Content-Type"))
+		assert.Contains(t, r.Body.String(), "event: photos.updated\ndata: {\"uid\":\"pt9jtdre2lvl0yh7\"}\n\n")
+		assert.Contains(t, r.Body.String(), "event: notify.info\ndata: {\"message\":\"Hello\"}\n\n")
+	})
+	t.Run("LastEventID", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		ServerSentEvents(router)
+
+		lastId := sseEvents.Add("albums.updated", event.Data{"uid": "as6sg6bxpogaaba7"})
+		sseEvents.Add("albums.deleted", event.Data{"uid": "as6sg6bxpogaaba8"})
+
+		header := map[string]string{"Last-Event-ID": fmt.Sprintf("%d", lastId)}
+		r := PerformStreamRequest(app, "/api/v1/events", 200*time.Millisecond, header)
+
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Contains(t, r.Body.String(), fmt.Sprintf("id: %d\nevent: albums.deleted\n", lastId+1))
+		assert.NotContains(t, r.Body.String(), "albums.updated")
+	})
+}
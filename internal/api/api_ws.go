@@ -36,6 +36,33 @@ var wsAuth = struct {
 	user: make(map[string]entity.User),
 }
 
+// wsTopics specifies the event topics forwarded to clients.
+var wsTopics = []string{
+	"user.*.*.*",
+	"session.*.*.*",
+	"log.fatal",
+	"log.error",
+	"log.warning",
+	"log.warn",
+	"log.info",
+	"notify.*",
+	"index.*",
+	"upload.*",
+	"import.*",
+	"config.*",
+	"count.*",
+	"photos.*",
+	"cameras.*",
+	"lenses.*",
+	"countries.*",
+	"albums.*",
+	"labels.*",
+	"subjects.*",
+	"people.*",
+	"sync.*",
+	"restore.*",
+}
+
 // wsConnection upgrades the HTTP server connection to the WebSocket protocol.
 var wsConnection = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool {
@@ -140,30 +167,7 @@ func wsWriter(ws *websocket.Conn, writeMutex *sync.Mutex, connId string) {
 	pingTicker := time.NewTicker(15 * time.Second)
 
 	// Subscribe to events.
-	e := event.Subscribe(
-		"user.*.*.*",
-		"session.*.*.*",
-		"log.fatal",
-		"log.error",
-		"log.warning",
-		"log.warn",
-		"log.info",
-		"notify.*",
-		"index.*",
-		"upload.*",
-		"import.*",
-		"config.*",
-		"count.*",
-		"photos.*",
-		"cameras.*",
-		"lenses.*",
-		"countries.*",
-		"albums.*",
-		"labels.*",
-		"subjects.*",
-		"people.*",
-		"sync.*",
-	)
+	e := event.Subscribe(wsTopics...)
 
 	defer func() {
 		pingTicker.Stop()
@@ -204,31 +208,40 @@ func wsWriter(ws *websocket.Conn, writeMutex *sync.Mutex, connId string) {
 
 			wsAuth.mutex.RUnlock()
 
-			// Split topic into sub-channels.
-			ev := msg.Topic()
-			ch := strings.Split(ev, ".")
-
 			// Send the message only to authorized recipients.
-			switch len(ch) {
-			case 2:
-				// Send to everyone who is allowed to subscribe.
-				if res := acl.Resource(ch[0]); acl.Events.AllowAll(res, user.AclRole(), wsSubscribePerms) {
-					wsSendMessage(ev, msg.Fields, ws, writeMutex)
-				}
-			case 4:
-				ev = strings.Join(ch[2:4], ".")
-				if acl.ChannelUser.Equal(ch[0]) && ch[1] == user.UID() || acl.Events.AllowAll(acl.Resource(ch[2]), user.AclRole(), wsSubscribePerms) {
-					// Send to matching user uid.
-					wsSendMessage(ev, msg.Fields, ws, writeMutex)
-				} else if acl.ChannelSession.Equal(ch[0]) && ch[1] == sid {
-					// Send to matching session id.
-					wsSendMessage(ev, msg.Fields, ws, writeMutex)
-				}
+			if ev, ok := wsRecipient(msg.Topic(), user, sid); ok {
+				wsSendMessage(ev, msg.Fields, ws, writeMutex)
 			}
 		}
 	}
 }
 
+// wsRecipient checks if the event may be sent to the user and session, and returns the topic name
+// under which it is sent to the client, e.g. "session.*.notify.info" is sent as "notify.info".
+func wsRecipient(topic string, user entity.User, sid string) (string, bool) {
+	// Split topic into sub-channels.
+	ch := strings.Split(topic, ".")
+
+	switch len(ch) {
+	case 2:
+		// Send to everyone who is allowed to subscribe.
+		if res := acl.Resource(ch[0]); acl.Events.AllowAll(res, user.AclRole(), wsSubscribePerms) {
+			return topic, true
+		}
+	case 4:
+		ev := strings.Join(ch[2:4], ".")
+		if acl.ChannelUser.Equal(ch[0]) && ch[1] == user.UID() || acl.Events.AllowAll(acl.Resource(ch[2]), user.AclRole(), wsSubscribePerms) {
+			// Send to matching user uid.
+			return ev, true
+		} else if acl.ChannelSession.Equal(ch[0]) && ch[1] == sid {
+			// Send to matching session id.
+			return ev, true
+		}
+	}
+
+	return "", false
+}
+
 // wsSendMessage sends a message to the WebSocket client.
 func wsSendMessage(topic string, data interface{}, ws *websocket.Conn, writeMutex *sync.Mutex) {
 	if topic == "" || ws == nil || writeMutex == nil {
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/i18n"
+	"github.com/photoprism/photoprism/internal/search"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// SearchAudit searches the audit log and returns the results as JSON.
+//
+// GET /api/v1/audit
+func SearchAudit(router *gin.RouterGroup) {
+	router.GET("/audit", func(c *gin.Context) {
+		// Disabled in public mode so that client addresses and sessions are not exposed.
+		if get.Config().Public() {
+			Abort(c, http.StatusForbidden, i18n.ErrPublic)
+			return
+		}
+
+		// Check authentication and authorization.
+		s := Auth(c, acl.ResourceAudit, acl.ActionSearch)
+
+		if s.Abort(c) {
+			return
+		}
+
+		var f form.SearchAudit
+
+		if err := c.MustBindWith(&f, binding.Form); err != nil {
+			AbortBadRequest(c)
+			return
+		}
+
+		// Find and return matching events.
+		result, err := search.AuditEvents(f)
+
+		if err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": txt.UpperFirst(err.Error())})
+			return
+		}
+
+		AddCountHeader(c, len(result))
+		AddLimitHeader(c, f.Count)
+		AddOffsetHeader(c, f.Offset)
+
+		c.JSON(http.StatusOK, result)
+	})
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	"github.com/photoprism/photoprism/internal/config"
+)
+
+func TestSearchAudit(t *testing.T) {
+	t.Run("Public", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		SearchAudit(router)
+		r := PerformRequest(app, "GET", "/api/v1/audit?count=10")
+		// Disabled in public mode, so error 403 is expected.
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+	t.Run("Alice", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		SearchAudit(router)
+		sessId := AuthenticateUser(app, router, "alice", "Alice123!")
+
+		r := AuthenticatedRequest(app, "GET", "/api/v1/audit?count=10&uid=as6sg6bxpogaaba8", sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, int64(1), gjson.Get(r.Body.String(), "#").Int())
+		assert.Equal(t, "deleted", gjson.Get(r.Body.String(), "0.Outcome").String())
+
+		r = AuthenticatedRequest(app, "GET", "/api/v1/audit?count=10&user=bob&level=warning", sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, int64(1), gjson.Get(r.Body.String(), "#").Int())
+		assert.Equal(t, "198.51.100.7", gjson.Get(r.Body.String(), "0.ClientIP").String())
+	})
+	t.Run("BadRequest", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		SearchAudit(router)
+		sessId := AuthenticateUser(app, router, "alice", "Alice123!")
+
+		r := AuthenticatedRequest(app, "GET", "/api/v1/audit", sessId)
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+}
@@ -1,10 +1,16 @@
 package api
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
+
 	"github.com/photoprism/photoprism/internal/acl"
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/i18n"
+	"github.com/photoprism/photoprism/internal/search"
 )
 
 // Auth checks if the user has permission to access the specified resource and returns the session if so.
@@ -14,12 +20,11 @@ func Auth(c *gin.Context, resource acl.Resource, grant acl.Permission) *entity.S
 
 // AuthAny checks if at least one permission allows access and returns the session in this case.
 func AuthAny(c *gin.Context, resource acl.Resource, grants acl.Permissions) (s *entity.Session) {
-	// Get client IP address and session ID, if any.
+	// Get client IP address and session, if any.
 	ip := ClientIP(c)
-	sessId := SessionID(c)
+	s = AuthSession(c)
 
-	// Find client session.
-	if s = Session(sessId); s == nil {
+	if s == nil {
 		event.AuditWarn([]string{ip, "unauthenticated", "%s %s as unknown user", "denied"}, grants.String(), string(resource))
 		return entity.SessionStatusUnauthorized()
 	} else {
@@ -33,8 +38,72 @@ func AuthAny(c *gin.Context, resource acl.Resource, grants acl.Permissions) (s *
 	} else if acl.Resources.DenyAll(resource, s.User().AclRole(), grants) {
 		event.AuditErr([]string{ip, "session %s", "%s %s as %s", "denied"}, s.RefID, grants.String(), string(resource), s.User().AclRole().String())
 		return entity.SessionStatusForbidden()
+	} else if !s.Scope().AllowAny(resource, grants) {
+		event.AuditErr([]string{ip, "session %s", "%s %s as %s", "denied by scope"}, s.RefID, grants.String(), string(resource), s.User().AclRole().String())
+		return entity.SessionStatusForbidden()
 	} else {
 		event.AuditInfo([]string{ip, "session %s", "%s %s as %s", "granted"}, s.RefID, grants.String(), string(resource), s.User().AclRole().String())
 		return s
 	}
 }
+
+// AuthSession returns the client session based on the request headers, or nil if the client is not authenticated.
+// The access token is used if no session ID was provided.
+func AuthSession(c *gin.Context) *entity.Session {
+	sessId := SessionID(c)
+
+	if token := BearerToken(c); sessId == "" && token != "" && !get.Config().Public() {
+		return TokenSession(token)
+	}
+
+	return Session(sessId)
+}
+
+// AbortPhotoAccess aborts with "not found" and returns true if the session may not access the specified photo.
+func AbortPhotoAccess(c *gin.Context, s *entity.Session, photoUid string) bool {
+	if search.PhotoAccess(photoUid, s) {
+		return false
+	}
+
+	event.AuditErr([]string{ClientIP(c), "session %s", "access photo %s as %s", "denied"}, s.RefID, photoUid, s.User().AclRole().String())
+	AbortEntityNotFound(c)
+
+	return true
+}
+
+// AbortLabelAccess aborts with "label not found" and returns true if the session may not access the specified label.
+func AbortLabelAccess(c *gin.Context, s *entity.Session, labelUid string) bool {
+	if search.LabelAccess(labelUid, s) {
+		return false
+	}
+
+	event.AuditErr([]string{ClientIP(c), "session %s", "access label %s as %s", "denied"}, s.RefID, labelUid, s.User().AclRole().String())
+	Abort(c, http.StatusNotFound, i18n.ErrLabelNotFound)
+
+	return true
+}
+
+// AbortAlbumAccess aborts with "album not found" and returns true if the session may not access the specified album.
+func AbortAlbumAccess(c *gin.Context, s *entity.Session, albumUid string) bool {
+	if search.AlbumAccess(albumUid, s) {
+		return false
+	}
+
+	event.AuditErr([]string{ClientIP(c), "session %s", "access album %s as %s", "denied"}, s.RefID, albumUid, s.User().AclRole().String())
+	AbortAlbumNotFound(c)
+
+	return true
+}
+
+// AbortAlbumPerm aborts with "forbidden" and returns true if the session lacks the permission for the specified album,
+// e.g. because it has only been shared with the user as viewer.
+func AbortAlbumPerm(c *gin.Context, s *entity.Session, albumUid string, perm uint) bool {
+	if search.AlbumPerm(albumUid, s) >= perm {
+		return false
+	}
+
+	event.AuditErr([]string{ClientIP(c), "session %s", "change album %s as %s", "denied"}, s.RefID, albumUid, s.User().AclRole().String())
+	AbortForbidden(c)
+
+	return true
+}
@@ -0,0 +1,321 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/i18n"
+	"github.com/photoprism/photoprism/internal/oidc"
+	"github.com/photoprism/photoprism/internal/server/limiter"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// OIDCStateCookie is the name of the cookie that binds an authorization request to the browser.
+const OIDCStateCookie = "oidc_state"
+
+// OIDCLogin redirects the browser to the OpenID Connect provider to start a new login.
+//
+// GET /api/v1/oidc/login
+func OIDCLogin(router *gin.RouterGroup) {
+	router.GET("/oidc/login", func(c *gin.Context) {
+		conf := get.Config()
+
+		if !conf.OIDCEnabled() {
+			AbortFeatureDisabled(c)
+			return
+		}
+
+		p, err := get.OIDC()
+
+		if err != nil {
+			event.AuditErr([]string{ClientIP(c), "oidc", "%s"}, err)
+			Abort(c, http.StatusBadGateway, i18n.ErrConnectionFailed)
+			return
+		}
+
+		r := oidc.NewAuthRequest()
+
+		c.SetCookie(OIDCStateCookie, r.State, int(oidc.StateExpires.Seconds()), conf.ApiUri()+"/oidc", "", conf.SiteHttps(), true)
+		c.Redirect(http.StatusTemporaryRedirect, p.AuthUrl(r.State, r.Nonce, r.Verifier))
+	})
+}
+
+// OIDCRedirect completes the login when the OpenID Connect provider redirects back,
+// and creates a new session for the authenticated user.
+//
+// GET /api/v1/oidc/redirect
+func OIDCRedirect(router *gin.RouterGroup) {
+	router.GET("/oidc/redirect", func(c *gin.Context) {
+		conf := get.Config()
+
+		if !conf.OIDCEnabled() {
+			AbortFeatureDisabled(c)
+			return
+		}
+
+		clientIp := ClientIP(c)
+
+		// Check limit for failed auth requests (max. 10 per minute).
+		if limiter.Auth.Reject(clientIp) {
+			limiter.Abort(c)
+			return
+		}
+
+		// Login denied by the provider, e.g. because the user cancelled it?
+		if e := c.Query("error"); e != "" {
+			event.AuditWarn([]string{clientIp, "oidc", "login failed", "%s"}, clean.Log(e))
+			Abort(c, http.StatusUnauthorized, i18n.ErrInvalidCredentials)
+			return
+		}
+
+		// The state must match the cookie set when the login was started.
+		state := c.Query("state")
+		cookie, _ := c.Cookie(OIDCStateCookie)
+		c.SetCookie(OIDCStateCookie, "", -1, conf.ApiUri()+"/oidc", "", conf.SiteHttps(), true)
+
+		r, found := oidc.FindAuthRequest(state)
+
+		if !found || state != cookie {
+			limiter.Auth.Reserve(clientIp)
+			event.AuditWarn([]string{clientIp, "oidc", "login failed", "invalid state"})
+			AbortBadRequest(c)
+			return
+		}
+
+		p, err := get.OIDC()
+
+		if err != nil {
+			event.AuditErr([]string{clientIp, "oidc", "%s"}, err)
+			Abort(c, http.StatusBadGateway, i18n.ErrConnectionFailed)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		claims, err := p.Exchange(ctx, c.Query("code"), r.Verifier, r.Nonce)
+
+		if err != nil {
+			limiter.Auth.Reserve(clientIp)
+			event.AuditErr([]string{clientIp, "oidc", "login failed", "%s"}, err)
+			Abort(c, http.StatusUnauthorized, i18n.ErrInvalidCredentials)
+			return
+		}
+
+		sub := claims.Subject()
+		user, err := OIDCUser(claims)
+
+		if err != nil {
+			limiter.Auth.Reserve(clientIp)
+			event.AuditWarn([]string{clientIp, "oidc", "login as %s", "%s"}, clean.LogQuote(sub), err)
+			event.LoginError(clientIp, entity.AuthProviderOIDC, sub, c.Request.UserAgent(), err.Error())
+			Abort(c, http.StatusUnauthorized, i18n.ErrInvalidCredentials)
+			return
+		}
+
+		// Ask for a verification code in a second step if two-factor authentication is enabled.
+		if passcode := user.Passcode(); passcode != nil && passcode.Verified() {
+			l := oidc.NewPendingLogin(clientIp, user.UserUID, p.Issuer, sub)
+			event.AuditInfo([]string{clientIp, "oidc", "login as %s", "verification code required"}, clean.LogQuote(user.Name()))
+			c.Redirect(http.StatusTemporaryRedirect, conf.BaseUri("/library/login")+"?oidc="+l.Token)
+			return
+		}
+
+		sess, err := oidcSession(c, user, p.Issuer, sub)
+
+		if err != nil {
+			event.AuditErr([]string{clientIp, "%s"}, err)
+			AbortUnexpected(c)
+			return
+		} else if sess == nil {
+			AbortUnexpected(c)
+			return
+		}
+
+		event.AuditInfo([]string{clientIp, "session %s", "login as %s", "succeeded"}, sess.RefID, clean.LogQuote(user.Name()))
+		event.LoginInfo(clientIp, entity.AuthProviderOIDC, user.Name(), c.Request.UserAgent())
+
+		// Pass the session to the web app, which then continues in the library.
+		c.HTML(http.StatusOK, "auth.tmpl", gin.H{
+			"auth": gin.H{
+				"id":   sess.ID,
+				"user": sess.User(),
+				"data": sess.Data(),
+				"uri":  conf.BaseUri("/library/"),
+			},
+			"config": conf.ClientSession(sess),
+		})
+	})
+}
+
+// OIDCPasscode completes an OpenID Connect login with a verification code if the user has enabled
+// two-factor authentication, and returns the new session as JSON.
+//
+// POST /api/v1/oidc/passcode
+func OIDCPasscode(router *gin.RouterGroup) {
+	router.POST("/oidc/passcode", func(c *gin.Context) {
+		conf := get.Config()
+
+		if !conf.OIDCEnabled() {
+			AbortFeatureDisabled(c)
+			return
+		}
+
+		clientIp := ClientIP(c)
+
+		// Check limit for failed auth requests (max. 10 per minute).
+		if limiter.Auth.Reject(clientIp) {
+			limiter.AbortJSON(c)
+			return
+		}
+
+		var f form.Login
+
+		if err := c.BindJSON(&f); err != nil {
+			AbortBadRequest(c)
+			return
+		}
+
+		// Pending logins can only be used once, and only from the same client address.
+		l, found := oidc.TakePendingLogin(f.AuthToken)
+
+		if !found || l.ClientIP != clientIp {
+			limiter.Auth.Reserve(clientIp)
+			event.AuditWarn([]string{clientIp, "oidc", "verification code", "invalid token"})
+			Abort(c, http.StatusUnauthorized, i18n.ErrLoginRequired)
+			return
+		}
+
+		user := entity.FindUserByUID(l.UserUID)
+
+		if user == nil || !user.CanLogIn() {
+			event.AuditWarn([]string{clientIp, "oidc", "login as %s", "account disabled"}, clean.LogQuote(l.Subject))
+			Abort(c, http.StatusUnauthorized, i18n.ErrInvalidCredentials)
+			return
+		}
+
+		// A wrong code also invalidates the pending login, so users must log in with the provider again.
+		if passcode := user.Passcode(); passcode != nil && passcode.Verified() {
+			if recovery, err := passcode.Verify(f.Passcode); err != nil {
+				message := "incorrect verification code"
+				limiter.Auth.Reserve(clientIp)
+				event.AuditErr([]string{clientIp, "oidc", "login as %s", message}, clean.LogQuote(user.Name()))
+				event.LoginError(clientIp, entity.AuthProviderOIDC, user.Name(), c.Request.UserAgent(), message)
+				Abort(c, http.StatusUnauthorized, i18n.ErrInvalidPasscode)
+				return
+			} else if recovery {
+				event.AuditWarn([]string{clientIp, "oidc", "login as %s", "recovery code used, %d left"}, clean.LogQuote(user.Name()), passcode.RecoveryCodesLeft())
+			}
+		}
+
+		sess, err := oidcSession(c, user, l.Issuer, l.Subject)
+
+		if err != nil {
+			event.AuditErr([]string{clientIp, "%s"}, err)
+			AbortUnexpected(c)
+			return
+		} else if sess == nil {
+			AbortUnexpected(c)
+			return
+		}
+
+		event.AuditInfo([]string{clientIp, "session %s", "login as %s", "succeeded"}, sess.RefID, clean.LogQuote(user.Name()))
+		event.LoginInfo(clientIp, entity.AuthProviderOIDC, user.Name(), c.Request.UserAgent())
+
+		// Add session id to response headers.
+		AddSessionHeader(c, sess.ID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"status": "ok",
+			"id":     sess.ID,
+			"user":   sess.User(),
+			"data":   sess.Data(),
+			"config": conf.ClientSession(sess),
+		})
+	})
+}
+
+// oidcSession creates and saves a new session for a user who has logged in with OpenID Connect.
+func oidcSession(c *gin.Context, user *entity.User, issuer, sub string) (*entity.Session, error) {
+	sess := get.Session().New(c)
+	sess.SetUser(user)
+	sess.AuthProvider = entity.AuthProviderOIDC
+	sess.AuthDomain = issuer
+	sess.AuthID = sub
+
+	return get.Session().Save(sess)
+}
+
+// OIDCUser returns the user account for the verified claims, and either creates it
+// or updates its role, depending on the config options. Accounts are identified by
+// issuer and subject, since other claims such as the username may be changed by users.
+func OIDCUser(claims oidc.Claims) (*entity.User, error) {
+	conf := get.Config()
+	iss, sub := claims.Issuer(), claims.Subject()
+
+	if iss == "" {
+		return nil, fmt.Errorf("issuer is missing")
+	} else if sub == "" {
+		return nil, fmt.Errorf("subject is missing")
+	}
+
+	// Determine role, either from the role claim or the default.
+	role := conf.OIDCRole()
+
+	if claim := conf.OIDCRoleClaim(); claim != "" {
+		role = conf.OIDCClaimRole(claims.Strings(claim))
+	}
+
+	user := entity.FindUserByAuthID(entity.AuthProviderOIDC, iss, sub)
+
+	if user == nil {
+		if !conf.OIDCRegister() {
+			return nil, fmt.Errorf("account not found")
+		} else if role == acl.RoleUnknown {
+			return nil, fmt.Errorf("no matching role")
+		}
+
+		// Use the preferred username or email if it is not taken by another account.
+		name := clean.Username(claims.Username())
+
+		if name == "" || entity.FindUserByName(name) != nil {
+			name = clean.Username(claims.Email())
+		}
+
+		if name == "" || entity.FindUserByName(name) != nil {
+			name = clean.Username(entity.AuthProviderOIDC + "-" + sub)
+		}
+
+		return entity.AddProviderUser(entity.AuthProviderOIDC, iss, sub, form.User{
+			UserName:    name,
+			UserEmail:   clean.Email(claims.Email()),
+			DisplayName: clean.Name(claims.Name()),
+			UserRole:    role.String(),
+			CanLogin:    true,
+		})
+	}
+
+	// Keep the role in sync with the provider if a role claim is configured.
+	if conf.OIDCRoleClaim() != "" {
+		if role == acl.RoleUnknown {
+			return nil, fmt.Errorf("no matching role")
+		} else if err := user.SetRole(role.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	if !user.CanLogIn() {
+		return nil, fmt.Errorf("account disabled")
+	}
+
+	return user, nil
+}
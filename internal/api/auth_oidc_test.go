@@ -0,0 +1,278 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+	"golang.org/x/time/rate"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/oidc"
+	"github.com/photoprism/photoprism/internal/server/limiter"
+	"github.com/photoprism/photoprism/pkg/totp"
+)
+
+// oidcAuthorize starts a login and returns the redirect query and state cookie.
+func oidcAuthorize(t *testing.T, app http.Handler) (query string, cookie *http.Cookie) {
+	r := PerformRequest(app, "GET", "/api/v1/oidc/login")
+
+	if r.Code != http.StatusTemporaryRedirect {
+		t.Fatalf("unexpected status %d", r.Code)
+	}
+
+	if cookies := r.Result().Cookies(); len(cookies) != 1 {
+		t.Fatal("state cookie is missing")
+	} else {
+		cookie = cookies[0]
+	}
+
+	client := &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	resp, err := client.Get(r.Header().Get("Location"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	u, err := url.Parse(resp.Header.Get("Location"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return u.RawQuery, cookie
+}
+
+// oidcRedirect completes a login with the query and state cookie.
+func oidcRedirect(app http.Handler, query string, cookie *http.Cookie) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/api/v1/oidc/redirect?"+query, nil)
+
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestOIDCLogin(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		OIDCLogin(router)
+		r := PerformRequest(app, "GET", "/api/v1/oidc/login")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+}
+
+func TestOIDCRedirect(t *testing.T) {
+	issuer := oidc.NewMockIssuer("photoprism", "secret", oidc.Claims{
+		"sub":                "oidc-api-jane",
+		"preferred_username": "oidc-api-jane",
+		"email":              "jane@example.com",
+		"name":               "Jane Doe",
+		"groups":             []string{"family"},
+	})
+	defer issuer.Close()
+
+	app, router, conf := NewApiTest()
+	app.LoadHTMLFiles(conf.TemplateFiles()...)
+	conf.SetAuthMode(config.AuthModePasswd)
+	defer conf.SetAuthMode(config.AuthModePublic)
+
+	conf.Options().OIDCUri = issuer.URL
+	conf.Options().OIDCClient = issuer.ClientID
+	conf.Options().OIDCSecret = issuer.ClientSecret
+	defer func() {
+		conf.Options().OIDCUri = ""
+		conf.Options().OIDCClient = ""
+		conf.Options().OIDCSecret = ""
+		conf.Options().OIDCRegister = false
+		conf.Options().OIDCRoleClaim = ""
+		conf.Options().OIDCRoleMap = ""
+	}()
+
+	// Failed logins must not affect the rate limit of other tests.
+	authLimit := limiter.Auth
+	limiter.Auth = limiter.NewLimit(rate.Every(limiter.DefaultAuthInterval), limiter.DefaultAuthLimit)
+	defer func() { limiter.Auth = authLimit }()
+
+	OIDCLogin(router)
+	OIDCRedirect(router)
+	OIDCPasscode(router)
+
+	t.Run("NotRegistered", func(t *testing.T) {
+		conf.Options().OIDCRegister = false
+		query, cookie := oidcAuthorize(t, app)
+		r := oidcRedirect(app, query, cookie)
+		assert.Equal(t, http.StatusUnauthorized, r.Code)
+		assert.Nil(t, entity.FindUserByAuthID(entity.AuthProviderOIDC, issuer.URL, "oidc-api-jane"))
+	})
+	t.Run("InvalidState", func(t *testing.T) {
+		query, _ := oidcAuthorize(t, app)
+		r := oidcRedirect(app, query, nil)
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("Error", func(t *testing.T) {
+		r := oidcRedirect(app, "error=access_denied", nil)
+		assert.Equal(t, http.StatusUnauthorized, r.Code)
+	})
+	t.Run("NoMatchingRole", func(t *testing.T) {
+		conf.Options().OIDCRegister = true
+		conf.Options().OIDCRoleClaim = "groups"
+		conf.Options().OIDCRoleMap = "photo-admins=admin"
+		query, cookie := oidcAuthorize(t, app)
+		r := oidcRedirect(app, query, cookie)
+		assert.Equal(t, http.StatusUnauthorized, r.Code)
+		assert.Nil(t, entity.FindUserByAuthID(entity.AuthProviderOIDC, issuer.URL, "oidc-api-jane"))
+	})
+	t.Run("Register", func(t *testing.T) {
+		conf.Options().OIDCRegister = true
+		conf.Options().OIDCRoleClaim = "groups"
+		conf.Options().OIDCRoleMap = "family=admin"
+		query, cookie := oidcAuthorize(t, app)
+		r := oidcRedirect(app, query, cookie)
+		assert.Equal(t, http.StatusOK, r.Code)
+
+		user := entity.FindUserByAuthID(entity.AuthProviderOIDC, issuer.URL, "oidc-api-jane")
+
+		if user == nil {
+			t.Fatal("user has not been created")
+		}
+
+		assert.Equal(t, "oidc-api-jane", user.Name())
+		assert.Equal(t, "jane@example.com", user.UserEmail)
+		assert.Equal(t, "admin", user.UserRole)
+		assert.True(t, strings.Contains(r.Body.String(), "session_id"))
+
+		// Same query cannot be used twice.
+		r = oidcRedirect(app, query, cookie)
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("Login", func(t *testing.T) {
+		conf.Options().OIDCRegister = false
+		conf.Options().OIDCRoleClaim = ""
+		query, cookie := oidcAuthorize(t, app)
+		r := oidcRedirect(app, query, cookie)
+		assert.Equal(t, http.StatusOK, r.Code)
+	})
+	t.Run("Passcode", func(t *testing.T) {
+		conf.Options().OIDCRegister = false
+		conf.Options().OIDCRoleClaim = ""
+
+		user := entity.FindUserByAuthID(entity.AuthProviderOIDC, issuer.URL, "oidc-api-jane")
+
+		if user == nil {
+			t.Fatal("user not found")
+		}
+
+		passcode, err := entity.NewUserPasscode(user.UserUID)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		code, _ := totp.Code(passcode.KeySecret, time.Now())
+		recoveryCodes, err := passcode.Activate(code)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer passcode.Delete()
+
+		// oidcLogin completes the login at the provider and returns the pending login token.
+		oidcLogin := func() string {
+			query, cookie := oidcAuthorize(t, app)
+			r := oidcRedirect(app, query, cookie)
+
+			// No session is created before the verification code has been entered.
+			assert.Equal(t, http.StatusTemporaryRedirect, r.Code)
+			assert.False(t, strings.Contains(r.Body.String(), "session_id"))
+
+			u, err := url.Parse(r.Header().Get("Location"))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, conf.BaseUri("/library/login"), u.Path)
+
+			return u.Query().Get("oidc")
+		}
+
+		token := oidcLogin()
+
+		r := PerformRequestWithBody(app, "POST", "/api/v1/oidc/passcode", `{"token": "`+token+`", "passcode": "123456"}`)
+		assert.Equal(t, http.StatusUnauthorized, r.Code)
+
+		// Pending logins are removed after a failed attempt.
+		r = PerformRequestWithBody(app, "POST", "/api/v1/oidc/passcode", `{"token": "`+token+`", "passcode": "`+recoveryCodes[0]+`"}`)
+		assert.Equal(t, http.StatusUnauthorized, r.Code)
+
+		token = oidcLogin()
+
+		r = PerformRequestWithBody(app, "POST", "/api/v1/oidc/passcode", `{"token": "`+token+`", "passcode": "`+recoveryCodes[0]+`"}`)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.NotEmpty(t, gjson.Get(r.Body.String(), "id").String())
+		assert.Equal(t, user.UserUID, gjson.Get(r.Body.String(), "user.UID").String())
+
+		// Pending logins can only be used once.
+		r = PerformRequestWithBody(app, "POST", "/api/v1/oidc/passcode", `{"token": "`+token+`", "passcode": "`+recoveryCodes[1]+`"}`)
+		assert.Equal(t, http.StatusUnauthorized, r.Code)
+	})
+	t.Run("NoIssuer", func(t *testing.T) {
+		conf.Options().OIDCRegister = false
+		conf.Options().OIDCRoleClaim = ""
+
+		// Accounts without issuer must not be assigned to the current issuer.
+		legacy := &entity.User{
+			UserName:     "oidc-api-legacy",
+			UserRole:     "admin",
+			AuthProvider: entity.AuthProviderOIDC,
+			AuthID:       "oidc-api-legacy",
+			CanLogin:     true,
+		}
+
+		if err := legacy.Create(); err != nil {
+			t.Fatal(err)
+		}
+
+		user, err := OIDCUser(oidc.Claims{"iss": issuer.URL, "sub": "oidc-api-legacy"})
+
+		assert.Error(t, err)
+		assert.Nil(t, user)
+		assert.Equal(t, "", entity.FindUserByUID(legacy.UserUID).AuthDomain)
+	})
+	t.Run("OtherIssuer", func(t *testing.T) {
+		conf.Options().OIDCRegister = true
+		conf.Options().OIDCRoleClaim = ""
+
+		// The same subject at another issuer is a different account, and usernames are never reused.
+		user, err := OIDCUser(oidc.Claims{
+			"iss":                "https://other.example.com",
+			"sub":                "oidc-api-jane",
+			"preferred_username": "oidc-api-jane",
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "https://other.example.com", user.AuthDomain)
+		assert.Equal(t, "oidc-oidc-api-jane", user.Name())
+		assert.NotEqual(t, entity.FindUserByAuthID(entity.AuthProviderOIDC, issuer.URL, "oidc-api-jane").UserUID, user.UserUID)
+	})
+}
@@ -52,6 +52,10 @@ func ChangePassword(router *gin.RouterGroup) {
 		if u == nil {
 			Abort(c, http.StatusNotFound, i18n.ErrUserNotFound)
 			return
+		} else if u.ExternalPassword() {
+			// Passwords managed by a directory server cannot be changed locally.
+			AbortForbidden(c)
+			return
 		}
 
 		f := form.ChangePassword{}
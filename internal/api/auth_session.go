@@ -1,6 +1,8 @@
 package api
 
 import (
+	"strings"
+
 	"github.com/gin-gonic/gin"
 
 	"github.com/photoprism/photoprism/internal/entity"
@@ -20,6 +22,50 @@ func SessionID(c *gin.Context) (sessId string) {
 	return clean.ID(c.GetHeader(session.Header))
 }
 
+// BearerToken returns the access token from the authorization header, if any.
+func BearerToken(c *gin.Context) string {
+	if c == nil {
+		// Should never happen.
+		return ""
+	}
+
+	// Get the bearer token from the HTTP headers.
+	if h := c.GetHeader(session.AuthHeader); len(h) > len(session.BearerPrefix) && strings.EqualFold(h[:len(session.BearerPrefix)], session.BearerPrefix) {
+		return clean.ID(h[len(session.BearerPrefix):])
+	}
+
+	return ""
+}
+
+// TokenSession returns a session for the given access token or nil if the token is invalid.
+func TokenSession(secret string) *entity.Session {
+	// Access tokens cannot be used in public mode.
+	if get.Config().Public() || secret == "" {
+		return nil
+	}
+
+	// Find access token.
+	t := entity.FindUserToken(secret)
+
+	if t == nil || t.Expired() {
+		return nil
+	}
+
+	// Check if the user is still allowed to log in.
+	u := t.User()
+
+	if u == nil || !u.CanLogIn() {
+		return nil
+	}
+
+	// Remember when the token was last used.
+	if err := t.UpdateLastUsed(); err != nil {
+		log.Warnf("auth: %s while updating token %s", err, t.TokenUID)
+	}
+
+	return t.Session(u)
+}
+
 // Session finds the client session for the given ID or returns nil otherwise.
 func Session(id string) *entity.Session {
 	// Return default session when public mode is enabled.
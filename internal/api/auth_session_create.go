@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -62,8 +63,12 @@ func CreateSession(router *gin.RouterGroup) {
 		}
 
 		// Try to log in and save session if successful.
-		if err := sess.LogIn(f, c); err != nil {
-			c.AbortWithStatusJSON(sess.HttpStatus(), gin.H{"error": i18n.Msg(i18n.ErrInvalidCredentials)})
+		if err := sess.LogIn(f, c); errors.Is(err, entity.ErrPasscodeRequired) {
+			// Ask the client for a verification code in a second step.
+			c.AbortWithStatusJSON(sess.HttpStatus(), gin.H{"error": i18n.Msg(i18n.ErrPasscodeRequired), "code": "passcode_required"})
+			return
+		} else if err != nil {
+			c.AbortWithStatusJSON(sess.HttpStatus(), gin.H{"error": err.Error()})
 			return
 		} else if sess, err = get.Session().Save(sess); err != nil {
 			event.AuditErr([]string{ClientIP(c), "%s"}, err)
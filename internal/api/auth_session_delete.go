@@ -28,7 +28,7 @@ func DeleteSession(router *gin.RouterGroup) {
 		if err := get.Session().Delete(id); err != nil {
 			event.AuditErr([]string{ClientIP(c), "session %s"}, err)
 		} else {
-			event.AuditDebug([]string{ClientIP(c), "session deleted"})
+			event.AuditInfo([]string{ClientIP(c), "session deleted", "logged out"})
 		}
 
 		c.JSON(http.StatusOK, gin.H{"status": "ok", "id": id})
@@ -2,14 +2,19 @@ package api
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/tidwall/gjson"
+	"golang.org/x/time/rate"
 
 	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/form"
 	"github.com/photoprism/photoprism/internal/i18n"
+	"github.com/photoprism/photoprism/internal/ldap"
+	"github.com/photoprism/photoprism/internal/server/limiter"
 	"github.com/photoprism/photoprism/internal/session"
 )
 
@@ -148,6 +153,90 @@ func TestCreateSession(t *testing.T) {
 	})
 }
 
+func TestCreateSession_Ldap(t *testing.T) {
+	srv := ldap.NewMockServer([]*ldap.Entry{
+		ldap.NewEntry("uid=ldap-api-jane,ou=people,dc=example,dc=com", map[string][]string{
+			"uid":         {"ldap-api-jane"},
+			"mail":        {"jane@ldap.example.com"},
+			"displayName": {"Jane Doe"},
+			"memberOf":    {"cn=admins,ou=groups,dc=example,dc=com"},
+		}),
+	}, map[string]string{
+		"uid=ldap-api-jane,ou=people,dc=example,dc=com": "Jane123!",
+	})
+	defer srv.Close()
+
+	app, router, conf := NewApiTest()
+	conf.SetAuthMode(config.AuthModePasswd)
+	defer conf.SetAuthMode(config.AuthModePublic)
+
+	conf.Options().AuthProvider = config.AuthProviderLDAP
+	conf.Options().LDAPUri = srv.Uri()
+	conf.Options().LDAPInsecure = true
+	conf.Options().LDAPBaseDN = "dc=example,dc=com"
+	conf.Options().LDAPAdminGroup = "admins"
+	conf.Propagate()
+	defer func() {
+		conf.Options().AuthProvider = ""
+		conf.Options().LDAPUri = ""
+		conf.Options().LDAPInsecure = false
+		conf.Options().LDAPBaseDN = ""
+		conf.Options().LDAPAdminGroup = ""
+		conf.Propagate()
+	}()
+
+	// Failed logins must not affect the rate limit of other tests.
+	authLimit := limiter.Auth
+	limiter.Auth = limiter.NewLimit(rate.Every(limiter.DefaultAuthInterval), limiter.DefaultAuthLimit)
+	defer func() { limiter.Auth = authLimit }()
+
+	CreateSession(router)
+	ChangePassword(router)
+
+	login := func(name, password string) *httptest.ResponseRecorder {
+		return PerformRequestWithBody(app, http.MethodPost, "/api/v1/session", form.AsJson(form.Login{UserName: name, Password: password}))
+	}
+
+	t.Run("InvalidPassword", func(t *testing.T) {
+		r := login("ldap-api-jane", "wrong")
+		assert.Equal(t, http.StatusUnauthorized, r.Code)
+		assert.Nil(t, entity.FindUserByName("ldap-api-jane"))
+	})
+
+	sessId := login("ldap-api-jane", "Jane123!").Header().Get(session.Header)
+
+	t.Run("Success", func(t *testing.T) {
+		assert.NotEqual(t, "", sessId)
+
+		user := entity.FindUserByName("ldap-api-jane")
+
+		if user == nil {
+			t.Fatal("user not found")
+		}
+
+		assert.Equal(t, entity.AuthProviderLDAP, user.AuthProvider)
+		assert.Equal(t, "uid=ldap-api-jane,ou=people,dc=example,dc=com", user.AuthID)
+		assert.Equal(t, "jane@ldap.example.com", user.UserEmail)
+		assert.Equal(t, "Jane Doe", user.DisplayName)
+		assert.Equal(t, "admin", user.UserRole)
+	})
+	t.Run("LocalUser", func(t *testing.T) {
+		r := login("bob", "Bobbob123!")
+		assert.Equal(t, http.StatusOK, r.Code)
+	})
+	t.Run("ChangePassword", func(t *testing.T) {
+		user := entity.FindUserByName("ldap-api-jane")
+
+		if user == nil {
+			t.Fatal("user not found")
+		}
+
+		r := AuthenticatedRequestWithBody(app, "PUT", "/api/v1/users/"+user.UserUID+"/password",
+			form.AsJson(form.ChangePassword{OldPassword: "Jane123!", NewPassword: "Jane1234!"}), sessId)
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+}
+
 func TestGetSession(t *testing.T) {
 	t.Run("AdminWithoutAuthentication", func(t *testing.T) {
 		app, router, conf := NewApiTest()
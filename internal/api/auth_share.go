@@ -11,9 +11,10 @@ import (
 	"github.com/photoprism/photoprism/internal/get"
 	"github.com/photoprism/photoprism/internal/query"
 	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/rnd"
 )
 
-// Shares handles link share
+// Shares handles link share visits, protected links require a password when redeeming the token.
 //
 // GET /s/:token/...
 func Shares(router *gin.RouterGroup) {
@@ -21,19 +22,23 @@ func Shares(router *gin.RouterGroup) {
 		conf := get.Config()
 
 		token := clean.Token(c.Param("token"))
-		links := entity.FindValidLinks(token, "")
+		links, status, err := entity.VerifyLinks(token, "")
 
-		if len(links) == 0 {
+		if status == http.StatusNotFound {
 			log.Debugf("share: invalid token")
 			c.Redirect(http.StatusTemporaryRedirect, conf.BaseUri(""))
 			return
+		} else if err != nil {
+			log.Debugf("share: %s", err)
+			c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+			return
 		}
 
 		clientConfig := conf.ClientShare()
 		clientConfig.SiteUrl = fmt.Sprintf("%ss/%s", clientConfig.SiteUrl, token)
 
 		uri := conf.BaseUri("/library/albums")
-		c.HTML(http.StatusOK, "share.tmpl", gin.H{"shared": gin.H{"token": token, "uri": uri}, "config": clientConfig})
+		c.HTML(http.StatusOK, "share.tmpl", gin.H{"shared": gin.H{"token": token, "uri": uri, "password": links.HasPassword()}, "config": clientConfig})
 	})
 
 	router.GET("/:token/:shared", func(c *gin.Context) {
@@ -42,12 +47,16 @@ func Shares(router *gin.RouterGroup) {
 		token := clean.Token(c.Param("token"))
 		shared := clean.Token(c.Param("shared"))
 
-		links := entity.FindValidLinks(token, shared)
+		links, status, err := entity.VerifyLinks(token, shared)
 
-		if len(links) < 1 {
+		if status == http.StatusNotFound {
 			log.Debugf("share: invalid token or slug")
 			c.Redirect(http.StatusTemporaryRedirect, conf.BaseUri(""))
 			return
+		} else if err != nil {
+			log.Debugf("share: %s", err)
+			c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+			return
 		}
 
 		uid := links[0].ShareUID
@@ -55,16 +64,41 @@ func Shares(router *gin.RouterGroup) {
 		clientConfig.SiteUrl = fmt.Sprintf("%s/%s", clientConfig.SiteUrl, path.Join("s", token, uid))
 		clientConfig.SitePreview = fmt.Sprintf("%s/preview", clientConfig.SiteUrl)
 
-		if a, err := query.AlbumByUID(uid); err == nil {
-			clientConfig.SiteCaption = a.AlbumTitle
+		var uri string
+
+		switch {
+		case rnd.IsUID(uid, entity.PhotoUID):
+			if p, err := query.PhotoByUID(uid); err == nil {
+				clientConfig.SiteCaption = p.PhotoTitle
 
-			if a.AlbumDescription != "" {
-				clientConfig.SiteDescription = a.AlbumDescription
+				if p.PhotoDescription != "" {
+					clientConfig.SiteDescription = p.PhotoDescription
+				}
 			}
-		}
 
-		uri := conf.BaseUri(path.Join("/library/albums", uid, shared))
+			uri = conf.BaseUri(path.Join("/library/photos", uid, shared))
+		case rnd.IsUID(uid, entity.LabelUID):
+			if l, err := query.LabelByUID(uid); err == nil {
+				clientConfig.SiteCaption = l.LabelName
+
+				if l.LabelDescription != "" {
+					clientConfig.SiteDescription = l.LabelDescription
+				}
+			}
+
+			uri = conf.BaseUri(path.Join("/library/labels", uid, shared))
+		default:
+			if a, err := query.AlbumByUID(uid); err == nil {
+				clientConfig.SiteCaption = a.AlbumTitle
+
+				if a.AlbumDescription != "" {
+					clientConfig.SiteDescription = a.AlbumDescription
+				}
+			}
+
+			uri = conf.BaseUri(path.Join("/library/albums", uid, shared))
+		}
 
-		c.HTML(http.StatusOK, "share.tmpl", gin.H{"shared": gin.H{"token": token, "uri": uri}, "config": clientConfig})
+		c.HTML(http.StatusOK, "share.tmpl", gin.H{"shared": gin.H{"token": token, "uri": uri, "password": links.HasPassword()}, "config": clientConfig})
 	})
 }
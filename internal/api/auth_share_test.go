@@ -34,4 +34,16 @@ func TestGetShares(t *testing.T) {
 		r := PerformRequest(app, "GET", "/api/v1/4jxf3jfn2k")
 		assert.Equal(t, http.StatusTemporaryRedirect, r.Code)
 	})*/
+	t.Run("ExpiredToken", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		Shares(router)
+		r := PerformRequest(app, "GET", "/api/v1/8jxf3jfn2k/expired")
+		assert.Equal(t, http.StatusGone, r.Code)
+	})
+	t.Run("ViewLimitReached", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		Shares(router)
+		r := PerformRequest(app, "GET", "/api/v1/9jxf3jfn2k")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
 }
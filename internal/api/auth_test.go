@@ -4,11 +4,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"testing"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/form"
 	"github.com/photoprism/photoprism/internal/session"
+	"github.com/photoprism/photoprism/pkg/rnd"
 )
 
 // AuthenticateAdmin Register session routes and returns valid SessionId.
@@ -60,3 +64,46 @@ func AuthenticatedRequestWithBody(r http.Handler, method, path, body string, ses
 
 	return w
 }
+
+// LimitedSession creates a user with a custom role that may only access own and shared content,
+// and returns the ID of a new session for this user. Requests are only authenticated in non-public mode.
+func LimitedSession(t *testing.T) (sessId string) {
+	role := acl.Role("limited")
+
+	grant := acl.Grant{acl.AccessOwn: true, acl.AccessPrivate: true, acl.ActionSearch: true, acl.ActionView: true,
+		acl.ActionUpdate: true, acl.ActionDelete: true, acl.ActionShare: true}
+
+	acl.CustomRoles{
+		role: acl.Grants{
+			acl.ResourcePhotos: grant,
+			acl.ResourceAlbums: grant,
+			acl.ResourceLabels: grant,
+		},
+	}.Apply(acl.Resources)
+
+	t.Cleanup(func() {
+		for resource := range acl.Resources {
+			delete(acl.Resources[resource], role)
+		}
+
+		delete(acl.ValidRoles, role.String())
+	})
+
+	user := &entity.User{
+		UserName: "limited-" + rnd.Base36(8),
+		UserRole: role.String(),
+		CanLogin: true,
+	}
+
+	if err := user.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	sess := entity.NewSession(0, 0).SetUser(user)
+
+	if err := sess.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	return sess.ID
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/i18n"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// GetBackups returns the backup archives and SQL dumps that can be restored as JSON, newest first.
+//
+// GET /api/v1/backups
+func GetBackups(router *gin.RouterGroup) {
+	router.GET("/backups", func(c *gin.Context) {
+		// Backups cannot be managed in public mode.
+		if get.Config().Public() {
+			Abort(c, http.StatusForbidden, i18n.ErrPublic)
+			return
+		}
+
+		s := Auth(c, acl.ResourceBackups, acl.ActionSearch)
+
+		if s.Abort(c) {
+			return
+		}
+
+		resp, err := photoprism.Backups()
+
+		if err != nil {
+			log.Errorf("restore: %s", err)
+			AbortUnexpected(c)
+			return
+		}
+
+		AddCountHeader(c, len(resp))
+
+		c.JSON(http.StatusOK, resp)
+	})
+}
+
+// RestoreBackup verifies a backup and restores it in the background while maintenance mode is enabled.
+// If DryRun is set, only the changes that would be made are reported.
+//
+// POST /api/v1/backups/:name/restore
+func RestoreBackup(router *gin.RouterGroup) {
+	router.POST("/backups/:name/restore", func(c *gin.Context) {
+		// Backups cannot be managed in public mode.
+		if get.Config().Public() {
+			Abort(c, http.StatusForbidden, i18n.ErrPublic)
+			return
+		}
+
+		s := Auth(c, acl.ResourceBackups, acl.ActionManage)
+
+		if s.Abort(c) {
+			return
+		}
+
+		var f form.Restore
+
+		if c.Request.ContentLength > 0 {
+			if err := c.BindJSON(&f); err != nil {
+				AbortBadRequest(c)
+				return
+			}
+		}
+
+		b, err := photoprism.FindBackup(c.Param("name"))
+
+		if err == photoprism.ErrBackupNotFound {
+			Abort(c, http.StatusNotFound, i18n.ErrBackupNotFound)
+			return
+		} else if err != nil {
+			log.Errorf("restore: %s", err)
+			AbortUnexpected(c)
+			return
+		}
+
+		// Make sure no other workers are running.
+		if !f.DryRun && (mutex.RestoreWorker.Running() || mutex.IndexWorkersRunning() || mutex.BackupWorker.Running()) {
+			AbortBusy(c)
+			return
+		}
+
+		report, err := photoprism.PreviewRestore(b)
+
+		if err != nil {
+			Error(c, http.StatusBadRequest, err, i18n.ErrBadRequest)
+			return
+		} else if f.DryRun {
+			c.JSON(http.StatusOK, report)
+			return
+		}
+
+		// Prevent other workers and restores from starting before the response is sent.
+		if err = photoprism.StartRestore(); err != nil {
+			AbortBusy(c)
+			return
+		}
+
+		report.DryRun = false
+
+		event.AuditWarn([]string{ClientIP(c), "session %s", "backup %s", "restore started"}, s.RefID, clean.Log(b.Name))
+
+		conf := get.Config()
+
+		// Enable maintenance mode and notify clients before the restore starts.
+		conf.SetMaintenance(true)
+		UpdateClientConfig()
+
+		go func() {
+			if err := photoprism.RunRestore(b); err != nil {
+				log.Errorf("restore: %s", err)
+			}
+
+			conf.SetMaintenance(false)
+
+			// Flush caches that may contain outdated data.
+			get.ThumbCache().Flush()
+			get.FolderCache().Flush()
+			FlushCoverCache()
+
+			UpdateClientConfig()
+		}()
+
+		c.JSON(http.StatusAccepted, report)
+	})
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/config"
+)
+
+func TestGetBackups(t *testing.T) {
+	t.Run("Public", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetBackups(router)
+		r := PerformRequest(app, "GET", "/api/v1/backups")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+	t.Run("Alice", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		GetBackups(router)
+		sessId := AuthenticateUser(app, router, "alice", "Alice123!")
+
+		r := AuthenticatedRequest(app, "GET", "/api/v1/backups", sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+	})
+}
+
+func TestRestoreBackup(t *testing.T) {
+	t.Run("Public", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		RestoreBackup(router)
+		r := PerformRequestWithBody(app, "POST", "/api/v1/backups/20221020-101010/restore", `{"DryRun": true}`)
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		RestoreBackup(router)
+		sessId := AuthenticateUser(app, router, "alice", "Alice123!")
+
+		r := AuthenticatedRequestWithBody(app, "POST", "/api/v1/backups/19000101-000000/restore", `{"DryRun": true}`, sessId)
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+}
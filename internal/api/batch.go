@@ -17,6 +17,7 @@ import (
 	"github.com/photoprism/photoprism/internal/i18n"
 	"github.com/photoprism/photoprism/internal/photoprism"
 	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/search"
 	"github.com/photoprism/photoprism/pkg/clean"
 )
 
@@ -45,15 +46,19 @@ func BatchPhotosArchive(router *gin.RouterGroup) {
 
 		log.Infof("photos: archiving %s", clean.Log(f.String()))
 
-		if get.Config().BackupYaml() {
-			// Fetch selection from index.
-			photos, err := query.SelectedPhotos(f)
+		// Fetch selection from index, limited to photos the session may access.
+		photos, err := query.UserSelectedPhotos(f, s)
 
-			if err != nil {
-				AbortEntityNotFound(c)
-				return
-			}
+		if err != nil {
+			AbortEntityNotFound(c)
+			return
+		}
+
+		uids := photos.UIDs()
 
+		if len(uids) == 0 {
+			// Nothing to archive.
+		} else if get.Config().BackupYaml() {
 			for _, p := range photos {
 				if err := p.Archive(); err != nil {
 					log.Errorf("archive: %s", err)
@@ -61,11 +66,11 @@ func BatchPhotosArchive(router *gin.RouterGroup) {
 					SavePhotoAsYaml(p)
 				}
 			}
-		} else if err := entity.Db().Where("photo_uid IN (?)", f.Photos).Delete(&entity.Photo{}).Error; err != nil {
+		} else if err := entity.Db().Where("photo_uid IN (?)", uids).Delete(&entity.Photo{}).Error; err != nil {
 			log.Errorf("archive: %s", err)
 			AbortSaveFailed(c)
 			return
-		} else if err := entity.Db().Model(&entity.PhotoAlbum{}).Where("photo_uid IN (?)", f.Photos).UpdateColumn("hidden", true).Error; err != nil {
+		} else if err := entity.Db().Model(&entity.PhotoAlbum{}).Where("photo_uid IN (?)", uids).UpdateColumn("hidden", true).Error; err != nil {
 			log.Errorf("archive: %s", err)
 		}
 
@@ -75,9 +80,13 @@ func BatchPhotosArchive(router *gin.RouterGroup) {
 		// Update album, subject, and label cover thumbs.
 		logWarn("index", query.UpdateCovers())
 
+		for _, uid := range uids {
+			event.AuditInfo([]string{ClientIP(c), "session %s", "photo %s", "archived"}, s.RefID, uid)
+		}
+
 		UpdateClientConfig()
 
-		event.EntitiesArchived("photos", f.Photos)
+		event.EntitiesArchived("photos", uids)
 
 		c.JSON(http.StatusOK, i18n.NewResponse(http.StatusOK, i18n.MsgSelectionArchived))
 	})
@@ -108,15 +117,19 @@ func BatchPhotosRestore(router *gin.RouterGroup) {
 
 		log.Infof("photos: restoring %s", clean.Log(f.String()))
 
-		if get.Config().BackupYaml() {
-			// Fetch selection from index.
-			photos, err := query.SelectedPhotos(f)
+		// Fetch selection from index, limited to photos the session may access.
+		photos, err := query.UserSelectedPhotos(f, s)
 
-			if err != nil {
-				AbortEntityNotFound(c)
-				return
-			}
+		if err != nil {
+			AbortEntityNotFound(c)
+			return
+		}
+
+		uids := photos.UIDs()
 
+		if len(uids) == 0 {
+			// Nothing to restore.
+		} else if get.Config().BackupYaml() {
 			for _, p := range photos {
 				if err := p.Restore(); err != nil {
 					log.Errorf("restore: %s", err)
@@ -124,7 +137,7 @@ func BatchPhotosRestore(router *gin.RouterGroup) {
 					SavePhotoAsYaml(p)
 				}
 			}
-		} else if err := entity.Db().Unscoped().Model(&entity.Photo{}).Where("photo_uid IN (?)", f.Photos).
+		} else if err := entity.Db().Unscoped().Model(&entity.Photo{}).Where("photo_uid IN (?)", uids).
 			UpdateColumn("deleted_at", gorm.Expr("NULL")).Error; err != nil {
 			log.Errorf("restore: %s", err)
 			AbortSaveFailed(c)
@@ -139,7 +152,7 @@ func BatchPhotosRestore(router *gin.RouterGroup) {
 
 		UpdateClientConfig()
 
-		event.EntitiesRestored("photos", f.Photos)
+		event.EntitiesRestored("photos", uids)
 
 		c.JSON(http.StatusOK, i18n.NewResponse(http.StatusOK, i18n.MsgSelectionRestored))
 	})
@@ -171,7 +184,7 @@ func BatchPhotosApprove(router *gin.RouterGroup) {
 		log.Infof("photos: approving %s", clean.Log(f.String()))
 
 		// Fetch selection from index.
-		photos, err := query.SelectedPhotos(f)
+		photos, err := query.UserSelectedPhotos(f, s)
 
 		if err != nil {
 			AbortEntityNotFound(c)
@@ -222,17 +235,34 @@ func BatchAlbumsDelete(router *gin.RouterGroup) {
 
 		log.Infof("albums: deleting %s", clean.Log(f.String()))
 
+		// Only delete albums for which the session has full permissions.
+		var uids []string
+
+		for _, uid := range f.Albums {
+			if search.AlbumPerm(uid, s) >= entity.PermAll {
+				uids = append(uids, uid)
+			} else {
+				event.AuditErr([]string{ClientIP(c), "session %s", "delete album %s as %s", "denied"}, s.RefID, uid, s.User().AclRole().String())
+			}
+		}
+
 		// Soft delete albums, can be restored.
-		entity.Db().Where("album_uid IN (?)", f.Albums).Delete(&entity.Album{})
+		if len(uids) > 0 {
+			entity.Db().Where("album_uid IN (?)", uids).Delete(&entity.Album{})
+		}
 
 		/*
 			KEEP ENTRIES AS ALBUMS MAY NOW BE RESTORED BY NAME
 			entity.Db().Where("album_uid IN (?)", f.Albums).Delete(&entity.PhotoAlbum{})
 		*/
 
+		for _, uid := range uids {
+			event.AuditInfo([]string{ClientIP(c), "session %s", "album %s", "deleted"}, s.RefID, uid)
+		}
+
 		UpdateClientConfig()
 
-		event.EntitiesDeleted("albums", f.Albums)
+		event.EntitiesDeleted("albums", uids)
 
 		c.JSON(http.StatusOK, i18n.NewResponse(http.StatusOK, i18n.MsgAlbumsDeleted))
 	})
@@ -263,8 +293,19 @@ func BatchPhotosPrivate(router *gin.RouterGroup) {
 
 		log.Infof("photos: updating private flag for %s", clean.Log(f.String()))
 
-		if err := entity.Db().Model(entity.Photo{}).Where("photo_uid IN (?)", f.Photos).UpdateColumn("photo_private",
-			gorm.Expr("CASE WHEN photo_private > 0 THEN 0 ELSE 1 END")).Error; err != nil {
+		// Fetch selection from index, limited to photos the session may access.
+		photos, err := query.UserSelectedPhotos(f, s)
+
+		if err != nil {
+			AbortEntityNotFound(c)
+			return
+		}
+
+		uids := photos.UIDs()
+
+		if len(uids) == 0 {
+			// Nothing to update.
+		} else if err := query.TogglePhotosPrivate(uids); err != nil {
 			log.Errorf("private: %s", err)
 			AbortSaveFailed(c)
 			return
@@ -273,8 +314,10 @@ func BatchPhotosPrivate(router *gin.RouterGroup) {
 		// Update precalculated photo and file counts.
 		logWarn("index", entity.UpdateCounts())
 
-		// Fetch selection from index.
-		if photos, err := query.SelectedPhotos(f); err == nil {
+		// Fetch updated photos from index.
+		if len(uids) == 0 {
+			// Nothing to save.
+		} else if photos, err = query.SelectedPhotos(form.Selection{Photos: uids}); err == nil {
 			for _, p := range photos {
 				SavePhotoAsYaml(p)
 			}
@@ -324,7 +367,11 @@ func BatchLabelsDelete(router *gin.RouterGroup) {
 		}
 
 		for _, label := range labels {
-			logError("labels", label.Delete())
+			if err := label.Delete(); err != nil {
+				logError("labels", err)
+			} else {
+				event.AuditInfo([]string{ClientIP(c), "session %s", "label %s", "deleted"}, s.RefID, label.LabelUID)
+			}
 		}
 
 		UpdateClientConfig()
@@ -335,7 +382,8 @@ func BatchLabelsDelete(router *gin.RouterGroup) {
 	})
 }
 
-// BatchPhotosDelete permanently removes multiple photos from the archive.
+// BatchPhotosDelete moves multiple photos from the archive to the trash,
+// or permanently removes them if the trash is disabled.
 //
 // POST /api/v1/batch/photos/delete
 func BatchPhotosDelete(router *gin.RouterGroup) {
@@ -369,7 +417,7 @@ func BatchPhotosDelete(router *gin.RouterGroup) {
 
 		// Fetch selection from index and record time.
 		deleteStart := time.Now()
-		photos, err := query.SelectedPhotos(f)
+		photos, err := query.UserSelectedPhotos(f, s)
 
 		if err != nil {
 			AbortEntityNotFound(c)
@@ -382,13 +430,14 @@ func BatchPhotosDelete(router *gin.RouterGroup) {
 
 		// Delete photos.
 		for _, p := range photos {
-			n, err := photoprism.DeletePhoto(p, true, true)
+			n, err := photoprism.TrashPhoto(p, s.UserUID)
 
 			numFiles += n
 
 			if err != nil {
 				log.Errorf("delete: %s", err)
 			} else {
+				event.AuditInfo([]string{ClientIP(c), "session %s", "photo %s", "deleted"}, s.RefID, p.PhotoUID)
 				deleted = append(deleted, p)
 			}
 		}
@@ -407,6 +456,10 @@ func BatchPhotosDelete(router *gin.RouterGroup) {
 			event.EntitiesDeleted("photos", deleted.UIDs())
 		}
 
-		c.JSON(http.StatusOK, i18n.NewResponse(http.StatusOK, i18n.MsgPermanentlyDeleted))
+		if conf.TrashRetention() > 0 {
+			c.JSON(http.StatusOK, i18n.NewResponse(http.StatusOK, i18n.MsgMovedToTrash))
+		} else {
+			c.JSON(http.StatusOK, i18n.NewResponse(http.StatusOK, i18n.MsgPermanentlyDeleted))
+		}
 	})
 }
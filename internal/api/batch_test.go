@@ -6,11 +6,22 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/i18n"
 	"github.com/stretchr/testify/assert"
 	"github.com/tidwall/gjson"
 )
 
+// batchPhoto returns the photo with the specified UID, including archived photos.
+func batchPhoto(t *testing.T, uid string) (photo entity.Photo) {
+	if err := entity.UnscopedDb().Where("photo_uid = ?", uid).First(&photo).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return photo
+}
+
 func TestBatchPhotosArchive(t *testing.T) {
 	t.Run("successful request", func(t *testing.T) {
 		app, router, _ := NewApiTest()
@@ -45,6 +56,21 @@ func TestBatchPhotosArchive(t *testing.T) {
 		r := PerformRequestWithBody(app, "POST", "/api/v1/batch/photos/archive", `{"photos": 123}`)
 		assert.Equal(t, http.StatusBadRequest, r.Code)
 	})
+	t.Run("OtherUser", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		BatchPhotosArchive(router)
+
+		before := batchPhoto(t, "pt9jtdre2lvl0y24")
+		sessId := LimitedSession(t)
+
+		r := AuthenticatedRequestWithBody(app, "POST", "/api/v1/batch/photos/archive", `{"photos": ["pt9jtdre2lvl0y24"]}`, sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+
+		// Photos of other users must remain unchanged.
+		assert.Equal(t, before.DeletedAt, batchPhoto(t, "pt9jtdre2lvl0y24").DeletedAt)
+	})
 }
 
 func TestBatchPhotosRestore(t *testing.T) {
@@ -90,6 +116,22 @@ func TestBatchPhotosRestore(t *testing.T) {
 		r := PerformRequestWithBody(app, "POST", "/api/v1/batch/photos/restore", `{"photos": 123}`)
 		assert.Equal(t, http.StatusBadRequest, r.Code)
 	})
+	t.Run("OtherUser", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		BatchPhotosRestore(router)
+
+		before := batchPhoto(t, "pt9jtdre2lvl0y25")
+		assert.NotNil(t, before.DeletedAt)
+		sessId := LimitedSession(t)
+
+		r := AuthenticatedRequestWithBody(app, "POST", "/api/v1/batch/photos/restore", `{"photos": ["pt9jtdre2lvl0y25"]}`, sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+
+		// Photos of other users must remain archived.
+		assert.NotNil(t, batchPhoto(t, "pt9jtdre2lvl0y25").DeletedAt)
+	})
 }
 
 func TestBatchAlbumsDelete(t *testing.T) {
@@ -134,6 +176,26 @@ func TestBatchAlbumsDelete(t *testing.T) {
 		r := PerformRequestWithBody(app, "POST", "/api/v1/batch/albums/delete", `{"albums": 123}`)
 		assert.Equal(t, http.StatusBadRequest, r.Code)
 	})
+	t.Run("OtherUser", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		BatchAlbumsDelete(router)
+
+		sessId := LimitedSession(t)
+
+		r := AuthenticatedRequestWithBody(app, "POST", "/api/v1/batch/albums/delete", `{"albums": ["at9lxuqxpogaaba7"]}`, sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+
+		// Albums of other users must not be deleted.
+		var album entity.Album
+
+		if err := entity.UnscopedDb().Where("album_uid = ?", "at9lxuqxpogaaba7").First(&album).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Nil(t, album.DeletedAt)
+	})
 }
 
 func TestBatchPhotosPrivate(t *testing.T) {
@@ -173,6 +235,21 @@ func TestBatchPhotosPrivate(t *testing.T) {
 		r := PerformRequestWithBody(app, "POST", "/api/v1/batch/photos/private", `{"photos": 123}`)
 		assert.Equal(t, http.StatusBadRequest, r.Code)
 	})
+	t.Run("OtherUser", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		BatchPhotosPrivate(router)
+
+		before := batchPhoto(t, "pt9jtdre2lvl0y24")
+		sessId := LimitedSession(t)
+
+		r := AuthenticatedRequestWithBody(app, "POST", "/api/v1/batch/photos/private", `{"photos": ["pt9jtdre2lvl0y24"]}`, sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+
+		// Photos of other users must remain unchanged.
+		assert.Equal(t, before.PhotoPrivate, batchPhoto(t, "pt9jtdre2lvl0y24").PhotoPrivate)
+	})
 }
 
 func TestBatchLabelsDelete(t *testing.T) {
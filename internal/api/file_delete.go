@@ -13,9 +13,10 @@ import (
 	"github.com/photoprism/photoprism/internal/i18n"
 	"github.com/photoprism/photoprism/internal/photoprism"
 	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/fs"
 )
 
-// DeleteFile removes a file from storage.
+// DeleteFile moves a file to the trash, or removes it from storage if the trash is disabled.
 // DELETE /api/v1/photos/:uid/files/:file_uid
 //
 // Parameters:
@@ -30,6 +31,10 @@ func DeleteFile(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortPhotoAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		conf := get.Config()
 
 		if conf.ReadOnly() || !conf.Settings().Features.Edit {
@@ -60,28 +65,19 @@ func DeleteFile(router *gin.RouterGroup) {
 		fileName := photoprism.FileName(file.FileRoot, file.FileName)
 		baseName := filepath.Base(fileName)
 
-		mediaFile, err := photoprism.NewMediaFile(fileName)
-
-		if err != nil {
-			log.Errorf("files: %s (delete %s)", err, clean.Log(baseName))
+		if !fs.FileExists(fileName) {
+			log.Errorf("files: %s not found (delete)", clean.Log(baseName))
 			AbortEntityNotFound(c)
 			return
 		}
 
-		// Remove file from storage.
-		if err = mediaFile.Remove(); err != nil {
-			log.Errorf("files: %s (delete %s from folder)", err, clean.Log(baseName))
-		} else {
-			log.Infof("files: deleted %s", clean.Log(baseName))
-		}
-
-		// Remove file from index.
-		if err = file.Delete(true); err != nil {
-			log.Errorf("files: %s (delete %s from index)", err, clean.Log(baseName))
+		// Move file to trash, or remove it permanently if the trash is disabled.
+		if err = photoprism.TrashPhotoFile(*file, s.UserUID); err != nil {
+			log.Errorf("files: %s (delete %s)", err, clean.Log(baseName))
 			AbortDeleteFailed(c)
 			return
 		} else {
-			log.Debugf("files: removed %s from index", clean.Log(baseName))
+			log.Infof("files: deleted %s", clean.Log(baseName))
 		}
 
 		// Notify clients by publishing events.
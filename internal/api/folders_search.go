@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,6 +15,7 @@ import (
 	"github.com/photoprism/photoprism/internal/form"
 	"github.com/photoprism/photoprism/internal/get"
 	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/search"
 	"github.com/photoprism/photoprism/pkg/clean"
 )
 
@@ -69,6 +71,14 @@ func SearchFolders(router *gin.RouterGroup, urlPath, rootName, rootPath string)
 		resp := FoldersResponse{Root: rootName, Recursive: recursive, Cached: !uncached}
 		path := clean.Path(c.Param("path"))
 
+		// Users without library access may only browse their base path.
+		if search.LimitedAccess(acl.ResourceFolders, s) {
+			if base := s.User().BasePath; rootName != entity.RootOriginals || base == "" || path != base && !strings.HasPrefix(path, base+"/") {
+				AbortForbidden(c)
+				return
+			}
+		}
+
 		cacheKey := fmt.Sprintf("folder:%s:%t:%t", filepath.Join(rootName, path), recursive, listFiles)
 
 		if !uncached {
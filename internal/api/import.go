@@ -1,14 +1,12 @@
 package api
 
 import (
+	"encoding/json"
 	"net/http"
-	"os"
 	"path"
-	"path/filepath"
 	"strings"
 	"time"
 
-	"github.com/dustin/go-humanize/english"
 	"github.com/gin-gonic/gin"
 
 	"github.com/photoprism/photoprism/internal/acl"
@@ -17,10 +15,9 @@ import (
 	"github.com/photoprism/photoprism/internal/form"
 	"github.com/photoprism/photoprism/internal/get"
 	"github.com/photoprism/photoprism/internal/i18n"
-	"github.com/photoprism/photoprism/internal/photoprism"
-	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/internal/workers"
 	"github.com/photoprism/photoprism/pkg/clean"
-	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/txt"
 )
 
 const (
@@ -55,7 +52,6 @@ func StartImport(router *gin.RouterGroup) {
 		}
 
 		srcFolder := ""
-		importPath := conf.ImportPath()
 
 		// Import from sub-folder?
 		if srcFolder = clean.Path(c.Param("path")); srcFolder != "" && srcFolder != "/" {
@@ -74,62 +70,41 @@ func StartImport(router *gin.RouterGroup) {
 			return
 		}
 
-		importPath = path.Join(importPath, srcFolder)
-
-		imp := get.Import()
-
-		RemoveFromFolderCache(entity.RootImport)
-
-		var destFolder string
-		if destFolder = s.User().UploadPath; destFolder == "" {
-			destFolder = conf.ImportDest()
-		}
-
-		var opt photoprism.ImportOptions
-
-		// Copy or move files to the destination folder?
-		if f.Move {
-			event.InfoMsg(i18n.MsgMovingFilesFrom, clean.Log(filepath.Base(importPath)))
-			opt = photoprism.ImportOptionsMove(importPath, destFolder)
-		} else {
-			event.InfoMsg(i18n.MsgCopyingFilesFrom, clean.Log(filepath.Base(importPath)))
-			opt = photoprism.ImportOptionsCopy(importPath, destFolder)
-		}
+		f.Path = srcFolder
 
 		// Add imported files to albums if allowed.
 		if len(f.Albums) > 0 &&
 			acl.Resources.AllowAny(acl.ResourceAlbums, s.User().AclRole(), acl.Permissions{acl.ActionCreate, acl.ActionUpload}) {
 			log.Debugf("import: adding files to album %s", clean.Log(strings.Join(f.Albums, " and ")))
-			opt.Albums = f.Albums
+		} else {
+			f.Albums = nil
 		}
 
-		// Set user UID if known.
-		if s.UserUID != "" {
-			opt.UserUID = s.UserUID
+		// Add import job to the queue.
+		opt, err := json.Marshal(f)
+
+		if err != nil {
+			AbortBadRequest(c)
+			return
 		}
 
-		// Start import.
-		imported := imp.Start(opt)
+		job, err := workers.EnqueueJob(entity.JobTypeImport, opt, s.UserUID)
 
-		// Delete empty import directory.
-		if srcFolder != "" && importPath != conf.ImportPath() && fs.DirIsEmpty(importPath) {
-			if err := os.Remove(importPath); err != nil {
-				log.Errorf("import: failed deleting empty folder %s: %s", clean.Log(importPath), err)
-			} else {
-				log.Infof("import: deleted empty folder %s", clean.Log(importPath))
-			}
+		if err != nil {
+			Error(c, http.StatusBadRequest, err, i18n.ErrInvalidJob)
+			return
 		}
 
-		// Update moments if files have been imported.
-		if n := len(imported); n == 0 {
-			log.Infof("import: no new files found to import", clean.Log(importPath))
-		} else {
-			log.Infof("import: imported %s", english.Plural(n, "file", "files"))
-			if moments := get.Moments(); moments == nil {
-				log.Warnf("import: moments service not set - possible bug")
-			} else if err := moments.Start(); err != nil {
-				log.Warnf("moments: %s", err)
-			}
+		// Wait for the job to finish, unless the client stops waiting.
+		if job, err = workers.WaitJob(c.Request.Context(), job.JobUID); err != nil {
+			log.Debugf("import: %s (wait for job)", err)
+			return
+		} else if job.JobState == entity.JobFailed {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UpperFirst(job.JobError)})
+			return
+		} else if job.JobState == entity.JobCanceled {
+			c.JSON(http.StatusOK, i18n.NewResponse(http.StatusOK, i18n.MsgImportCanceled))
+			return
 		}
 
 		elapsed := int(time.Since(start).Seconds())
@@ -138,8 +113,6 @@ func StartImport(router *gin.RouterGroup) {
 		msg := i18n.Msg(i18n.MsgImportCompletedIn, elapsed)
 
 		event.Success(msg)
-		event.Publish("import.completed", event.Data{"path": importPath, "seconds": elapsed})
-		event.Publish("index.completed", event.Data{"path": importPath, "seconds": elapsed})
 
 		for _, uid := range f.Albums {
 			PublishAlbumEvent(EntityUpdated, uid, c)
@@ -148,11 +121,6 @@ func StartImport(router *gin.RouterGroup) {
 		// Update the user interface.
 		UpdateClientConfig()
 
-		// Update album, label, and subject cover thumbs.
-		if err := query.UpdateCovers(); err != nil {
-			log.Warnf("index: %s (update covers)", err)
-		}
-
 		c.JSON(http.StatusOK, i18n.Response{Code: http.StatusOK, Msg: msg})
 	})
 }
@@ -175,9 +143,9 @@ func CancelImport(router *gin.RouterGroup) {
 			return
 		}
 
-		imp := get.Import()
-
-		imp.Cancel()
+		if _, err := workers.CancelJobs(entity.JobTypeImport); err != nil {
+			log.Errorf("import: %s (cancel)", err)
+		}
 
 		c.JSON(http.StatusOK, i18n.NewResponse(http.StatusOK, i18n.MsgImportCanceled))
 	})
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"net/http"
 	"path/filepath"
 	"time"
@@ -13,7 +14,7 @@ import (
 	"github.com/photoprism/photoprism/internal/form"
 	"github.com/photoprism/photoprism/internal/get"
 	"github.com/photoprism/photoprism/internal/i18n"
-	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/workers"
 	"github.com/photoprism/photoprism/pkg/clean"
 	"github.com/photoprism/photoprism/pkg/txt"
 )
@@ -46,49 +47,39 @@ func StartIndexing(router *gin.RouterGroup) {
 			return
 		}
 
-		// Configure index options.
-		path := conf.OriginalsPath()
-		convert := settings.Index.Convert && conf.SidecarWritable()
-		skipArchived := settings.Index.SkipArchived
+		f.Path = filepath.Clean(f.Path)
 
-		indOpt := photoprism.NewIndexOptions(filepath.Clean(f.Path), f.Rescan, convert, true, false, skipArchived)
-
-		if len(indOpt.Path) > 1 {
-			event.InfoMsg(i18n.MsgIndexingFiles, clean.Log(indOpt.Path))
+		if len(f.Path) > 1 {
+			event.InfoMsg(i18n.MsgIndexingFiles, clean.Log(f.Path))
 		} else {
 			event.InfoMsg(i18n.MsgIndexingOriginals)
 		}
 
-		// Start indexing.
-		ind := get.Index()
-		indexed := ind.Start(indOpt)
-
-		RemoveFromFolderCache(entity.RootOriginals)
+		// Add indexing job to the queue.
+		opt, err := json.Marshal(f)
 
-		// Configure purge options.
-		prgOpt := photoprism.PurgeOptions{
-			Path:   filepath.Clean(f.Path),
-			Ignore: indexed,
+		if err != nil {
+			AbortBadRequest(c)
+			return
 		}
 
-		// Start purging.
-		prg := get.Purge()
+		job, err := workers.EnqueueJob(entity.JobTypeIndex, opt, s.User().UserUID)
 
-		if files, photos, err := prg.Start(prgOpt); err != nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UpperFirst(err.Error())})
+		if err != nil {
+			Error(c, http.StatusBadRequest, err, i18n.ErrInvalidJob)
 			return
-		} else if len(files) > 0 || len(photos) > 0 {
-			event.InfoMsg(i18n.MsgRemovedFilesAndPhotos, len(files), len(photos))
 		}
 
-		event.Publish("index.updating", event.Data{
-			"step": "moments",
-		})
-
-		moments := get.Moments()
-
-		if err := moments.Start(); err != nil {
-			log.Warnf("moments: %s", err)
+		// Wait for the job to finish, unless the client stops waiting.
+		if job, err = workers.WaitJob(c.Request.Context(), job.JobUID); err != nil {
+			log.Debugf("index: %s (wait for job)", err)
+			return
+		} else if job.JobState == entity.JobFailed {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UpperFirst(job.JobError)})
+			return
+		} else if job.JobState == entity.JobCanceled {
+			c.JSON(http.StatusOK, i18n.NewResponse(http.StatusOK, i18n.MsgIndexingCanceled))
+			return
 		}
 
 		elapsed := int(time.Since(start).Seconds())
@@ -96,7 +87,6 @@ func StartIndexing(router *gin.RouterGroup) {
 		msg := i18n.Msg(i18n.MsgIndexingCompletedIn, elapsed)
 
 		event.Success(msg)
-		event.Publish("index.completed", event.Data{"path": path, "seconds": elapsed})
 
 		UpdateClientConfig()
 
@@ -122,9 +112,9 @@ func CancelIndexing(router *gin.RouterGroup) {
 			return
 		}
 
-		ind := get.Index()
-
-		ind.Cancel()
+		if _, err := workers.CancelJobs(entity.JobTypeIndex); err != nil {
+			log.Errorf("index: %s (cancel)", err)
+		}
 
 		c.JSON(http.StatusOK, i18n.NewResponse(http.StatusOK, i18n.MsgIndexingCanceled))
 	})
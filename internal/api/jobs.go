@@ -0,0 +1,137 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/i18n"
+	"github.com/photoprism/photoprism/internal/workers"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// GetJobs returns background jobs as JSON, newest first.
+//
+// GET /api/v1/jobs
+//
+// Query:
+//
+//	state: queued, running, completed, failed, or canceled (optional)
+//	type: index, import, faces, or convert (optional)
+//	count: maximum number of results (default 100)
+//	offset: result offset
+func GetJobs(router *gin.RouterGroup) {
+	router.GET("/jobs", func(c *gin.Context) {
+		s := Auth(c, acl.ResourceJobs, acl.ActionSearch)
+
+		if s.Abort(c) {
+			return
+		}
+
+		limit := txt.Int(c.Query("count"))
+		offset := txt.Int(c.Query("offset"))
+
+		if limit <= 0 || limit > 1000 {
+			limit = 100
+		}
+
+		resp, err := entity.FindJobs(clean.TypeLower(c.Query("state")), clean.TypeLower(c.Query("type")), limit, offset)
+
+		if err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": txt.UpperFirst(err.Error())})
+			return
+		}
+
+		AddCountHeader(c, len(resp))
+		AddLimitHeader(c, limit)
+		AddOffsetHeader(c, offset)
+
+		c.JSON(http.StatusOK, resp)
+	})
+}
+
+// GetJob returns a background job including its progress as JSON.
+//
+// GET /api/v1/jobs/:uid
+func GetJob(router *gin.RouterGroup) {
+	router.GET("/jobs/:uid", func(c *gin.Context) {
+		s := Auth(c, acl.ResourceJobs, acl.ActionView)
+
+		if s.Abort(c) {
+			return
+		}
+
+		m := entity.FindJob(clean.UID(c.Param("uid")))
+
+		if m == nil {
+			AbortEntityNotFound(c)
+			return
+		}
+
+		c.JSON(http.StatusOK, m)
+	})
+}
+
+// CreateJob adds a background job to the queue and returns it as JSON.
+//
+// POST /api/v1/jobs
+func CreateJob(router *gin.RouterGroup) {
+	router.POST("/jobs", func(c *gin.Context) {
+		s := Auth(c, acl.ResourceJobs, acl.ActionCreate)
+
+		if s.Abort(c) {
+			return
+		}
+
+		var f form.Job
+
+		if err := c.BindJSON(&f); err != nil {
+			AbortBadRequest(c)
+			return
+		}
+
+		m, err := workers.EnqueueJob(clean.TypeLower(f.Type), f.Options, s.User().UserUID)
+
+		if err != nil {
+			Error(c, http.StatusBadRequest, err, i18n.ErrInvalidJob)
+			return
+		}
+
+		event.AuditInfo([]string{ClientIP(c), "session %s", "%s job %s", "queued"}, s.RefID, m.JobType, m.JobUID)
+
+		c.JSON(http.StatusOK, m)
+	})
+}
+
+// CancelJob cancels a queued or running background job and returns it as JSON.
+//
+// DELETE /api/v1/jobs/:uid
+func CancelJob(router *gin.RouterGroup) {
+	router.DELETE("/jobs/:uid", func(c *gin.Context) {
+		s := Auth(c, acl.ResourceJobs, acl.ActionUpdate)
+
+		if s.Abort(c) {
+			return
+		}
+
+		m, err := workers.CancelJob(clean.UID(c.Param("uid")))
+
+		switch err {
+		case nil:
+			event.AuditInfo([]string{ClientIP(c), "session %s", "%s job %s", "canceled"}, s.RefID, m.JobType, m.JobUID)
+			c.JSON(http.StatusOK, m)
+		case workers.ErrJobNotFound:
+			AbortEntityNotFound(c)
+		case workers.ErrJobFinished:
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": txt.UpperFirst(err.Error())})
+		default:
+			log.Errorf("jobs: %s", err)
+			AbortSaveFailed(c)
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+)
+
+func TestGetJobs(t *testing.T) {
+	t.Run("All", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetJobs(router)
+		r := PerformRequest(app, "GET", "/api/v1/jobs")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.GreaterOrEqual(t, gjson.Get(r.Body.String(), "#").Int(), int64(3))
+	})
+	t.Run("Failed", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetJobs(router)
+		r := PerformRequest(app, "GET", "/api/v1/jobs?state=failed&type=faces")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, entity.JobFixtures.Get("faces-failed").JobUID, gjson.Get(r.Body.String(), "0.UID").String())
+	})
+	t.Run("Alice", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		GetJobs(router)
+		sessId := AuthenticateUser(app, router, "alice", "Alice123!")
+
+		r := AuthenticatedRequest(app, "GET", "/api/v1/jobs?count=1", sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, int64(1), gjson.Get(r.Body.String(), "#").Int())
+	})
+}
+
+func TestGetJob(t *testing.T) {
+	t.Run("Found", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetJob(router)
+		r := PerformRequest(app, "GET", "/api/v1/jobs/"+entity.JobFixtures.Get("index-completed").JobUID)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, entity.JobCompleted, gjson.Get(r.Body.String(), "State").String())
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetJob(router)
+		r := PerformRequest(app, "GET", "/api/v1/jobs/qrhn1bzi0fw2x7zz")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+}
+
+func TestCreateJob(t *testing.T) {
+	app, router, _ := NewApiTest()
+	CreateJob(router)
+	CancelJob(router)
+
+	t.Run("InvalidType", func(t *testing.T) {
+		r := PerformRequestWithBody(app, "POST", "/api/v1/jobs", `{"Type": "foo"}`)
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("CreateCancel", func(t *testing.T) {
+		r := PerformRequestWithBody(app, "POST", "/api/v1/jobs", `{"Type": "convert", "Options": {"path": "2790", "force": true}}`)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, entity.JobQueued, gjson.Get(r.Body.String(), "State").String())
+
+		uid := gjson.Get(r.Body.String(), "UID").String()
+
+		r = PerformRequest(app, "DELETE", "/api/v1/jobs/"+uid)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, entity.JobCanceled, gjson.Get(r.Body.String(), "State").String())
+
+		r = PerformRequest(app, "DELETE", "/api/v1/jobs/"+uid)
+		assert.Equal(t, http.StatusConflict, r.Code)
+	})
+}
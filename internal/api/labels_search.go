@@ -32,7 +32,7 @@ func SearchLabels(router *gin.RouterGroup) {
 			return
 		}
 
-		result, err := search.Labels(f)
+		result, err := search.UserLabels(f, s)
 
 		if err != nil {
 			c.AbortWithStatusJSON(400, gin.H{"error": txt.UpperFirst(err.Error())})
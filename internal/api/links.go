@@ -13,6 +13,7 @@ import (
 	"github.com/photoprism/photoprism/internal/i18n"
 	"github.com/photoprism/photoprism/internal/query"
 	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/rnd"
 	"github.com/photoprism/photoprism/pkg/txt"
 )
 
@@ -37,7 +38,14 @@ func UpdateLink(c *gin.Context) {
 
 	link := entity.FindLink(clean.Token(c.Param("link")))
 
+	// Link must exist and belong to the shared entity.
+	if link == nil || link.ShareUID != clean.UID(c.Param("uid")) {
+		Abort(c, http.StatusNotFound, i18n.ErrInvalidLink)
+		return
+	}
+
 	link.SetSlug(f.ShareSlug)
+	link.SetPerm(f.CanComment, f.CanEdit)
 	link.MaxViews = f.MaxViews
 	link.LinkExpires = f.LinkExpires
 
@@ -59,9 +67,7 @@ func UpdateLink(c *gin.Context) {
 
 	UpdateClientConfig()
 
-	event.SuccessMsg(i18n.MsgAlbumSaved)
-
-	PublishAlbumEvent(EntityUpdated, link.ShareUID, c)
+	PublishLinkEvent(link.ShareUID, c)
 
 	c.JSON(http.StatusOK, link)
 }
@@ -79,6 +85,12 @@ func DeleteLink(c *gin.Context) {
 
 	link := entity.FindLink(clean.Token(c.Param("link")))
 
+	// Link must exist and belong to the shared entity.
+	if link == nil || link.ShareUID != clean.UID(c.Param("uid")) {
+		Abort(c, http.StatusNotFound, i18n.ErrInvalidLink)
+		return
+	}
+
 	if err := link.Delete(); err != nil {
 		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": txt.UpperFirst(err.Error())})
 		return
@@ -86,9 +98,7 @@ func DeleteLink(c *gin.Context) {
 
 	UpdateClientConfig()
 
-	event.SuccessMsg(i18n.MsgAlbumSaved)
-
-	PublishAlbumEvent(EntityUpdated, link.ShareUID, c)
+	PublishLinkEvent(link.ShareUID, c)
 
 	c.JSON(http.StatusOK, link)
 }
@@ -121,6 +131,7 @@ func CreateLink(c *gin.Context) {
 	link := entity.NewUserLink(uid, s.UserUID)
 
 	link.SetSlug(f.ShareSlug)
+	link.SetPerm(f.CanComment, f.CanEdit)
 	link.MaxViews = f.MaxViews
 	link.LinkExpires = f.LinkExpires
 
@@ -138,13 +149,26 @@ func CreateLink(c *gin.Context) {
 
 	UpdateClientConfig()
 
-	event.SuccessMsg(i18n.MsgAlbumSaved)
-
-	PublishAlbumEvent(EntityUpdated, link.ShareUID, c)
+	PublishLinkEvent(link.ShareUID, c)
 
 	c.JSON(http.StatusOK, link)
 }
 
+// PublishLinkEvent notifies clients that the links of a shared entity have changed.
+func PublishLinkEvent(uid string, c *gin.Context) {
+	switch {
+	case rnd.IsUID(uid, entity.PhotoUID):
+		event.SuccessMsg(i18n.MsgChangesSaved)
+		PublishPhotoEvent(EntityUpdated, uid, c)
+	case rnd.IsUID(uid, entity.LabelUID):
+		event.SuccessMsg(i18n.MsgChangesSaved)
+		PublishLabelEvent(EntityUpdated, uid, c)
+	default:
+		event.SuccessMsg(i18n.MsgAlbumSaved)
+		PublishAlbumEvent(EntityUpdated, uid, c)
+	}
+}
+
 // CreateAlbumLink adds a new album share link and return it as JSON.
 //
 // POST /api/v1/albums/:uid/links
@@ -156,6 +180,10 @@ func CreateAlbumLink(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortAlbumAccess(c, s, clean.UID(c.Param("uid"))) || AbortAlbumPerm(c, s, clean.UID(c.Param("uid")), entity.PermShare) {
+			return
+		}
+
 		if _, err := query.AlbumByUID(clean.UID(c.Param("uid"))); err != nil {
 			AbortAlbumNotFound(c)
 			return
@@ -176,6 +204,10 @@ func UpdateAlbumLink(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortAlbumAccess(c, s, clean.UID(c.Param("uid"))) || AbortAlbumPerm(c, s, clean.UID(c.Param("uid")), entity.PermShare) {
+			return
+		}
+
 		UpdateLink(c)
 	})
 }
@@ -191,6 +223,10 @@ func DeleteAlbumLink(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortAlbumAccess(c, s, clean.UID(c.Param("uid"))) || AbortAlbumPerm(c, s, clean.UID(c.Param("uid")), entity.PermShare) {
+			return
+		}
+
 		DeleteLink(c)
 	})
 }
@@ -206,6 +242,10 @@ func GetAlbumLinks(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortAlbumAccess(c, s, clean.UID(c.Param("uid"))) || AbortAlbumPerm(c, s, clean.UID(c.Param("uid")), entity.PermShare) {
+			return
+		}
+
 		m, err := query.AlbumByUID(clean.UID(c.Param("uid")))
 
 		if err != nil {
@@ -217,8 +257,6 @@ func GetAlbumLinks(router *gin.RouterGroup) {
 	})
 }
 
-/*
-
 // CreatePhotoLink adds a new photo share link and return it as JSON.
 //
 // POST /api/v1/photos/:uid/links
@@ -230,6 +268,10 @@ func CreatePhotoLink(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortPhotoAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		if _, err := query.PhotoByUID(clean.UID(c.Param("uid"))); err != nil {
 			AbortEntityNotFound(c)
 			return
@@ -250,6 +292,10 @@ func UpdatePhotoLink(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortPhotoAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		UpdateLink(c)
 	})
 }
@@ -265,6 +311,10 @@ func DeletePhotoLink(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortPhotoAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		DeleteLink(c)
 	})
 }
@@ -280,10 +330,14 @@ func GetPhotoLinks(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortPhotoAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		m, err := query.PhotoByUID(clean.UID(c.Param("uid")))
 
 		if err != nil {
-			AbortAlbumNotFound(c)
+			AbortEntityNotFound(c)
 			return
 		}
 
@@ -302,6 +356,10 @@ func CreateLabelLink(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortLabelAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		if _, err := query.LabelByUID(clean.UID(c.Param("uid"))); err != nil {
 			Abort(c, http.StatusNotFound, i18n.ErrLabelNotFound)
 			return
@@ -322,6 +380,10 @@ func UpdateLabelLink(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortLabelAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		UpdateLink(c)
 	})
 }
@@ -337,6 +399,10 @@ func DeleteLabelLink(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortLabelAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		DeleteLink(c)
 	})
 }
@@ -352,14 +418,17 @@ func GetLabelLinks(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortLabelAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		m, err := query.LabelByUID(clean.UID(c.Param("uid")))
 
 		if err != nil {
-			AbortAlbumNotFound(c)
+			Abort(c, http.StatusNotFound, i18n.ErrLabelNotFound)
 			return
 		}
 
 		c.JSON(http.StatusOK, m.Links())
 	})
 }
-*/
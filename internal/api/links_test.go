@@ -7,6 +7,7 @@ import (
 
 	"github.com/tidwall/gjson"
 
+	"github.com/photoprism/photoprism/internal/config"
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/stretchr/testify/assert"
 )
@@ -146,7 +147,6 @@ func TestGetAlbumLinks(t *testing.T) {
 	})
 }
 
-/*
 func TestCreatePhotoLink(t *testing.T) {
 	t.Run("create share link", func(t *testing.T) {
 		app, router, _ := NewApiTest()
@@ -156,7 +156,6 @@ func TestCreatePhotoLink(t *testing.T) {
 		CreatePhotoLink(router)
 
 		resp := PerformRequestWithBody(app, "POST", "/api/v1/photos/pt9jtdre2lvl0yh7/links", `{"Password":"foobar","Expires":0,"CanEdit":true}`)
-		log.Debugf("BODY: %s", resp.Body.String())
 		assert.Equal(t, http.StatusOK, resp.Code)
 
 		if err := json.Unmarshal(resp.Body.Bytes(), &link); err != nil {
@@ -167,8 +166,8 @@ func TestCreatePhotoLink(t *testing.T) {
 		assert.NotEmpty(t, link.ShareUID)
 		assert.NotEmpty(t, link.LinkToken)
 		assert.Equal(t, 0, link.LinkExpires)
-		assert.False(t, link.CanComment)
-		assert.True(t, link.CanEdit)
+		assert.False(t, link.CanComment())
+		assert.True(t, link.CanEdit())
 	})
 	t.Run("photo not found", func(t *testing.T) {
 		app, router, _ := NewApiTest()
@@ -214,6 +213,13 @@ func TestUpdatePhotoLink(t *testing.T) {
 		assert.Equal(t, http.StatusOK, r.Code)
 	})
 
+	t.Run("link not found", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		UpdatePhotoLink(router)
+		r := PerformRequestWithBody(app, "PUT", "/api/v1/photos/pt9jtdre2lvl0yh8/links/"+uid, `{"Token": "newToken", "Expires": 8000}`)
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+
 	t.Run("bad request", func(t *testing.T) {
 		app, router, _ := NewApiTest()
 		UpdatePhotoLink(router)
@@ -222,7 +228,6 @@ func TestUpdatePhotoLink(t *testing.T) {
 	})
 }
 
-// TODO Fully assert once functionality exists
 func TestDeletePhotoLink(t *testing.T) {
 	app, router, _ := NewApiTest()
 
@@ -235,9 +240,9 @@ func TestDeletePhotoLink(t *testing.T) {
 	}
 	uid := gjson.Get(r.Body.String(), "UID").String()
 
-	//GetPhotoLinks(router)
-	//r2 := PerformRequest(app, "GET", "/api/v1/photos/pt9jtdre2lvl0yh7/links")
-	//len := gjson.Get(r2.Body.String(), "#")
+	GetPhotoLinks(router)
+	r2 := PerformRequest(app, "GET", "/api/v1/photos/pt9jtdre2lvl0yh7/links")
+	len := gjson.Get(r2.Body.String(), "#")
 
 	t.Run("successful deletion", func(t *testing.T) {
 		app, router, _ := NewApiTest()
@@ -246,9 +251,8 @@ func TestDeletePhotoLink(t *testing.T) {
 		assert.Equal(t, http.StatusOK, r.Code)
 		GetPhotoLinks(router)
 		r2 := PerformRequest(app, "GET", "/api/v1/photos/pt9jtdre2lvl0yh7/links")
-		t.Log(r2)
-		//len2 := gjson.Get(r2.Body.String(), "#")
-		//assert.Greater(t, len.Int(), len2.Int())
+		len2 := gjson.Get(r2.Body.String(), "#")
+		assert.Greater(t, len.Int(), len2.Int())
 	})
 }
 
@@ -264,8 +268,8 @@ func TestGetPhotoLinks(t *testing.T) {
 		}
 		GetPhotoLinks(router)
 		r2 := PerformRequest(app, "GET", "/api/v1/photos/pt9jtdre2lvl0yh7/links")
-		//len := gjson.Get(r2.Body.String(), "#")
-		//assert.GreaterOrEqual(t, len.Int(), int64(1))
+		len := gjson.Get(r2.Body.String(), "#")
+		assert.GreaterOrEqual(t, len.Int(), int64(1))
 		assert.Equal(t, http.StatusOK, r2.Code)
 	})
 
@@ -296,8 +300,8 @@ func TestCreateLabelLink(t *testing.T) {
 		assert.NotEmpty(t, link.ShareUID)
 		assert.NotEmpty(t, link.LinkToken)
 		assert.Equal(t, 0, link.LinkExpires)
-		assert.False(t, link.CanComment)
-		assert.True(t, link.CanEdit)
+		assert.False(t, link.CanComment())
+		assert.True(t, link.CanEdit())
 	})
 	t.Run("label not found", func(t *testing.T) {
 		app, router, _ := NewApiTest()
@@ -314,6 +318,22 @@ func TestCreateLabelLink(t *testing.T) {
 		r := PerformRequestWithBody(app, "POST", "/api/v1/labels/lt9k3pw1wowuy3c2/links", `{"xxx": 123, "Expires": "abc", "CanEdit": "xxx"}`)
 		assert.Equal(t, http.StatusBadRequest, r.Code)
 	})
+	t.Run("OtherUser", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		CreateLabelLink(router)
+		GetLabelLinks(router)
+
+		sessId := LimitedSession(t)
+
+		// Labels that are not assigned to accessible photos cannot be shared.
+		r := AuthenticatedRequestWithBody(app, "POST", "/api/v1/labels/lt9k3pw1wowuy3c2/links", `{"Expires": 0}`, sessId)
+		assert.Equal(t, http.StatusNotFound, r.Code)
+
+		r = AuthenticatedRequest(app, "GET", "/api/v1/labels/lt9k3pw1wowuy3c2/links", sessId)
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
 }
 
 func TestUpdateLabelLink(t *testing.T) {
@@ -361,19 +381,19 @@ func TestDeleteLabelLink(t *testing.T) {
 	}
 	uid := gjson.Get(r.Body.String(), "UID").String()
 
-	//GetLabelLinks(router)
-	//r2 := PerformRequest(app, "GET", "/api/v1/labels/lt9k3pw1wowuy3c2/links")
-	//len := gjson.Get(r2.Body.String(), "#")
+	GetLabelLinks(router)
+	r2 := PerformRequest(app, "GET", "/api/v1/labels/lt9k3pw1wowuy3c2/links")
+	len := gjson.Get(r2.Body.String(), "#")
 
 	t.Run("successful deletion", func(t *testing.T) {
 		app, router, _ := NewApiTest()
 		DeleteLabelLink(router)
 		r := PerformRequest(app, "DELETE", "/api/v1/labels/lt9k3pw1wowuy3c2/links/"+uid)
 		assert.Equal(t, http.StatusOK, r.Code)
-		//GetLabelLinks(router)
-		//r2 := PerformRequest(app, "GET", "/api/v1/labels/lt9k3pw1wowuy3c2/links")
-		//len2 := gjson.Get(r2.Body.String(), "#")
-		//assert.Greater(t, len.Int(), len2.Int())
+		GetLabelLinks(router)
+		r2 := PerformRequest(app, "GET", "/api/v1/labels/lt9k3pw1wowuy3c2/links")
+		len2 := gjson.Get(r2.Body.String(), "#")
+		assert.Greater(t, len.Int(), len2.Int())
 	})
 }
 
@@ -390,8 +410,8 @@ func TestGetLabelLinks(t *testing.T) {
 		}
 		GetLabelLinks(router)
 		r2 := PerformRequest(app, "GET", "/api/v1/labels/lt9k3pw1wowuy3c2/links")
-		//len := gjson.Get(r2.Body.String(), "#")
-		//assert.GreaterOrEqual(t, len.Int(), int64(1))
+		len := gjson.Get(r2.Body.String(), "#")
+		assert.GreaterOrEqual(t, len.Int(), int64(1))
 		assert.Equal(t, http.StatusOK, r2.Code)
 	})
 
@@ -402,4 +422,3 @@ func TestGetLabelLinks(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, r.Code)
 	})
 }
-*/
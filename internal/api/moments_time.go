@@ -24,7 +24,7 @@ func GetMomentsTime(router *gin.RouterGroup) {
 
 		conf := get.Config()
 
-		result, err := query.MomentsTime(1, conf.Settings().Features.Private)
+		result, err := query.UserMomentsTime(1, conf.Settings().Features.Private, s)
 
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": txt.UpperFirst(err.Error())})
@@ -29,6 +29,10 @@ func AddPhotoLabel(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortPhotoAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		m, err := query.PhotoByUID(clean.UID(c.Param("uid")))
 
 		if err != nil {
@@ -104,6 +108,10 @@ func RemovePhotoLabel(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortPhotoAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		m, err := query.PhotoByUID(clean.UID(c.Param("uid")))
 
 		if err != nil {
@@ -168,6 +176,10 @@ func UpdatePhotoLabel(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortPhotoAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		// TODO: Code clean-up, simplify
 
 		m, err := query.PhotoByUID(clean.UID(c.Param("uid")))
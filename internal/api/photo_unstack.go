@@ -33,6 +33,10 @@ func PhotoUnstack(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortPhotoAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		conf := get.Config()
 		fileUid := clean.UID(c.Param("file_uid"))
 		file, err := query.FileByUID(fileUid)
@@ -146,7 +150,7 @@ func PhotoUnstack(router *gin.RouterGroup) {
 			}
 
 			if err := entity.UnscopedDb().Exec(`UPDATE files 
-				SET photo_id = ?, photo_uid = ?, file_name = ?, file_missing = 0
+				SET photo_id = ?, photo_uid = ?, file_name = ?, file_missing = FALSE
 				WHERE file_name = ? AND file_root = ?`,
 				newPhoto.ID, newPhoto.PhotoUID, r.RootRelName(),
 				relName, relRoot).Error; err != nil {
@@ -49,6 +49,10 @@ func GetPhoto(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortPhotoAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		p, err := query.PhotoPreloadByUID(clean.UID(c.Param("uid")))
 
 		if err != nil {
@@ -71,6 +75,10 @@ func UpdatePhoto(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortPhotoAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		uid := clean.UID(c.Param("uid"))
 		m, err := query.PhotoByUID(uid)
 
@@ -206,6 +214,10 @@ func ApprovePhoto(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortPhotoAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		id := clean.UID(c.Param("uid"))
 		m, err := query.PhotoByUID(id)
 
@@ -243,6 +255,10 @@ func PhotoPrimary(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortPhotoAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		uid := clean.UID(c.Param("uid"))
 		fileUid := clean.UID(c.Param("file_uid"))
 		err := query.SetPhotoPrimary(uid, fileUid)
@@ -23,6 +23,10 @@ func LikePhoto(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortPhotoAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		id := clean.UID(c.Param("uid"))
 		m, err := query.PhotoByUID(id)
 
@@ -63,6 +67,10 @@ func DislikePhoto(router *gin.RouterGroup) {
 			return
 		}
 
+		if AbortPhotoAccess(c, s, clean.UID(c.Param("uid"))) {
+			return
+		}
+
 		id := clean.UID(c.Param("uid"))
 		m, err := query.PhotoByUID(id)
 
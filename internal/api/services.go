@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -17,6 +18,7 @@ import (
 	"github.com/photoprism/photoprism/internal/workers"
 	"github.com/photoprism/photoprism/pkg/clean"
 	"github.com/photoprism/photoprism/pkg/fs"
+	"github.com/photoprism/photoprism/pkg/txt"
 )
 
 // Namespaces for caching and logs.
@@ -106,6 +108,59 @@ func GetServiceFolders(router *gin.RouterGroup) {
 	})
 }
 
+// GetServiceConflicts returns files of an account for which conflicting changes have been resolved as JSON.
+//
+// GET /api/v1/services/:id/conflicts
+//
+// Query:
+//
+//	count: maximum number of results (default 100)
+//	offset: result offset
+func GetServiceConflicts(router *gin.RouterGroup) {
+	router.GET("/services/:id/conflicts", func(c *gin.Context) {
+		s := Auth(c, acl.ResourceServices, acl.ActionView)
+
+		if s.Abort(c) {
+			return
+		}
+
+		conf := get.Config()
+
+		if conf.Demo() || conf.DisableSettings() {
+			AbortForbidden(c)
+			return
+		}
+
+		id := clean.IdUint(c.Param("id"))
+
+		if _, err := query.AccountByID(id); err != nil {
+			Abort(c, http.StatusNotFound, i18n.ErrAccountNotFound)
+			return
+		}
+
+		limit := txt.Int(c.Query("count"))
+		offset := txt.Int(c.Query("offset"))
+
+		if limit <= 0 || limit > 1000 {
+			limit = 100
+		}
+
+		resp, err := query.FileSyncConflicts(id, limit, offset)
+
+		if err != nil {
+			log.Errorf("services: %s", err)
+			AbortUnexpected(c)
+			return
+		}
+
+		AddCountHeader(c, len(resp))
+		AddLimitHeader(c, limit)
+		AddOffsetHeader(c, offset)
+
+		c.JSON(http.StatusOK, resp)
+	})
+}
+
 // AddService creates a new remote account configuration.
 //
 // POST /api/v1/services
@@ -131,7 +186,15 @@ func AddService(router *gin.RouterGroup) {
 			return
 		}
 
-		if err := f.Discovery(); err != nil {
+		var hostKeyErr *form.HostKeyError
+
+		if err := f.Discovery(); errors.As(err, &hostKeyErr) {
+			// The host key fingerprint is returned as details, so it can be confirmed by the user.
+			resp := i18n.NewResponse(http.StatusConflict, i18n.ErrConfirmHostKey, hostKeyErr.Fingerprint)
+			resp.Details = hostKeyErr.Fingerprint
+			c.AbortWithStatusJSON(http.StatusConflict, resp)
+			return
+		} else if err != nil {
 			log.Error(err)
 			Abort(c, http.StatusBadRequest, i18n.ErrConnectionFailed)
 			return
@@ -50,6 +50,26 @@ func TestGetServiceFolders(t *testing.T) {
 	})
 }
 
+func TestGetServiceConflicts(t *testing.T) {
+	t.Run("Ok", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetServiceConflicts(router)
+		r := PerformRequest(app, "GET", "/api/v1/services/1000001/conflicts?count=10")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "10", r.Header().Get("X-Limit"))
+		val := gjson.Get(r.Body.String(), "0.Conflict")
+		assert.Equal(t, "keep", val.String())
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetServiceConflicts(router)
+		r := PerformRequest(app, "GET", "/api/v1/services/999000/conflicts")
+		val := gjson.Get(r.Body.String(), "error")
+		assert.Equal(t, i18n.Msg(i18n.ErrAccountNotFound), val.String())
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+}
+
 func TestCreateService(t *testing.T) {
 	t.Run("BadRequest", func(t *testing.T) {
 		app, router, _ := NewApiTest()
@@ -48,7 +48,7 @@ func UploadToService(router *gin.RouterGroup) {
 
 		// Find files to share.
 		selection := query.ShareSelection(m.ShareOriginals())
-		files, err := query.SelectedFiles(f.Selection, selection)
+		files, err := query.UserSelectedFiles(f.Selection, selection, s)
 
 		if err != nil {
 			AbortEntityNotFound(c)
@@ -32,12 +32,16 @@ func SharePreview(router *gin.RouterGroup) {
 
 		token := clean.Token(c.Param("token"))
 		shared := clean.Token(c.Param("shared"))
-		links := entity.FindLinks(token, shared)
+		links, _, err := entity.VerifyLinks(token, shared)
 
-		if len(links) != 1 {
+		if err != nil || len(links) != 1 {
 			log.Warn("share: invalid token (preview)")
 			c.Redirect(http.StatusTemporaryRedirect, conf.SitePreview())
 			return
+		} else if links.HasPassword() {
+			log.Debug("share: link is password protected (preview)")
+			c.Redirect(http.StatusTemporaryRedirect, conf.SitePreview())
+			return
 		}
 
 		thumbPath := path.Join(conf.ThumbCachePath(), "share")
@@ -65,8 +69,8 @@ func SharePreview(router *gin.RouterGroup) {
 
 		var f form.SearchPhotos
 
-		// Covers may only contain public content in shared albums.
-		f.Album = shared
+		// Covers may only contain public content of the shared album, label, or photo.
+		f.Scope = links[0].ShareUID
 		f.Public = true
 		f.Private = false
 		f.Hidden = false
@@ -74,7 +78,7 @@ func SharePreview(router *gin.RouterGroup) {
 		f.Review = false
 		f.Primary = true
 
-		// Get first 12 album entries.
+		// Get first 12 entries.
 		f.Count = 12
 		f.Order = "relevance"
 
@@ -4,11 +4,24 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/workers"
 )
 
+// GetStatus returns the server status and, for users who may manage the config, the status of scheduled jobs.
+//
 // GET /api/v1/status
 func GetStatus(router *gin.RouterGroup) {
 	router.GET("/status", func(c *gin.Context) {
+		// Scheduled jobs are not included in the response for anonymous health checks.
+		if s := Session(SessionID(c)); s == nil || s.User() == nil {
+			// Do nothing.
+		} else if acl.Resources.AllowAll(acl.ResourceConfig, s.User().AclRole(), acl.Permissions{acl.ActionManage}) {
+			c.JSON(http.StatusOK, gin.H{"status": "operational", "jobs": workers.ScheduledJobs()})
+			return
+		}
+
 		c.JSON(http.StatusOK, gin.H{"status": "operational"})
 	})
 }
@@ -16,5 +16,7 @@ func TestGetStatus(t *testing.T) {
 		val := gjson.Get(r.Body.String(), "status")
 		assert.Equal(t, "operational", val.String())
 		assert.Equal(t, http.StatusOK, r.Code)
+		// Public mode sessions may see the status of scheduled jobs.
+		assert.True(t, gjson.Get(r.Body.String(), "jobs").IsArray())
 	})
 }
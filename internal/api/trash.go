@@ -0,0 +1,171 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/dustin/go-humanize/english"
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/i18n"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/search"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/rnd"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// GetTrash returns the files in the trash as JSON, most recently deleted first.
+//
+// GET /api/v1/trash
+//
+// Query:
+//
+//	count: maximum number of results (default 100)
+//	offset: result offset
+func GetTrash(router *gin.RouterGroup) {
+	router.GET("/trash", func(c *gin.Context) {
+		s := Auth(c, acl.ResourcePhotos, acl.ActionDelete)
+
+		if s.Abort(c) {
+			return
+		}
+
+		limit := txt.Int(c.Query("count"))
+		offset := txt.Int(c.Query("offset"))
+
+		if limit <= 0 || limit > 1000 {
+			limit = 100
+		}
+
+		// Only list files of photos the session may access.
+		resp, err := search.UserTrashFiles(s, limit, offset)
+
+		if err != nil {
+			log.Errorf("trash: %s", err)
+			AbortUnexpected(c)
+			return
+		}
+
+		AddCountHeader(c, len(resp))
+		AddLimitHeader(c, limit)
+		AddOffsetHeader(c, offset)
+
+		c.JSON(http.StatusOK, resp)
+	})
+}
+
+// RestoreTrash restores the files of a photo from the trash, as well as the photo itself if it was deleted.
+//
+// POST /api/v1/trash/:uid/restore
+//
+// Parameters:
+//
+//	uid: string Photo UID as returned by the API
+func RestoreTrash(router *gin.RouterGroup) {
+	router.POST("/trash/:uid/restore", func(c *gin.Context) {
+		s := Auth(c, acl.ResourcePhotos, acl.ActionDelete)
+
+		if s.Abort(c) {
+			return
+		}
+
+		conf := get.Config()
+
+		if conf.ReadOnly() {
+			Abort(c, http.StatusForbidden, i18n.ErrReadOnly)
+			return
+		}
+
+		photoUid := clean.UID(c.Param("uid"))
+
+		if rnd.InvalidUID(photoUid, entity.PhotoUID) {
+			AbortBadRequest(c)
+			return
+		} else if AbortPhotoAccess(c, s, photoUid) {
+			return
+		}
+
+		numFiles, err := photoprism.RestoreTrash(photoUid)
+
+		if err == photoprism.ErrNotInTrash {
+			Abort(c, http.StatusNotFound, i18n.ErrNotInTrash)
+			return
+		} else if err != nil {
+			log.Errorf("trash: %s", err)
+			AbortSaveFailed(c)
+			return
+		}
+
+		event.AuditInfo([]string{ClientIP(c), "session %s", "photo %s", "restored %s from trash"}, s.RefID, clean.Log(photoUid), english.Plural(numFiles, "file", "files"))
+
+		// Update precalculated photo and file counts.
+		logWarn("index", entity.UpdateCounts())
+
+		UpdateClientConfig()
+
+		// Notify clients by publishing events.
+		event.EntitiesRestored("photos", []string{photoUid})
+		PublishPhotoEvent(EntityUpdated, photoUid, c)
+
+		c.JSON(http.StatusOK, i18n.NewResponse(http.StatusOK, i18n.MsgRestored, clean.Log(photoUid)))
+	})
+}
+
+// EmptyTrash permanently removes all files from the trash, or only those whose retention period has expired.
+//
+// DELETE /api/v1/trash
+//
+// Query:
+//
+//	expired: only remove files whose retention period has expired (optional)
+func EmptyTrash(router *gin.RouterGroup) {
+	router.DELETE("/trash", func(c *gin.Context) {
+		s := Auth(c, acl.ResourcePhotos, acl.ActionDelete)
+
+		if s.Abort(c) {
+			return
+		}
+
+		conf := get.Config()
+
+		if conf.ReadOnly() || !conf.Settings().Features.Delete {
+			AbortFeatureDisabled(c)
+			return
+		}
+
+		expired := txt.Bool(c.Query("expired"))
+
+		var numPhotos, numFiles int
+		var err error
+
+		// Only users with access to all photos may empty the whole trash,
+		// others can only remove the files of their own photos.
+		if acl.Resources.Allow(acl.ResourcePhotos, s.User().AclRole(), acl.AccessAll) {
+			numPhotos, numFiles, err = photoprism.EmptyTrash(expired)
+		} else if trashed, findErr := search.OwnTrashFiles(s.User()); findErr != nil {
+			err = findErr
+		} else {
+			if expired {
+				trashed = trashed.Expired(entity.TimeStamp())
+			}
+
+			numPhotos, numFiles, err = photoprism.RemoveTrash(trashed)
+		}
+
+		if err != nil {
+			log.Errorf("trash: %s", err)
+			AbortDeleteFailed(c)
+			return
+		}
+
+		event.AuditInfo([]string{ClientIP(c), "session %s", "trash emptied", "removed %s and %s"}, s.RefID, english.Plural(numPhotos, "photo", "photos"), english.Plural(numFiles, "file", "files"))
+
+		UpdateClientConfig()
+
+		c.JSON(http.StatusOK, i18n.NewResponse(http.StatusOK, i18n.MsgTrashEmptied))
+	})
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+)
+
+// otherUserTrashFile adds a file of a photo that was not added by a test user to the trash.
+func otherUserTrashFile(t *testing.T) *entity.TrashFile {
+	m := &entity.TrashFile{
+		PhotoUID:  "pt9jtdre2lvl0y24",
+		FileRoot:  entity.RootOriginals,
+		FileName:  "2022/10/other.jpg",
+		CreatedAt: entity.TimeStamp(),
+		ExpiresAt: entity.TimeStamp().AddDate(0, 0, -1),
+	}
+
+	if err := m.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = m.Delete() })
+
+	return m
+}
+
+func TestGetTrash(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetTrash(router)
+		r := PerformRequest(app, "GET", "/api/v1/trash?count=10")
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, "10", r.Header().Get("X-Limit"))
+	})
+	t.Run("OtherUser", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		GetTrash(router)
+
+		m := otherUserTrashFile(t)
+		sessId := LimitedSession(t)
+
+		r := AuthenticatedRequest(app, "GET", "/api/v1/trash?count=1000", sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.NotContains(t, r.Body.String(), m.PhotoUID)
+	})
+}
+
+func TestRestoreTrash(t *testing.T) {
+	t.Run("NotFound", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		RestoreTrash(router)
+		r := PerformRequest(app, "POST", "/api/v1/trash/pt9jtdre2lvl0y11/restore")
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+	t.Run("InvalidUID", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		RestoreTrash(router)
+		r := PerformRequest(app, "POST", "/api/v1/trash/xxx/restore")
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("OtherUser", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		RestoreTrash(router)
+
+		m := otherUserTrashFile(t)
+		sessId := LimitedSession(t)
+
+		r := AuthenticatedRequest(app, "POST", "/api/v1/trash/"+m.PhotoUID+"/restore", sessId)
+		assert.Equal(t, http.StatusNotFound, r.Code)
+
+		found, err := entity.FindTrashFiles(m.PhotoUID)
+		assert.NoError(t, err)
+		assert.Len(t, found, 1)
+	})
+}
+
+func TestEmptyTrash(t *testing.T) {
+	t.Run("OtherUser", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		EmptyTrash(router)
+
+		settings := conf.Settings()
+		deleteEnabled := settings.Features.Delete
+		settings.Features.Delete = true
+		defer func() { settings.Features.Delete = deleteEnabled }()
+
+		m := otherUserTrashFile(t)
+		sessId := LimitedSession(t)
+
+		for _, uri := range []string{"/api/v1/trash", "/api/v1/trash?expired=true"} {
+			r := AuthenticatedRequest(app, "DELETE", uri, sessId)
+			assert.Equal(t, http.StatusOK, r.Code)
+
+			// Files of photos added by other users remain in the trash.
+			found, err := entity.FindTrashFiles(m.PhotoUID)
+			assert.NoError(t, err)
+			assert.Len(t, found, 1)
+		}
+	})
+}
@@ -0,0 +1,243 @@
+package api
+
+import (
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/i18n"
+	"github.com/photoprism/photoprism/internal/server/limiter"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// PasscodeLoginMaxAge is the maximum time since logging in with a provider that does not support
+// password confirmation, after which two-factor authentication settings can no longer be changed.
+var PasscodeLoginMaxAge = 15 * time.Minute
+
+// passcodeSession returns the session if the request is allowed to manage the two-factor
+// authentication settings of its user, or nil after aborting the request.
+func passcodeSession(c *gin.Context, perm acl.Permission) *entity.Session {
+	// Two-factor authentication cannot be set up in public mode.
+	if get.Config().Public() {
+		Abort(c, http.StatusForbidden, i18n.ErrPublic)
+		return nil
+	}
+
+	// Check limit for failed auth requests (max. 10 per minute).
+	if limiter.Auth.Reject(ClientIP(c)) {
+		limiter.AbortJSON(c)
+		return nil
+	}
+
+	s := Auth(c, acl.ResourcePassword, perm)
+
+	if s.Abort(c) {
+		return nil
+	}
+
+	// Users may only change their own settings, and not by using an access token.
+	if s.User().UserUID != clean.UID(c.Param("uid")) || s.AuthMethod == entity.AuthMethodToken {
+		AbortForbidden(c)
+		return nil
+	}
+
+	return s
+}
+
+// passcodeConfirmed checks if the user has confirmed a change of its two-factor authentication settings
+// with a current verification code, its password, or by having recently logged in with an external
+// identity provider. It returns false after aborting the request otherwise.
+func passcodeConfirmed(c *gin.Context, s *entity.Session, f form.UserPasscode) bool {
+	u := s.User()
+
+	switch {
+	case f.Code != "":
+		// Verification codes can only be used if two-factor authentication is already enabled.
+		if m := u.Passcode(); m != nil && m.Verified() {
+			if _, err := m.Verify(f.Code); err == nil {
+				return true
+			}
+		}
+
+		limiter.Auth.Reserve(ClientIP(c))
+		Abort(c, http.StatusBadRequest, i18n.ErrInvalidPasscode)
+		return false
+	case f.Password != "":
+		// Passwords of LDAP accounts are checked with the directory server.
+		if u.VerifyPassword(f.Password) {
+			return true
+		}
+
+		limiter.Auth.Reserve(ClientIP(c))
+		Abort(c, http.StatusBadRequest, i18n.ErrInvalidPassword)
+		return false
+	case u.AuthProvider == entity.AuthProviderOIDC:
+		// OpenID Connect accounts have no password, so they must have logged in recently instead.
+		if s.RecentLogin(entity.AuthProviderOIDC, PasscodeLoginMaxAge) {
+			return true
+		}
+
+		Abort(c, http.StatusUnauthorized, i18n.ErrLoginRequired)
+		return false
+	default:
+		limiter.Auth.Reserve(ClientIP(c))
+		Abort(c, http.StatusBadRequest, i18n.ErrInvalidPassword)
+		return false
+	}
+}
+
+// CreateUserPasscode generates a new secret for two-factor authentication and returns it as JSON,
+// including a QR code for authenticator apps. It must be activated with a verification code.
+//
+// POST /api/v1/users/:uid/passcode
+func CreateUserPasscode(router *gin.RouterGroup) {
+	router.POST("/users/:uid/passcode", func(c *gin.Context) {
+		s := passcodeSession(c, acl.ActionCreate)
+
+		if s == nil {
+			return
+		}
+
+		u := s.User()
+
+		var f form.UserPasscode
+
+		if err := c.BindJSON(&f); err != nil {
+			AbortBadRequest(c)
+			return
+		}
+
+		// Verify that the change has been confirmed.
+		if !passcodeConfirmed(c, s, f) {
+			return
+		}
+
+		// Already enabled?
+		if u.HasPasscode() {
+			Abort(c, http.StatusConflict, i18n.ErrAlreadyExists, "Passcode")
+			return
+		}
+
+		m, err := entity.NewUserPasscode(u.UserUID)
+
+		if err != nil {
+			Error(c, http.StatusBadRequest, err, i18n.ErrUnexpected)
+			return
+		} else if err = m.Save(); err != nil {
+			log.Errorf("auth: %s", err)
+			AbortSaveFailed(c)
+			return
+		}
+
+		issuer := get.Config().AppName()
+		qrCode, err := m.QrCode(issuer, u.Name())
+
+		if err != nil {
+			log.Errorf("auth: %s", err)
+			AbortUnexpected(c)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"Type":   m.KeyType,
+			"Secret": m.Secret(),
+			"URL":    m.URL(issuer, u.Name()),
+			"QrCode": "data:image/png;base64," + base64.StdEncoding.EncodeToString(qrCode),
+		})
+	})
+}
+
+// ActivateUserPasscode enables two-factor authentication if the verification code is valid,
+// and returns the recovery codes as JSON, which cannot be retrieved later.
+//
+// POST /api/v1/users/:uid/passcode/activate
+func ActivateUserPasscode(router *gin.RouterGroup) {
+	router.POST("/users/:uid/passcode/activate", func(c *gin.Context) {
+		s := passcodeSession(c, acl.ActionUpdate)
+
+		if s == nil {
+			return
+		}
+
+		u := s.User()
+
+		var f form.UserPasscode
+
+		if err := c.BindJSON(&f); err != nil {
+			AbortBadRequest(c)
+			return
+		}
+
+		m := u.Passcode()
+
+		if m == nil {
+			AbortEntityNotFound(c)
+			return
+		} else if m.Verified() {
+			Abort(c, http.StatusConflict, i18n.ErrAlreadyExists, "Passcode")
+			return
+		}
+
+		recoveryCodes, err := m.Activate(f.Code)
+
+		if err != nil {
+			limiter.Auth.Reserve(ClientIP(c))
+			Abort(c, http.StatusBadRequest, i18n.ErrInvalidPasscode)
+			return
+		}
+
+		event.AuditInfo([]string{ClientIP(c), "user %s", "two-factor authentication enabled"}, clean.LogQuote(u.Name()))
+
+		c.JSON(http.StatusOK, gin.H{"Passcode": m, "RecoveryCodes": recoveryCodes})
+	})
+}
+
+// DeactivateUserPasscode disables two-factor authentication after confirming the password
+// or a current verification code.
+//
+// POST /api/v1/users/:uid/passcode/deactivate
+func DeactivateUserPasscode(router *gin.RouterGroup) {
+	router.POST("/users/:uid/passcode/deactivate", func(c *gin.Context) {
+		s := passcodeSession(c, acl.ActionDelete)
+
+		if s == nil {
+			return
+		}
+
+		u := s.User()
+
+		var f form.UserPasscode
+
+		if err := c.BindJSON(&f); err != nil {
+			AbortBadRequest(c)
+			return
+		}
+
+		// Verify that the change has been confirmed.
+		if !passcodeConfirmed(c, s, f) {
+			return
+		}
+
+		m := u.Passcode()
+
+		if m == nil {
+			AbortEntityNotFound(c)
+			return
+		} else if err := m.Delete(); err != nil {
+			log.Errorf("auth: %s", err)
+			AbortDeleteFailed(c)
+			return
+		}
+
+		event.AuditInfo([]string{ClientIP(c), "user %s", "two-factor authentication disabled"}, clean.LogQuote(u.Name()))
+
+		c.JSON(http.StatusOK, m)
+	})
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+	"golang.org/x/time/rate"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/server/limiter"
+	"github.com/photoprism/photoprism/pkg/totp"
+)
+
+func TestCreateUserPasscode(t *testing.T) {
+	t.Run("Public", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		CreateUserPasscode(router)
+		r := PerformRequest(app, "POST", "/api/v1/users/uqxqg7i1kperxvu7/passcode")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+	t.Run("OtherUser", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		CreateUserPasscode(router)
+		sessId := AuthenticateUser(app, router, "alice", "Alice123!")
+		r := AuthenticatedRequestWithBody(app, "POST", "/api/v1/users/uqxqg7i1kperxvu7/passcode", `{"Password": "Alice123!"}`, sessId)
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+}
+
+func TestUserPasscode(t *testing.T) {
+	app, router, conf := NewApiTest()
+	conf.SetAuthMode(config.AuthModePasswd)
+	defer conf.SetAuthMode(config.AuthModePublic)
+
+	// Failed attempts must not affect the rate limit of other tests.
+	authLimit := limiter.Auth
+	limiter.Auth = limiter.NewLimit(rate.Every(limiter.DefaultAuthInterval), limiter.DefaultAuthLimit)
+	defer func() { limiter.Auth = authLimit }()
+
+	CreateUserPasscode(router)
+	ActivateUserPasscode(router)
+	DeactivateUserPasscode(router)
+
+	// Also registers the login endpoint.
+	sessId := AuthenticateUser(app, router, "friend", "!Friend321")
+	uri := "/api/v1/users/uqxqg7i1kperxvu7/passcode"
+
+	var secret string
+	var recoveryCodes []gjson.Result
+
+	t.Run("WrongPassword", func(t *testing.T) {
+		r := AuthenticatedRequestWithBody(app, "POST", uri, `{"Password": "wrong"}`, sessId)
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("Create", func(t *testing.T) {
+		r := AuthenticatedRequestWithBody(app, "POST", uri, `{"Password": "!Friend321"}`, sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+		secret = gjson.Get(r.Body.String(), "Secret").String()
+		assert.Len(t, secret, 32)
+		assert.Contains(t, gjson.Get(r.Body.String(), "URL").String(), "otpauth://totp/")
+		assert.Contains(t, gjson.Get(r.Body.String(), "QrCode").String(), "data:image/png;base64,")
+	})
+	t.Run("ActivateInvalid", func(t *testing.T) {
+		r := AuthenticatedRequestWithBody(app, "POST", uri+"/activate", `{"Code": "123"}`, sessId)
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("Activate", func(t *testing.T) {
+		code, _ := totp.Code(secret, time.Now())
+		r := AuthenticatedRequestWithBody(app, "POST", uri+"/activate", `{"Code": "`+code+`"}`, sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+		recoveryCodes = gjson.Get(r.Body.String(), "RecoveryCodes").Array()
+		assert.Len(t, recoveryCodes, entity.RecoveryCodeCount)
+		assert.False(t, gjson.Get(r.Body.String(), "Passcode.Secret").Exists())
+
+		// Already enabled.
+		r = AuthenticatedRequestWithBody(app, "POST", uri, `{"Password": "!Friend321"}`, sessId)
+		assert.Equal(t, http.StatusConflict, r.Code)
+	})
+	t.Run("Login", func(t *testing.T) {
+		r := PerformRequestWithBody(app, "POST", "/api/v1/session", `{"name": "friend", "password": "!Friend321"}`)
+		assert.Equal(t, http.StatusUnauthorized, r.Code)
+		assert.Equal(t, "passcode_required", gjson.Get(r.Body.String(), "code").String())
+
+		r = PerformRequestWithBody(app, "POST", "/api/v1/session", `{"name": "friend", "password": "!Friend321", "passcode": "000000"}`)
+		assert.Equal(t, http.StatusUnauthorized, r.Code)
+		assert.False(t, gjson.Get(r.Body.String(), "code").Exists())
+
+		r = PerformRequestWithBody(app, "POST", "/api/v1/session", `{"name": "friend", "password": "!Friend321", "passcode": "`+recoveryCodes[0].String()+`"}`)
+		assert.Equal(t, http.StatusOK, r.Code)
+	})
+	t.Run("Deactivate", func(t *testing.T) {
+		r := AuthenticatedRequestWithBody(app, "POST", uri+"/deactivate", `{"Password": "wrong"}`, sessId)
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+		r = AuthenticatedRequestWithBody(app, "POST", uri+"/deactivate", `{"Code": "000000"}`, sessId)
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+		r = AuthenticatedRequestWithBody(app, "POST", uri+"/deactivate", `{}`, sessId)
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+		r = AuthenticatedRequestWithBody(app, "POST", uri+"/deactivate", `{"Password": "!Friend321"}`, sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Nil(t, entity.FindUserPasscode("uqxqg7i1kperxvu7"))
+	})
+}
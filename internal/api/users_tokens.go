@@ -0,0 +1,141 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/i18n"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// GetUserTokens returns the access tokens of the currently authenticated user as JSON.
+//
+// GET /api/v1/users/:uid/tokens
+func GetUserTokens(router *gin.RouterGroup) {
+	router.GET("/users/:uid/tokens", func(c *gin.Context) {
+		// Access tokens cannot be managed in public mode.
+		if get.Config().Public() {
+			Abort(c, http.StatusForbidden, i18n.ErrPublic)
+			return
+		}
+
+		s := Auth(c, acl.ResourcePassword, acl.ActionView)
+
+		if s.Abort(c) {
+			return
+		}
+
+		// Users may only view their own tokens.
+		if s.User().UserUID != clean.UID(c.Param("uid")) {
+			AbortForbidden(c)
+			return
+		}
+
+		c.JSON(http.StatusOK, entity.FindUserTokens(s.User().UserUID))
+	})
+}
+
+// CreateUserToken creates a new access token for the currently authenticated user
+// and returns it as JSON, including the secret, which cannot be retrieved later.
+//
+// POST /api/v1/users/:uid/tokens
+func CreateUserToken(router *gin.RouterGroup) {
+	router.POST("/users/:uid/tokens", func(c *gin.Context) {
+		// Access tokens cannot be managed in public mode.
+		if get.Config().Public() {
+			Abort(c, http.StatusForbidden, i18n.ErrPublic)
+			return
+		}
+
+		s := Auth(c, acl.ResourcePassword, acl.ActionCreate)
+
+		if s.Abort(c) {
+			return
+		}
+
+		// Users may only create their own tokens, and not by using another token.
+		if s.User().UserUID != clean.UID(c.Param("uid")) || s.AuthMethod == entity.AuthMethodToken {
+			AbortForbidden(c)
+			return
+		}
+
+		var f form.UserToken
+
+		if err := c.BindJSON(&f); err != nil {
+			AbortBadRequest(c)
+			return
+		}
+
+		var expires *time.Time
+
+		if f.Expires > 0 {
+			t := entity.TimeStamp().Add(time.Duration(f.Expires) * time.Second)
+			expires = &t
+		}
+
+		m, secret, err := entity.NewUserToken(s.User().UserUID, f.Name, f.Scope, expires)
+
+		if err != nil {
+			Error(c, http.StatusBadRequest, err, i18n.ErrInvalidScope)
+			return
+		} else if err = m.Create(); err != nil {
+			log.Errorf("auth: %s", err)
+			AbortSaveFailed(c)
+			return
+		}
+
+		event.AuditInfo([]string{ClientIP(c), "session %s", "token %s", "created"}, s.RefID, m.TokenUID)
+
+		c.JSON(http.StatusOK, gin.H{"Token": m, "Secret": secret})
+	})
+}
+
+// DeleteUserToken revokes an access token of the currently authenticated user.
+//
+// DELETE /api/v1/users/:uid/tokens/:token
+func DeleteUserToken(router *gin.RouterGroup) {
+	router.DELETE("/users/:uid/tokens/:token", func(c *gin.Context) {
+		// Access tokens cannot be managed in public mode.
+		if get.Config().Public() {
+			Abort(c, http.StatusForbidden, i18n.ErrPublic)
+			return
+		}
+
+		s := Auth(c, acl.ResourcePassword, acl.ActionDelete)
+
+		if s.Abort(c) {
+			return
+		}
+
+		// Users may only revoke their own tokens, and not by using another token.
+		if s.User().UserUID != clean.UID(c.Param("uid")) || s.AuthMethod == entity.AuthMethodToken {
+			AbortForbidden(c)
+			return
+		}
+
+		m := entity.FindUserTokenByUID(clean.UID(c.Param("token")))
+
+		// Token must exist and belong to the user.
+		if m == nil || m.UserUID != s.User().UserUID {
+			Abort(c, http.StatusNotFound, i18n.ErrInvalidToken)
+			return
+		}
+
+		if err := m.Delete(); err != nil {
+			log.Errorf("auth: %s", err)
+			AbortDeleteFailed(c)
+			return
+		}
+
+		event.AuditInfo([]string{ClientIP(c), "session %s", "token %s", "revoked"}, s.RefID, m.TokenUID)
+
+		c.JSON(http.StatusOK, m)
+	})
+}
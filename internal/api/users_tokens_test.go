@@ -0,0 +1,142 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/session"
+)
+
+// BearerRequest performs an API request authenticated with an access token.
+func BearerRequest(r http.Handler, method, path, token string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, nil)
+
+	if token != "" {
+		req.Header.Add(session.AuthHeader, session.BearerPrefix+token)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestGetUserTokens(t *testing.T) {
+	t.Run("Public", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetUserTokens(router)
+		r := PerformRequest(app, "GET", "/api/v1/users/uqxetse3cy5eo9z2/tokens")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+	t.Run("Alice", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		GetUserTokens(router)
+		sessId := AuthenticateUser(app, router, "alice", "Alice123!")
+
+		r := AuthenticatedRequest(app, "GET", "/api/v1/users/uqxetse3cy5eo9z2/tokens", sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Equal(t, int64(2), gjson.Get(r.Body.String(), "#").Int())
+		assert.False(t, gjson.Get(r.Body.String(), "0.TokenHash").Exists())
+
+		r = AuthenticatedRequest(app, "GET", "/api/v1/users/uqxc08w3d0ej2283/tokens", sessId)
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+}
+
+func TestCreateUserToken(t *testing.T) {
+	app, router, conf := NewApiTest()
+	conf.SetAuthMode(config.AuthModePasswd)
+	defer conf.SetAuthMode(config.AuthModePublic)
+	CreateUserToken(router)
+	DeleteUserToken(router)
+	sessId := AuthenticateUser(app, router, "alice", "Alice123!")
+
+	t.Run("InvalidScope", func(t *testing.T) {
+		r := AuthenticatedRequestWithBody(app, "POST", "/api/v1/users/uqxetse3cy5eo9z2/tokens", `{"Name": "Test", "Scope": "photos:fly"}`, sessId)
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("OtherUser", func(t *testing.T) {
+		r := AuthenticatedRequestWithBody(app, "POST", "/api/v1/users/uqxc08w3d0ej2283/tokens", `{"Name": "Test"}`, sessId)
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+	t.Run("CreateAndDelete", func(t *testing.T) {
+		r := AuthenticatedRequestWithBody(app, "POST", "/api/v1/users/uqxetse3cy5eo9z2/tokens", `{"Name": "Test", "Scope": "albums:view", "Expires": 3600}`, sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+
+		uid := gjson.Get(r.Body.String(), "Token.UID").String()
+		secret := gjson.Get(r.Body.String(), "Secret").String()
+		assert.Equal(t, "Test", gjson.Get(r.Body.String(), "Token.Name").String())
+		assert.Equal(t, "albums:view", gjson.Get(r.Body.String(), "Token.Scope").String())
+		assert.True(t, gjson.Get(r.Body.String(), "Token.ExpiresAt").Exists())
+		assert.NotNil(t, entity.FindUserToken(secret))
+
+		// Access tokens cannot be used to create other tokens.
+		r = BearerRequest(app, "POST", "/api/v1/users/uqxetse3cy5eo9z2/tokens", secret)
+		assert.Equal(t, http.StatusForbidden, r.Code)
+
+		r = AuthenticatedRequest(app, "DELETE", "/api/v1/users/uqxetse3cy5eo9z2/tokens/"+uid, sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Nil(t, entity.FindUserToken(secret))
+	})
+	t.Run("DeleteWithToken", func(t *testing.T) {
+		r := AuthenticatedRequestWithBody(app, "POST", "/api/v1/users/uqxetse3cy5eo9z2/tokens", `{"Name": "Revoke", "Scope": "password:delete"}`, sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+		revokeUid := gjson.Get(r.Body.String(), "Token.UID").String()
+		revokeSecret := gjson.Get(r.Body.String(), "Secret").String()
+
+		r = AuthenticatedRequestWithBody(app, "POST", "/api/v1/users/uqxetse3cy5eo9z2/tokens", `{"Name": "Other", "Scope": "albums:view"}`, sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+		otherUid := gjson.Get(r.Body.String(), "Token.UID").String()
+		otherSecret := gjson.Get(r.Body.String(), "Secret").String()
+
+		// Access tokens cannot be used to revoke other tokens, even if their scope includes passwords.
+		r = BearerRequest(app, "DELETE", "/api/v1/users/uqxetse3cy5eo9z2/tokens/"+otherUid, revokeSecret)
+		assert.Equal(t, http.StatusForbidden, r.Code)
+		assert.NotNil(t, entity.FindUserToken(otherSecret))
+
+		for _, uid := range []string{revokeUid, otherUid} {
+			r = AuthenticatedRequest(app, "DELETE", "/api/v1/users/uqxetse3cy5eo9z2/tokens/"+uid, sessId)
+			assert.Equal(t, http.StatusOK, r.Code)
+		}
+	})
+	t.Run("DeleteOtherUsersToken", func(t *testing.T) {
+		r := AuthenticatedRequest(app, "DELETE", "/api/v1/users/uqxetse3cy5eo9z2/tokens/tqxc08w3d0ej2283", sessId)
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+}
+
+func TestBearerToken(t *testing.T) {
+	app, router, conf := NewApiTest()
+	conf.SetAuthMode(config.AuthModePasswd)
+	defer conf.SetAuthMode(config.AuthModePublic)
+	SearchPhotos(router)
+	SearchAlbums(router)
+
+	t.Run("Unrestricted", func(t *testing.T) {
+		r := BearerRequest(app, "GET", "/api/v1/albums?count=1", entity.UserTokenSecrets["alice"])
+		assert.Equal(t, http.StatusOK, r.Code)
+	})
+	t.Run("Scope", func(t *testing.T) {
+		r := BearerRequest(app, "GET", "/api/v1/photos?count=1", entity.UserTokenSecrets["photos"])
+		assert.Equal(t, http.StatusOK, r.Code)
+		r = BearerRequest(app, "GET", "/api/v1/albums?count=1", entity.UserTokenSecrets["photos"])
+		assert.Equal(t, http.StatusForbidden, r.Code)
+		assert.NotNil(t, entity.FindUserToken(entity.UserTokenSecrets["photos"]).LastUsedAt)
+	})
+	t.Run("Expired", func(t *testing.T) {
+		r := BearerRequest(app, "GET", "/api/v1/photos?count=1", entity.UserTokenSecrets["expired"])
+		assert.Equal(t, http.StatusUnauthorized, r.Code)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		r := BearerRequest(app, "GET", "/api/v1/photos?count=1", "foo")
+		assert.Equal(t, http.StatusUnauthorized, r.Code)
+	})
+}
@@ -0,0 +1,211 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/i18n"
+	"github.com/photoprism/photoprism/internal/webhook"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// GetWebhooks returns the registered webhooks as JSON.
+//
+// GET /api/v1/webhooks
+func GetWebhooks(router *gin.RouterGroup) {
+	router.GET("/webhooks", func(c *gin.Context) {
+		// Webhooks cannot be managed in public mode.
+		if get.Config().Public() {
+			Abort(c, http.StatusForbidden, i18n.ErrPublic)
+			return
+		}
+
+		s := Auth(c, acl.ResourceWebhooks, acl.ActionSearch)
+
+		if s.Abort(c) {
+			return
+		}
+
+		c.JSON(http.StatusOK, entity.FindWebhooks(false))
+	})
+}
+
+// CreateWebhook registers a new webhook and returns it as JSON, including the secret
+// used to sign payloads, which cannot be retrieved later.
+//
+// POST /api/v1/webhooks
+func CreateWebhook(router *gin.RouterGroup) {
+	router.POST("/webhooks", func(c *gin.Context) {
+		// Webhooks cannot be managed in public mode.
+		if get.Config().Public() {
+			Abort(c, http.StatusForbidden, i18n.ErrPublic)
+			return
+		}
+
+		s := Auth(c, acl.ResourceWebhooks, acl.ActionCreate)
+
+		if s.Abort(c) {
+			return
+		}
+
+		var f form.Webhook
+
+		if err := c.BindJSON(&f); err != nil {
+			AbortBadRequest(c)
+			return
+		}
+
+		m, secret, err := entity.NewWebhook(f.URL, f.Topics, s.User().UserUID)
+
+		if err != nil {
+			Error(c, http.StatusBadRequest, err, i18n.ErrInvalidWebhook)
+			return
+		} else if err = m.Create(); err != nil {
+			log.Errorf("webhook: %s", err)
+			AbortSaveFailed(c)
+			return
+		}
+
+		webhook.Flush()
+
+		event.AuditInfo([]string{ClientIP(c), "session %s", "webhook %s", "created"}, s.RefID, m.WebhookUID)
+
+		c.JSON(http.StatusOK, gin.H{"Webhook": m, "Secret": secret})
+	})
+}
+
+// UpdateWebhook changes the URL, topics, or status of a webhook and returns it as JSON.
+//
+// PUT /api/v1/webhooks/:uid
+func UpdateWebhook(router *gin.RouterGroup) {
+	router.PUT("/webhooks/:uid", func(c *gin.Context) {
+		// Webhooks cannot be managed in public mode.
+		if get.Config().Public() {
+			Abort(c, http.StatusForbidden, i18n.ErrPublic)
+			return
+		}
+
+		s := Auth(c, acl.ResourceWebhooks, acl.ActionUpdate)
+
+		if s.Abort(c) {
+			return
+		}
+
+		m := entity.FindWebhook(clean.UID(c.Param("uid")))
+
+		if m == nil {
+			AbortEntityNotFound(c)
+			return
+		}
+
+		// Only change the values that were sent.
+		f := form.Webhook{URL: m.WebhookURL, Topics: m.WebhookTopics, Enabled: m.WebhookEnabled}
+
+		if err := c.BindJSON(&f); err != nil {
+			AbortBadRequest(c)
+			return
+		}
+
+		if err := m.SaveForm(f); err != nil {
+			Error(c, http.StatusBadRequest, err, i18n.ErrInvalidWebhook)
+			return
+		}
+
+		webhook.Flush()
+
+		event.AuditInfo([]string{ClientIP(c), "session %s", "webhook %s", "updated"}, s.RefID, m.WebhookUID)
+
+		c.JSON(http.StatusOK, m)
+	})
+}
+
+// DeleteWebhook removes a webhook along with its delivery log.
+//
+// DELETE /api/v1/webhooks/:uid
+func DeleteWebhook(router *gin.RouterGroup) {
+	router.DELETE("/webhooks/:uid", func(c *gin.Context) {
+		// Webhooks cannot be managed in public mode.
+		if get.Config().Public() {
+			Abort(c, http.StatusForbidden, i18n.ErrPublic)
+			return
+		}
+
+		s := Auth(c, acl.ResourceWebhooks, acl.ActionDelete)
+
+		if s.Abort(c) {
+			return
+		}
+
+		m := entity.FindWebhook(clean.UID(c.Param("uid")))
+
+		if m == nil {
+			AbortEntityNotFound(c)
+			return
+		}
+
+		if err := m.Delete(); err != nil {
+			log.Errorf("webhook: %s", err)
+			AbortDeleteFailed(c)
+			return
+		}
+
+		webhook.Flush()
+
+		event.AuditInfo([]string{ClientIP(c), "session %s", "webhook %s", "deleted"}, s.RefID, m.WebhookUID)
+
+		c.JSON(http.StatusOK, m)
+	})
+}
+
+// GetWebhookDeliveries returns the delivery log of a webhook as JSON, newest first.
+//
+// GET /api/v1/webhooks/:uid/deliveries
+func GetWebhookDeliveries(router *gin.RouterGroup) {
+	router.GET("/webhooks/:uid/deliveries", func(c *gin.Context) {
+		// Webhooks cannot be managed in public mode.
+		if get.Config().Public() {
+			Abort(c, http.StatusForbidden, i18n.ErrPublic)
+			return
+		}
+
+		s := Auth(c, acl.ResourceWebhooks, acl.ActionView)
+
+		if s.Abort(c) {
+			return
+		}
+
+		m := entity.FindWebhook(clean.UID(c.Param("uid")))
+
+		if m == nil {
+			AbortEntityNotFound(c)
+			return
+		}
+
+		limit := txt.Int(c.Query("count"))
+		offset := txt.Int(c.Query("offset"))
+
+		if limit <= 0 || limit > 1000 {
+			limit = 100
+		}
+
+		resp, err := entity.FindWebhookDeliveries(m.WebhookUID, limit, offset)
+
+		if err != nil {
+			c.AbortWithStatusJSON(400, gin.H{"error": txt.UpperFirst(err.Error())})
+			return
+		}
+
+		AddCountHeader(c, len(resp))
+		AddLimitHeader(c, limit)
+		AddOffsetHeader(c, offset)
+
+		c.JSON(http.StatusOK, resp)
+	})
+}
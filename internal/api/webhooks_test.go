@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+)
+
+func TestGetWebhooks(t *testing.T) {
+	t.Run("Public", func(t *testing.T) {
+		app, router, _ := NewApiTest()
+		GetWebhooks(router)
+		r := PerformRequest(app, "GET", "/api/v1/webhooks")
+		assert.Equal(t, http.StatusForbidden, r.Code)
+	})
+	t.Run("Alice", func(t *testing.T) {
+		app, router, conf := NewApiTest()
+		conf.SetAuthMode(config.AuthModePasswd)
+		defer conf.SetAuthMode(config.AuthModePublic)
+		GetWebhooks(router)
+		sessId := AuthenticateUser(app, router, "alice", "Alice123!")
+
+		r := AuthenticatedRequest(app, "GET", "/api/v1/webhooks", sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.GreaterOrEqual(t, gjson.Get(r.Body.String(), "#").Int(), int64(2))
+		assert.False(t, gjson.Get(r.Body.String(), "0.WebhookSecret").Exists())
+	})
+}
+
+func TestCreateWebhook(t *testing.T) {
+	app, router, conf := NewApiTest()
+	conf.SetAuthMode(config.AuthModePasswd)
+	defer conf.SetAuthMode(config.AuthModePublic)
+	CreateWebhook(router)
+	UpdateWebhook(router)
+	DeleteWebhook(router)
+	sessId := AuthenticateUser(app, router, "alice", "Alice123!")
+
+	t.Run("InvalidURL", func(t *testing.T) {
+		r := AuthenticatedRequestWithBody(app, "POST", "/api/v1/webhooks", `{"URL": "ftp://example.com", "Topics": "photos.*"}`, sessId)
+		assert.Equal(t, http.StatusBadRequest, r.Code)
+	})
+	t.Run("CreateUpdateDelete", func(t *testing.T) {
+		r := AuthenticatedRequestWithBody(app, "POST", "/api/v1/webhooks", `{"URL": "https://bot.example.com/new", "Topics": "photos.created"}`, sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+
+		uid := gjson.Get(r.Body.String(), "Webhook.UID").String()
+		assert.NotEmpty(t, gjson.Get(r.Body.String(), "Secret").String())
+		assert.True(t, gjson.Get(r.Body.String(), "Webhook.Enabled").Bool())
+
+		r = AuthenticatedRequestWithBody(app, "PUT", "/api/v1/webhooks/"+uid, `{"Enabled": false}`, sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.False(t, gjson.Get(r.Body.String(), "Enabled").Bool())
+		assert.Equal(t, "photos.created", gjson.Get(r.Body.String(), "Topics").String())
+
+		r = AuthenticatedRequest(app, "DELETE", "/api/v1/webhooks/"+uid, sessId)
+		assert.Equal(t, http.StatusOK, r.Code)
+		assert.Nil(t, entity.FindWebhook(uid))
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		r := AuthenticatedRequest(app, "DELETE", "/api/v1/webhooks/wrhn0cz3gzecxxxx", sessId)
+		assert.Equal(t, http.StatusNotFound, r.Code)
+	})
+}
+
+func TestGetWebhookDeliveries(t *testing.T) {
+	app, router, conf := NewApiTest()
+	conf.SetAuthMode(config.AuthModePasswd)
+	defer conf.SetAuthMode(config.AuthModePublic)
+	GetWebhookDeliveries(router)
+	sessId := AuthenticateUser(app, router, "alice", "Alice123!")
+
+	r := AuthenticatedRequest(app, "GET", "/api/v1/webhooks/wrhn0cz3gzecxmxy/deliveries?count=10", sessId)
+	assert.Equal(t, http.StatusOK, r.Code)
+	assert.GreaterOrEqual(t, gjson.Get(r.Body.String(), "#").Int(), int64(2))
+	assert.Equal(t, "photos.created", gjson.Get(r.Body.String(), "0.Topic").String())
+}
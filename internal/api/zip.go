@@ -65,7 +65,7 @@ func ZipCreate(router *gin.RouterGroup) {
 		}
 
 		// Find files to download.
-		files, err := query.SelectedFiles(f, selection)
+		files, err := query.UserSelectedFiles(f, selection, s)
 
 		if err != nil {
 			Error(c, http.StatusBadRequest, err, i18n.ErrZipFailed)
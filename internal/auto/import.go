@@ -1,18 +1,14 @@
 package auto
 
 import (
-	"path/filepath"
 	"sync"
 	"time"
 
-	"github.com/photoprism/photoprism/internal/api"
 	"github.com/photoprism/photoprism/internal/entity"
-	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
 	"github.com/photoprism/photoprism/internal/get"
-	"github.com/photoprism/photoprism/internal/i18n"
 	"github.com/photoprism/photoprism/internal/mutex"
-	"github.com/photoprism/photoprism/internal/photoprism"
-	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/internal/workers"
 )
 
 var autoImport = time.Time{}
@@ -46,57 +42,17 @@ func mustImport(delay time.Duration) bool {
 	return !autoImport.IsZero() && autoImport.Sub(time.Now()) < -1*delay && !mutex.MainWorker.Running()
 }
 
-// Import starts importing originals e.g. after WebDAV uploads.
+// Import queues importing files e.g. after WebDAV uploads.
 func Import() error {
-	if mutex.MainWorker.Running() {
-		return nil
-	}
-
 	conf := get.Config()
 
 	if conf.ReadOnly() || !conf.Settings().Features.Import {
 		return nil
 	}
 
-	start := time.Now()
-
-	path := filepath.Clean(conf.ImportPath())
-
-	imp := get.Import()
-
-	api.RemoveFromFolderCache(entity.RootImport)
-
-	event.InfoMsg(i18n.MsgCopyingFilesFrom, clean.Log(filepath.Base(path)))
-
-	var opt photoprism.ImportOptions
-
-	if conf.Settings().Import.Move {
-		opt = photoprism.ImportOptionsMove(path, conf.ImportDest())
-	} else {
-		opt = photoprism.ImportOptionsCopy(path, conf.ImportDest())
-	}
-
-	imported := imp.Start(opt)
-
-	if len(imported) == 0 {
-		return nil
-	}
-
-	moments := get.Moments()
-
-	if err := moments.Start(); err != nil {
-		log.Warnf("moments: %s", err)
+	if _, err := workers.QueueJob(entity.JobTypeImport, form.ImportOptions{Move: conf.Settings().Import.Move}, ""); err != nil && err != workers.ErrJobQueued {
+		return err
 	}
 
-	elapsed := int(time.Since(start).Seconds())
-
-	msg := i18n.Msg(i18n.MsgImportCompletedIn, elapsed)
-
-	event.Success(msg)
-	event.Publish("import.completed", event.Data{"path": path, "seconds": elapsed})
-	event.Publish("index.completed", event.Data{"path": path, "seconds": elapsed})
-
-	api.UpdateClientConfig()
-
 	return nil
 }
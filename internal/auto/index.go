@@ -1,17 +1,13 @@
 package auto
 
 import (
-	"path/filepath"
 	"sync"
 	"time"
 
-	"github.com/photoprism/photoprism/internal/api"
 	"github.com/photoprism/photoprism/internal/entity"
-	"github.com/photoprism/photoprism/internal/event"
-	"github.com/photoprism/photoprism/internal/get"
-	"github.com/photoprism/photoprism/internal/i18n"
+	"github.com/photoprism/photoprism/internal/form"
 	"github.com/photoprism/photoprism/internal/mutex"
-	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/workers"
 )
 
 var autoIndex = time.Time{}
@@ -45,63 +41,11 @@ func mustIndex(delay time.Duration) bool {
 	return !autoIndex.IsZero() && autoIndex.Sub(time.Now()) < -1*delay && !mutex.MainWorker.Running()
 }
 
-// Index starts indexing originals e.g. after WebDAV uploads.
+// Index queues indexing originals e.g. after WebDAV uploads.
 func Index() error {
-	if mutex.MainWorker.Running() {
-		return nil
-	}
-
-	conf := get.Config()
-	settings := conf.Settings()
-
-	start := time.Now()
-
-	path := conf.OriginalsPath()
-
-	ind := get.Index()
-
-	convert := settings.Index.Convert && conf.SidecarWritable()
-	indOpt := photoprism.NewIndexOptions(entity.RootPath, false, convert, true, false, true)
-
-	indexed := ind.Start(indOpt)
-
-	if len(indexed) == 0 {
-		return nil
-	}
-
-	api.RemoveFromFolderCache(entity.RootOriginals)
-
-	prg := get.Purge()
-
-	prgOpt := photoprism.PurgeOptions{
-		Path:   filepath.Clean(entity.RootPath),
-		Ignore: indexed,
-	}
-
-	if files, photos, err := prg.Start(prgOpt); err != nil {
+	if _, err := workers.QueueJob(entity.JobTypeIndex, form.IndexOptions{Path: entity.RootPath}, ""); err != nil && err != workers.ErrJobQueued {
 		return err
-	} else if len(files) > 0 || len(photos) > 0 {
-		event.InfoMsg(i18n.MsgRemovedFilesAndPhotos, len(files), len(photos))
 	}
 
-	event.Publish("index.updating", event.Data{
-		"step": "moments",
-	})
-
-	moments := get.Moments()
-
-	if err := moments.Start(); err != nil {
-		log.Warnf("moments: %s", err)
-	}
-
-	elapsed := int(time.Since(start).Seconds())
-
-	msg := i18n.Msg(i18n.MsgIndexingCompletedIn, elapsed)
-
-	event.Success(msg)
-	event.Publish("index.completed", event.Data{"path": path, "seconds": elapsed})
-
-	api.UpdateClientConfig()
-
 	return nil
 }
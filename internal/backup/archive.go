@@ -0,0 +1,97 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Archive represents a backup archive folder.
+type Archive struct {
+	Name      string
+	Path      string
+	CreatedAt time.Time
+}
+
+// Archives represents a list of backup archives.
+type Archives []Archive
+
+// List returns the backup archives in dir, newest first.
+func List(dir string) (result Archives, err error) {
+	entries, err := os.ReadDir(dir)
+
+	if os.IsNotExist(err) {
+		return result, nil
+	} else if err != nil {
+		return result, err
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		// Ignore folders with other names, e.g. incomplete archives.
+		created, err := time.Parse(TimeFormat, e.Name())
+
+		if err != nil {
+			continue
+		}
+
+		result = append(result, Archive{Name: e.Name(), Path: filepath.Join(dir, e.Name()), CreatedAt: created})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
+
+// Find returns the archive in dir with the specified name or path, or the newest archive if name is empty.
+func Find(dir, name string) (a Archive, err error) {
+	name = strings.TrimSpace(name)
+
+	if name == "" {
+		archives, err := List(dir)
+
+		if err != nil {
+			return a, err
+		} else if len(archives) == 0 {
+			return a, fmt.Errorf("no backup archives found in %s", dir)
+		}
+
+		return archives[0], nil
+	}
+
+	path := name
+
+	if !strings.ContainsRune(name, filepath.Separator) {
+		path = filepath.Join(dir, name)
+	}
+
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		return a, fmt.Errorf("backup archive %s not found", name)
+	}
+
+	a = Archive{Name: filepath.Base(path), Path: path}
+
+	if created, err := time.Parse(TimeFormat, a.Name); err == nil {
+		a.CreatedAt = created
+	}
+
+	return a, nil
+}
+
+// Manifest reads the archive manifest.
+func (a Archive) Manifest() (Manifest, error) {
+	return ReadManifest(a.Path)
+}
+
+// FileName returns the absolute file name of a file in the archive.
+func (a Archive) FileName(name string) string {
+	return filepath.Join(a.Path, filepath.FromSlash(name))
+}
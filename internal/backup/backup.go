@@ -0,0 +1,43 @@
+/*
+Package backup creates, verifies, and rotates checksum-verified backup archives.
+
+Copyright (c) 2018 - 2022 PhotoPrism UG. All rights reserved.
+
+	This program is free software: you can redistribute it and/or modify
+	it under Version 3 of the GNU Affero General Public License (the "AGPL"):
+	<https://docs.photoprism.app/license/agpl>
+
+	This program is distributed in the hope that it will be useful,
+	but WITHOUT ANY WARRANTY; without even the implied warranty of
+	MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+	GNU Affero General Public License for more details.
+
+	The AGPL is supplemented by our Trademark and Brand Guidelines,
+	which describe how our Brand Assets may be used:
+	<https://photoprism.app/trademark>
+
+Feel free to send an email to hello@photoprism.app if you have questions,
+want to support our work, or just want to say hello.
+
+Additional information can be found in our Developer Guide:
+<https://docs.photoprism.app/developer-guide/>
+*/
+package backup
+
+import (
+	"github.com/photoprism/photoprism/internal/event"
+)
+
+var log = event.Log
+
+// Archive folder and file names.
+const (
+	ManifestFile = "manifest.json"
+	IndexFile    = "index.sql"
+	AlbumsDir    = "albums"
+	SidecarDir   = "sidecar"
+	SettingsDir  = "settings"
+)
+
+// TimeFormat is the layout of archive folder names, which are based on the UTC creation time.
+const TimeFormat = "20060102-150405"
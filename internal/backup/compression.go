@@ -0,0 +1,119 @@
+package backup
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression represents a file compression method.
+type Compression string
+
+// Supported compression methods.
+const (
+	CompressNone Compression = "none"
+	CompressGzip Compression = "gzip"
+	CompressZstd Compression = "zstd"
+)
+
+// ParseCompression returns the compression method matching the string, or CompressNone if it is unknown.
+func ParseCompression(s string) Compression {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "gzip", "gz":
+		return CompressGzip
+	case "zstd", "zst":
+		return CompressZstd
+	default:
+		return CompressNone
+	}
+}
+
+// String returns the compression method as string.
+func (c Compression) String() string {
+	if c == "" {
+		return string(CompressNone)
+	}
+
+	return string(c)
+}
+
+// Ext returns the file extension of compressed files including the dot, or an empty string.
+func (c Compression) Ext() string {
+	switch c {
+	case CompressGzip:
+		return ".gz"
+	case CompressZstd:
+		return ".zst"
+	default:
+		return ""
+	}
+}
+
+// NewWriter returns a writer that compresses data written to w. Closing it does not close w.
+func (c Compression) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	switch c {
+	case CompressGzip:
+		return gzip.NewWriter(w), nil
+	case CompressZstd:
+		return zstd.NewWriter(w)
+	default:
+		return nopWriteCloser{w}, nil
+	}
+}
+
+// OpenFile opens a file for reading and decompresses it based on its extension.
+func OpenFile(fileName string) (io.ReadCloser, error) {
+	f, err := os.Open(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.HasSuffix(fileName, CompressGzip.Ext()):
+		r, err := gzip.NewReader(f)
+
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("%s is not gzip compressed", fileName)
+		}
+
+		return readCloser{Reader: r, close: func() error { r.Close(); return f.Close() }}, nil
+	case strings.HasSuffix(fileName, CompressZstd.Ext()):
+		r, err := zstd.NewReader(f)
+
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+
+		return readCloser{Reader: r, close: func() error { r.Close(); return f.Close() }}, nil
+	default:
+		return f, nil
+	}
+}
+
+// nopWriteCloser adds a Close method without effect to a writer.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+// Close implements io.Closer.
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// readCloser closes the underlying file along with a decompressing reader.
+type readCloser struct {
+	io.Reader
+	close func() error
+}
+
+// Close implements io.Closer.
+func (r readCloser) Close() error {
+	return r.close()
+}
@@ -0,0 +1,23 @@
+package backup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCompression(t *testing.T) {
+	assert.Equal(t, CompressGzip, ParseCompression("gzip"))
+	assert.Equal(t, CompressGzip, ParseCompression(" GZ "))
+	assert.Equal(t, CompressZstd, ParseCompression("zstd"))
+	assert.Equal(t, CompressNone, ParseCompression("none"))
+	assert.Equal(t, CompressNone, ParseCompression(""))
+	assert.Equal(t, CompressNone, ParseCompression("bzip2"))
+}
+
+func TestCompression_Ext(t *testing.T) {
+	assert.Equal(t, ".gz", CompressGzip.Ext())
+	assert.Equal(t, ".zst", CompressZstd.Ext())
+	assert.Equal(t, "", CompressNone.Ext())
+	assert.Equal(t, "none", Compression("").String())
+}
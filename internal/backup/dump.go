@@ -0,0 +1,60 @@
+package backup
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// CountRows returns the estimated number of rows per table in an SQL dump created with mysqldump, pg_dump,
+// or sqlite3. Extended inserts are counted based on the row separators they contain.
+func CountRows(r io.Reader) (map[string]int, error) {
+	result := make(map[string]int)
+	br := bufio.NewReader(r)
+	table := ""
+
+	for {
+		line, err := br.ReadString('\n')
+
+		if line != "" {
+			switch {
+			case table != "":
+				// Data rows of a PostgreSQL COPY statement end with "\.".
+				if strings.HasPrefix(line, "\\.") {
+					table = ""
+				} else {
+					result[table]++
+				}
+			case strings.HasPrefix(line, "INSERT INTO "):
+				if name := dumpTableName(line[len("INSERT INTO "):]); name != "" {
+					result[name] += strings.Count(line, "),(") + 1
+				}
+			case strings.HasPrefix(line, "COPY "):
+				if strings.Contains(line, "FROM stdin") {
+					table = dumpTableName(line[len("COPY "):])
+				}
+			}
+		}
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// dumpTableName returns the unquoted table name at the beginning of s, without schema prefix.
+func dumpTableName(s string) string {
+	if i := strings.IndexAny(s, " ("); i > 0 {
+		s = s[:i]
+	}
+
+	if i := strings.LastIndex(s, "."); i >= 0 {
+		s = s[i+1:]
+	}
+
+	return strings.Trim(s, "`\"")
+}
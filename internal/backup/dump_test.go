@@ -0,0 +1,47 @@
+package backup
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountRows(t *testing.T) {
+	t.Run("MySQL", func(t *testing.T) {
+		dump := "-- MySQL dump\n" +
+			"DROP TABLE IF EXISTS `photos`;\n" +
+			"INSERT INTO `photos` VALUES (1,'a'),(2,'b'),(3,'c');\n" +
+			"INSERT INTO `albums` VALUES (1,'x');\n"
+
+		rows, err := CountRows(strings.NewReader(dump))
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"photos": 3, "albums": 1}, rows)
+	})
+	t.Run("SQLite", func(t *testing.T) {
+		dump := "PRAGMA foreign_keys=OFF;\n" +
+			"CREATE TABLE `photos` (`id` integer);\n" +
+			"INSERT INTO photos VALUES(1);\n" +
+			"INSERT INTO photos VALUES(2);\n" +
+			"INSERT INTO \"labels\" VALUES(1,'cat')"
+
+		rows, err := CountRows(strings.NewReader(dump))
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"photos": 2, "labels": 1}, rows)
+	})
+	t.Run("PostgreSQL", func(t *testing.T) {
+		dump := "COPY public.photos (id, photo_uid) FROM stdin;\n" +
+			"1\tpr1\n" +
+			"2\tpr2\n" +
+			"\\.\n" +
+			"COPY public.albums (id) FROM stdin;\n" +
+			"\\.\n"
+
+		rows, err := CountRows(strings.NewReader(dump))
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"photos": 2}, rows)
+	})
+}
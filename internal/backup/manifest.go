@@ -0,0 +1,73 @@
+package backup
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ManifestVersion is the version of the archive manifest format.
+const ManifestVersion = 1
+
+// File represents a file in a backup archive.
+type File struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	Sha256 string `json:"sha256"`
+	Linked bool   `json:"linked,omitempty"`
+}
+
+// Manifest lists the files in a backup archive along with their SHA-256 checksums.
+type Manifest struct {
+	Version     int       `json:"version"`
+	CreatedAt   time.Time `json:"createdAt"`
+	AppVersion  string    `json:"appVersion,omitempty"`
+	Driver      string    `json:"driver,omitempty"`
+	Compression string    `json:"compression"`
+	Files       []File    `json:"files"`
+}
+
+// ReadManifest reads the manifest of the archive in dir.
+func ReadManifest(dir string) (m Manifest, err error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+
+	if err != nil {
+		return m, err
+	}
+
+	err = json.Unmarshal(data, &m)
+
+	return m, err
+}
+
+// Write saves the manifest in dir.
+func (m *Manifest) Write(dir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, ManifestFile), data, os.ModePerm)
+}
+
+// File returns the manifest entry with the specified name, if any.
+func (m *Manifest) File(name string) (File, bool) {
+	for _, f := range m.Files {
+		if f.Name == name {
+			return f, true
+		}
+	}
+
+	return File{}, false
+}
+
+// Size returns the total size of all files in bytes.
+func (m *Manifest) Size() (size int64) {
+	for _, f := range m.Files {
+		size += f.Size
+	}
+
+	return size
+}
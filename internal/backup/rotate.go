@@ -0,0 +1,96 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+)
+
+// Retention specifies how many daily, weekly, and monthly archive generations to keep.
+type Retention struct {
+	Daily   int
+	Weekly  int
+	Monthly int
+}
+
+// Enabled checks if outdated archives should be removed.
+func (r Retention) Enabled() bool {
+	return r.Daily > 0 || r.Weekly > 0 || r.Monthly > 0
+}
+
+// String returns the retention policy as string.
+func (r Retention) String() string {
+	return fmt.Sprintf("%d daily, %d weekly, %d monthly", r.Daily, r.Weekly, r.Monthly)
+}
+
+// Keep returns the archives to keep, newest first. The newest archive of each day, week, and month is kept
+// until the number of generations for that period has been reached. The newest archive is always kept.
+func (r Retention) Keep(archives Archives) (keep, remove Archives) {
+	if !r.Enabled() {
+		return archives, nil
+	}
+
+	days := make(map[string]bool)
+	weeks := make(map[string]bool)
+	months := make(map[string]bool)
+
+	for i, a := range archives {
+		t := a.CreatedAt
+		year, week := t.ISOWeek()
+
+		day := t.Format("2006-01-02")
+		weekKey := fmt.Sprintf("%04d-%02d", year, week)
+		month := t.Format("2006-01")
+
+		k := i == 0
+
+		if !days[day] && len(days) < r.Daily {
+			days[day] = true
+			k = true
+		}
+
+		if !weeks[weekKey] && len(weeks) < r.Weekly {
+			weeks[weekKey] = true
+			k = true
+		}
+
+		if !months[month] && len(months) < r.Monthly {
+			months[month] = true
+			k = true
+		}
+
+		if k {
+			keep = append(keep, a)
+		} else {
+			remove = append(remove, a)
+		}
+	}
+
+	return keep, remove
+}
+
+// Prune removes archives in dir that are no longer needed according to the retention policy.
+func Prune(dir string, r Retention) (removed Archives, err error) {
+	if !r.Enabled() {
+		return removed, nil
+	}
+
+	archives, err := List(dir)
+
+	if err != nil {
+		return removed, err
+	}
+
+	_, remove := r.Keep(archives)
+
+	for _, a := range remove {
+		if err = os.RemoveAll(a.Path); err != nil {
+			return removed, err
+		}
+
+		log.Debugf("backup: removed archive %s", a.Name)
+
+		removed = append(removed, a)
+	}
+
+	return removed, nil
+}
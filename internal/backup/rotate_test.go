@@ -0,0 +1,75 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetention_Keep(t *testing.T) {
+	var archives Archives
+
+	// One archive every 12 hours for 100 days, newest first.
+	start := time.Date(2022, 10, 8, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 200; i++ {
+		created := start.Add(time.Duration(-12*i) * time.Hour)
+		archives = append(archives, Archive{Name: created.Format(TimeFormat), CreatedAt: created})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		keep, remove := Retention{}.Keep(archives)
+		assert.Len(t, keep, 200)
+		assert.Len(t, remove, 0)
+	})
+	t.Run("Daily", func(t *testing.T) {
+		keep, remove := Retention{Daily: 7}.Keep(archives)
+		assert.Len(t, keep, 7)
+		assert.Len(t, remove, 193)
+		assert.Equal(t, "20221008-120000", keep[0].Name)
+		assert.Equal(t, "20221002-120000", keep[6].Name)
+	})
+	t.Run("DailyWeeklyMonthly", func(t *testing.T) {
+		keep, _ := Retention{Daily: 7, Weekly: 4, Monthly: 3}.Keep(archives)
+
+		names := make([]string, len(keep))
+
+		for i, a := range keep {
+			names[i] = a.Name
+		}
+
+		assert.Equal(t, []string{
+			"20221008-120000", "20221007-120000", "20221006-120000", "20221005-120000",
+			"20221004-120000", "20221003-120000", "20221002-120000", "20220930-120000",
+			"20220925-120000", "20220918-120000", "20220831-120000",
+		}, names)
+	})
+	t.Run("NewestAlwaysKept", func(t *testing.T) {
+		keep, _ := Retention{Monthly: 1}.Keep(archives[:2])
+		assert.Len(t, keep, 1)
+		assert.Equal(t, "20221008-120000", keep[0].Name)
+	})
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{"20221008-120000", "20221008-000000", "20221007-120000", "20221001-120000"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, name), os.ModePerm))
+	}
+
+	removed, err := Prune(dir, Retention{Daily: 2})
+
+	assert.NoError(t, err)
+	assert.Len(t, removed, 2)
+
+	archives, err := List(dir)
+
+	assert.NoError(t, err)
+	assert.Len(t, archives, 2)
+	assert.Equal(t, "20221008-120000", archives[0].Name)
+	assert.Equal(t, "20221007-120000", archives[1].Name)
+}
@@ -0,0 +1,71 @@
+package backup
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// Verify checks that the files listed in the archive manifest exist and match their size and SHA-256 checksum,
+// and that compressed files can be decompressed. Each problem found is logged as a warning.
+func Verify(a Archive) (m Manifest, err error) {
+	if m, err = a.Manifest(); err != nil {
+		return m, fmt.Errorf("cannot read manifest of %s (%s)", a.Name, err)
+	} else if m.Version < 1 || m.Version > ManifestVersion {
+		return m, fmt.Errorf("unsupported manifest version %d", m.Version)
+	} else if len(m.Files) == 0 {
+		return m, fmt.Errorf("manifest of %s is empty", a.Name)
+	}
+
+	failed := 0
+
+	for _, f := range m.Files {
+		if err := verifyFile(a, f); err != nil {
+			log.Warnf("backup: %s", err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return m, fmt.Errorf("%d of %d files in %s are missing or damaged", failed, len(m.Files), a.Name)
+	}
+
+	return m, nil
+}
+
+// verifyFile checks a single archive file.
+func verifyFile(a Archive, f File) error {
+	fileName := a.FileName(f.Name)
+
+	sum, size, err := Checksum(fileName)
+
+	if err != nil {
+		return fmt.Errorf("%s is missing or cannot be read", clean.Log(f.Name))
+	} else if size != f.Size {
+		return fmt.Errorf("%s has size %d instead of %d", clean.Log(f.Name), size, f.Size)
+	} else if sum != f.Sha256 {
+		return fmt.Errorf("%s has an invalid checksum", clean.Log(f.Name))
+	}
+
+	// Make sure compressed files can be decompressed.
+	if ParseCompression(strings.TrimPrefix(filepath.Ext(f.Name), ".")) == CompressNone {
+		return nil
+	}
+
+	r, err := OpenFile(fileName)
+
+	if err != nil {
+		return fmt.Errorf("%s cannot be decompressed (%s)", clean.Log(f.Name), err)
+	}
+
+	defer r.Close()
+
+	if _, err = io.Copy(io.Discard, r); err != nil {
+		return fmt.Errorf("%s cannot be decompressed (%s)", clean.Log(f.Name), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,318 @@
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Writer creates a new backup archive. Files are written to a temporary folder that is renamed
+// when the archive is committed, so that incomplete archives are never listed.
+type Writer struct {
+	Manifest    Manifest
+	dir         string
+	tmp         string
+	name        string
+	compression Compression
+	prev        *Archive
+	prevFiles   map[string]File
+}
+
+// NewWriter starts a new backup archive in dir. Unchanged files are hard-linked to the previous archive
+// instead of being copied again.
+func NewWriter(dir string, compression Compression, created time.Time) (*Writer, error) {
+	created = created.UTC()
+	name := created.Format(TimeFormat)
+
+	if fs.PathExists(filepath.Join(dir, name)) {
+		return nil, fmt.Errorf("backup archive %s already exists", name)
+	}
+
+	w := &Writer{
+		Manifest: Manifest{
+			Version:     ManifestVersion,
+			CreatedAt:   created,
+			Compression: compression.String(),
+			Files:       []File{},
+		},
+		dir:         dir,
+		tmp:         filepath.Join(dir, "."+name+".tmp"),
+		name:        name,
+		compression: compression,
+		prevFiles:   make(map[string]File),
+	}
+
+	// Remove leftovers of a failed attempt.
+	if err := os.RemoveAll(w.tmp); err != nil {
+		return nil, err
+	} else if err = os.MkdirAll(w.tmp, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	// Find previous archive for incremental backups.
+	if archives, err := List(dir); err != nil {
+		log.Warnf("backup: %s", err)
+	} else if len(archives) > 0 {
+		if m, err := archives[0].Manifest(); err != nil {
+			log.Warnf("backup: %s (read previous manifest)", err)
+		} else {
+			w.prev = &archives[0]
+
+			for _, f := range m.Files {
+				w.prevFiles[f.Name] = f
+			}
+		}
+	}
+
+	return w, nil
+}
+
+// Name returns the archive name.
+func (w *Writer) Name() string {
+	return w.name
+}
+
+// Create returns a writer for a new file in the archive, which is compressed as configured.
+// The compression extension is added to the file name, and the file is added to the manifest
+// when the returned writer is closed.
+func (w *Writer) Create(name string) (io.WriteCloser, error) {
+	name = cleanName(name) + w.compression.Ext()
+	fileName := filepath.Join(w.tmp, filepath.FromSlash(name))
+
+	if err := os.MkdirAll(filepath.Dir(fileName), os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Create(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fw := &fileWriter{w: w, name: name, file: f, hash: sha256.New()}
+
+	if fw.compressor, err = w.compression.NewWriter(io.MultiWriter(f, fw.hash, &fw.size)); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return fw, nil
+}
+
+// AddFile adds an existing file to the archive. It is hard-linked to the previous archive if it has not changed.
+func (w *Writer) AddFile(name, src string) error {
+	name = cleanName(name)
+	dest := filepath.Join(w.tmp, filepath.FromSlash(name))
+
+	sum, size, err := Checksum(src)
+
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(dest), os.ModePerm); err != nil {
+		return err
+	}
+
+	linked := false
+
+	// Link unchanged file?
+	if prev, ok := w.prevFiles[name]; ok && prev.Sha256 == sum && prev.Size == size {
+		if err = os.Link(w.prev.FileName(name), dest); err == nil {
+			linked = true
+		} else {
+			log.Debugf("backup: %s (link %s)", err, name)
+		}
+	}
+
+	if !linked {
+		if err = copyFile(src, dest); err != nil {
+			return err
+		}
+	}
+
+	w.Manifest.Files = append(w.Manifest.Files, File{Name: name, Size: size, Sha256: sum, Linked: linked})
+
+	return nil
+}
+
+// AddDir adds the files in src to the archive folder prefix. If extensions are passed,
+// only files with a matching extension are added.
+func (w *Writer) AddDir(prefix, src string, extensions ...string) (count int, err error) {
+	if !fs.PathExists(src) {
+		return 0, nil
+	}
+
+	err = filepath.Walk(src, func(fileName string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		} else if info.IsDir() {
+			if fileName != src && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		} else if !info.Mode().IsRegular() || strings.HasPrefix(info.Name(), ".") {
+			return nil
+		}
+
+		if len(extensions) > 0 && !hasExt(fileName, extensions) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(src, fileName)
+
+		if err != nil {
+			return err
+		}
+
+		if err = w.AddFile(path.Join(prefix, filepath.ToSlash(rel)), fileName); err != nil {
+			return err
+		}
+
+		count++
+
+		return nil
+	})
+
+	return count, err
+}
+
+// Commit saves the manifest and makes the archive available.
+func (w *Writer) Commit() (Archive, error) {
+	sort.Slice(w.Manifest.Files, func(i, j int) bool {
+		return w.Manifest.Files[i].Name < w.Manifest.Files[j].Name
+	})
+
+	if err := w.Manifest.Write(w.tmp); err != nil {
+		return Archive{}, err
+	}
+
+	dest := filepath.Join(w.dir, w.name)
+
+	if err := os.Rename(w.tmp, dest); err != nil {
+		return Archive{}, err
+	}
+
+	return Archive{Name: w.name, Path: dest, CreatedAt: w.Manifest.CreatedAt}, nil
+}
+
+// Abort removes the files written so far.
+func (w *Writer) Abort() {
+	if err := os.RemoveAll(w.tmp); err != nil {
+		log.Warnf("backup: %s (abort)", err)
+	}
+}
+
+// fileWriter writes a compressed file to the archive and adds it to the manifest when closed.
+type fileWriter struct {
+	w          *Writer
+	name       string
+	file       *os.File
+	compressor io.WriteCloser
+	hash       hash.Hash
+	size       counter
+}
+
+// Write implements io.Writer.
+func (fw *fileWriter) Write(p []byte) (int, error) {
+	return fw.compressor.Write(p)
+}
+
+// Close implements io.Closer.
+func (fw *fileWriter) Close() error {
+	if err := fw.compressor.Close(); err != nil {
+		fw.file.Close()
+		return err
+	} else if err = fw.file.Close(); err != nil {
+		return err
+	}
+
+	fw.w.Manifest.Files = append(fw.w.Manifest.Files, File{
+		Name:   fw.name,
+		Size:   int64(fw.size),
+		Sha256: hex.EncodeToString(fw.hash.Sum(nil)),
+	})
+
+	return nil
+}
+
+// counter counts the number of bytes written.
+type counter int64
+
+// Write implements io.Writer.
+func (c *counter) Write(p []byte) (int, error) {
+	*c += counter(len(p))
+	return len(p), nil
+}
+
+// Checksum returns the SHA-256 checksum and size of a file.
+func Checksum(fileName string) (sum string, size int64, err error) {
+	f, err := os.Open(fileName)
+
+	if err != nil {
+		return "", 0, err
+	}
+
+	defer f.Close()
+
+	h := sha256.New()
+
+	if size, err = io.Copy(h, f); err != nil {
+		return "", 0, err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// cleanName returns a relative archive file name with forward slashes.
+func cleanName(name string) string {
+	return strings.TrimLeft(path.Clean("/"+filepath.ToSlash(name)), "/")
+}
+
+// hasExt checks if the file name has one of the extensions, ignoring case.
+func hasExt(fileName string, extensions []string) bool {
+	ext := strings.ToLower(filepath.Ext(fileName))
+
+	for _, e := range extensions {
+		if ext == strings.ToLower(e) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// copyFile copies the contents of src to a new file dest.
+func copyFile(src, dest string) error {
+	in, err := os.Open(src)
+
+	if err != nil {
+		return err
+	}
+
+	defer in.Close()
+
+	out, err := os.Create(dest)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,185 @@
+package backup
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestArchive(t *testing.T, dir, src string, compression Compression, created time.Time) Archive {
+	w, err := NewWriter(dir, compression, created)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := w.Create(IndexFile)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = io.WriteString(f, "CREATE TABLE photos (id INTEGER);\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if count, err := w.AddDir(SidecarDir, src, ".yml"); err != nil {
+		t.Fatal(err)
+	} else {
+		assert.Equal(t, 2, count)
+	}
+
+	a, err := w.Commit()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return a
+}
+
+func TestWriter(t *testing.T) {
+	dir := t.TempDir()
+	src := t.TempDir()
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(src, "2022", ".hidden"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "2022", "a.yml"), []byte("Title: A\n"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "2022", "b.yml"), []byte("Title: B\n"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "2022", "b.jpg"), []byte("jpeg"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "2022", ".hidden", "c.yml"), []byte("Title: C\n"), os.ModePerm))
+
+	created := time.Date(2022, 10, 8, 10, 15, 0, 0, time.UTC)
+
+	t.Run("Gzip", func(t *testing.T) {
+		a := writeTestArchive(t, dir, src, CompressGzip, created)
+
+		assert.Equal(t, "20221008-101500", a.Name)
+
+		m, err := Verify(a)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "gzip", m.Compression)
+		assert.Len(t, m.Files, 3)
+
+		index, ok := m.File(IndexFile + ".gz")
+		assert.True(t, ok)
+		assert.Len(t, index.Sha256, 64)
+
+		r, err := OpenFile(a.FileName(index.Name))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := io.ReadAll(r)
+		assert.NoError(t, r.Close())
+		assert.NoError(t, err)
+		assert.Equal(t, "CREATE TABLE photos (id INTEGER);\n", string(data))
+
+		sidecar, ok := m.File("sidecar/2022/a.yml")
+		assert.True(t, ok)
+		assert.False(t, sidecar.Linked)
+	})
+	t.Run("Incremental", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(filepath.Join(src, "2022", "b.yml"), []byte("Title: Changed\n"), os.ModePerm))
+
+		a := writeTestArchive(t, dir, src, CompressZstd, created.Add(time.Hour))
+
+		m, err := Verify(a)
+
+		assert.NoError(t, err)
+
+		unchanged, _ := m.File("sidecar/2022/a.yml")
+		changed, _ := m.File("sidecar/2022/b.yml")
+
+		assert.True(t, unchanged.Linked)
+		assert.False(t, changed.Linked)
+
+		_, ok := m.File(IndexFile + ".zst")
+		assert.True(t, ok)
+	})
+	t.Run("Exists", func(t *testing.T) {
+		_, err := NewWriter(dir, CompressNone, created)
+		assert.Error(t, err)
+	})
+	t.Run("Abort", func(t *testing.T) {
+		w, err := NewWriter(dir, CompressNone, created.Add(2*time.Hour))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w.Abort()
+
+		archives, err := List(dir)
+
+		assert.NoError(t, err)
+		assert.Len(t, archives, 2)
+		assert.Equal(t, "20221008-111500", archives[0].Name)
+	})
+}
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+	src := t.TempDir()
+
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "a.yml"), []byte("Title: A\n"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "b.yml"), []byte("Title: B\n"), os.ModePerm))
+
+	a := writeTestArchive(t, dir, src, CompressNone, time.Now())
+
+	t.Run("Valid", func(t *testing.T) {
+		_, err := Verify(a)
+		assert.NoError(t, err)
+	})
+	t.Run("Damaged", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(a.FileName("sidecar/a.yml"), []byte("Title: X\n"), os.ModePerm))
+		assert.NoError(t, os.Remove(a.FileName("sidecar/b.yml")))
+
+		_, err := Verify(a)
+		assert.EqualError(t, err, "2 of 3 files in "+a.Name+" are missing or damaged")
+	})
+	t.Run("NoManifest", func(t *testing.T) {
+		assert.NoError(t, os.Remove(a.FileName(ManifestFile)))
+
+		_, err := Verify(a)
+		assert.Error(t, err)
+	})
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("Empty", func(t *testing.T) {
+		_, err := Find(dir, "")
+		assert.Error(t, err)
+	})
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "20221001-000000"), os.ModePerm))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "20221002-000000"), os.ModePerm))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, ".20221003-000000.tmp"), os.ModePerm))
+
+	t.Run("Newest", func(t *testing.T) {
+		a, err := Find(dir, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "20221002-000000", a.Name)
+	})
+	t.Run("Name", func(t *testing.T) {
+		a, err := Find(dir, "20221001-000000")
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "20221001-000000"), a.Path)
+		assert.Equal(t, 2022, a.CreatedAt.Year())
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := Find(dir, "20221003-000000")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dustin/go-humanize/english"
+	"github.com/urfave/cli"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/search"
+	"github.com/photoprism/photoprism/pkg/report"
+)
+
+// AuditCommand configures the command name, flags, and action.
+var AuditCommand = cli.Command{
+	Name:      "audit",
+	Usage:     "Searches the audit log for security-relevant events",
+	ArgsUsage: "[filter]",
+	Flags: append(report.CliFlags,
+		cli.StringFlag{
+			Name:  "level, l",
+			Usage: "only show events with this log `LEVEL`, e.g. warning|error",
+		},
+		cli.StringFlag{
+			Name:  "user, u",
+			Usage: "only show events of the user with this `NAME` or UID",
+		},
+		cli.StringFlag{
+			Name:  "ip",
+			Usage: "only show events from this client `IP` address",
+		},
+		cli.StringFlag{
+			Name:  "uid",
+			Usage: "only show events that affect the resource with this `UID`",
+		},
+		cli.IntFlag{
+			Name:  "count, n",
+			Usage: "maximum `NUMBER` of events to show",
+			Value: 100,
+		},
+		cli.IntFlag{
+			Name:  "offset, o",
+			Usage: "result `OFFSET` for paging",
+		},
+	),
+	Action: auditAction,
+}
+
+// auditAction displays audit log events that match the filter.
+func auditAction(ctx *cli.Context) error {
+	return CallWithDependencies(ctx, func(conf *config.Config) error {
+		cols := []string{"Time", "Level", "Client IP", "User", "Action", "Resource", "Outcome"}
+
+		f := form.SearchAudit{
+			Query:  strings.TrimSpace(strings.Join(ctx.Args(), " ")),
+			Level:  ctx.String("level"),
+			User:   ctx.String("user"),
+			IP:     ctx.String("ip"),
+			UID:    ctx.String("uid"),
+			Count:  ctx.Int("count"),
+			Offset: ctx.Int("offset"),
+		}
+
+		// Fetch events from database.
+		events, err := search.AuditEvents(f)
+
+		if err != nil {
+			return err
+		}
+
+		rows := make([][]string, len(events))
+
+		// Show log message.
+		log.Infof("found %s", english.Plural(len(events), "event", "events"))
+
+		// Display report.
+		for i, ev := range events {
+			rows[i] = []string{
+				report.DateTime(&ev.EventTime),
+				ev.EventLevel,
+				ev.ClientIP,
+				ev.UserName,
+				ev.Action,
+				ev.ResourceUID,
+				ev.Outcome,
+			}
+		}
+
+		result, err := report.RenderFormat(rows, cols, report.CliFormat(ctx))
+
+		fmt.Printf("\n%s\n", result)
+
+		return err
+	})
+}
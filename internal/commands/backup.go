@@ -3,17 +3,12 @@ package commands
 import (
 	"bytes"
 	"context"
-	"errors"
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
 	"time"
 
 	"github.com/dustin/go-humanize/english"
 	"github.com/urfave/cli"
 
-	"github.com/photoprism/photoprism/internal/config"
 	"github.com/photoprism/photoprism/internal/photoprism"
 	"github.com/photoprism/photoprism/pkg/clean"
 	"github.com/photoprism/photoprism/pkg/fs"
@@ -22,7 +17,8 @@ import (
 const backupDescription = "A user-defined SQL dump FILENAME or - for stdout can be passed as the first argument. " +
 	"The -i parameter can be omitted in this case.\n" +
 	"   Make sure to run the command with exec -T when using Docker to prevent log messages from being sent to stdout.\n" +
-	"   The index backup and album file paths are automatically detected if not specified explicitly."
+	"   The index backup and album file paths are automatically detected if not specified explicitly.\n" +
+	"   Use --archive to create a checksum-verified backup archive that also includes sidecar YAML files and settings."
 
 // BackupCommand configures the backup cli command.
 var BackupCommand = cli.Command{
@@ -32,6 +28,10 @@ var BackupCommand = cli.Command{
 	ArgsUsage:   "[filename.sql | -]",
 	Flags:       backupFlags,
 	Action:      backupAction,
+	Subcommands: []cli.Command{
+		BackupListCommand,
+		BackupVerifyCommand,
+	},
 }
 
 var backupFlags = []cli.Flag{
@@ -55,6 +55,10 @@ var backupFlags = []cli.Flag{
 		Name:  "index-path",
 		Usage: "custom index backup `PATH`",
 	},
+	cli.BoolFlag{
+		Name:  "archive",
+		Usage: "create a backup archive with a manifest and remove outdated archives",
+	},
 }
 
 // backupAction creates a database backup.
@@ -67,8 +71,9 @@ func backupAction(ctx *cli.Context) error {
 	albumsPath := ctx.String("albums-path")
 
 	backupAlbums := ctx.Bool("albums") || albumsPath != ""
+	backupArchive := ctx.Bool("archive")
 
-	if !backupIndex && !backupAlbums {
+	if !backupIndex && !backupAlbums && !backupArchive {
 		return cli.ShowSubcommandHelp(ctx)
 	}
 
@@ -86,85 +91,35 @@ func backupAction(ctx *cli.Context) error {
 	conf.RegisterDb()
 	defer conf.Shutdown()
 
+	if backupArchive {
+		if _, err = photoprism.BackupArchive(); err != nil {
+			return err
+		}
+	}
+
 	if backupIndex {
-		// If empty, use default backup file name.
 		if indexFileName == "" {
 			if !fs.PathWritable(indexPath) {
 				if indexPath != "" {
 					log.Warnf("custom index backup path not writable, using default")
 				}
 
-				indexPath = filepath.Join(conf.BackupPath(), conf.DatabaseDriver())
+				indexPath = photoprism.IndexBackupPath()
 			}
 
-			backupFile := time.Now().UTC().Format("2006-01-02") + ".sql"
-			indexFileName = filepath.Join(indexPath, backupFile)
+			indexFileName = photoprism.IndexBackupFile(indexPath)
 		}
 
-		if indexFileName != "-" {
-			if _, err := os.Stat(indexFileName); err == nil && !ctx.Bool("force") {
-				return fmt.Errorf("SQL dump already exists: %s", indexFileName)
-			} else if err == nil {
-				log.Warnf("replacing existing SQL dump")
-			}
-
-			// Create backup directory if not exists.
-			if dir := filepath.Dir(indexFileName); dir != "." {
-				if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-					return err
-				}
-			}
-
-			log.Infof("writing SQL dump to %s", clean.Log(indexFileName))
-		}
-
-		var cmd *exec.Cmd
-
-		switch conf.DatabaseDriver() {
-		case config.MySQL, config.MariaDB:
-			cmd = exec.Command(
-				conf.MysqldumpBin(),
-				"--protocol", "tcp",
-				"-h", conf.DatabaseHost(),
-				"-P", conf.DatabasePortString(),
-				"-u", conf.DatabaseUser(),
-				"-p"+conf.DatabasePassword(),
-				conf.DatabaseName(),
-			)
-		case config.SQLite3:
-			cmd = exec.Command(
-				conf.SqliteBin(),
-				conf.DatabaseDsn(),
-				".dump",
-			)
-		default:
-			return fmt.Errorf("unsupported database type: %s", conf.DatabaseDriver())
-		}
-
-		// Fetch command output.
-		var out bytes.Buffer
-		var stderr bytes.Buffer
-		cmd.Stdout = &out
-		cmd.Stderr = &stderr
-
-		// Log exact command for debugging in trace mode.
-		log.Trace(cmd.String())
+		if indexFileName == "-" {
+			var out bytes.Buffer
 
-		// Run backup command.
-		if err := cmd.Run(); err != nil {
-			if stderr.String() != "" {
-				return errors.New(stderr.String())
+			if err = photoprism.BackupIndex(&out); err != nil {
+				return err
 			}
-		}
 
-		if indexFileName == "-" {
-			// Return output via stdout.
 			fmt.Println(out.String())
-		} else {
-			// Write output to file.
-			if err := os.WriteFile(indexFileName, []byte(out.String()), os.ModePerm); err != nil {
-				return err
-			}
+		} else if err = photoprism.BackupIndexFile(indexFileName, ctx.Bool("force")); err != nil {
+			return err
 		}
 	}
 
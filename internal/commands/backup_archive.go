@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/dustin/go-humanize/english"
+	"github.com/urfave/cli"
+
+	"github.com/photoprism/photoprism/internal/backup"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/report"
+)
+
+// BackupListCommand configures the command name, flags, and action.
+var BackupListCommand = cli.Command{
+	Name:   "ls",
+	Usage:  "Lists backup archives",
+	Flags:  report.CliFlags,
+	Action: backupListAction,
+}
+
+// BackupVerifyCommand configures the command name, flags, and action.
+var BackupVerifyCommand = cli.Command{
+	Name:      "verify",
+	Usage:     "Verifies the checksums of a backup archive, e.g. before restoring it",
+	ArgsUsage: "[archive name or path]",
+	Action:    backupVerifyAction,
+}
+
+// backupListAction lists backup archives, newest first.
+func backupListAction(ctx *cli.Context) error {
+	return CallWithDependencies(ctx, func(conf *config.Config) error {
+		cols := []string{"Name", "Created At", "Files", "Size", "Compression", "Database"}
+
+		archives, err := backup.List(conf.BackupArchivePath())
+
+		if err != nil {
+			return err
+		}
+
+		rows := make([][]string, len(archives))
+
+		// Show log message.
+		log.Infof("found %s", english.Plural(len(archives), "backup archive", "backup archives"))
+
+		// Display report.
+		for i, a := range archives {
+			m, err := a.Manifest()
+
+			if err != nil {
+				log.Warnf("backup: %s", err)
+			}
+
+			rows[i] = []string{
+				a.Name,
+				report.DateTime(&a.CreatedAt),
+				fmt.Sprintf("%d", len(m.Files)),
+				humanize.Bytes(uint64(m.Size())),
+				m.Compression,
+				m.Driver,
+			}
+		}
+
+		result, err := report.RenderFormat(rows, cols, report.CliFormat(ctx))
+
+		fmt.Printf("\n%s\n", result)
+
+		return err
+	})
+}
+
+// backupVerifyAction verifies the checksums of a backup archive.
+func backupVerifyAction(ctx *cli.Context) error {
+	return CallWithDependencies(ctx, func(conf *config.Config) error {
+		a, err := backup.Find(conf.BackupArchivePath(), ctx.Args().First())
+
+		if err != nil {
+			return err
+		}
+
+		log.Infof("verifying backup archive %s", clean.Log(a.Name))
+
+		m, err := backup.Verify(a)
+
+		if err != nil {
+			return err
+		}
+
+		log.Infof("%s in %s verified", english.Plural(len(m.Files), "file", "files"), clean.Log(a.Name))
+
+		return nil
+	})
+}
@@ -47,6 +47,8 @@ var PhotoPrism = []cli.Command{
 	IndexCommand,
 	ImportCommand,
 	CopyCommand,
+	ExportCommand,
+	ImportArchiveCommand,
 	FacesCommand,
 	PlacesCommand,
 	PurgeCommand,
@@ -62,6 +64,10 @@ var PhotoPrism = []cli.Command{
 	ResetCommand,
 	PasswdCommand,
 	UsersCommand,
+	AuditCommand,
+	WebhooksCommand,
+	TrashCommand,
+	JobsCommand,
 	ShowCommand,
 	VersionCommand,
 	ShowConfigCommand,
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize/english"
+	"github.com/urfave/cli"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+const exportDescription = "Creates a portable library archive with originals, sidecar YAML files, labels, people, " +
+	"face markers, albums, and share links.\n" +
+	"   The archive is gzip compressed if the FILENAME ends with .gz or .tgz. Pass - to write it to stdout.\n" +
+	"   Use the import-archive command to restore it, e.g. in a new instance with a different database."
+
+// ExportCommand configures the library export cli command.
+var ExportCommand = cli.Command{
+	Name:        "export",
+	Description: exportDescription,
+	Usage:       "Exports the library to a portable archive",
+	ArgsUsage:   "[filename.tar | filename.tar.gz | -]",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "force, f",
+			Usage: "replace existing files",
+		},
+	},
+	Action: exportAction,
+}
+
+// exportAction creates a portable library archive.
+func exportAction(ctx *cli.Context) error {
+	fileName := strings.TrimSpace(ctx.Args().First())
+
+	if fileName == "" {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	return CallWithDependencies(ctx, func(conf *config.Config) (err error) {
+		start := time.Now()
+
+		var out io.Writer
+
+		if fileName == "-" {
+			out = os.Stdout
+		} else {
+			if fileName, err = filepath.Abs(fileName); err != nil {
+				return err
+			} else if fs.FileExists(fileName) && !ctx.Bool("force") {
+				return fmt.Errorf("%s already exists", clean.Log(fileName))
+			}
+
+			f, err := os.Create(fileName)
+
+			if err != nil {
+				return err
+			}
+
+			defer f.Close()
+
+			out = f
+		}
+
+		// Compress archive?
+		if ext := strings.ToLower(filepath.Ext(fileName)); ext == ".gz" || ext == ".tgz" {
+			zw := gzip.NewWriter(out)
+			defer zw.Close()
+			out = zw
+		}
+
+		if fileName != "-" {
+			log.Infof("exporting library to %s", clean.Log(fileName))
+		}
+
+		archive, err := photoprism.ExportLibrary(out)
+
+		if err != nil {
+			return err
+		}
+
+		log.Infof("exported %s in %s", english.Plural(len(archive.Photos), "photo", "photos"), time.Since(start))
+
+		return nil
+	})
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize/english"
+	"github.com/urfave/cli"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+const importArchiveDescription = "Restores a portable library archive created with the export command. " +
+	"Pass - to read it from stdin.\n" +
+	"   Existing files with the same content are skipped, conflicting files are renamed, and labels, people,\n" +
+	"   and albums are merged with existing ones of the same name. UIDs are preserved unless they are already in use."
+
+// ImportArchiveCommand configures the library import cli command.
+var ImportArchiveCommand = cli.Command{
+	Name:        "import-archive",
+	Description: importArchiveDescription,
+	Usage:       "Imports a portable library archive",
+	ArgsUsage:   "[filename.tar | filename.tar.gz | -]",
+	Action:      importArchiveAction,
+}
+
+// importArchiveAction restores a portable library archive.
+func importArchiveAction(ctx *cli.Context) error {
+	fileName := strings.TrimSpace(ctx.Args().First())
+
+	if fileName == "" {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	return CallWithDependencies(ctx, func(conf *config.Config) error {
+		if conf.ReadOnly() {
+			return config.ErrReadOnly
+		}
+
+		start := time.Now()
+
+		conf.InitDb()
+
+		in := os.Stdin
+
+		if fileName != "-" {
+			f, err := os.Open(fileName)
+
+			if err != nil {
+				return err
+			}
+
+			defer f.Close()
+
+			in = f
+
+			log.Infof("importing library from %s", clean.Log(fileName))
+		}
+
+		result, err := photoprism.NewLibraryImport(conf, get.Index()).Start(in)
+
+		if err != nil {
+			return err
+		}
+
+		log.Infof("imported %s with %s, %s skipped, %s renamed",
+			english.Plural(result.Photos, "photo", "photos"),
+			english.Plural(result.Files, "new file", "new files"),
+			english.Plural(result.Existing, "existing photo", "existing photos"),
+			english.Plural(result.Renamed, "photo", "photos"))
+
+		log.Infof("added %s, %s, %s, %s, and %s",
+			english.Plural(result.Labels, "label", "labels"),
+			english.Plural(result.Subjects, "person", "people"),
+			english.Plural(result.Markers, "marker", "markers"),
+			english.Plural(result.Albums, "album", "albums"),
+			english.Plural(result.Links, "share link", "share links"))
+
+		if result.Markers > 0 {
+			log.Infof("run 'photoprism faces index' to match the imported faces")
+		}
+
+		log.Infof("completed in %s", time.Since(start))
+
+		return nil
+	})
+}
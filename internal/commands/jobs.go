@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/dustin/go-humanize/english"
+	"github.com/urfave/cli"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/report"
+)
+
+// JobsCommand configures the command name, flags, and action.
+var JobsCommand = cli.Command{
+	Name:  "jobs",
+	Usage: "Lists queued, running, and finished background jobs",
+	Flags: append(report.CliFlags,
+		cli.StringFlag{
+			Name:  "state, s",
+			Usage: "only show jobs with this `STATE`, e.g. queued, running, completed, failed, or canceled",
+		},
+		cli.StringFlag{
+			Name:  "type, t",
+			Usage: "only show jobs of this `TYPE`, e.g. index, import, faces, or convert",
+		},
+		cli.IntFlag{
+			Name:  "count, n",
+			Usage: "maximum `NUMBER` of jobs to show",
+			Value: 100,
+		},
+		cli.IntFlag{
+			Name:  "offset, o",
+			Usage: "result `OFFSET`",
+		},
+	),
+	Action: jobsAction,
+}
+
+// jobsAction lists background jobs, newest first.
+func jobsAction(ctx *cli.Context) error {
+	return CallWithDependencies(ctx, func(conf *config.Config) error {
+		conf.MigrateDb(false, nil)
+
+		cols := []string{"UID", "Type", "State", "Progress", "Step", "Error", "Created At", "Started At", "Finished At"}
+
+		// Fetch jobs from database.
+		jobs, err := entity.FindJobs(clean.TypeLower(ctx.String("state")), clean.TypeLower(ctx.String("type")), ctx.Int("count"), ctx.Int("offset"))
+
+		if err != nil {
+			return err
+		}
+
+		rows := make([][]string, len(jobs))
+
+		// Show log message.
+		log.Infof("found %s", english.Plural(len(jobs), "job", "jobs"))
+
+		// Display report.
+		for i, m := range jobs {
+			rows[i] = []string{
+				m.JobUID,
+				m.JobType,
+				m.JobState,
+				fmt.Sprintf("%d", m.JobProgress),
+				m.JobStep,
+				m.JobError,
+				report.DateTime(&m.CreatedAt),
+				report.DateTime(m.StartedAt),
+				report.DateTime(m.FinishedAt),
+			}
+		}
+
+		result, err := report.RenderFormat(rows, cols, report.CliFormat(ctx))
+
+		fmt.Printf("\n%s\n", result)
+
+		return err
+	})
+}
@@ -1,12 +1,9 @@
 package commands
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"time"
@@ -14,8 +11,6 @@ import (
 	"github.com/dustin/go-humanize/english"
 	"github.com/urfave/cli"
 
-	"github.com/photoprism/photoprism/internal/config"
-	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/get"
 	"github.com/photoprism/photoprism/internal/photoprism"
 	"github.com/photoprism/photoprism/pkg/clean"
@@ -129,67 +124,16 @@ func restoreAction(ctx *cli.Context) error {
 
 		log.Infof("restoring index from %s", clean.Log(indexFileName))
 
-		sqlBackup, err := os.ReadFile(indexFileName)
+		f, err := os.Open(indexFileName)
 
 		if err != nil {
 			return err
 		}
 
-		tables := entity.Entities
-
-		var cmd *exec.Cmd
-
-		switch conf.DatabaseDriver() {
-		case config.MySQL, config.MariaDB:
-			cmd = exec.Command(
-				conf.MysqlBin(),
-				"--protocol", "tcp",
-				"-h", conf.DatabaseHost(),
-				"-P", conf.DatabasePortString(),
-				"-u", conf.DatabaseUser(),
-				"-p"+conf.DatabasePassword(),
-				"-f",
-				conf.DatabaseName(),
-			)
-		case config.SQLite3:
-			log.Infoln("dropping existing tables")
-			tables.Drop(conf.Db())
-			cmd = exec.Command(
-				conf.SqliteBin(),
-				conf.DatabaseDsn(),
-			)
-		default:
-			return fmt.Errorf("unsupported database type: %s", conf.DatabaseDriver())
-		}
-
-		// Fetch command output.
-		var out bytes.Buffer
-		var stderr bytes.Buffer
-		cmd.Stdout = &out
-		cmd.Stderr = &stderr
-
-		stdin, err := cmd.StdinPipe()
-
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		go func() {
-			defer stdin.Close()
-			if _, err := io.WriteString(stdin, string(sqlBackup)); err != nil {
-				log.Errorf(err.Error())
-			}
-		}()
+		defer f.Close()
 
-		// Log exact command for debugging in trace mode.
-		log.Trace(cmd.String())
-
-		// Run backup command.
-		if err := cmd.Run(); err != nil {
-			if stderr.String() != "" {
-				log.Debugln(stderr.String())
-				log.Warnf("index could not be restored completely")
-			}
+		if err = photoprism.RestoreIndex(f); err != nil {
+			log.Warnf("%s", err)
 		}
 	}
 
@@ -14,5 +14,7 @@ var ShowCommand = cli.Command{
 		ShowFiltersCommand,
 		ShowFormatsCommand,
 		ShowTagsCommand,
+		ShowRolesCommand,
+		ShowScheduleCommand,
 	},
 }
@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/pkg/report"
+)
+
+// ShowRolesCommand configures the command name, flags, and action.
+var ShowRolesCommand = cli.Command{
+	Name:   "roles",
+	Usage:  "Displays user roles and the permissions granted to them",
+	Flags:  report.CliFlags,
+	Action: showRolesAction,
+}
+
+// showRolesAction lists built-in and custom user roles with their permissions by resource.
+func showRolesAction(ctx *cli.Context) error {
+	conf := config.NewConfig(ctx)
+	conf.SetLogLevel(logrus.FatalLevel)
+
+	if err := conf.LoadRoles(); err != nil {
+		return err
+	}
+
+	rows, cols := conf.RolesReport()
+
+	result, err := report.RenderFormat(rows, cols, report.CliFormat(ctx))
+
+	fmt.Println(result)
+
+	return err
+}
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/pkg/report"
+)
+
+// ShowScheduleCommand configures the command name, flags, and action.
+var ShowScheduleCommand = cli.Command{
+	Name:   "schedule",
+	Usage:  "Displays scheduled jobs and when they run next",
+	Flags:  report.CliFlags,
+	Action: showScheduleAction,
+}
+
+// showScheduleAction lists jobs with their cron schedule and next run.
+func showScheduleAction(ctx *cli.Context) error {
+	conf := config.NewConfig(ctx)
+	conf.SetLogLevel(logrus.FatalLevel)
+
+	rows, cols := conf.ScheduleReport()
+
+	result, err := report.RenderFormat(rows, cols, report.CliFormat(ctx))
+
+	fmt.Println(result)
+
+	return err
+}
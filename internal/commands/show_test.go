@@ -94,3 +94,42 @@ func TestShowFormatsCommand(t *testing.T) {
 	assert.Contains(t, output, "Format")
 	assert.Contains(t, output, "Description")
 }
+
+func TestShowRolesCommand(t *testing.T) {
+	var err error
+
+	ctx := config.CliTestContext()
+
+	output := capture.Output(func() {
+		err = ShowRolesCommand.Run(ctx)
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the command output for plausibility.
+	assert.Contains(t, output, "Permissions")
+	assert.Contains(t, output, "admin")
+	assert.Contains(t, output, "visitor")
+	assert.Contains(t, output, "access_shared")
+}
+
+func TestShowScheduleCommand(t *testing.T) {
+	var err error
+
+	ctx := config.CliTestContext()
+
+	output := capture.Output(func() {
+		err = ShowScheduleCommand.Run(ctx)
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the command output for plausibility.
+	assert.Contains(t, output, "Next Run")
+	assert.Contains(t, output, "backup")
+	assert.Contains(t, output, "thumbs")
+}
@@ -13,10 +13,12 @@ import (
 	"github.com/urfave/cli"
 
 	"github.com/photoprism/photoprism/internal/auto"
+	"github.com/photoprism/photoprism/internal/mqtt"
 	"github.com/photoprism/photoprism/internal/mutex"
 	"github.com/photoprism/photoprism/internal/photoprism"
 	"github.com/photoprism/photoprism/internal/server"
 	"github.com/photoprism/photoprism/internal/session"
+	"github.com/photoprism/photoprism/internal/webhook"
 	"github.com/photoprism/photoprism/internal/workers"
 	"github.com/photoprism/photoprism/pkg/clean"
 	"github.com/photoprism/photoprism/pkg/fs"
@@ -119,7 +121,22 @@ func startAction(ctx *cli.Context) error {
 	// Start background workers.
 	session.Monitor(time.Hour)
 	workers.Start(conf)
+	workers.StartScheduler(conf)
+	workers.StartQueue(conf)
 	auto.Start(conf)
+	webhook.Start(conf)
+
+	// Publish events to MQTT broker?
+	var bridge *mqtt.Bridge
+
+	if conf.MQTTEnabled() {
+		bridge = conf.MQTT()
+
+		if err := bridge.Start(); err != nil {
+			log.Error(err)
+			bridge = nil
+		}
+	}
 
 	// Wait for signal to initiate server shutdown.
 	quit := make(chan os.Signal)
@@ -128,7 +145,14 @@ func startAction(ctx *cli.Context) error {
 	<-quit
 
 	// Stop all background activity.
+	if bridge != nil {
+		bridge.Stop()
+	}
+
+	webhook.Stop()
 	auto.Stop()
+	workers.StopQueue()
+	workers.StopScheduler()
 	workers.Stop()
 	session.Shutdown()
 	mutex.CancelAll()
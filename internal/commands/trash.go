@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/dustin/go-humanize/english"
+	"github.com/manifoldco/promptui"
+	"github.com/urfave/cli"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/report"
+)
+
+// TrashCommand registers the trash subcommands.
+var TrashCommand = cli.Command{
+	Name:  "trash",
+	Usage: "Lists, restores, and permanently removes deleted files",
+	Subcommands: []cli.Command{
+		TrashListCommand,
+		TrashRestoreCommand,
+		TrashEmptyCommand,
+	},
+}
+
+// TrashListCommand configures the command name, flags, and action.
+var TrashListCommand = cli.Command{
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Usage:   "Lists the files in the trash",
+	Flags: append(report.CliFlags, cli.IntFlag{
+		Name:  "count, n",
+		Usage: "maximum `NUMBER` of files to show",
+		Value: 100,
+	}),
+	Action: trashListAction,
+}
+
+// TrashRestoreCommand configures the command name, flags, and action.
+var TrashRestoreCommand = cli.Command{
+	Name:      "restore",
+	Usage:     "Restores deleted photos and files from the trash",
+	ArgsUsage: "[photo uid]...",
+	Action:    trashRestoreAction,
+}
+
+// TrashEmptyCommand configures the command name, flags, and action.
+var TrashEmptyCommand = cli.Command{
+	Name:  "empty",
+	Usage: "Permanently removes files from the trash",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "expired, e",
+			Usage: "only remove files whose retention period has expired",
+		},
+		cli.BoolFlag{
+			Name:  "yes, y",
+			Usage: "assume \"yes\" and run non-interactively",
+		},
+	},
+	Action: trashEmptyAction,
+}
+
+// trashListAction lists the files in the trash.
+func trashListAction(ctx *cli.Context) error {
+	return CallWithDependencies(ctx, func(conf *config.Config) error {
+		conf.InitDb()
+
+		cols := []string{"Photo UID", "File Name", "Size", "Deleted At", "Expires At"}
+
+		files, err := entity.ListTrashFiles(ctx.Int("count"), 0)
+
+		if err != nil {
+			return err
+		}
+
+		rows := make([][]string, len(files))
+
+		// Show log message.
+		log.Infof("found %s in trash", english.Plural(len(files), "file", "files"))
+
+		// Display report.
+		for i, m := range files {
+			rows[i] = []string{
+				m.PhotoUID,
+				m.FileName,
+				humanize.Bytes(uint64(m.FileSize)),
+				report.DateTime(&m.CreatedAt),
+				report.DateTime(&m.ExpiresAt),
+			}
+		}
+
+		result, err := report.RenderFormat(rows, cols, report.CliFormat(ctx))
+
+		fmt.Printf("\n%s\n", result)
+
+		return err
+	})
+}
+
+// trashRestoreAction restores deleted photos and files from the trash.
+func trashRestoreAction(ctx *cli.Context) error {
+	if !ctx.Args().Present() {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	return CallWithDependencies(ctx, func(conf *config.Config) error {
+		if conf.ReadOnly() {
+			return config.ErrReadOnly
+		}
+
+		conf.InitDb()
+
+		for _, photoUid := range ctx.Args() {
+			if n, err := photoprism.RestoreTrash(clean.UID(photoUid)); err != nil {
+				log.Errorf("trash: %s (restore %s)", err, clean.Log(photoUid))
+			} else {
+				log.Infof("restored %s with %s", clean.Log(photoUid), english.Plural(n, "file", "files"))
+			}
+		}
+
+		if err := entity.UpdateCounts(); err != nil {
+			log.Warnf("index: %s (update counts)", err)
+		}
+
+		return nil
+	})
+}
+
+// trashEmptyAction permanently removes files from the trash.
+func trashEmptyAction(ctx *cli.Context) error {
+	return CallWithDependencies(ctx, func(conf *config.Config) error {
+		if conf.ReadOnly() {
+			return config.ErrReadOnly
+		}
+
+		expired := ctx.Bool("expired")
+
+		if !expired && !ctx.Bool("yes") {
+			actionPrompt := promptui.Prompt{
+				Label:     "Permanently remove all files from the trash?",
+				IsConfirm: true,
+			}
+
+			if _, err := actionPrompt.Run(); err != nil {
+				return nil
+			}
+		}
+
+		start := time.Now()
+
+		conf.InitDb()
+
+		numPhotos, numFiles, err := photoprism.EmptyTrash(expired)
+
+		if err != nil {
+			return err
+		}
+
+		log.Infof("removed %s and %s in %s",
+			english.Plural(numPhotos, "photo", "photos"),
+			english.Plural(numFiles, "file", "files"),
+			time.Since(start))
+
+		return nil
+	})
+}
@@ -11,7 +11,7 @@ const (
 	UserNameUsage     = "full `NAME` for display in the interface"
 	UserEmailUsage    = "unique `EMAIL` address of the user"
 	UserPasswordUsage = "`PASSWORD` for authentication"
-	UserRoleUsage     = "user account `ROLE`"
+	UserRoleUsage     = "user account `ROLE`, e.g. admin, visitor, or a custom role defined in roles.yml"
 	UserAttrUsage     = "custom user account `ATTRIBUTES`"
 	UserAdminUsage    = "make user super admin with full access"
 	UserNoLoginUsage  = "disable login on the web interface"
@@ -31,6 +31,8 @@ var UsersCommand = cli.Command{
 		UsersModCommand,
 		UsersRemoveCommand,
 		UsersResetCommand,
+		UsersTokensCommand,
+		UsersPasscodeCommand,
 	},
 }
 
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/manifoldco/promptui"
+	"github.com/urfave/cli"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/rnd"
+)
+
+// UsersPasscodeCommand registers the two-factor authentication subcommands.
+var UsersPasscodeCommand = cli.Command{
+	Name:  "2fa",
+	Usage: "Two-factor authentication subcommands",
+	Subcommands: []cli.Command{
+		UsersPasscodeResetCommand,
+	},
+}
+
+// UsersPasscodeResetCommand configures the command name, flags, and action.
+var UsersPasscodeResetCommand = cli.Command{
+	Name:      "reset",
+	Usage:     "Disables two-factor authentication, e.g. if a user has lost their device and recovery codes",
+	ArgsUsage: "[username]",
+	Action:    usersPasscodeResetAction,
+}
+
+// usersPasscodeResetAction disables two-factor authentication for a user account.
+func usersPasscodeResetAction(ctx *cli.Context) error {
+	return CallWithDependencies(ctx, func(conf *config.Config) error {
+		conf.MigrateDb(false, nil)
+
+		id := clean.Username(ctx.Args().First())
+
+		// Name or UID provided?
+		if id == "" {
+			return cli.ShowSubcommandHelp(ctx)
+		}
+
+		// Find user record.
+		var m *entity.User
+
+		if rnd.IsUID(id, entity.UserUID) {
+			m = entity.FindUserByUID(id)
+		} else {
+			m = entity.FindUserByName(id)
+		}
+
+		if m == nil {
+			return fmt.Errorf("user %s not found", clean.LogQuote(id))
+		}
+
+		passcode := m.Passcode()
+
+		if passcode == nil {
+			log.Infof("two-factor authentication is not enabled for user %s", m.String())
+			return nil
+		}
+
+		actionPrompt := promptui.Prompt{
+			Label:     fmt.Sprintf("Disable two-factor authentication for user %s?", m.String()),
+			IsConfirm: true,
+		}
+
+		if _, err := actionPrompt.Run(); err == nil {
+			if err = passcode.Delete(); err != nil {
+				return err
+			} else {
+				log.Infof("two-factor authentication has been disabled for user %s", m.String())
+			}
+		} else {
+			log.Infof("two-factor authentication was not disabled for user %s", m.String())
+		}
+
+		return nil
+	})
+}
@@ -53,7 +53,7 @@ func usersResetAction(ctx *cli.Context) error {
 		db := conf.Db()
 
 		// Drop existing user management tables.
-		if err := db.DropTableIfExists(entity.User{}, entity.UserDetails{}, entity.UserSettings{}, entity.UserShare{}).Error; err != nil {
+		if err := db.DropTableIfExists(entity.User{}, entity.UserDetails{}, entity.UserSettings{}, entity.UserShare{}, entity.UserToken{}, entity.UserPasscode{}).Error; err != nil {
 			return err
 		}
 
@@ -77,6 +77,16 @@ func usersResetAction(ctx *cli.Context) error {
 			return err
 		}
 
+		// Re-create auth_users_tokens.
+		if err := db.CreateTable(entity.UserToken{}).Error; err != nil {
+			return err
+		}
+
+		// Re-create auth_users_passcodes.
+		if err := db.CreateTable(entity.UserPasscode{}).Error; err != nil {
+			return err
+		}
+
 		log.Infof("the user database has been recreated and is now in a clean state")
 
 		return nil
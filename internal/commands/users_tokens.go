@@ -0,0 +1,203 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dustin/go-humanize/english"
+	"github.com/manifoldco/promptui"
+	"github.com/urfave/cli"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/report"
+	"github.com/photoprism/photoprism/pkg/rnd"
+)
+
+// UsersTokensCommand registers the access token management subcommands.
+var UsersTokensCommand = cli.Command{
+	Name:  "tokens",
+	Usage: "Access token management subcommands",
+	Subcommands: []cli.Command{
+		UsersTokensListCommand,
+		UsersTokensAddCommand,
+		UsersTokensRemoveCommand,
+	},
+}
+
+// UsersTokensListCommand configures the command name, flags, and action.
+var UsersTokensListCommand = cli.Command{
+	Name:      "ls",
+	Usage:     "Lists the access tokens of a user",
+	ArgsUsage: "[username]",
+	Flags:     report.CliFlags,
+	Action:    usersTokensListAction,
+}
+
+// UsersTokensAddCommand configures the command name, flags, and action.
+var UsersTokensAddCommand = cli.Command{
+	Name:      "add",
+	Usage:     "Creates a new access token for a user",
+	ArgsUsage: "[username]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "name, n",
+			Usage: "token `NAME` e.g. of the app that will use it",
+		},
+		cli.StringFlag{
+			Name:  "scope, s",
+			Usage: "authorization `SCOPE` e.g. \"photos:view,download albums\" (default: unrestricted)",
+		},
+		cli.DurationFlag{
+			Name:  "expires, e",
+			Usage: "token lifetime `DURATION` e.g. 720h (default: never expires)",
+		},
+	},
+	Action: usersTokensAddAction,
+}
+
+// UsersTokensRemoveCommand configures the command name, flags, and action.
+var UsersTokensRemoveCommand = cli.Command{
+	Name:      "rm",
+	Usage:     "Revokes an access token",
+	ArgsUsage: "[token uid]",
+	Action:    usersTokensRemoveAction,
+}
+
+// usersTokensListAction lists the access tokens of a user.
+func usersTokensListAction(ctx *cli.Context) error {
+	return CallWithDependencies(ctx, func(conf *config.Config) error {
+		id := clean.Username(ctx.Args().First())
+
+		// Name or UID provided?
+		if id == "" {
+			return cli.ShowSubcommandHelp(ctx)
+		}
+
+		// Find user record.
+		var m *entity.User
+
+		if rnd.IsUID(id, entity.UserUID) {
+			m = entity.FindUserByUID(id)
+		} else {
+			m = entity.FindUserByName(id)
+		}
+
+		if m == nil {
+			return fmt.Errorf("user %s not found", clean.LogQuote(id))
+		}
+
+		cols := []string{"UID", "Name", "Scope", "Last Used", "Expires", "Created At"}
+
+		// Fetch tokens from database.
+		tokens := entity.FindUserTokens(m.UserUID)
+		rows := make([][]string, len(tokens))
+
+		// Show log message.
+		log.Infof("found %s", english.Plural(len(tokens), "token", "tokens"))
+
+		// Display report.
+		for i, t := range tokens {
+			rows[i] = []string{
+				t.TokenUID,
+				t.TokenName,
+				t.TokenScope,
+				report.DateTime(t.LastUsedAt),
+				report.DateTime(t.ExpiresAt),
+				t.CreatedAt.Format("2006-01-02 15:04:05"),
+			}
+		}
+
+		result, err := report.RenderFormat(rows, cols, report.CliFormat(ctx))
+
+		fmt.Printf("\n%s\n", result)
+
+		return err
+	})
+}
+
+// usersTokensAddAction creates a new access token for a user.
+func usersTokensAddAction(ctx *cli.Context) error {
+	return CallWithDependencies(ctx, func(conf *config.Config) error {
+		conf.MigrateDb(false, nil)
+
+		id := clean.Username(ctx.Args().First())
+
+		// Name or UID provided?
+		if id == "" {
+			return cli.ShowSubcommandHelp(ctx)
+		}
+
+		// Find user record.
+		var m *entity.User
+
+		if rnd.IsUID(id, entity.UserUID) {
+			m = entity.FindUserByUID(id)
+		} else {
+			m = entity.FindUserByName(id)
+		}
+
+		if m == nil {
+			return fmt.Errorf("user %s not found", clean.LogQuote(id))
+		}
+
+		var expires *time.Time
+
+		if d := ctx.Duration("expires"); d > 0 {
+			t := entity.TimeStamp().Add(d)
+			expires = &t
+		}
+
+		t, secret, err := entity.NewUserToken(m.UserUID, ctx.String("name"), ctx.String("scope"), expires)
+
+		if err != nil {
+			return err
+		} else if err = t.Create(); err != nil {
+			return err
+		}
+
+		log.Infof("token %s has been created for user %s", t.TokenUID, m.String())
+
+		fmt.Printf("\nPlease copy the access token now, it cannot be displayed again:\n\n%s\n\n", secret)
+
+		return nil
+	})
+}
+
+// usersTokensRemoveAction revokes an access token.
+func usersTokensRemoveAction(ctx *cli.Context) error {
+	return CallWithDependencies(ctx, func(conf *config.Config) error {
+		conf.MigrateDb(false, nil)
+
+		id := clean.UID(ctx.Args().First())
+
+		// Token UID provided?
+		if id == "" {
+			return cli.ShowSubcommandHelp(ctx)
+		}
+
+		m := entity.FindUserTokenByUID(id)
+
+		if m == nil {
+			return fmt.Errorf("token %s not found", clean.LogQuote(id))
+		}
+
+		actionPrompt := promptui.Prompt{
+			Label:     fmt.Sprintf("Revoke token %s (%s)?", m.String(), clean.Log(m.TokenName)),
+			IsConfirm: true,
+		}
+
+		if _, err := actionPrompt.Run(); err == nil {
+			if err = m.Delete(); err != nil {
+				return err
+			} else {
+				log.Infof("token %s has been revoked", m.String())
+			}
+		} else {
+			log.Infof("token %s was not revoked", m.String())
+		}
+
+		return nil
+	})
+}
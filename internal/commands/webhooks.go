@@ -0,0 +1,223 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dustin/go-humanize/english"
+	"github.com/manifoldco/promptui"
+	"github.com/urfave/cli"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/report"
+)
+
+// WebhooksCommand registers the webhook management subcommands.
+var WebhooksCommand = cli.Command{
+	Name:  "webhooks",
+	Usage: "Webhook management subcommands",
+	Subcommands: []cli.Command{
+		WebhooksListCommand,
+		WebhooksAddCommand,
+		WebhooksRemoveCommand,
+		WebhooksLogCommand,
+	},
+}
+
+// WebhooksListCommand configures the command name, flags, and action.
+var WebhooksListCommand = cli.Command{
+	Name:   "ls",
+	Usage:  "Lists registered webhooks",
+	Flags:  report.CliFlags,
+	Action: webhooksListAction,
+}
+
+// WebhooksAddCommand configures the command name, flags, and action.
+var WebhooksAddCommand = cli.Command{
+	Name:      "add",
+	Usage:     "Registers a new webhook",
+	ArgsUsage: "[url]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "topics",
+			Usage: "event `TOPICS` to deliver, e.g. \"photos.created albums.*\"",
+			Value: "photos.created",
+		},
+		cli.BoolFlag{
+			Name:  "disabled",
+			Usage: "register the webhook without enabling it",
+		},
+	},
+	Action: webhooksAddAction,
+}
+
+// WebhooksRemoveCommand configures the command name, flags, and action.
+var WebhooksRemoveCommand = cli.Command{
+	Name:      "rm",
+	Usage:     "Removes a webhook and its delivery log",
+	ArgsUsage: "[webhook uid]",
+	Action:    webhooksRemoveAction,
+}
+
+// WebhooksLogCommand configures the command name, flags, and action.
+var WebhooksLogCommand = cli.Command{
+	Name:      "log",
+	Usage:     "Shows the delivery log of a webhook",
+	ArgsUsage: "[webhook uid]",
+	Flags: append(report.CliFlags, cli.IntFlag{
+		Name:  "count, n",
+		Usage: "maximum `NUMBER` of deliveries to show",
+		Value: 100,
+	}),
+	Action: webhooksLogAction,
+}
+
+// webhooksListAction lists registered webhooks.
+func webhooksListAction(ctx *cli.Context) error {
+	return CallWithDependencies(ctx, func(conf *config.Config) error {
+		cols := []string{"UID", "URL", "Topics", "Enabled", "Created At"}
+
+		// Fetch webhooks from database.
+		hooks := entity.FindWebhooks(false)
+		rows := make([][]string, len(hooks))
+
+		// Show log message.
+		log.Infof("found %s", english.Plural(len(hooks), "webhook", "webhooks"))
+
+		// Display report.
+		for i, m := range hooks {
+			rows[i] = []string{
+				m.WebhookUID,
+				m.WebhookURL,
+				m.WebhookTopics,
+				report.Bool(m.WebhookEnabled, report.Yes, report.No),
+				report.DateTime(&m.CreatedAt),
+			}
+		}
+
+		result, err := report.RenderFormat(rows, cols, report.CliFormat(ctx))
+
+		fmt.Printf("\n%s\n", result)
+
+		return err
+	})
+}
+
+// webhooksAddAction registers a new webhook.
+func webhooksAddAction(ctx *cli.Context) error {
+	return CallWithDependencies(ctx, func(conf *config.Config) error {
+		conf.MigrateDb(false, nil)
+
+		endpoint := strings.TrimSpace(ctx.Args().First())
+
+		// URL provided?
+		if endpoint == "" {
+			return cli.ShowSubcommandHelp(ctx)
+		}
+
+		m, secret, err := entity.NewWebhook(endpoint, ctx.String("topics"), "")
+
+		if err != nil {
+			return err
+		}
+
+		m.WebhookEnabled = !ctx.Bool("disabled")
+
+		if err = m.Create(); err != nil {
+			return err
+		}
+
+		log.Infof("webhook %s has been registered", m.String())
+
+		fmt.Printf("\nPlease copy the signing secret now, it cannot be displayed again:\n\n%s\n\n", secret)
+
+		return nil
+	})
+}
+
+// webhooksRemoveAction removes a webhook.
+func webhooksRemoveAction(ctx *cli.Context) error {
+	return CallWithDependencies(ctx, func(conf *config.Config) error {
+		conf.MigrateDb(false, nil)
+
+		id := clean.UID(ctx.Args().First())
+
+		// Webhook UID provided?
+		if id == "" {
+			return cli.ShowSubcommandHelp(ctx)
+		}
+
+		m := entity.FindWebhook(id)
+
+		if m == nil {
+			return fmt.Errorf("webhook %s not found", clean.LogQuote(id))
+		}
+
+		actionPrompt := promptui.Prompt{
+			Label:     fmt.Sprintf("Remove webhook %s (%s)?", m.String(), clean.Log(m.WebhookURL)),
+			IsConfirm: true,
+		}
+
+		if _, err := actionPrompt.Run(); err == nil {
+			if err = m.Delete(); err != nil {
+				return err
+			} else {
+				log.Infof("webhook %s has been removed", m.String())
+			}
+		} else {
+			log.Infof("webhook %s was not removed", m.String())
+		}
+
+		return nil
+	})
+}
+
+// webhooksLogAction shows the delivery log of a webhook.
+func webhooksLogAction(ctx *cli.Context) error {
+	return CallWithDependencies(ctx, func(conf *config.Config) error {
+		id := clean.UID(ctx.Args().First())
+
+		// Webhook UID provided?
+		if id == "" {
+			return cli.ShowSubcommandHelp(ctx)
+		}
+
+		m := entity.FindWebhook(id)
+
+		if m == nil {
+			return fmt.Errorf("webhook %s not found", clean.LogQuote(id))
+		}
+
+		cols := []string{"Delivered At", "Delivery", "Topic", "Attempt", "Status", "Duration", "Error"}
+
+		// Fetch deliveries from database.
+		deliveries, err := entity.FindWebhookDeliveries(m.WebhookUID, ctx.Int("count"), 0)
+
+		if err != nil {
+			return err
+		}
+
+		rows := make([][]string, len(deliveries))
+
+		// Display report.
+		for i, d := range deliveries {
+			rows[i] = []string{
+				report.DateTime(&d.DeliveredAt),
+				d.DeliveryID,
+				d.EventTopic,
+				fmt.Sprintf("%d", d.Attempt),
+				fmt.Sprintf("%d", d.StatusCode),
+				fmt.Sprintf("%d ms", d.Duration),
+				d.Error,
+			}
+		}
+
+		result, err := report.RenderFormat(rows, cols, report.CliFormat(ctx))
+
+		fmt.Printf("\n%s\n", result)
+
+		return err
+	})
+}
@@ -55,9 +55,11 @@ type ClientConfig struct {
 	Demo            bool                `json:"demo"`
 	Sponsor         bool                `json:"sponsor"`
 	ReadOnly        bool                `json:"readonly"`
+	Maintenance     bool                `json:"maintenance"`
 	UploadNSFW      bool                `json:"uploadNSFW"`
 	Public          bool                `json:"public"`
 	AuthMode        string              `json:"authMode"`
+	AuthOIDC        bool                `json:"authOidc"`
 	Experimental    bool                `json:"experimental"`
 	AlbumCategories []string            `json:"albumCategories"`
 	Albums          entity.Albums       `json:"albums"`
@@ -187,6 +189,10 @@ func (c *Config) Flags() (flags []string) {
 		flags = append(flags, "readonly")
 	}
 
+	if c.Maintenance() {
+		flags = append(flags, "maintenance")
+	}
+
 	if !c.DisableSettings() {
 		flags = append(flags, "settings")
 	}
@@ -256,8 +262,10 @@ func (c *Config) ClientPublic() ClientConfig {
 		Demo:            c.Demo(),
 		Sponsor:         c.Sponsor(),
 		ReadOnly:        c.ReadOnly(),
+		Maintenance:     c.Maintenance(),
 		Public:          c.Public(),
 		AuthMode:        c.AuthMode(),
+		AuthOIDC:        c.OIDCEnabled(),
 		Experimental:    c.Experimental(),
 		Albums:          entity.Albums{},
 		Cameras:         entity.Cameras{},
@@ -332,9 +340,11 @@ func (c *Config) ClientShare() ClientConfig {
 		Demo:            c.Demo(),
 		Sponsor:         c.Sponsor(),
 		ReadOnly:        c.ReadOnly(),
+		Maintenance:     c.Maintenance(),
 		UploadNSFW:      c.UploadNSFW(),
 		Public:          c.Public(),
 		AuthMode:        c.AuthMode(),
+		AuthOIDC:        c.OIDCEnabled(),
 		Experimental:    c.Experimental(),
 		Albums:          entity.Albums{},
 		Cameras:         entity.Cameras{},
@@ -414,9 +424,11 @@ func (c *Config) ClientUser(withSettings bool) ClientConfig {
 		Demo:            c.Demo(),
 		Sponsor:         c.Sponsor(),
 		ReadOnly:        c.ReadOnly(),
+		Maintenance:     c.Maintenance(),
 		UploadNSFW:      c.UploadNSFW(),
 		Public:          c.Public(),
 		AuthMode:        c.AuthMode(),
+		AuthOIDC:        c.OIDCEnabled(),
 		Experimental:    c.Experimental(),
 		Albums:          entity.Albums{},
 		Cameras:         entity.Cameras{},
@@ -460,25 +472,25 @@ func (c *Config) ClientUser(withSettings bool) ClientConfig {
 	if hidePrivate {
 		c.Db().
 			Table("photos").
-			Select("SUM(photo_type = 'video' AND photo_quality > -1 AND photo_private = 0) AS videos, " +
-				"SUM(photo_type = 'live' AND photo_quality > -1 AND photo_private = 0) AS live, " +
-				"SUM(photo_quality = -1) AS hidden, SUM(photo_type IN ('image','raw','animated') AND photo_private = 0 AND photo_quality > -1) AS photos, " +
-				"SUM(photo_type IN ('image','raw','live','animated') AND photo_quality < 3 AND photo_quality > -1 AND photo_private = 0) AS review, " +
-				"SUM(photo_favorite = 1 AND photo_private = 0 AND photo_quality > -1) AS favorites, " +
-				"SUM(photo_private = 1 AND photo_quality > -1) AS private").
-			Where("photos.id NOT IN (SELECT photo_id FROM files WHERE file_primary = 1 AND (file_missing = 1 OR file_error <> ''))").
+			Select("SUM(CASE WHEN photo_type = 'video' AND photo_quality > -1 AND photo_private = FALSE THEN 1 ELSE 0 END) AS videos, " +
+				"SUM(CASE WHEN photo_type = 'live' AND photo_quality > -1 AND photo_private = FALSE THEN 1 ELSE 0 END) AS live, " +
+				"SUM(CASE WHEN photo_quality = -1 THEN 1 ELSE 0 END) AS hidden, SUM(CASE WHEN photo_type IN ('image','raw','animated') AND photo_private = FALSE AND photo_quality > -1 THEN 1 ELSE 0 END) AS photos, " +
+				"SUM(CASE WHEN photo_type IN ('image','raw','live','animated') AND photo_quality < 3 AND photo_quality > -1 AND photo_private = FALSE THEN 1 ELSE 0 END) AS review, " +
+				"SUM(CASE WHEN photo_favorite = TRUE AND photo_private = FALSE AND photo_quality > -1 THEN 1 ELSE 0 END) AS favorites, " +
+				"SUM(CASE WHEN photo_private = TRUE AND photo_quality > -1 THEN 1 ELSE 0 END) AS private").
+			Where("photos.id NOT IN (SELECT photo_id FROM files WHERE file_primary = TRUE AND (file_missing = TRUE OR file_error <> ''))").
 			Where("deleted_at IS NULL").
 			Take(&cfg.Count)
 	} else {
 		c.Db().
 			Table("photos").
-			Select("SUM(photo_type = 'video' AND photo_quality > -1) AS videos, " +
-				"SUM(photo_type = 'live' AND photo_quality > -1) AS live, " +
-				"SUM(photo_quality = -1) AS hidden, SUM(photo_type IN ('image','raw','animated') AND photo_quality > -1) AS photos, " +
-				"SUM(photo_type IN ('image','raw','live','animated') AND photo_quality < 3 AND photo_quality > -1) AS review, " +
-				"SUM(photo_favorite = 1 AND photo_quality > -1) AS favorites, " +
+			Select("SUM(CASE WHEN photo_type = 'video' AND photo_quality > -1 THEN 1 ELSE 0 END) AS videos, " +
+				"SUM(CASE WHEN photo_type = 'live' AND photo_quality > -1 THEN 1 ELSE 0 END) AS live, " +
+				"SUM(CASE WHEN photo_quality = -1 THEN 1 ELSE 0 END) AS hidden, SUM(CASE WHEN photo_type IN ('image','raw','animated') AND photo_quality > -1 THEN 1 ELSE 0 END) AS photos, " +
+				"SUM(CASE WHEN photo_type IN ('image','raw','live','animated') AND photo_quality < 3 AND photo_quality > -1 THEN 1 ELSE 0 END) AS review, " +
+				"SUM(CASE WHEN photo_favorite = TRUE AND photo_quality > -1 THEN 1 ELSE 0 END) AS favorites, " +
 				"0 AS private").
-			Where("photos.id NOT IN (SELECT photo_id FROM files WHERE file_primary = 1 AND (file_missing = 1 OR file_error <> ''))").
+			Where("photos.id NOT IN (SELECT photo_id FROM files WHERE file_primary = TRUE AND (file_missing = TRUE OR file_error <> ''))").
 			Where("deleted_at IS NULL").
 			Take(&cfg.Count)
 	}
@@ -490,19 +502,19 @@ func (c *Config) ClientUser(withSettings bool) ClientConfig {
 		Select("MAX(photo_count) AS label_max_photos, COUNT(*) AS labels").
 		Where("photo_count > 0").
 		Where("deleted_at IS NULL").
-		Where("(label_priority >= 0 OR label_favorite = 1)").
+		Where("(label_priority >= 0 OR label_favorite = TRUE)").
 		Take(&cfg.Count)
 
 	if hidePrivate {
 		c.Db().
 			Table("albums").
-			Select("SUM(album_type = ?) AS albums, SUM(album_type = ?) AS moments, SUM(album_type = ?) AS months, SUM(album_type = ?) AS states, SUM(album_type = ?) AS folders", entity.AlbumDefault, entity.AlbumMoment, entity.AlbumMonth, entity.AlbumState, entity.AlbumFolder).
-			Where("deleted_at IS NULL AND (albums.album_type <> 'folder' OR albums.album_path IN (SELECT photos.photo_path FROM photos WHERE photos.photo_private = 0 AND photos.deleted_at IS NULL))").
+			Select("SUM(CASE WHEN album_type = ? THEN 1 ELSE 0 END) AS albums, SUM(CASE WHEN album_type = ? THEN 1 ELSE 0 END) AS moments, SUM(CASE WHEN album_type = ? THEN 1 ELSE 0 END) AS months, SUM(CASE WHEN album_type = ? THEN 1 ELSE 0 END) AS states, SUM(CASE WHEN album_type = ? THEN 1 ELSE 0 END) AS folders", entity.AlbumDefault, entity.AlbumMoment, entity.AlbumMonth, entity.AlbumState, entity.AlbumFolder).
+			Where("deleted_at IS NULL AND (albums.album_type <> 'folder' OR albums.album_path IN (SELECT photos.photo_path FROM photos WHERE photos.photo_private = FALSE AND photos.deleted_at IS NULL))").
 			Take(&cfg.Count)
 	} else {
 		c.Db().
 			Table("albums").
-			Select("SUM(album_type = ?) AS albums, SUM(album_type = ?) AS moments, SUM(album_type = ?) AS months, SUM(album_type = ?) AS states, SUM(album_type = ?) AS folders", entity.AlbumDefault, entity.AlbumMoment, entity.AlbumMonth, entity.AlbumState, entity.AlbumFolder).
+			Select("SUM(CASE WHEN album_type = ? THEN 1 ELSE 0 END) AS albums, SUM(CASE WHEN album_type = ? THEN 1 ELSE 0 END) AS moments, SUM(CASE WHEN album_type = ? THEN 1 ELSE 0 END) AS months, SUM(CASE WHEN album_type = ? THEN 1 ELSE 0 END) AS states, SUM(CASE WHEN album_type = ? THEN 1 ELSE 0 END) AS folders", entity.AlbumDefault, entity.AlbumMoment, entity.AlbumMonth, entity.AlbumState, entity.AlbumFolder).
 			Where("deleted_at IS NULL AND (albums.album_type <> 'folder' OR albums.album_path IN (SELECT photos.photo_path FROM photos WHERE photos.deleted_at IS NULL))").
 			Take(&cfg.Count)
 	}
@@ -510,7 +522,7 @@ func (c *Config) ClientUser(withSettings bool) ClientConfig {
 	c.Db().
 		Table("files").
 		Select("COUNT(*) AS files").
-		Where("file_missing = 0 AND file_root = ?", entity.RootOriginals).
+		Where("file_missing = FALSE AND file_root = ?", entity.RootOriginals).
 		Take(&cfg.Count)
 
 	c.Db().
@@ -520,7 +532,7 @@ func (c *Config) ClientUser(withSettings bool) ClientConfig {
 
 	c.Db().
 		Table("places").
-		Select("SUM(photo_count > 0) AS places").
+		Select("SUM(CASE WHEN photo_count > 0 THEN 1 ELSE 0 END) AS places").
 		Where("id <> 'zz'").
 		Take(&cfg.Count)
 
@@ -544,7 +556,7 @@ func (c *Config) ClientUser(withSettings bool) ClientConfig {
 		Find(&cfg.Lenses)
 
 	c.Db().
-		Where("deleted_at IS NULL AND album_favorite = 1").
+		Where("deleted_at IS NULL AND album_favorite = TRUE").
 		Limit(20).Order("album_title").
 		Find(&cfg.Albums)
 
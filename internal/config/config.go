@@ -22,6 +22,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 
+	"github.com/photoprism/photoprism/internal/acl"
 	"github.com/photoprism/photoprism/internal/customize"
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/event"
@@ -47,11 +48,13 @@ type Config struct {
 	cliCtx   *cli.Context
 	options  *Options
 	settings *customize.Settings
+	roles    acl.CustomRoles
 	db       *gorm.DB
 	hub      *hub.Config
 	token    string
 	serial   string
 	env      string
+	maint    int32
 }
 
 func init() {
@@ -162,6 +165,16 @@ func (c *Config) Propagate() {
 	entity.DownloadToken.Set(c.DownloadToken(), entity.TokenConfig)
 	entity.CheckTokens = !c.Public()
 
+	// Set audit log retention period.
+	entity.AuditRetention = time.Duration(c.AuditRetention()) * 24 * time.Hour
+
+	// Authenticate password logins against a directory server if enabled.
+	if c.LDAPEnabled() {
+		entity.LdapAuth = c.LDAPAuth
+	} else {
+		entity.LdapAuth = nil
+	}
+
 	// Set face recognition parameters.
 	face.ScoreThreshold = c.FaceScore()
 	face.OverlapThreshold = c.FaceOverlap()
@@ -223,6 +236,10 @@ func (c *Config) Init() error {
 	// Set HTTP user agent.
 	places.UserAgent = c.UserAgent()
 
+	if err := c.LoadRoles(); err != nil {
+		return err
+	}
+
 	c.initSettings()
 	c.initHub()
 
@@ -520,9 +537,9 @@ func (c *Config) Experimental() bool {
 	return c.options.Experimental
 }
 
-// ReadOnly checks if photo directories are write protected.
+// ReadOnly checks if photo directories are write protected, e.g. while in maintenance mode.
 func (c *Config) ReadOnly() bool {
-	return c.options.ReadOnly
+	return c.options.ReadOnly || c.Maintenance()
 }
 
 // DetectNSFW checks if NSFW photos should be detected and flagged.
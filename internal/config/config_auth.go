@@ -62,6 +62,17 @@ func (c *Config) SessTimeout() int64 {
 	return c.options.SessTimeout
 }
 
+// AuditRetention returns the number of days until audit log events are deleted, or 0 to keep them.
+func (c *Config) AuditRetention() int {
+	if c.options.AuditRetention < 0 {
+		return 0
+	} else if c.options.AuditRetention == 0 {
+		return DefaultAuditRetention
+	}
+
+	return c.options.AuditRetention
+}
+
 // Public checks if app runs in public mode and requires no authentication.
 func (c *Config) Public() bool {
 	return c.AuthMode() == AuthModePublic
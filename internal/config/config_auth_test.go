@@ -64,6 +64,17 @@ func TestSessTimeout(t *testing.T) {
 	assert.Equal(t, DefaultSessTimeout, c.SessTimeout())
 }
 
+func TestAuditRetention(t *testing.T) {
+	c := NewConfig(CliTestContext())
+	assert.Equal(t, DefaultAuditRetention, c.AuditRetention())
+	c.options.AuditRetention = -1
+	assert.Equal(t, 0, c.AuditRetention())
+	c.options.AuditRetention = 30
+	assert.Equal(t, 30, c.AuditRetention())
+	c.options.AuditRetention = 0
+	assert.Equal(t, DefaultAuditRetention, c.AuditRetention())
+}
+
 func TestUtils_CheckPassword(t *testing.T) {
 	c := NewConfig(CliTestContext())
 
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"path/filepath"
+
+	"github.com/photoprism/photoprism/internal/backup"
+)
+
+// BackupArchivePath returns the path where backup archives are stored.
+func (c *Config) BackupArchivePath() string {
+	return filepath.Join(c.BackupPath(), "archive")
+}
+
+// BackupCompression returns the compression method for SQL dumps in backup archives.
+func (c *Config) BackupCompression() backup.Compression {
+	return backup.ParseCompression(c.options.BackupCompression)
+}
+
+// BackupRetention returns the number of daily, weekly, and monthly backup archives to keep.
+func (c *Config) BackupRetention() backup.Retention {
+	r := backup.Retention{
+		Daily:   c.options.BackupDaily,
+		Weekly:  c.options.BackupWeekly,
+		Monthly: c.options.BackupMonthly,
+	}
+
+	if r.Daily < 0 {
+		r.Daily = 0
+	}
+
+	if r.Weekly < 0 {
+		r.Weekly = 0
+	}
+
+	if r.Monthly < 0 {
+		r.Monthly = 0
+	}
+
+	return r
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/backup"
+)
+
+func TestConfig_BackupArchivePath(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, filepath.Join(c.BackupPath(), "archive"), c.BackupArchivePath())
+}
+
+func TestConfig_BackupCompression(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, backup.CompressNone, c.BackupCompression())
+	c.options.BackupCompression = "gzip"
+	assert.Equal(t, backup.CompressGzip, c.BackupCompression())
+	c.options.BackupCompression = "zstd"
+	assert.Equal(t, backup.CompressZstd, c.BackupCompression())
+	c.options.BackupCompression = ""
+}
+
+func TestConfig_BackupRetention(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.False(t, c.BackupRetention().Enabled())
+
+	c.options.BackupDaily = DefaultBackupDaily
+	c.options.BackupWeekly = -1
+	c.options.BackupMonthly = DefaultBackupMonthly
+
+	assert.Equal(t, backup.Retention{Daily: DefaultBackupDaily, Weekly: 0, Monthly: DefaultBackupMonthly}, c.BackupRetention())
+
+	c.options.BackupDaily = 0
+	c.options.BackupWeekly = 0
+	c.options.BackupMonthly = 0
+}
@@ -63,3 +63,18 @@ const DefaultSessMaxAge = UnixWeek * 2
 
 // DefaultSessTimeout is the default session timeout time in seconds.
 const DefaultSessTimeout = UnixWeek
+
+// DefaultAuditRetention is the default number of days until audit log events are deleted.
+const DefaultAuditRetention = 90
+
+// DefaultBackupDaily is the default number of daily backup archives to keep.
+const DefaultBackupDaily = 7
+
+// DefaultBackupWeekly is the default number of weekly backup archives to keep.
+const DefaultBackupWeekly = 4
+
+// DefaultBackupMonthly is the default number of monthly backup archives to keep.
+const DefaultBackupMonthly = 6
+
+// DefaultTrashRetention is the default number of days deleted files are kept in the trash.
+const DefaultTrashRetention = 30
@@ -14,6 +14,7 @@ import (
 
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/mysql"
+	_ "github.com/jinzhu/gorm/dialects/postgres"
 	_ "github.com/jinzhu/gorm/dialects/sqlite"
 
 	"github.com/photoprism/photoprism/internal/entity"
@@ -23,7 +24,6 @@ import (
 )
 
 // SQL Databases.
-// TODO: PostgresSQL support requires upgrading GORM, so generic column data types can be used.
 const (
 	MySQL    = "mysql"
 	MariaDB  = "mariadb"
@@ -42,6 +42,8 @@ func (c *Config) DatabaseDriver() string {
 	switch strings.ToLower(c.options.DatabaseDriver) {
 	case MySQL, MariaDB:
 		c.options.DatabaseDriver = MySQL
+	case Postgres, "postgresql", "pgsql":
+		c.options.DatabaseDriver = Postgres
 	case SQLite3, "sqlite", "sqllite", "test", "file", "":
 		c.options.DatabaseDriver = SQLite3
 	case "tidb":
@@ -82,10 +84,10 @@ func (c *Config) DatabaseDsn() string {
 		case Postgres:
 			return fmt.Sprintf(
 				"user=%s password=%s dbname=%s host=%s port=%d sslmode=disable TimeZone=UTC",
-				c.DatabaseUser(),
-				c.DatabasePassword(),
-				c.DatabaseName(),
-				c.DatabaseHost(),
+				dsnQuote(c.DatabaseUser()),
+				dsnQuote(c.DatabasePassword()),
+				dsnQuote(c.DatabaseName()),
+				dsnQuote(c.DatabaseHost()),
 				c.DatabasePort(),
 			)
 		case SQLite3:
@@ -107,6 +109,10 @@ func (c *Config) ParseDatabaseDsn() {
 
 	d := NewDSN(c.options.DatabaseDsn)
 
+	if d.Server == "" && d.Name == "" {
+		return
+	}
+
 	c.options.DatabaseName = d.Name
 	c.options.DatabaseServer = d.Server
 	c.options.DatabaseUser = d.User
@@ -141,7 +147,11 @@ func (c *Config) DatabaseHost() string {
 
 // DatabasePort the database server port.
 func (c *Config) DatabasePort() int {
-	const defaultPort = 3306
+	defaultPort := 3306
+
+	if c.DatabaseDriver() == Postgres {
+		defaultPort = 5432
+	}
 
 	if server := c.DatabaseServer(); server == "" {
 		return 0
@@ -262,7 +272,7 @@ func (c *Config) SetDbOptions() {
 	case MySQL, MariaDB:
 		c.Db().Set("gorm:table_options", "ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci")
 	case Postgres:
-		// Ignore for now.
+		// Not required as the database encoding is set when creating it.
 	case SQLite3:
 		// Not required as unicode is default.
 	}
@@ -282,6 +292,15 @@ func (c *Config) InitDb() {
 
 // MigrateDb initializes the database and migrates the schema if needed.
 func (c *Config) MigrateDb(runFailed bool, ids []string) {
+	c.MigrateSchema(runFailed, ids)
+
+	go entity.Error{}.LogEvents()
+	go entity.AuditEvent{}.LogEvents()
+}
+
+// MigrateSchema migrates the database schema and initializes the admin account if needed,
+// e.g. after the index has been restored from a backup.
+func (c *Config) MigrateSchema(runFailed bool, ids []string) {
 	entity.Admin.UserName = c.AdminUser()
 	entity.InitDb(migrate.Opt(runFailed, ids))
 
@@ -291,8 +310,6 @@ func (c *Config) MigrateDb(runFailed bool, ids []string) {
 	} else {
 		entity.Admin.InitAccount(c.AdminUser(), c.AdminPassword())
 	}
-
-	go entity.Error{}.LogEvents()
 }
 
 // InitTestDb drops all tables in the currently configured database and re-creates them.
@@ -370,6 +387,19 @@ func (c *Config) connectDb() error {
 		} else if txt.UInt(v[1]) <= 5 && txt.UInt(v[2]) <= 12 {
 			log.Errorf("config: MariaDB %s is not supported, please upgrade to MariaDB 10.5.12 or later (https://docs.photoprism.app/getting-started/#databases)", res.Value)
 		}
+	case Postgres:
+		type Res struct {
+			Version string `gorm:"column:server_version_num;"`
+		}
+		var res Res
+		if err = db.Raw("SHOW server_version_num").Scan(&res).Error; err != nil {
+			return err
+		} else if v := txt.UInt(res.Version); v == 0 {
+			log.Warnf("config: unknown database server version")
+		} else if v < 120000 {
+			err = fmt.Errorf("config: PostgreSQL %d.%d is not supported, please upgrade to PostgreSQL 12 or later (https://docs.photoprism.app/getting-started/#databases)", v/10000, v%10000)
+			return err
+		}
 	}
 
 	c.db = db
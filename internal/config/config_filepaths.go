@@ -223,6 +223,15 @@ func (c *Config) SettingsYaml() string {
 	return filepath.Join(c.ConfigPath(), "settings.yml")
 }
 
+// RolesYaml returns the custom roles YAML filename.
+func (c *Config) RolesYaml() string {
+	if c.options.RolesYaml == "" {
+		return filepath.Join(c.ConfigPath(), "roles.yml")
+	}
+
+	return fs.Abs(c.options.RolesYaml)
+}
+
 // PIDFilename returns the filename for storing the server process id (pid).
 func (c *Config) PIDFilename() string {
 	if c.options.PIDFilename == "" {
@@ -499,6 +508,16 @@ func (c *Config) MysqldumpBin() string {
 	return findBin("", "mysqldump")
 }
 
+// PsqlBin returns the psql executable file name.
+func (c *Config) PsqlBin() string {
+	return findBin("", "psql")
+}
+
+// PgDumpBin returns the pg_dump executable file name.
+func (c *Config) PgDumpBin() string {
+	return findBin("", "pg_dump")
+}
+
 // SqliteBin returns the sqlite executable file name.
 func (c *Config) SqliteBin() string {
 	return findBin("", "sqlite3")
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/ldap"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// AuthProviderLDAP authenticates password logins against a directory server.
+const AuthProviderLDAP = entity.AuthProviderLDAP
+
+// AuthProvider returns the external authentication provider for password logins, if any.
+func (c *Config) AuthProvider() string {
+	return clean.TypeLower(c.options.AuthProvider)
+}
+
+// LDAPUri returns the LDAP server URI.
+func (c *Config) LDAPUri() string {
+	return strings.TrimSpace(c.options.LDAPUri)
+}
+
+// LDAPInsecure checks if the LDAP server certificate should not be verified.
+func (c *Config) LDAPInsecure() bool {
+	return c.options.LDAPInsecure
+}
+
+// LDAPBindDN returns the distinguished name of the service account used to search users.
+func (c *Config) LDAPBindDN() string {
+	return strings.TrimSpace(c.options.LDAPBindDN)
+}
+
+// LDAPBindPassword returns the service account password.
+func (c *Config) LDAPBindPassword() string {
+	return c.options.LDAPBindPassword
+}
+
+// LDAPBaseDN returns the distinguished name below which users and groups are searched.
+func (c *Config) LDAPBaseDN() string {
+	return strings.TrimSpace(c.options.LDAPBaseDN)
+}
+
+// LDAPUserFilter returns the search filter for user entries.
+func (c *Config) LDAPUserFilter() string {
+	if s := strings.TrimSpace(c.options.LDAPUserFilter); s != "" {
+		return s
+	}
+
+	return ldap.DefaultUserFilter
+}
+
+// LDAPAdminGroup returns the group whose members get the admin role.
+func (c *Config) LDAPAdminGroup() string {
+	return strings.TrimSpace(c.options.LDAPAdminGroup)
+}
+
+// LDAPUserGroup returns the group whose members get the role returned by LDAPUserRole.
+func (c *Config) LDAPUserGroup() string {
+	return strings.TrimSpace(c.options.LDAPUserGroup)
+}
+
+// LDAPUserRole returns the role of user group members. Since there is no built-in role
+// between admin and visitor, it should be a custom role with the desired permissions.
+func (c *Config) LDAPUserRole() acl.Role {
+	if role := acl.ValidRoles[clean.Role(c.options.LDAPUserRole)]; role != acl.RoleUnknown {
+		return role
+	}
+
+	return acl.RoleVisitor
+}
+
+// LDAPGuestGroup returns the group whose members get the visitor role.
+func (c *Config) LDAPGuestGroup() string {
+	return strings.TrimSpace(c.options.LDAPGuestGroup)
+}
+
+// LDAPEnabled checks if password logins are authenticated against a directory server.
+func (c *Config) LDAPEnabled() bool {
+	return !c.Public() && c.AuthProvider() == AuthProviderLDAP && c.LDAPUri() != "" && c.LDAPBaseDN() != ""
+}
+
+// LDAP returns the directory server configuration.
+func (c *Config) LDAP() *ldap.Provider {
+	return &ldap.Provider{
+		Uri:          c.LDAPUri(),
+		BindDN:       c.LDAPBindDN(),
+		BindPassword: c.LDAPBindPassword(),
+		BaseDN:       c.LDAPBaseDN(),
+		UserFilter:   c.LDAPUserFilter(),
+		Insecure:     c.LDAPInsecure(),
+	}
+}
+
+// LDAPRole returns the user role for the groups, or acl.RoleUnknown if none matches.
+// Groups match by distinguished name or common name. Members of the admin group get
+// the admin role, members of the user group the configured user role, and members
+// of the guest group the visitor role, in this order of precedence.
+func (c *Config) LDAPRole(groups []string) acl.Role {
+	admin, user, guest := c.LDAPAdminGroup(), c.LDAPUserGroup(), c.LDAPGuestGroup()
+
+	// All users are admins if no group is configured.
+	if admin == "" && user == "" && guest == "" {
+		return acl.RoleAdmin
+	}
+
+	result := acl.RoleUnknown
+
+	for _, g := range groups {
+		if ldapGroupMatch(g, admin) {
+			return acl.RoleAdmin
+		} else if ldapGroupMatch(g, user) {
+			result = c.LDAPUserRole()
+		} else if ldapGroupMatch(g, guest) && result == acl.RoleUnknown {
+			result = acl.RoleVisitor
+		}
+	}
+
+	return result
+}
+
+// LDAPAuth authenticates a user against the directory server and returns the account details.
+func (c *Config) LDAPAuth(name, password string) (*entity.ProviderAccount, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ldap.Timeout)
+	defer cancel()
+
+	account, err := c.LDAP().Authenticate(ctx, name, password)
+
+	if err != nil {
+		return nil, err
+	}
+
+	role := c.LDAPRole(account.Groups)
+
+	if role == acl.RoleUnknown {
+		return nil, fmt.Errorf("no matching role")
+	}
+
+	return &entity.ProviderAccount{
+		AuthID:      account.DN,
+		UserName:    account.Username,
+		UserEmail:   account.Email,
+		DisplayName: account.Name,
+		UserRole:    role.String(),
+	}, nil
+}
+
+// ldapGroupMatch checks if the group has the configured distinguished name or common name.
+func ldapGroupMatch(group, configured string) bool {
+	if group == "" || configured == "" {
+		return false
+	} else if strings.EqualFold(group, configured) {
+		return true
+	}
+
+	// Compare common name, e.g. "admins" matches "cn=admins,ou=groups,dc=example,dc=com".
+	rdn, _, _ := strings.Cut(group, ",")
+
+	if k, v, found := strings.Cut(rdn, "="); found && strings.EqualFold(strings.TrimSpace(k), "cn") {
+		return strings.EqualFold(strings.TrimSpace(v), configured)
+	}
+
+	return false
+}
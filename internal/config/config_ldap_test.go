@@ -0,0 +1,123 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/ldap"
+)
+
+func TestConfig_LDAPEnabled(t *testing.T) {
+	c := NewConfig(CliTestContext())
+	c.options.Public = false
+
+	assert.False(t, c.LDAPEnabled())
+	c.options.AuthProvider = " LDAP "
+	assert.Equal(t, AuthProviderLDAP, c.AuthProvider())
+	assert.False(t, c.LDAPEnabled())
+	c.options.LDAPUri = "ldaps://ldap.example.com"
+	c.options.LDAPBaseDN = "dc=example,dc=com"
+	assert.True(t, c.LDAPEnabled())
+	c.options.Public = true
+	assert.False(t, c.LDAPEnabled())
+}
+
+func TestConfig_LDAPUserFilter(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	c.options.LDAPUserFilter = ""
+	assert.Equal(t, "(uid=%s)", c.LDAPUserFilter())
+	c.options.LDAPUserFilter = "(mail=%s)"
+	assert.Equal(t, "(mail=%s)", c.LDAP().UserFilter)
+}
+
+func TestConfig_LDAPRole(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	groups := []string{"cn=family,ou=groups,dc=example,dc=com", "cn=Photo Admins,ou=groups,dc=example,dc=com"}
+
+	c.options.LDAPAdminGroup = ""
+	c.options.LDAPGuestGroup = ""
+	assert.Equal(t, acl.RoleAdmin, c.LDAPRole(nil))
+
+	c.options.LDAPAdminGroup = "photo admins"
+	c.options.LDAPGuestGroup = "cn=family,ou=groups,dc=example,dc=com"
+	assert.Equal(t, acl.RoleAdmin, c.LDAPRole(groups))
+	assert.Equal(t, acl.RoleVisitor, c.LDAPRole(groups[:1]))
+	assert.Equal(t, acl.RoleUnknown, c.LDAPRole([]string{"cn=staff,ou=groups,dc=example,dc=com"}))
+	assert.Equal(t, acl.RoleUnknown, c.LDAPRole(nil))
+
+	c.options.LDAPUserGroup = "staff"
+	c.options.LDAPUserRole = ""
+	assert.Equal(t, acl.RoleVisitor, c.LDAPRole([]string{"cn=staff,ou=groups,dc=example,dc=com"}))
+	c.options.LDAPUserRole = "admin"
+	assert.Equal(t, acl.RoleAdmin, c.LDAPRole([]string{"cn=family,ou=groups,dc=example,dc=com", "cn=staff,ou=groups,dc=example,dc=com"}))
+	assert.Equal(t, acl.RoleVisitor, c.LDAPRole(groups[:1]))
+
+	c.options.LDAPAdminGroup = ""
+	c.options.LDAPGuestGroup = ""
+	assert.Equal(t, acl.RoleUnknown, c.LDAPRole(nil))
+}
+
+func TestConfig_LDAPUserRole(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	c.options.LDAPUserRole = ""
+	assert.Equal(t, acl.RoleVisitor, c.LDAPUserRole())
+	c.options.LDAPUserRole = " Admin "
+	assert.Equal(t, acl.RoleAdmin, c.LDAPUserRole())
+	c.options.LDAPUserRole = "unknown"
+	assert.Equal(t, acl.RoleVisitor, c.LDAPUserRole())
+}
+
+func TestConfig_LDAPAuth(t *testing.T) {
+	srv := ldap.NewMockServer([]*ldap.Entry{
+		ldap.NewEntry("uid=jane,ou=people,dc=example,dc=com", map[string][]string{
+			"uid":         {"jane"},
+			"mail":        {"jane@example.com"},
+			"displayName": {"Jane Doe"},
+			"memberOf":    {"cn=admins,ou=groups,dc=example,dc=com"},
+		}),
+		ldap.NewEntry("uid=john,ou=people,dc=example,dc=com", map[string][]string{
+			"uid": {"john"},
+		}),
+	}, map[string]string{
+		"uid=jane,ou=people,dc=example,dc=com": "Jane123!",
+		"uid=john,ou=people,dc=example,dc=com": "John123!",
+	})
+
+	defer srv.Close()
+
+	c := NewConfig(CliTestContext())
+	c.options.LDAPUri = srv.Uri()
+	c.options.LDAPInsecure = true
+	c.options.LDAPBaseDN = "dc=example,dc=com"
+	c.options.LDAPAdminGroup = "admins"
+
+	t.Run("Success", func(t *testing.T) {
+		account, err := c.LDAPAuth("jane", "Jane123!")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, entity.ProviderAccount{
+			AuthID:      "uid=jane,ou=people,dc=example,dc=com",
+			UserName:    "jane",
+			UserEmail:   "jane@example.com",
+			DisplayName: "Jane Doe",
+			UserRole:    "admin",
+		}, *account)
+	})
+	t.Run("NoMatchingRole", func(t *testing.T) {
+		_, err := c.LDAPAuth("john", "John123!")
+		assert.EqualError(t, err, "no matching role")
+	})
+	t.Run("InvalidPassword", func(t *testing.T) {
+		_, err := c.LDAPAuth("jane", "John123!")
+		assert.Equal(t, ldap.ErrInvalidCredentials, err)
+	})
+}
@@ -0,0 +1,24 @@
+package config
+
+import "sync/atomic"
+
+// Maintenance checks if maintenance mode is enabled, e.g. while a backup is restored.
+func (c *Config) Maintenance() bool {
+	return atomic.LoadInt32(&c.maint) == 1
+}
+
+// SetMaintenance enables or disables maintenance mode. While enabled, the configuration is read-only
+// and requests that modify data are rejected. Since features that require write access are disabled
+// in the meantime, the settings are reloaded from their YAML file when maintenance mode ends.
+func (c *Config) SetMaintenance(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&c.maint, 1)
+		return
+	} else if !atomic.CompareAndSwapInt32(&c.maint, 1, 0) || c.settings == nil {
+		return
+	}
+
+	if err := c.settings.Load(c.SettingsYaml()); err != nil {
+		log.Debugf("settings: %s", err)
+	}
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Maintenance(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.False(t, c.Maintenance())
+	assert.False(t, c.ReadOnly())
+
+	c.SetMaintenance(true)
+
+	assert.True(t, c.Maintenance())
+	assert.True(t, c.ReadOnly())
+	assert.True(t, c.ClientUser(false).Maintenance)
+
+	c.SetMaintenance(false)
+
+	assert.False(t, c.Maintenance())
+	assert.False(t, c.ReadOnly())
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/photoprism/photoprism/internal/mqtt"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// MQTTUri returns the MQTT broker URI.
+func (c *Config) MQTTUri() string {
+	return strings.TrimSpace(c.options.MQTTUri)
+}
+
+// MQTTClient returns the MQTT client id.
+func (c *Config) MQTTClient() string {
+	if s := strings.TrimSpace(c.options.MQTTClient); s != "" {
+		return s
+	}
+
+	return mqtt.DefaultClientID
+}
+
+// MQTTUser returns the MQTT broker username.
+func (c *Config) MQTTUser() string {
+	return strings.TrimSpace(c.options.MQTTUser)
+}
+
+// MQTTPassword returns the MQTT broker password.
+func (c *Config) MQTTPassword() string {
+	return c.options.MQTTPassword
+}
+
+// MQTTPrefix returns the MQTT topic prefix.
+func (c *Config) MQTTPrefix() string {
+	if s := strings.Trim(strings.TrimSpace(c.options.MQTTPrefix), "/"); s != "" {
+		return s
+	}
+
+	return mqtt.DefaultPrefix
+}
+
+// MQTTQoS returns the MQTT quality of service level.
+func (c *Config) MQTTQoS() byte {
+	switch {
+	case c.options.MQTTQoS <= 0:
+		return 0
+	case c.options.MQTTQoS >= 2:
+		return 2
+	default:
+		return byte(c.options.MQTTQoS)
+	}
+}
+
+// MQTTInsecure checks if the MQTT broker certificate should not be verified.
+func (c *Config) MQTTInsecure() bool {
+	return c.options.MQTTInsecure
+}
+
+// MQTTCa returns the CA certificate filename for verifying the MQTT broker, if any.
+func (c *Config) MQTTCa() string {
+	return fs.Abs(c.options.MQTTCa)
+}
+
+// MQTTEnabled checks if events should be published to an MQTT broker.
+func (c *Config) MQTTEnabled() bool {
+	return c.MQTTUri() != ""
+}
+
+// MQTT returns the MQTT bridge configuration.
+func (c *Config) MQTT() *mqtt.Bridge {
+	return &mqtt.Bridge{
+		Uri:      c.MQTTUri(),
+		ClientID: c.MQTTClient(),
+		Username: c.MQTTUser(),
+		Password: c.MQTTPassword(),
+		Prefix:   c.MQTTPrefix(),
+		QoS:      c.MQTTQoS(),
+		Insecure: c.MQTTInsecure(),
+		CAFile:   c.MQTTCa(),
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_MQTTEnabled(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.False(t, c.MQTTEnabled())
+	c.options.MQTTUri = " tcp://mqtt.example.com:1883 "
+	assert.True(t, c.MQTTEnabled())
+	assert.Equal(t, "tcp://mqtt.example.com:1883", c.MQTTUri())
+	c.options.MQTTUri = ""
+	assert.False(t, c.MQTTEnabled())
+}
+
+func TestConfig_MQTTPrefix(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	c.options.MQTTPrefix = ""
+	assert.Equal(t, "photoprism", c.MQTTPrefix())
+	c.options.MQTTPrefix = "/home/photos/"
+	assert.Equal(t, "home/photos", c.MQTTPrefix())
+	c.options.MQTTPrefix = ""
+}
+
+func TestConfig_MQTTQoS(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, byte(0), c.MQTTQoS())
+	c.options.MQTTQoS = 1
+	assert.Equal(t, byte(1), c.MQTTQoS())
+	c.options.MQTTQoS = 5
+	assert.Equal(t, byte(2), c.MQTTQoS())
+	c.options.MQTTQoS = -1
+	assert.Equal(t, byte(0), c.MQTTQoS())
+	c.options.MQTTQoS = 0
+}
+
+func TestConfig_MQTT(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	c.options.MQTTUri = "ssl://mqtt.example.com:8883"
+	c.options.MQTTUser = "photoprism"
+	c.options.MQTTPassword = "secret"
+	c.options.MQTTInsecure = true
+
+	b := c.MQTT()
+
+	assert.Equal(t, "ssl://mqtt.example.com:8883", b.Uri)
+	assert.Equal(t, "photoprism", b.ClientID)
+	assert.Equal(t, "photoprism", b.Username)
+	assert.Equal(t, "secret", b.Password)
+	assert.Equal(t, "photoprism", b.Prefix)
+	assert.True(t, b.Insecure)
+	assert.Equal(t, "", b.CAFile)
+
+	c.options.MQTTUri = ""
+	c.options.MQTTUser = ""
+	c.options.MQTTPassword = ""
+	c.options.MQTTInsecure = false
+}
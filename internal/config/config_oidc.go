@@ -0,0 +1,117 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/list"
+)
+
+// OIDCDefaultScopes specifies the scopes requested from OpenID Connect providers by default.
+const OIDCDefaultScopes = "openid email profile"
+
+// OIDCUri returns the OpenID Connect issuer URL without trailing slash.
+func (c *Config) OIDCUri() string {
+	return strings.TrimRight(strings.TrimSpace(c.options.OIDCUri), "/")
+}
+
+// OIDCClient returns the OpenID Connect client ID.
+func (c *Config) OIDCClient() string {
+	return strings.TrimSpace(c.options.OIDCClient)
+}
+
+// OIDCSecret returns the OpenID Connect client secret.
+func (c *Config) OIDCSecret() string {
+	return strings.TrimSpace(c.options.OIDCSecret)
+}
+
+// OIDCScopes returns the OpenID Connect scopes, which always include "openid".
+func (c *Config) OIDCScopes() []string {
+	s := strings.TrimSpace(c.options.OIDCScopes)
+
+	if s == "" {
+		s = OIDCDefaultScopes
+	}
+
+	scopes := strings.Fields(strings.ReplaceAll(s, ",", " "))
+
+	if list.Excludes(scopes, "openid") {
+		scopes = append([]string{"openid"}, scopes...)
+	}
+
+	return scopes
+}
+
+// OIDCRoleClaim returns the name of the ID token claim that contains the user role, if any.
+func (c *Config) OIDCRoleClaim() string {
+	return strings.TrimSpace(c.options.OIDCRoleClaim)
+}
+
+// OIDCRoleMap returns the mapping of role claim values to user roles.
+func (c *Config) OIDCRoleMap() map[string]acl.Role {
+	result := make(map[string]acl.Role)
+
+	for _, item := range strings.Fields(strings.ReplaceAll(c.options.OIDCRoleMap, ",", " ")) {
+		if k, v, found := strings.Cut(item, "="); !found || k == "" {
+			log.Warnf("config: invalid oidc role mapping %s", clean.Log(item))
+		} else if role, ok := acl.ValidRoles[clean.TypeLower(v)]; !ok || role == acl.RoleUnknown {
+			log.Warnf("config: invalid oidc role %s", clean.Log(v))
+		} else {
+			result[k] = role
+		}
+	}
+
+	return result
+}
+
+// OIDCClaimRole returns the user role for the role claim values, or acl.RoleUnknown if none matches.
+// Admin takes precedence if the values match more than one role.
+func (c *Config) OIDCClaimRole(values []string) acl.Role {
+	roleMap := c.OIDCRoleMap()
+	result := acl.RoleUnknown
+
+	for _, v := range values {
+		var role acl.Role
+
+		if len(roleMap) > 0 {
+			role = roleMap[v]
+		} else {
+			role = acl.ValidRoles[clean.TypeLower(v)]
+		}
+
+		if role == acl.RoleAdmin {
+			return role
+		} else if role != acl.RoleUnknown {
+			result = role
+		}
+	}
+
+	return result
+}
+
+// OIDCRole returns the default role of OpenID Connect users.
+func (c *Config) OIDCRole() acl.Role {
+	s := clean.TypeLower(c.options.OIDCRole)
+
+	if s == "" {
+		return acl.RoleAdmin
+	}
+
+	return acl.ValidRoles[s]
+}
+
+// OIDCRegister checks if accounts should be created automatically for new OpenID Connect users.
+func (c *Config) OIDCRegister() bool {
+	return c.options.OIDCRegister
+}
+
+// OIDCEnabled checks if users can log in with an OpenID Connect provider.
+func (c *Config) OIDCEnabled() bool {
+	return !c.Public() && c.OIDCUri() != "" && c.OIDCClient() != ""
+}
+
+// OIDCRedirectUri returns the URL to which the OpenID Connect provider redirects after authentication.
+func (c *Config) OIDCRedirectUri() string {
+	return strings.TrimRight(c.SiteUrl(), "/") + ApiUri + "/oidc/redirect"
+}
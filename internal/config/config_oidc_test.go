@@ -0,0 +1,69 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/acl"
+)
+
+func TestConfig_OIDCEnabled(t *testing.T) {
+	c := NewConfig(CliTestContext())
+	c.options.Public = false
+
+	assert.False(t, c.OIDCEnabled())
+	c.options.OIDCUri = "https://sso.example.com/realms/photos/"
+	assert.Equal(t, "https://sso.example.com/realms/photos", c.OIDCUri())
+	assert.False(t, c.OIDCEnabled())
+	c.options.OIDCClient = " photoprism "
+	assert.Equal(t, "photoprism", c.OIDCClient())
+	assert.True(t, c.OIDCEnabled())
+	c.options.Public = true
+	assert.False(t, c.OIDCEnabled())
+}
+
+func TestConfig_OIDCScopes(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	c.options.OIDCScopes = ""
+	assert.Equal(t, []string{"openid", "email", "profile"}, c.OIDCScopes())
+	c.options.OIDCScopes = "email, groups"
+	assert.Equal(t, []string{"openid", "email", "groups"}, c.OIDCScopes())
+	c.options.OIDCScopes = "profile openid"
+	assert.Equal(t, []string{"profile", "openid"}, c.OIDCScopes())
+}
+
+func TestConfig_OIDCRole(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	c.options.OIDCRole = ""
+	assert.Equal(t, acl.RoleAdmin, c.OIDCRole())
+	c.options.OIDCRole = "Visitor"
+	assert.Equal(t, acl.RoleVisitor, c.OIDCRole())
+	c.options.OIDCRole = "foo"
+	assert.Equal(t, acl.RoleUnknown, c.OIDCRole())
+}
+
+func TestConfig_OIDCRedirectUri(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	c.options.SiteUrl = "https://photos.example.com/library/"
+	assert.Equal(t, "https://photos.example.com/library/api/v1/oidc/redirect", c.OIDCRedirectUri())
+}
+
+func TestConfig_OIDCClaimRole(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	c.options.OIDCRoleMap = ""
+	assert.Equal(t, acl.RoleAdmin, c.OIDCClaimRole([]string{"visitor", "Admin"}))
+	assert.Equal(t, acl.RoleVisitor, c.OIDCClaimRole([]string{"visitor", "foo"}))
+	assert.Equal(t, acl.RoleUnknown, c.OIDCClaimRole([]string{"foo"}))
+	assert.Equal(t, acl.RoleUnknown, c.OIDCClaimRole(nil))
+
+	c.options.OIDCRoleMap = "photo-admins=admin, family=visitor invalid foo=bar"
+	assert.Len(t, c.OIDCRoleMap(), 2)
+	assert.Equal(t, acl.RoleVisitor, c.OIDCClaimRole([]string{"staff", "family"}))
+	assert.Equal(t, acl.RoleAdmin, c.OIDCClaimRole([]string{"family", "photo-admins"}))
+	assert.Equal(t, acl.RoleUnknown, c.OIDCClaimRole([]string{"admin"}))
+}
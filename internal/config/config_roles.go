@@ -0,0 +1,43 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// LoadRoles loads custom user roles from the roles YAML file, if it exists, and grants their permissions.
+func (c *Config) LoadRoles() error {
+	fileName := c.RolesYaml()
+
+	if !fs.FileExists(fileName) {
+		return nil
+	}
+
+	roles, err := acl.LoadRoles(fileName)
+
+	if err != nil {
+		return fmt.Errorf("config: invalid roles in %s (%s)", clean.Log(fileName), err)
+	}
+
+	roles.Apply(acl.Resources)
+	c.roles = roles
+
+	log.Debugf("config: loaded %d custom roles from %s", len(roles), clean.Log(fileName))
+
+	return nil
+}
+
+// CustomRoles returns the custom user roles loaded from the roles YAML file.
+func (c *Config) CustomRoles() acl.CustomRoles {
+	return c.roles
+}
+
+// RolesReport returns the permissions granted to built-in and custom user roles by resource.
+func (c *Config) RolesReport() (rows [][]string, cols []string) {
+	roles := []acl.Role{acl.RoleAdmin, acl.RoleVisitor}
+
+	return acl.Resources.Report(append(roles, c.roles.Roles()...))
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/acl"
+)
+
+func TestConfig_RolesYaml(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	c.options.RolesYaml = ""
+	assert.Equal(t, filepath.Join(c.ConfigPath(), "roles.yml"), c.RolesYaml())
+	c.options.RolesYaml = "/etc/photoprism/roles.yml"
+	assert.Equal(t, "/etc/photoprism/roles.yml", c.RolesYaml())
+}
+
+func TestConfig_LoadRoles(t *testing.T) {
+	c := NewConfig(CliTestContext())
+	dir := t.TempDir()
+
+	t.Run("NotFound", func(t *testing.T) {
+		c.options.RolesYaml = filepath.Join(dir, "missing.yml")
+		assert.NoError(t, c.LoadRoles())
+		assert.Len(t, c.CustomRoles(), 0)
+	})
+	t.Run("Success", func(t *testing.T) {
+		fileName := filepath.Join(dir, "roles.yml")
+		data := "Roles:\n  contributor:\n    config: [access_own]\n    photos: [access_library, search, view, upload]\n    labels: [access_library, search, view, update]\n"
+
+		if err := os.WriteFile(fileName, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		c.options.RolesYaml = fileName
+
+		defer func() {
+			for resource := range acl.Resources {
+				delete(acl.Resources[resource], "contributor")
+			}
+
+			delete(acl.ValidRoles, "contributor")
+		}()
+
+		assert.NoError(t, c.LoadRoles())
+		assert.Equal(t, []acl.Role{"contributor"}, c.CustomRoles().Roles())
+		assert.True(t, acl.Resources.Allow(acl.ResourceLabels, "contributor", acl.ActionUpdate))
+		assert.False(t, acl.Resources.Allow(acl.ResourceLabels, "contributor", acl.ActionDelete))
+
+		rows, cols := c.RolesReport()
+		assert.Equal(t, []string{"Role", "Resource", "Permissions"}, cols)
+		assert.Contains(t, rows, []string{"contributor", "labels", "access_library, search, update, view"})
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		fileName := filepath.Join(dir, "invalid.yml")
+
+		if err := os.WriteFile(fileName, []byte("Roles:\n  contributor:\n    photos: [fly]\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		c.options.RolesYaml = fileName
+
+		assert.Error(t, c.LoadRoles())
+	})
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// Names of jobs that can be scheduled.
+const (
+	JobIndex   = "index"
+	JobImport  = "import"
+	JobFaces   = "faces"
+	JobMoments = "moments"
+	JobPlaces  = "places"
+	JobCleanup = "cleanup"
+	JobBackup  = "backup"
+	JobThumbs  = "thumbs"
+	JobTrash   = "trash"
+)
+
+// Jobs lists the names of jobs that can be scheduled.
+var Jobs = []string{JobIndex, JobImport, JobFaces, JobMoments, JobPlaces, JobCleanup, JobBackup, JobThumbs, JobTrash}
+
+// IndexSchedule returns the cron schedule for indexing originals.
+func (c *Config) IndexSchedule() string {
+	return strings.TrimSpace(c.options.IndexSchedule)
+}
+
+// ImportSchedule returns the cron schedule for importing files.
+func (c *Config) ImportSchedule() string {
+	return strings.TrimSpace(c.options.ImportSchedule)
+}
+
+// FacesSchedule returns the cron schedule for face recognition.
+func (c *Config) FacesSchedule() string {
+	return strings.TrimSpace(c.options.FacesSchedule)
+}
+
+// MomentsSchedule returns the cron schedule for updating moments.
+func (c *Config) MomentsSchedule() string {
+	return strings.TrimSpace(c.options.MomentsSchedule)
+}
+
+// PlacesSchedule returns the cron schedule for updating location details.
+func (c *Config) PlacesSchedule() string {
+	return strings.TrimSpace(c.options.PlacesSchedule)
+}
+
+// CleanupSchedule returns the cron schedule for removing orphaned index entries and files.
+func (c *Config) CleanupSchedule() string {
+	return strings.TrimSpace(c.options.CleanupSchedule)
+}
+
+// BackupSchedule returns the cron schedule for creating backups.
+func (c *Config) BackupSchedule() string {
+	return strings.TrimSpace(c.options.BackupSchedule)
+}
+
+// ThumbsSchedule returns the cron schedule for pre-rendering thumbnails.
+func (c *Config) ThumbsSchedule() string {
+	return strings.TrimSpace(c.options.ThumbsSchedule)
+}
+
+// TrashSchedule returns the cron schedule for permanently removing files from the trash.
+func (c *Config) TrashSchedule() string {
+	return strings.TrimSpace(c.options.TrashSchedule)
+}
+
+// JobSchedule returns the cron schedule of a job, or an empty string if it is not scheduled or disabled.
+func (c *Config) JobSchedule(job string) string {
+	switch job {
+	case JobIndex:
+		return c.IndexSchedule()
+	case JobImport:
+		if c.ReadOnly() {
+			return ""
+		}
+
+		return c.ImportSchedule()
+	case JobFaces:
+		if c.DisableFaces() {
+			return ""
+		}
+
+		return c.FacesSchedule()
+	case JobMoments:
+		return c.MomentsSchedule()
+	case JobPlaces:
+		if c.DisablePlaces() {
+			return ""
+		}
+
+		return c.PlacesSchedule()
+	case JobCleanup:
+		return c.CleanupSchedule()
+	case JobBackup:
+		if c.DisableBackups() {
+			return ""
+		}
+
+		return c.BackupSchedule()
+	case JobThumbs:
+		return c.ThumbsSchedule()
+	case JobTrash:
+		if c.TrashRetention() == 0 {
+			return ""
+		}
+
+		return c.TrashSchedule()
+	default:
+		return ""
+	}
+}
+
+// Schedule returns the valid cron schedules of enabled jobs by name.
+func (c *Config) Schedule() map[string]string {
+	result := make(map[string]string, len(Jobs))
+
+	for _, job := range Jobs {
+		s := c.JobSchedule(job)
+
+		if s == "" {
+			continue
+		} else if _, err := cron.ParseStandard(s); err != nil {
+			log.Warnf("config: invalid %s schedule %s (%s)", job, clean.LogQuote(s), err)
+			continue
+		}
+
+		result[job] = s
+	}
+
+	return result
+}
+
+// ScheduleReport returns the cron schedules of jobs and when they run next.
+func (c *Config) ScheduleReport() (rows [][]string, cols []string) {
+	cols = []string{"Job", "Schedule", "Next Run"}
+	rows = make([][]string, 0, len(Jobs))
+
+	now := time.Now()
+
+	for _, job := range Jobs {
+		s := c.JobSchedule(job)
+		next := "disabled"
+
+		if s == "" {
+			// Not scheduled.
+		} else if sched, err := cron.ParseStandard(s); err != nil {
+			next = "invalid schedule"
+		} else {
+			next = sched.Next(now).Format(time.RFC1123)
+		}
+
+		rows = append(rows, []string{job, s, next})
+	}
+
+	return rows, cols
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_JobSchedule(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, "", c.JobSchedule(JobBackup))
+	c.options.BackupSchedule = " 0 3 * * * "
+	assert.Equal(t, "0 3 * * *", c.BackupSchedule())
+	assert.Equal(t, "0 3 * * *", c.JobSchedule(JobBackup))
+	c.options.DisableBackups = true
+	assert.Equal(t, "", c.JobSchedule(JobBackup))
+	c.options.DisableBackups = false
+	c.options.BackupSchedule = ""
+	assert.Equal(t, "", c.JobSchedule("foo"))
+}
+
+func TestConfig_Schedule(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Empty(t, c.Schedule())
+
+	c.options.BackupSchedule = "0 3 * * *"
+	c.options.FacesSchedule = "@hourly"
+	c.options.ThumbsSchedule = "not a cron expression"
+
+	assert.Equal(t, map[string]string{JobBackup: "0 3 * * *", JobFaces: "@hourly"}, c.Schedule())
+
+	c.options.BackupSchedule = ""
+	c.options.FacesSchedule = ""
+	c.options.ThumbsSchedule = ""
+}
+
+func TestConfig_ScheduleReport(t *testing.T) {
+	c := NewConfig(CliTestContext())
+	c.options.MomentsSchedule = "@daily"
+	c.options.ThumbsSchedule = "61 * * * *"
+
+	rows, cols := c.ScheduleReport()
+
+	assert.Equal(t, []string{"Job", "Schedule", "Next Run"}, cols)
+	assert.Len(t, rows, len(Jobs))
+
+	for _, row := range rows {
+		switch row[0] {
+		case JobMoments:
+			assert.Equal(t, "@daily", row[1])
+			assert.NotEqual(t, "disabled", row[2])
+		case JobThumbs:
+			assert.Equal(t, "invalid schedule", row[2])
+		default:
+			assert.Equal(t, "disabled", row[2])
+		}
+	}
+
+	c.options.MomentsSchedule = ""
+	c.options.ThumbsSchedule = ""
+}
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"path/filepath"
+)
+
+// TrashPath returns the path where deleted files are kept until they are permanently removed.
+func (c *Config) TrashPath() string {
+	return filepath.Join(c.StoragePath(), "trash")
+}
+
+// TrashRetention returns the number of days deleted files are kept in the trash,
+// or 0 if files should be deleted immediately.
+func (c *Config) TrashRetention() int {
+	if c.options.TrashRetention < 0 {
+		return 0
+	}
+
+	return c.options.TrashRetention
+}
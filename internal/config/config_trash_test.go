@@ -0,0 +1,39 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_TrashPath(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	assert.Equal(t, filepath.Join(c.StoragePath(), "trash"), c.TrashPath())
+}
+
+func TestConfig_TrashRetention(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	c.options.TrashRetention = 30
+	assert.Equal(t, 30, c.TrashRetention())
+	c.options.TrashRetention = -1
+	assert.Equal(t, 0, c.TrashRetention())
+	c.options.TrashRetention = 0
+	assert.Equal(t, 0, c.TrashRetention())
+}
+
+func TestConfig_TrashSchedule(t *testing.T) {
+	c := NewConfig(CliTestContext())
+
+	c.options.TrashSchedule = " @daily "
+	c.options.TrashRetention = 30
+	assert.Equal(t, "@daily", c.TrashSchedule())
+	assert.Equal(t, "@daily", c.JobSchedule(JobTrash))
+
+	c.options.TrashRetention = 0
+	assert.Equal(t, "", c.JobSchedule(JobTrash))
+
+	c.options.TrashSchedule = ""
+}
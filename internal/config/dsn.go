@@ -1,6 +1,9 @@
 package config
 
-import "regexp"
+import (
+	"regexp"
+	"strings"
+)
 
 // dsnPattern is a regular expression matching a database DSN string.
 var dsnPattern = regexp.MustCompile(
@@ -9,6 +12,9 @@ var dsnPattern = regexp.MustCompile(
 		`\/(?P<name>.*?)` +
 		`(?:\?(?P<params>[^\?]*))?$`)
 
+// dsnKeyValuePattern is a regular expression matching a key/value DSN string, e.g. for PostgreSQL.
+var dsnKeyValuePattern = regexp.MustCompile(`^\s*[a-z_]+\s*=`)
+
 // DSN represents parts of a data source name.
 type DSN struct {
 	Driver   string
@@ -31,6 +37,9 @@ func NewDSN(dsn string) DSN {
 func (d *DSN) Parse(dsn string) {
 	if dsn == "" {
 		return
+	} else if dsnKeyValuePattern.MatchString(dsn) {
+		d.parseKeyValues(dsn)
+		return
 	}
 
 	matches := dsnPattern.FindStringSubmatch(dsn)
@@ -60,3 +69,98 @@ func (d *DSN) Parse(dsn string) {
 		d.Net = ""
 	}
 }
+
+// parseKeyValues parses a data source name in the "key=value key=value" format used by PostgreSQL.
+// Values may be enclosed in single quotes, and backslashes escape the following character.
+func (d *DSN) parseKeyValues(dsn string) {
+	var host, port string
+	var params []string
+
+	for i := 0; i < len(dsn); {
+		// Skip whitespace.
+		if dsn[i] == ' ' || dsn[i] == '\t' || dsn[i] == '\n' {
+			i++
+			continue
+		}
+
+		start := i
+
+		// Read key.
+		for i < len(dsn) && dsn[i] != '=' && dsn[i] != ' ' {
+			i++
+		}
+
+		key := strings.ToLower(dsn[start:i])
+
+		for i < len(dsn) && dsn[i] == ' ' {
+			i++
+		}
+
+		if i >= len(dsn) || dsn[i] != '=' {
+			continue
+		}
+
+		i++
+
+		for i < len(dsn) && dsn[i] == ' ' {
+			i++
+		}
+
+		// Read value.
+		var val strings.Builder
+
+		quoted := i < len(dsn) && dsn[i] == '\''
+
+		if quoted {
+			i++
+		}
+
+		for i < len(dsn) {
+			c := dsn[i]
+
+			if c == '\\' && i+1 < len(dsn) {
+				val.WriteByte(dsn[i+1])
+				i += 2
+				continue
+			} else if quoted && c == '\'' {
+				i++
+				break
+			} else if !quoted && (c == ' ' || c == '\t' || c == '\n') {
+				break
+			}
+
+			val.WriteByte(c)
+			i++
+		}
+
+		switch key {
+		case "user":
+			d.User = val.String()
+		case "password":
+			d.Password = val.String()
+		case "host":
+			host = val.String()
+		case "port":
+			port = val.String()
+		case "dbname":
+			d.Name = val.String()
+		default:
+			params = append(params, dsn[start:i])
+		}
+	}
+
+	if host != "" && port != "" {
+		d.Server = host + ":" + port
+	} else {
+		d.Server = host
+	}
+
+	d.Driver = "postgres"
+	d.Params = strings.Join(params, " ")
+}
+
+// dsnQuote returns the value enclosed in single quotes for use in a key/value data source name,
+// with backslashes and single quotes escaped.
+func dsnQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
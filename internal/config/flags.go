@@ -6,6 +6,8 @@ import (
 
 	"github.com/photoprism/photoprism/internal/face"
 	"github.com/photoprism/photoprism/internal/i18n"
+	"github.com/photoprism/photoprism/internal/ldap"
+	"github.com/photoprism/photoprism/internal/mqtt"
 	"github.com/photoprism/photoprism/internal/server/header"
 	"github.com/photoprism/photoprism/internal/thumb"
 )
@@ -48,6 +50,111 @@ var Flags = CliFlags{
 			Usage:  "time in `SECONDS` until user sessions expire due to inactivity (-1 to disable)",
 			EnvVar: "PHOTOPRISM_SESS_TIMEOUT",
 		}}, {
+		Flag: cli.IntFlag{
+			Name:   "audit-retention",
+			Value:  DefaultAuditRetention,
+			Usage:  "number of `DAYS` until audit log events are deleted (-1 to keep them)",
+			EnvVar: "PHOTOPRISM_AUDIT_RETENTION",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "oidc-uri",
+			Usage:  "OpenID Connect issuer `URL` for single sign-on, requires a client id",
+			EnvVar: "PHOTOPRISM_OIDC_URI",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "oidc-client",
+			Usage:  "OpenID Connect client `ID`",
+			EnvVar: "PHOTOPRISM_OIDC_CLIENT",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "oidc-secret",
+			Usage:  "OpenID Connect client `SECRET`",
+			EnvVar: "PHOTOPRISM_OIDC_SECRET",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "oidc-scopes",
+			Usage:  "OpenID Connect `SCOPES` to request from the issuer",
+			Value:  OIDCDefaultScopes,
+			EnvVar: "PHOTOPRISM_OIDC_SCOPES",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "oidc-role-claim",
+			Usage:  "ID token `CLAIM` that contains the user role, e.g. groups (uses the default role if empty)",
+			EnvVar: "PHOTOPRISM_OIDC_ROLE_CLAIM",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "oidc-role-map",
+			Usage:  "maps role claim `VALUES` to user roles, e.g. \"photo-admins=admin family=visitor\" (matches role names if empty)",
+			EnvVar: "PHOTOPRISM_OIDC_ROLE_MAP",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "oidc-role",
+			Usage:  "default user `ROLE` for OpenID Connect users if no role claim is configured",
+			Value:  "admin",
+			EnvVar: "PHOTOPRISM_OIDC_ROLE",
+		}}, {
+		Flag: cli.BoolFlag{
+			Name:   "oidc-register",
+			Usage:  "automatically create accounts for new OpenID Connect users",
+			EnvVar: "PHOTOPRISM_OIDC_REGISTER",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "auth-provider",
+			Usage:  "external authentication `PROVIDER` for password logins, e.g. ldap (uses local accounts only if empty)",
+			EnvVar: "PHOTOPRISM_AUTH_PROVIDER",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "ldap-uri",
+			Usage:  "LDAP server `URI`, e.g. ldaps://ldap.example.com (ldap:// connections are upgraded with StartTLS)",
+			EnvVar: "PHOTOPRISM_LDAP_URI",
+		}}, {
+		Flag: cli.BoolFlag{
+			Name:   "ldap-insecure",
+			Usage:  "skip LDAP server certificate verification",
+			EnvVar: "PHOTOPRISM_LDAP_INSECURE",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "ldap-bind-dn",
+			Usage:  "service account `DN` for searching users (binds anonymously if empty)",
+			EnvVar: "PHOTOPRISM_LDAP_BIND_DN",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "ldap-bind-password",
+			Usage:  "service account `PASSWORD`",
+			EnvVar: "PHOTOPRISM_LDAP_BIND_PASSWORD",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "ldap-base-dn",
+			Usage:  "base `DN` for searching users and groups, e.g. dc=example,dc=com",
+			EnvVar: "PHOTOPRISM_LDAP_BASE_DN",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "ldap-user-filter",
+			Usage:  "search `FILTER` for user entries, %s is replaced by the username",
+			Value:  ldap.DefaultUserFilter,
+			EnvVar: "PHOTOPRISM_LDAP_USER_FILTER",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "ldap-admin-group",
+			Usage:  "group `DN` or name whose members get the admin role (all users are admins if no group is configured)",
+			EnvVar: "PHOTOPRISM_LDAP_ADMIN_GROUP",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "ldap-user-group",
+			Usage:  "group `DN` or name whose members get the role specified with ldap-user-role",
+			EnvVar: "PHOTOPRISM_LDAP_USER_GROUP",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "ldap-user-role",
+			Usage:  "user `ROLE` for members of the user group, e.g. a custom role, as there is no built-in role between admin and visitor",
+			Value:  "visitor",
+			EnvVar: "PHOTOPRISM_LDAP_USER_ROLE",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "ldap-guest-group",
+			Usage:  "group `DN` or name whose members get the read-only visitor role",
+			EnvVar: "PHOTOPRISM_LDAP_GUEST_GROUP",
+		}}, {
 		Flag: cli.StringFlag{
 			Name:   "log-level, l",
 			Usage:  "log message verbosity `LEVEL` (trace, debug, info, warning, error, fatal, panic)",
@@ -110,6 +217,11 @@ var Flags = CliFlags{
 			Value:  "/etc/photoprism/defaults.yml",
 			EnvVar: "PHOTOPRISM_DEFAULTS_YAML",
 		}}, {
+		Flag: cli.StringFlag{
+			Name:   "roles-yaml",
+			Usage:  "load custom user roles and permissions from `FILE` if exists (default: roles.yml in the config path)",
+			EnvVar: "PHOTOPRISM_ROLES_YAML",
+		}}, {
 		Flag: cli.StringFlag{
 			Name:   "originals-path, o",
 			Usage:  "storage `PATH` of your original media files (photos and videos)",
@@ -145,6 +257,36 @@ var Flags = CliFlags{
 			Usage:  "custom backup `PATH` for index backup files *optional*",
 			EnvVar: "PHOTOPRISM_BACKUP_PATH",
 		}}, {
+		Flag: cli.StringFlag{
+			Name:   "backup-compression",
+			Value:  "gzip",
+			Usage:  "backup archive SQL dump `COMPRESSION` (none, gzip, zstd)",
+			EnvVar: "PHOTOPRISM_BACKUP_COMPRESSION",
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "backup-daily",
+			Value:  DefaultBackupDaily,
+			Usage:  "`NUMBER` of daily backup archives to keep (0 to disable)",
+			EnvVar: "PHOTOPRISM_BACKUP_DAILY",
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "backup-weekly",
+			Value:  DefaultBackupWeekly,
+			Usage:  "`NUMBER` of weekly backup archives to keep (0 to disable)",
+			EnvVar: "PHOTOPRISM_BACKUP_WEEKLY",
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "backup-monthly",
+			Value:  DefaultBackupMonthly,
+			Usage:  "`NUMBER` of monthly backup archives to keep (0 to disable)",
+			EnvVar: "PHOTOPRISM_BACKUP_MONTHLY",
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "trash-retention",
+			Value:  DefaultTrashRetention,
+			Usage:  "number of `DAYS` deleted files are kept in the trash before they are permanently removed (0 to delete immediately)",
+			EnvVar: "PHOTOPRISM_TRASH_RETENTION",
+		}}, {
 		Flag: cli.StringFlag{
 			Name:   "cache-path, ca",
 			Usage:  "custom cache `PATH` for sessions and thumbnail files *optional*",
@@ -194,6 +336,52 @@ var Flags = CliFlags{
 			Value:  DefaultAutoImportDelay,
 			EnvVar: "PHOTOPRISM_AUTO_IMPORT",
 		}}, {
+		Flag: cli.StringFlag{
+			Name:   "index-schedule",
+			Usage:  "cron `SCHEDULE` to index originals, e.g. \"0 2 * * *\" for nightly at 2:00 (disabled if empty)",
+			EnvVar: "PHOTOPRISM_INDEX_SCHEDULE",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "import-schedule",
+			Usage:  "cron `SCHEDULE` to import files from the import folder (disabled if empty)",
+			EnvVar: "PHOTOPRISM_IMPORT_SCHEDULE",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "faces-schedule",
+			Usage:  "cron `SCHEDULE` to run face recognition, e.g. \"@hourly\" (disabled if empty)",
+			EnvVar: "PHOTOPRISM_FACES_SCHEDULE",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "moments-schedule",
+			Usage:  "cron `SCHEDULE` to update moments (disabled if empty)",
+			EnvVar: "PHOTOPRISM_MOMENTS_SCHEDULE",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "places-schedule",
+			Usage:  "cron `SCHEDULE` to update location details (disabled if empty)",
+			EnvVar: "PHOTOPRISM_PLACES_SCHEDULE",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "cleanup-schedule",
+			Usage:  "cron `SCHEDULE` to remove orphaned index entries, sidecar and thumbnail files (disabled if empty)",
+			EnvVar: "PHOTOPRISM_CLEANUP_SCHEDULE",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "backup-schedule",
+			Usage:  "cron `SCHEDULE` to create a backup archive and remove outdated archives, e.g. \"0 3 * * *\" for nightly at 3:00 (disabled if empty)",
+			EnvVar: "PHOTOPRISM_BACKUP_SCHEDULE",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "thumbs-schedule",
+			Usage:  "cron `SCHEDULE` to pre-render thumbnail images (disabled if empty)",
+			EnvVar: "PHOTOPRISM_THUMBS_SCHEDULE",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "trash-schedule",
+			Value:  "@daily",
+			Usage:  "cron `SCHEDULE` to permanently remove files from the trash after the retention period (disabled if empty)",
+			EnvVar: "PHOTOPRISM_TRASH_SCHEDULE",
+		}}, {
 		Flag: cli.BoolFlag{
 			Name:   "read-only, r",
 			Usage:  "disable import, upload, delete, and all other operations that require write permissions",
@@ -445,13 +633,13 @@ var Flags = CliFlags{
 		}}, {
 		Flag: cli.StringFlag{
 			Name:   "database-driver, db",
-			Usage:  "database `DRIVER` (sqlite, mysql)",
+			Usage:  "database `DRIVER` (sqlite, mysql, postgres)",
 			Value:  "sqlite",
 			EnvVar: "PHOTOPRISM_DATABASE_DRIVER",
 		}}, {
 		Flag: cli.StringFlag{
 			Name:   "database-dsn, dsn",
-			Usage:  "database connection `DSN` (sqlite file, optional for mysql)",
+			Usage:  "database connection `DSN` (sqlite file, optional for mysql and postgres)",
 			EnvVar: "PHOTOPRISM_DATABASE_DSN",
 		}}, {
 		Flag: cli.StringFlag{
@@ -486,6 +674,48 @@ var Flags = CliFlags{
 			Usage:  "maximum `NUMBER` of idle database connections",
 			EnvVar: "PHOTOPRISM_DATABASE_CONNS_IDLE",
 		}}, {
+		Flag: cli.StringFlag{
+			Name:   "mqtt-uri",
+			Usage:  "MQTT broker `URI` for publishing events, e.g. tcp://mqtt.example.com:1883 or ssl://mqtt.example.com:8883",
+			EnvVar: "PHOTOPRISM_MQTT_URI",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "mqtt-client",
+			Usage:  "MQTT client `ID`",
+			Value:  mqtt.DefaultClientID,
+			EnvVar: "PHOTOPRISM_MQTT_CLIENT",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "mqtt-user",
+			Usage:  "MQTT broker `USERNAME`",
+			EnvVar: "PHOTOPRISM_MQTT_USER",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "mqtt-password",
+			Usage:  "MQTT broker `PASSWORD`",
+			EnvVar: "PHOTOPRISM_MQTT_PASSWORD",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "mqtt-prefix",
+			Usage:  "MQTT topic `PREFIX`, e.g. photoprism/photos/updated",
+			Value:  mqtt.DefaultPrefix,
+			EnvVar: "PHOTOPRISM_MQTT_PREFIX",
+		}}, {
+		Flag: cli.IntFlag{
+			Name:   "mqtt-qos",
+			Usage:  "MQTT quality of service `LEVEL` (0-2)",
+			EnvVar: "PHOTOPRISM_MQTT_QOS",
+		}}, {
+		Flag: cli.BoolFlag{
+			Name:   "mqtt-insecure",
+			Usage:  "skip MQTT broker certificate verification",
+			EnvVar: "PHOTOPRISM_MQTT_INSECURE",
+		}}, {
+		Flag: cli.StringFlag{
+			Name:   "mqtt-ca",
+			Usage:  "CA certificate `FILENAME` for verifying the MQTT broker",
+			EnvVar: "PHOTOPRISM_MQTT_CA",
+		}}, {
 		Flag: cli.StringFlag{
 			Name:   "darktable-bin",
 			Usage:  "Darktable CLI `COMMAND` for RAW to JPEG conversion",
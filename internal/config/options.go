@@ -28,6 +28,26 @@ type Options struct {
 	AdminPassword         string        `yaml:"AdminPassword" json:"-" flag:"admin-password"`
 	SessMaxAge            int64         `yaml:"SessMaxAge" json:"-" flag:"sess-maxage"`
 	SessTimeout           int64         `yaml:"SessTimeout" json:"-" flag:"sess-timeout"`
+	AuditRetention        int           `yaml:"AuditRetention" json:"-" flag:"audit-retention"`
+	OIDCUri               string        `yaml:"OIDCUri" json:"-" flag:"oidc-uri"`
+	OIDCClient            string        `yaml:"OIDCClient" json:"-" flag:"oidc-client"`
+	OIDCSecret            string        `yaml:"OIDCSecret" json:"-" flag:"oidc-secret"`
+	OIDCScopes            string        `yaml:"OIDCScopes" json:"-" flag:"oidc-scopes"`
+	OIDCRoleClaim         string        `yaml:"OIDCRoleClaim" json:"-" flag:"oidc-role-claim"`
+	OIDCRoleMap           string        `yaml:"OIDCRoleMap" json:"-" flag:"oidc-role-map"`
+	OIDCRole              string        `yaml:"OIDCRole" json:"-" flag:"oidc-role"`
+	OIDCRegister          bool          `yaml:"OIDCRegister" json:"-" flag:"oidc-register"`
+	AuthProvider          string        `yaml:"AuthProvider" json:"-" flag:"auth-provider"`
+	LDAPUri               string        `yaml:"LDAPUri" json:"-" flag:"ldap-uri"`
+	LDAPInsecure          bool          `yaml:"LDAPInsecure" json:"-" flag:"ldap-insecure"`
+	LDAPBindDN            string        `yaml:"LDAPBindDN" json:"-" flag:"ldap-bind-dn"`
+	LDAPBindPassword      string        `yaml:"LDAPBindPassword" json:"-" flag:"ldap-bind-password"`
+	LDAPBaseDN            string        `yaml:"LDAPBaseDN" json:"-" flag:"ldap-base-dn"`
+	LDAPUserFilter        string        `yaml:"LDAPUserFilter" json:"-" flag:"ldap-user-filter"`
+	LDAPAdminGroup        string        `yaml:"LDAPAdminGroup" json:"-" flag:"ldap-admin-group"`
+	LDAPUserGroup         string        `yaml:"LDAPUserGroup" json:"-" flag:"ldap-user-group"`
+	LDAPUserRole          string        `yaml:"LDAPUserRole" json:"-" flag:"ldap-user-role"`
+	LDAPGuestGroup        string        `yaml:"LDAPGuestGroup" json:"-" flag:"ldap-guest-group"`
 	LogLevel              string        `yaml:"LogLevel" json:"-" flag:"log-level"`
 	Prod                  bool          `yaml:"Prod" json:"Prod" flag:"prod"`
 	Debug                 bool          `yaml:"Debug" json:"Debug" flag:"debug"`
@@ -40,12 +60,18 @@ type Options struct {
 	Experimental          bool          `yaml:"Experimental" json:"Experimental" flag:"experimental"`
 	ConfigPath            string        `yaml:"ConfigPath" json:"-" flag:"config-path"`
 	DefaultsYaml          string        `json:"-" yaml:"-" flag:"defaults-yaml"`
+	RolesYaml             string        `yaml:"RolesYaml" json:"-" flag:"roles-yaml"`
 	OriginalsPath         string        `yaml:"OriginalsPath" json:"-" flag:"originals-path"`
 	OriginalsLimit        int           `yaml:"OriginalsLimit" json:"OriginalsLimit" flag:"originals-limit"`
 	ResolutionLimit       int           `yaml:"ResolutionLimit" json:"ResolutionLimit" flag:"resolution-limit"`
 	StoragePath           string        `yaml:"StoragePath" json:"-" flag:"storage-path"`
 	SidecarPath           string        `yaml:"SidecarPath" json:"-" flag:"sidecar-path"`
 	BackupPath            string        `yaml:"BackupPath" json:"-" flag:"backup-path"`
+	BackupCompression     string        `yaml:"BackupCompression" json:"-" flag:"backup-compression"`
+	BackupDaily           int           `yaml:"BackupDaily" json:"-" flag:"backup-daily"`
+	BackupWeekly          int           `yaml:"BackupWeekly" json:"-" flag:"backup-weekly"`
+	BackupMonthly         int           `yaml:"BackupMonthly" json:"-" flag:"backup-monthly"`
+	TrashRetention        int           `yaml:"TrashRetention" json:"-" flag:"trash-retention"`
 	CachePath             string        `yaml:"CachePath" json:"-" flag:"cache-path"`
 	ImportPath            string        `yaml:"ImportPath" json:"-" flag:"import-path"`
 	ImportDest            string        `yaml:"ImportDest" json:"-" flag:"import-dest"`
@@ -56,6 +82,15 @@ type Options struct {
 	WakeupInterval        time.Duration `yaml:"WakeupInterval" json:"WakeupInterval" flag:"wakeup-interval"`
 	AutoIndex             int           `yaml:"AutoIndex" json:"AutoIndex" flag:"auto-index"`
 	AutoImport            int           `yaml:"AutoImport" json:"AutoImport" flag:"auto-import"`
+	IndexSchedule         string        `yaml:"IndexSchedule" json:"-" flag:"index-schedule"`
+	ImportSchedule        string        `yaml:"ImportSchedule" json:"-" flag:"import-schedule"`
+	FacesSchedule         string        `yaml:"FacesSchedule" json:"-" flag:"faces-schedule"`
+	MomentsSchedule       string        `yaml:"MomentsSchedule" json:"-" flag:"moments-schedule"`
+	PlacesSchedule        string        `yaml:"PlacesSchedule" json:"-" flag:"places-schedule"`
+	CleanupSchedule       string        `yaml:"CleanupSchedule" json:"-" flag:"cleanup-schedule"`
+	BackupSchedule        string        `yaml:"BackupSchedule" json:"-" flag:"backup-schedule"`
+	ThumbsSchedule        string        `yaml:"ThumbsSchedule" json:"-" flag:"thumbs-schedule"`
+	TrashSchedule         string        `yaml:"TrashSchedule" json:"-" flag:"trash-schedule"`
 	DisableWebDAV         bool          `yaml:"DisableWebDAV" json:"DisableWebDAV" flag:"disable-webdav"`
 	DisableBackups        bool          `yaml:"DisableBackups" json:"DisableBackups" flag:"disable-backups"`
 	DisableSettings       bool          `yaml:"DisableSettings" json:"-" flag:"disable-settings"`
@@ -109,6 +144,14 @@ type Options struct {
 	DatabasePassword      string        `yaml:"DatabasePassword" json:"-" flag:"database-password"`
 	DatabaseConns         int           `yaml:"DatabaseConns" json:"-" flag:"database-conns"`
 	DatabaseConnsIdle     int           `yaml:"DatabaseConnsIdle" json:"-" flag:"database-conns-idle"`
+	MQTTUri               string        `yaml:"MQTTUri" json:"-" flag:"mqtt-uri"`
+	MQTTClient            string        `yaml:"MQTTClient" json:"-" flag:"mqtt-client"`
+	MQTTUser              string        `yaml:"MQTTUser" json:"-" flag:"mqtt-user"`
+	MQTTPassword          string        `yaml:"MQTTPassword" json:"-" flag:"mqtt-password"`
+	MQTTPrefix            string        `yaml:"MQTTPrefix" json:"-" flag:"mqtt-prefix"`
+	MQTTQoS               int           `yaml:"MQTTQoS" json:"-" flag:"mqtt-qos"`
+	MQTTInsecure          bool          `yaml:"MQTTInsecure" json:"-" flag:"mqtt-insecure"`
+	MQTTCa                string        `yaml:"MQTTCa" json:"-" flag:"mqtt-ca"`
 	DarktableBin          string        `yaml:"DarktableBin" json:"-" flag:"darktable-bin"`
 	DarktableCachePath    string        `yaml:"DarktableCachePath" json:"-" flag:"darktable-cache-path"`
 	DarktableConfigPath   string        `yaml:"DarktableConfigPath" json:"-" flag:"darktable-config-path"`
@@ -27,6 +27,26 @@ func (c *Config) Report() (rows [][]string, cols []string) {
 		{"public", fmt.Sprintf("%t", c.Public())},
 		{"sess-maxage", fmt.Sprintf("%d", c.SessMaxAge())},
 		{"sess-timeout", fmt.Sprintf("%d", c.SessTimeout())},
+		{"audit-retention", fmt.Sprintf("%d", c.AuditRetention())},
+		{"oidc-uri", c.OIDCUri()},
+		{"oidc-client", c.OIDCClient()},
+		{"oidc-secret", strings.Repeat("*", utf8.RuneCountInString(c.OIDCSecret()))},
+		{"oidc-scopes", strings.Join(c.OIDCScopes(), " ")},
+		{"oidc-role-claim", c.OIDCRoleClaim()},
+		{"oidc-role-map", c.options.OIDCRoleMap},
+		{"oidc-role", c.OIDCRole().String()},
+		{"oidc-register", fmt.Sprintf("%t", c.OIDCRegister())},
+		{"auth-provider", c.AuthProvider()},
+		{"ldap-uri", c.LDAPUri()},
+		{"ldap-insecure", fmt.Sprintf("%t", c.LDAPInsecure())},
+		{"ldap-bind-dn", c.LDAPBindDN()},
+		{"ldap-bind-password", strings.Repeat("*", utf8.RuneCountInString(c.LDAPBindPassword()))},
+		{"ldap-base-dn", c.LDAPBaseDN()},
+		{"ldap-user-filter", c.LDAPUserFilter()},
+		{"ldap-admin-group", c.LDAPAdminGroup()},
+		{"ldap-user-group", c.LDAPUserGroup()},
+		{"ldap-user-role", c.LDAPUserRole().String()},
+		{"ldap-guest-group", c.LDAPGuestGroup()},
 
 		// Logging.
 		{"log-level", c.LogLevel().String()},
@@ -39,6 +59,7 @@ func (c *Config) Report() (rows [][]string, cols []string) {
 		{"options-yaml", c.OptionsYaml()},
 		{"defaults-yaml", c.DefaultsYaml()},
 		{"settings-yaml", c.SettingsYaml()},
+		{"roles-yaml", c.RolesYaml()},
 
 		// Originals.
 		{"originals-path", c.OriginalsPath()},
@@ -50,6 +71,12 @@ func (c *Config) Report() (rows [][]string, cols []string) {
 		{"sidecar-path", c.SidecarPath()},
 		{"albums-path", c.AlbumsPath()},
 		{"backup-path", c.BackupPath()},
+		{"backup-compression", c.BackupCompression().String()},
+		{"backup-daily", fmt.Sprintf("%d", c.BackupRetention().Daily)},
+		{"backup-weekly", fmt.Sprintf("%d", c.BackupRetention().Weekly)},
+		{"backup-monthly", fmt.Sprintf("%d", c.BackupRetention().Monthly)},
+		{"trash-path", c.TrashPath()},
+		{"trash-retention", fmt.Sprintf("%d", c.TrashRetention())},
 		{"cache-path", c.CachePath()},
 		{"cmd-cache-path", c.CmdCachePath()},
 		{"thumb-cache-path", c.ThumbCachePath()},
@@ -68,6 +95,17 @@ func (c *Config) Report() (rows [][]string, cols []string) {
 		{"auto-index", fmt.Sprintf("%d", c.AutoIndex()/time.Second)},
 		{"auto-import", fmt.Sprintf("%d", c.AutoImport()/time.Second)},
 
+		// Scheduled Jobs.
+		{"index-schedule", c.IndexSchedule()},
+		{"import-schedule", c.ImportSchedule()},
+		{"faces-schedule", c.FacesSchedule()},
+		{"moments-schedule", c.MomentsSchedule()},
+		{"places-schedule", c.PlacesSchedule()},
+		{"cleanup-schedule", c.CleanupSchedule()},
+		{"backup-schedule", c.BackupSchedule()},
+		{"thumbs-schedule", c.ThumbsSchedule()},
+		{"trash-schedule", c.TrashSchedule()},
+
 		// Feature Flags.
 		{"read-only", fmt.Sprintf("%t", c.ReadOnly())},
 		{"experimental", fmt.Sprintf("%t", c.Experimental())},
@@ -148,6 +186,16 @@ func (c *Config) Report() (rows [][]string, cols []string) {
 		{"database-conns", fmt.Sprintf("%d", c.DatabaseConns())},
 		{"database-conns-idle", fmt.Sprintf("%d", c.DatabaseConnsIdle())},
 
+		// MQTT.
+		{"mqtt-uri", c.MQTTUri()},
+		{"mqtt-client", c.MQTTClient()},
+		{"mqtt-user", c.MQTTUser()},
+		{"mqtt-password", strings.Repeat("*", utf8.RuneCountInString(c.MQTTPassword()))},
+		{"mqtt-prefix", c.MQTTPrefix()},
+		{"mqtt-qos", fmt.Sprintf("%d", c.MQTTQoS())},
+		{"mqtt-insecure", fmt.Sprintf("%t", c.MQTTInsecure())},
+		{"mqtt-ca", c.MQTTCa()},
+
 		// External Tools.
 		{"darktable-bin", c.DarktableBin()},
 		{"darktable-cache-path", c.DarktableCachePath()},
@@ -0,0 +1,178 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	gc "github.com/patrickmn/go-cache"
+	"github.com/sirupsen/logrus"
+
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// AuditRetention specifies how long audit events are stored, or 0 to keep them.
+var AuditRetention time.Duration
+
+// AuditPurgeInterval specifies how often expired audit events are deleted.
+var AuditPurgeInterval = time.Hour
+
+// auditUserCache caches the user account of a session reference to avoid a database query for each event.
+var auditUserCache = gc.New(15*time.Minute, 5*time.Minute)
+
+// auditKeywords are the words in the action or outcome of informational events that make them security-relevant,
+// e.g. logins, logouts, role and permission changes, or deleted resources.
+var auditKeywords = []string{
+	"login", "logged out", "password", "two-factor", "role", "superadmin", "shared with", "access revoked",
+	"token", "deleted", "archived", "trash", "removed", "revoked", "restore",
+}
+
+// AuditEvent represents a security-relevant event such as a login or an access check.
+type AuditEvent struct {
+	ID          uint      `gorm:"primary_key" json:"ID" yaml:"ID"`
+	EventTime   time.Time `sql:"index" json:"Time" yaml:"Time"`
+	EventLevel  string    `gorm:"type:VARBINARY(32);index;" json:"Level" yaml:"Level"`
+	ClientIP    string    `gorm:"size:64;column:client_ip;index;" json:"ClientIP,omitempty" yaml:"ClientIP,omitempty"`
+	SessionRef  string    `gorm:"type:VARBINARY(16);index;" json:"Session,omitempty" yaml:"Session,omitempty"`
+	UserUID     string    `gorm:"type:VARBINARY(42);index;" json:"UserUID,omitempty" yaml:"UserUID,omitempty"`
+	UserName    string    `gorm:"size:64;index;" json:"UserName,omitempty" yaml:"UserName,omitempty"`
+	Action      string    `gorm:"size:255;" json:"Action" yaml:"Action"`
+	ResourceUID string    `gorm:"type:VARBINARY(42);index;" json:"ResourceUID,omitempty" yaml:"ResourceUID,omitempty"`
+	Outcome     string    `gorm:"size:255;" json:"Outcome,omitempty" yaml:"Outcome,omitempty"`
+	Message     string    `gorm:"type:VARBINARY(2048)" json:"Message" yaml:"Message"`
+}
+
+// AuditEvents represents a list of audit events.
+type AuditEvents []AuditEvent
+
+// TableName returns the entity table name.
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
+
+// NewAuditEvent creates a new audit event based on the published event data.
+func NewAuditEvent(data event.Data) *AuditEvent {
+	m := &AuditEvent{
+		EventTime:   TimeStamp(),
+		EventLevel:  auditValue(data, "level"),
+		ClientIP:    txt.Clip(auditValue(data, "ip"), txt.ClipIP),
+		SessionRef:  txt.Clip(auditValue(data, "session"), 16),
+		UserName:    txt.Clip(auditValue(data, "user"), txt.ClipUserName),
+		Action:      txt.Clip(auditValue(data, "action"), txt.ClipEmail),
+		ResourceUID: txt.Clip(auditValue(data, "uid"), 42),
+		Outcome:     txt.Clip(auditValue(data, "outcome"), txt.ClipEmail),
+		Message:     txt.Clip(auditValue(data, "message"), txt.ClipText),
+	}
+
+	if t, ok := data["time"].(time.Time); ok {
+		m.EventTime = t
+	}
+
+	// Add the user account based on the session reference, if the event is stored.
+	if m.SessionRef != "" && m.Relevant() {
+		s := Session{}
+
+		if cached, ok := auditUserCache.Get(m.SessionRef); ok {
+			s = cached.(Session)
+		} else if err := UnscopedDb().Select("user_uid, user_name").First(&s, "ref_id = ?", m.SessionRef).Error; err == nil {
+			auditUserCache.SetDefault(m.SessionRef, s)
+		}
+
+		m.UserUID = s.UserUID
+
+		if m.UserName == "" {
+			m.UserName = s.UserName
+		}
+	}
+
+	return m
+}
+
+// Relevant checks if the event is security-relevant and should therefore be stored, e.g. warnings and errors
+// such as denied requests, logins, logouts, role and permission changes, or deleted resources. Informational
+// events such as granted requests are not stored.
+func (m *AuditEvent) Relevant() bool {
+	if level, err := logrus.ParseLevel(m.EventLevel); err == nil && level <= logrus.WarnLevel {
+		return true
+	}
+
+	s := strings.ToLower(m.Action + " " + m.Outcome)
+
+	for _, w := range auditKeywords {
+		if strings.Contains(s, w) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// auditValue returns the event data value with the specified key as string.
+func auditValue(data event.Data, key string) string {
+	if val, ok := data[key]; !ok || val == nil {
+		return ""
+	} else if s, ok := val.(string); ok {
+		return s
+	} else {
+		return fmt.Sprint(val)
+	}
+}
+
+// Create inserts a new record into the database.
+func (m *AuditEvent) Create() error {
+	return UnscopedDb().Create(m).Error
+}
+
+// LogEvents stores security-relevant audit events in the database.
+func (AuditEvent) LogEvents() {
+	s := event.Subscribe("audit.*")
+
+	defer func() {
+		event.Unsubscribe(s)
+	}()
+
+	var purged time.Time
+
+	// Events are held back while a backup is restored and saved along with the next event.
+	var held []*AuditEvent
+
+	for msg := range s.Receiver {
+		if m := NewAuditEvent(msg.Fields); m.Relevant() {
+			held = append(held, m)
+		}
+
+		if mutex.RestoreWorker.Running() {
+			continue
+		}
+
+		for _, m := range held {
+			if err := m.Create(); err != nil {
+				log.Errorf("audit: %s (save event)", err)
+			}
+		}
+
+		held = nil
+
+		// Delete expired events from time to time.
+		if time.Since(purged) > AuditPurgeInterval {
+			purged = time.Now()
+
+			if _, err := PurgeAuditEvents(); err != nil {
+				log.Errorf("audit: %s (purge events)", err)
+			}
+		}
+	}
+}
+
+// PurgeAuditEvents deletes events older than the retention period and returns the number of deleted events.
+func PurgeAuditEvents() (int64, error) {
+	if AuditRetention <= 0 {
+		return 0, nil
+	}
+
+	res := UnscopedDb().Where("event_time < ?", TimeStamp().Add(-1*AuditRetention)).Delete(&AuditEvent{})
+
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"time"
+)
+
+type AuditEventMap map[string]AuditEvent
+
+// Get returns a fixture for use in tests.
+func (m AuditEventMap) Get(name string) AuditEvent {
+	if result, ok := m[name]; ok {
+		return result
+	}
+
+	return AuditEvent{}
+}
+
+// Pointer returns a fixture pointer for use in tests.
+func (m AuditEventMap) Pointer(name string) *AuditEvent {
+	if result, ok := m[name]; ok {
+		return &result
+	}
+
+	return &AuditEvent{}
+}
+
+// AuditEventFixtures specifies fixtures for use in tests.
+var AuditEventFixtures = AuditEventMap{
+	"album-deleted": {
+		ID:          1000001,
+		EventTime:   time.Date(2022, 10, 10, 9, 30, 0, 0, time.UTC),
+		EventLevel:  "info",
+		ClientIP:    "192.0.2.10",
+		SessionRef:  "sessxkkcabce",
+		UserUID:     "uqxetse3cy5eo9z2",
+		UserName:    "alice",
+		Action:      "album as6sg6bxpogaaba8",
+		ResourceUID: "as6sg6bxpogaaba8",
+		Outcome:     "deleted",
+		Message:     "192.0.2.10 › session sessxkkcabce › album as6sg6bxpogaaba8 › deleted",
+	},
+	"login-failed": {
+		ID:         1000002,
+		EventTime:  time.Date(2022, 10, 11, 18, 0, 0, 0, time.UTC),
+		EventLevel: "warning",
+		ClientIP:   "198.51.100.7",
+		UserName:   "bob",
+		Action:     "login as 'bob'",
+		Outcome:    "invalid password",
+		Message:    "198.51.100.7 › login as 'bob' › invalid password",
+	},
+	"access-denied": {
+		ID:          1000003,
+		EventTime:   time.Date(2022, 10, 12, 7, 15, 0, 0, time.UTC),
+		EventLevel:  "error",
+		ClientIP:    "192.0.2.10",
+		SessionRef:  "sessxkkcabcf",
+		UserUID:     "uqxc08w3d0ej2283",
+		UserName:    "bob",
+		Action:      "access photo pt9jtdre2lvl0yh7 as visitor",
+		ResourceUID: "pt9jtdre2lvl0yh7",
+		Outcome:     "denied",
+		Message:     "192.0.2.10 › session sessxkkcabcf › access photo pt9jtdre2lvl0yh7 as visitor › denied",
+	},
+}
+
+// CreateAuditEventFixtures creates the fixtures specified above.
+func CreateAuditEventFixtures() {
+	for _, entity := range AuditEventFixtures {
+		Db().Create(&entity)
+	}
+}
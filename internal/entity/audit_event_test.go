@@ -0,0 +1,124 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/event"
+)
+
+func TestNewAuditEvent(t *testing.T) {
+	t.Run("Session", func(t *testing.T) {
+		sess := NewSession(UnixDay, UnixHour)
+		sess.SetUser(UserFixtures.Pointer("alice"))
+
+		if err := sess.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		ev := []string{"192.0.2.1", "session %s", "album %s", "deleted"}
+		data := event.AuditData(ev, sess.RefID, "as6sg6bxpogaaba8")
+		data["level"] = "info"
+		data["message"] = event.Format(ev, sess.RefID, "as6sg6bxpogaaba8")
+
+		m := NewAuditEvent(data)
+
+		assert.Equal(t, "info", m.EventLevel)
+		assert.Equal(t, "192.0.2.1", m.ClientIP)
+		assert.Equal(t, sess.RefID, m.SessionRef)
+		assert.Equal(t, "uqxetse3cy5eo9z2", m.UserUID)
+		assert.Equal(t, "alice", m.UserName)
+		assert.Equal(t, "album as6sg6bxpogaaba8", m.Action)
+		assert.Equal(t, "as6sg6bxpogaaba8", m.ResourceUID)
+		assert.Equal(t, "deleted", m.Outcome)
+		assert.Equal(t, "192.0.2.1 › session "+sess.RefID+" › album as6sg6bxpogaaba8 › deleted", m.Message)
+		assert.False(t, m.EventTime.IsZero())
+	})
+	t.Run("NoSession", func(t *testing.T) {
+		now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+		m := NewAuditEvent(event.Data{"time": now, "level": "warning", "ip": "198.51.100.7", "action": "webdav login as 'bob'", "outcome": "invalid password"})
+
+		assert.Equal(t, now, m.EventTime)
+		assert.Equal(t, "warning", m.EventLevel)
+		assert.Equal(t, "", m.SessionRef)
+		assert.Equal(t, "", m.UserUID)
+		assert.Equal(t, "invalid password", m.Outcome)
+	})
+}
+
+func TestAuditEvent_Relevant(t *testing.T) {
+	t.Run("Granted", func(t *testing.T) {
+		m := AuditEvent{EventLevel: "info", Action: "view photos as admin", Outcome: "granted"}
+		assert.False(t, m.Relevant())
+	})
+	t.Run("Denied", func(t *testing.T) {
+		m := AuditEvent{EventLevel: "error", Action: "view photos as guest", Outcome: "denied"}
+		assert.True(t, m.Relevant())
+	})
+	t.Run("Login", func(t *testing.T) {
+		m := AuditEvent{EventLevel: "info", Action: "login as 'alice'", Outcome: "succeeded"}
+		assert.True(t, m.Relevant())
+	})
+	t.Run("Logout", func(t *testing.T) {
+		m := AuditEvent{EventLevel: "info", Action: "session deleted", Outcome: "logged out"}
+		assert.True(t, m.Relevant())
+	})
+	t.Run("Role", func(t *testing.T) {
+		m := AuditEvent{EventLevel: "info", Action: "user uqxetse3cy5eo9z2", Outcome: "role changed to admin"}
+		assert.True(t, m.Relevant())
+	})
+	t.Run("Deleted", func(t *testing.T) {
+		m := AuditEvent{EventLevel: "info", Action: "album as6sg6bxpogaaba8", Outcome: "deleted"}
+		assert.True(t, m.Relevant())
+	})
+}
+
+func TestAuditEvent_Create(t *testing.T) {
+	m := NewAuditEvent(event.Data{"level": "info", "action": "test", "message": "test"})
+
+	if err := m.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NotEmpty(t, m.ID)
+}
+
+func TestPurgeAuditEvents(t *testing.T) {
+	t.Run("KeepForever", func(t *testing.T) {
+		AuditRetention = 0
+
+		n, err := PurgeAuditEvents()
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), n)
+	})
+	t.Run("Expired", func(t *testing.T) {
+		m := AuditEvent{EventTime: TimeStamp().Add(-48 * time.Hour), EventLevel: "info", Action: "expired"}
+
+		if err := m.Create(); err != nil {
+			t.Fatal(err)
+		}
+
+		AuditRetention = 24 * time.Hour
+
+		// Restore the fixtures, which are older than the retention period.
+		defer func() {
+			AuditRetention = 0
+			CreateAuditEventFixtures()
+		}()
+
+		var expired int64
+
+		if err := UnscopedDb().Model(&AuditEvent{}).Where("event_time < ?", TimeStamp().Add(-1*AuditRetention)).Count(&expired).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		n, err := PurgeAuditEvents()
+
+		assert.NoError(t, err)
+		assert.Equal(t, expired, n)
+		assert.GreaterOrEqual(t, n, int64(1))
+	})
+}
@@ -10,8 +10,10 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jinzhu/gorm"
 
+	"github.com/photoprism/photoprism/internal/acl"
 	"github.com/photoprism/photoprism/internal/event"
 	"github.com/photoprism/photoprism/internal/i18n"
+	"github.com/photoprism/photoprism/internal/mutex"
 	"github.com/photoprism/photoprism/pkg/clean"
 	"github.com/photoprism/photoprism/pkg/rnd"
 	"github.com/photoprism/photoprism/pkg/txt"
@@ -169,6 +171,11 @@ func (m *Session) Create() (err error) {
 
 // Save updates the record in the database or inserts a new record if it does not already exist.
 func (m *Session) Save() error {
+	// Don't write to the database while a backup is restored.
+	if mutex.RestoreWorker.Running() {
+		return nil
+	}
+
 	if err := Db().Save(m).Error; err != nil {
 		return err
 	} else if rnd.IsSessionID(m.ID) {
@@ -262,7 +269,9 @@ func (m *Session) ChangePassword(newPw string) (err error) {
 	}
 
 	// Change password.
-	err = u.SetPassword(newPw)
+	if err = u.SetPassword(newPw); err != nil {
+		return err
+	}
 
 	m.SetPreviewToken(u.PreviewToken)
 	m.SetDownloadToken(u.DownloadToken)
@@ -464,6 +473,16 @@ func (m *Session) TimedOut() bool {
 	}
 }
 
+// RecentLogin checks if the session was created by logging in with the specified provider
+// no longer ago than the specified duration.
+func (m *Session) RecentLogin(provider string, d time.Duration) bool {
+	if m.AuthProvider != provider || m.LoginAt.IsZero() {
+		return false
+	}
+
+	return m.LoginAt.After(UTC().Add(-1 * d))
+}
+
 // Expired checks if the session has expired.
 func (m *Session) Expired() bool {
 	if m.SessExpires <= 0 {
@@ -483,6 +502,11 @@ func (m *Session) UpdateLastActive() *Session {
 
 	m.LastActive = UnixTime()
 
+	// Don't write to the database while a backup is restored.
+	if mutex.RestoreWorker.Running() {
+		return m
+	}
+
 	if err := Db().Model(m).UpdateColumn("LastActive", m.LastActive).Error; err != nil {
 		event.AuditWarn([]string{m.IP(), "session %s", "failed to update last active time", "%s"}, m.RefID, err)
 	}
@@ -500,6 +524,16 @@ func (m *Session) Valid() bool {
 	return m.User().IsRegistered() || m.IsVisitor() && m.HasShares()
 }
 
+// Scope returns the authorization scope of the session, an empty scope does not restrict access.
+func (m *Session) Scope() acl.Scope {
+	if s, err := acl.ParseScope(m.AuthScope); err != nil {
+		// Deny access if the scope is invalid.
+		return acl.Scope{acl.ResourceDefault: acl.Grant{}}
+	} else {
+		return s
+	}
+}
+
 // Abort aborts the request with the appropriate error code if access to the requested resource is denied.
 func (m *Session) Abort(c *gin.Context) bool {
 	if m.Valid() {
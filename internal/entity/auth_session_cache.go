@@ -106,3 +106,16 @@ func DeleteFromSessionCache(id string) {
 
 	sessionCache.Delete(id)
 }
+
+// FlushUserSessions removes the sessions of the specified user from the cache, e.g. after shares have changed.
+func FlushUserSessions(userUid string) {
+	if userUid == "" {
+		return
+	}
+
+	for id, item := range sessionCache.Items() {
+		if s, ok := item.Object.(*Session); ok && s.UserUID == userUid {
+			sessionCache.Delete(id)
+		}
+	}
+}
@@ -64,7 +64,6 @@ func (data *SessionData) RedeemToken(token string) (n int) {
 	// Append new shares.
 	for _, link := range links {
 		data.Shares = append(data.Shares, link.ShareUID)
-		link.Redeem()
 	}
 
 	return n
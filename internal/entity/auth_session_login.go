@@ -30,6 +30,26 @@ func (m *Session) LogIn(f form.Login, c *gin.Context) (err error) {
 		name := f.Name()
 		user := FindUserByName(name)
 
+		// Authenticate with the directory server if enabled, unless it is a local account.
+		external := LdapAuth != nil && (user == nil || user.AuthProvider == AuthProviderLDAP)
+
+		if external {
+			account, err := LdapAuth(name, f.Password)
+
+			if err == nil {
+				user, err = SyncProviderUser(AuthProviderLDAP, account)
+			}
+
+			if err != nil {
+				message := err.Error()
+				limiter.Auth.Reserve(m.IP())
+				event.AuditErr([]string{m.IP(), "session %s", "login as %s", "ldap", "%s"}, m.RefID, clean.LogQuote(name), err)
+				event.LoginError(m.IP(), AuthProviderLDAP, name, m.UserAgent, message)
+				m.Status = http.StatusUnauthorized
+				return i18n.Error(i18n.ErrInvalidCredentials)
+			}
+		}
+
 		// User found?
 		if user == nil {
 			message := "account not found"
@@ -50,18 +70,38 @@ func (m *Session) LogIn(f form.Login, c *gin.Context) (err error) {
 		}
 
 		// Password valid?
-		if user.WrongPassword(f.Password) {
+		if !external && user.WrongPassword(f.Password) {
 			message := "incorrect password"
 			limiter.Auth.Reserve(m.IP())
 			event.AuditErr([]string{m.IP(), "session %s", "login as %s", message}, m.RefID, clean.LogQuote(name))
 			event.LoginError(m.IP(), "api", name, m.UserAgent, message)
 			m.Status = http.StatusUnauthorized
 			return i18n.Error(i18n.ErrInvalidCredentials)
-		} else {
-			event.AuditInfo([]string{m.IP(), "session %s", "login as %s", "succeeded"}, m.RefID, clean.LogQuote(name))
-			event.LoginInfo(m.IP(), "api", name, m.UserAgent)
 		}
 
+		// Verification code required?
+		if passcode := user.Passcode(); passcode != nil && passcode.Verified() {
+			if !f.HasPasscode() {
+				event.AuditInfo([]string{m.IP(), "session %s", "login as %s", "verification code required"}, m.RefID, clean.LogQuote(name))
+				m.Status = http.StatusUnauthorized
+				return ErrPasscodeRequired
+			}
+
+			if recovery, err := passcode.Verify(f.Passcode); err != nil {
+				message := "incorrect verification code"
+				limiter.Auth.Reserve(m.IP())
+				event.AuditErr([]string{m.IP(), "session %s", "login as %s", message}, m.RefID, clean.LogQuote(name))
+				event.LoginError(m.IP(), "api", name, m.UserAgent, message)
+				m.Status = http.StatusUnauthorized
+				return i18n.Error(i18n.ErrInvalidPasscode)
+			} else if recovery {
+				event.AuditWarn([]string{m.IP(), "session %s", "login as %s", "recovery code used, %d left"}, m.RefID, clean.LogQuote(name), passcode.RecoveryCodesLeft())
+			}
+		}
+
+		event.AuditInfo([]string{m.IP(), "session %s", "login as %s", "succeeded"}, m.RefID, clean.LogQuote(name))
+		event.LoginInfo(m.IP(), "api", name, m.UserAgent)
+
 		m.SetUser(user)
 	}
 
@@ -69,6 +109,22 @@ func (m *Session) LogIn(f form.Login, c *gin.Context) (err error) {
 	if f.HasToken() {
 		user := m.User()
 
+		// Links must not be expired and protected links require a valid password.
+		links, status, err := VerifyLinks(f.AuthToken, "")
+
+		if err != nil {
+			limiter.Auth.Reserve(m.IP())
+			event.AuditWarn([]string{m.IP(), "session %s", "share token %s", "%s"}, m.RefID, clean.LogQuote(f.AuthToken), err)
+			m.Status = status
+			return err
+		} else if links.InvalidPassword(f.SharePassword()) {
+			limiter.Auth.Reserve(m.IP())
+			event.AuditWarn([]string{m.IP(), "session %s", "share token %s", "incorrect password"}, m.RefID, clean.LogQuote(f.AuthToken))
+			event.LoginError(m.IP(), "api", "", m.UserAgent, "incorrect share password")
+			m.Status = http.StatusUnauthorized
+			return i18n.Error(i18n.ErrInvalidPassword)
+		}
+
 		// Redeem token.
 		if user.IsRegistered() {
 			if shares := user.RedeemToken(f.AuthToken); shares == 0 {
@@ -93,6 +149,9 @@ func (m *Session) LogIn(f form.Login, c *gin.Context) (err error) {
 			event.AuditInfo([]string{m.IP(), "session %s", "token redeemed for %d shares"}, m.RefID, shares, data)
 		}
 
+		// Count each visit, not only the first one per session or user.
+		links.Redeem()
+
 		// Upgrade session to visitor.
 		if user.IsUnknown() {
 			user = &Visitor
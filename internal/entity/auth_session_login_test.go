@@ -1,13 +1,16 @@
 package entity
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
 
 	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/pkg/totp"
 )
 
 func TestSessionLogIn(t *testing.T) {
@@ -73,4 +76,162 @@ func TestSessionLogIn(t *testing.T) {
 			t.Fatal("login should fail")
 		}
 	})
+	t.Run("ProtectedLink", func(t *testing.T) {
+		m := NewSession(UnixDay, UnixHour*6)
+		m.SetClientIP(clientIp)
+
+		// Create login form.
+		frm := form.Login{
+			Password:  "wrong",
+			AuthToken: "3jxf3jfn2k",
+		}
+
+		// Try to redeem token with wrong password.
+		if err := m.LogIn(frm, nil); err == nil {
+			t.Fatal("login should fail")
+		} else {
+			assert.Equal(t, http.StatusUnauthorized, m.HttpStatus())
+		}
+
+		// Try again with correct password.
+		frm.Password = "Secret123!"
+
+		if err := m.LogIn(frm, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, m.HasShare("at9lxuqxpogaaba9"))
+	})
+	t.Run("ExpiredLink", func(t *testing.T) {
+		m := NewSession(UnixDay, UnixHour*6)
+		m.SetClientIP(clientIp)
+
+		// Create login form.
+		frm := form.Login{
+			AuthToken: "8jxf3jfn2k",
+		}
+
+		// Try to redeem expired token.
+		if err := m.LogIn(frm, nil); err == nil {
+			t.Fatal("login should fail")
+		} else {
+			assert.Equal(t, http.StatusGone, m.HttpStatus())
+		}
+	})
+	t.Run("CountViews", func(t *testing.T) {
+		link := NewLink("at9lxuqxpogaaba8", false, false)
+
+		if err := link.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		defer link.Delete()
+
+		m := NewSession(UnixDay, UnixHour*6)
+		m.SetClientIP(clientIp)
+
+		frm := form.Login{
+			AuthToken: link.LinkToken,
+		}
+
+		// Each visit must be counted, even if the session already has the token.
+		for i := 0; i < 2; i++ {
+			if err := m.LogIn(frm, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if found := FindLink(link.LinkUID); assert.NotNil(t, found) {
+			assert.Equal(t, uint(2), found.LinkViews)
+		}
+	})
+	t.Run("ExhaustedLink", func(t *testing.T) {
+		m := NewSession(UnixDay, UnixHour*6)
+		m.SetClientIP(clientIp)
+
+		// Create login form.
+		frm := form.Login{
+			AuthToken: "9jxf3jfn2k",
+		}
+
+		// Try to redeem token without views left.
+		if err := m.LogIn(frm, nil); err == nil {
+			t.Fatal("login should fail")
+		} else {
+			assert.Equal(t, http.StatusForbidden, m.HttpStatus())
+		}
+	})
+}
+
+func TestSessionLogIn_Passcode(t *testing.T) {
+	passcode, err := NewUserPasscode("uqxqg7i1kperxvu7")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, _ := totp.Code(passcode.Secret(), TimeStamp())
+
+	if _, err = passcode.Activate(code); err != nil {
+		t.Fatal(err)
+	}
+
+	defer passcode.Delete()
+
+	t.Run("Required", func(t *testing.T) {
+		m := NewSession(UnixDay, UnixHour*6)
+		err := m.LogIn(form.Login{UserName: "friend", Password: "!Friend321"}, nil)
+		assert.Equal(t, ErrPasscodeRequired, err)
+		assert.Equal(t, http.StatusUnauthorized, m.HttpStatus())
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		m := NewSession(UnixDay, UnixHour*6)
+		err := m.LogIn(form.Login{UserName: "friend", Password: "!Friend321", Passcode: code}, nil)
+		assert.Error(t, err)
+		assert.NotEqual(t, ErrPasscodeRequired, err)
+	})
+	t.Run("Success", func(t *testing.T) {
+		next, _ := totp.Code(passcode.Secret(), TimeStamp().Add(totp.Period))
+		m := NewSession(UnixDay, UnixHour*6)
+		assert.NoError(t, m.LogIn(form.Login{UserName: "friend", Password: "!Friend321", Passcode: next}, nil))
+		assert.Equal(t, "uqxqg7i1kperxvu7", m.UserUID)
+	})
+}
+
+func TestSessionLogIn_Ldap(t *testing.T) {
+	LdapAuth = func(name, password string) (*ProviderAccount, error) {
+		if name != "ldap-max" || password != "Max123!" {
+			return nil, fmt.Errorf("invalid credentials")
+		}
+
+		return &ProviderAccount{
+			AuthID:      "uid=ldap-max,ou=people,dc=example,dc=com",
+			UserName:    "ldap-max",
+			UserEmail:   "max@ldap.example.com",
+			DisplayName: "Max Mustermann",
+			UserRole:    "admin",
+		}, nil
+	}
+
+	defer func() { LdapAuth = nil }()
+
+	t.Run("Success", func(t *testing.T) {
+		m := NewSession(UnixDay, UnixHour*6)
+		assert.NoError(t, m.LogIn(form.Login{UserName: "ldap-max", Password: "Max123!"}, nil))
+
+		user := m.User()
+		assert.Equal(t, "ldap-max", user.Name())
+		assert.Equal(t, AuthProviderLDAP, user.AuthProvider)
+		assert.Equal(t, "max@ldap.example.com", user.UserEmail)
+	})
+	t.Run("WrongPassword", func(t *testing.T) {
+		m := NewSession(UnixDay, UnixHour*6)
+		assert.Error(t, m.LogIn(form.Login{UserName: "ldap-max", Password: "wrong"}, nil))
+		assert.Equal(t, http.StatusUnauthorized, m.HttpStatus())
+	})
+	t.Run("LocalUser", func(t *testing.T) {
+		m := NewSession(UnixDay, UnixHour*6)
+		assert.NoError(t, m.LogIn(form.Login{UserName: "bob", Password: "Bobbob123!"}, nil))
+		assert.Equal(t, "uqxc08w3d0ej2283", m.UserUID)
+	})
 }
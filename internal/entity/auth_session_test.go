@@ -2,6 +2,7 @@ package entity
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -158,3 +159,13 @@ func TestSession_Expired(t *testing.T) {
 		assert.Equal(t, m.ExpiresAt(), m.TimeoutAt())
 	})
 }
+
+func TestSession_RecentLogin(t *testing.T) {
+	m := &Session{AuthProvider: AuthProviderOIDC}
+	assert.False(t, m.RecentLogin(AuthProviderOIDC, time.Minute))
+
+	m.LoginAt = TimeStamp().Add(-5 * time.Minute)
+	assert.True(t, m.RecentLogin(AuthProviderOIDC, 10*time.Minute))
+	assert.False(t, m.RecentLogin(AuthProviderOIDC, time.Minute))
+	assert.False(t, m.RecentLogin(AuthProviderLDAP, 10*time.Minute))
+}
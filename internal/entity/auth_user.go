@@ -39,6 +39,7 @@ type User struct {
 	UUID          string        `gorm:"type:VARBINARY(64);column:user_uuid;index;" json:"UUID,omitempty" yaml:"UUID,omitempty"`
 	UserUID       string        `gorm:"type:VARBINARY(42);column:user_uid;unique_index;" json:"UID" yaml:"UID"`
 	AuthProvider  string        `gorm:"type:VARBINARY(128);default:'';" json:"AuthProvider,omitempty" yaml:"AuthProvider,omitempty"`
+	AuthDomain    string        `gorm:"type:VARBINARY(255);default:'';" json:"AuthDomain,omitempty" yaml:"AuthDomain,omitempty"`
 	AuthID        string        `gorm:"type:VARBINARY(128);index;default:'';" json:"AuthID,omitempty" yaml:"AuthID,omitempty"`
 	UserName      string        `gorm:"size:64;index;" json:"Name" yaml:"Name,omitempty"`
 	DisplayName   string        `gorm:"size:200;" json:"DisplayName" yaml:"DisplayName,omitempty"`
@@ -546,6 +547,8 @@ func (m *User) DeleteSessions(omit []string) (deleted int) {
 func (m *User) SetPassword(password string) error {
 	if !m.IsRegistered() {
 		return fmt.Errorf("only registered users can change their password")
+	} else if m.ExternalPassword() {
+		return fmt.Errorf("password is managed by %s", m.AuthProvider)
 	}
 
 	if len(password) < LenPasswordMin {
@@ -754,8 +757,6 @@ func (m *User) RedeemToken(token string) (n int) {
 
 			if err := share.Save(); err != nil {
 				event.AuditErr([]string{"user %s", "token %s", "failed to redeem shares", "%s"}, m.RefID, clean.Log(token), err)
-			} else {
-				link.Redeem()
 			}
 		} else if err := found.UpdateLink(link); err != nil {
 			event.AuditErr([]string{"user %s", "token %s", "failed to update shares", "%s"}, m.RefID, clean.Log(token), err)
@@ -4,6 +4,7 @@ import (
 	"github.com/photoprism/photoprism/pkg/clean"
 	"github.com/urfave/cli"
 
+	"github.com/photoprism/photoprism/internal/event"
 	"github.com/photoprism/photoprism/internal/form"
 )
 
@@ -22,12 +23,14 @@ func (m *User) SetValuesFromCli(ctx *cli.Context) error {
 	}
 
 	// User role.
-	if ctx.IsSet("role") {
-		m.UserRole = frm.Role()
+	if role := frm.Role(); ctx.IsSet("role") && role != m.UserRole {
+		event.AuditInfo([]string{"user %s", "role changed from %s to %s"}, m.RefID, clean.Log(m.UserRole), clean.Log(role))
+		m.UserRole = role
 	}
 
 	// Super-admin status.
-	if ctx.IsSet("superadmin") {
+	if ctx.IsSet("superadmin") && frm.SuperAdmin != m.SuperAdmin {
+		event.AuditInfo([]string{"user %s", "superadmin changed to %t"}, m.RefID, frm.SuperAdmin)
 		m.SuperAdmin = frm.SuperAdmin
 	}
 
@@ -0,0 +1,220 @@
+package entity
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/photoprism/photoprism/pkg/qrcode"
+	"github.com/photoprism/photoprism/pkg/rnd"
+	"github.com/photoprism/photoprism/pkg/totp"
+)
+
+// PasscodeTypeTOTP is the passcode type for time-based one-time passwords.
+const PasscodeTypeTOTP = "totp"
+
+// RecoveryCodeCount specifies how many one-time recovery codes are generated.
+const RecoveryCodeCount = 10
+
+// ErrPasscodeRequired is returned if a user with two-factor authentication tries to log in without a passcode.
+var ErrPasscodeRequired = errors.New("passcode required")
+
+// ErrInvalidPasscode is returned if neither a valid verification code nor an unused recovery code was provided.
+var ErrInvalidPasscode = errors.New("invalid passcode")
+
+// UserPasscode represents the second authentication factor of a user account.
+type UserPasscode struct {
+	UserUID       string     `gorm:"type:VARBINARY(42);primary_key;auto_increment:false;" json:"UserUID" yaml:"UserUID"`
+	KeyType       string     `gorm:"type:VARBINARY(64);default:'';" json:"Type" yaml:"Type"`
+	KeySecret     string     `gorm:"type:VARBINARY(255);" json:"-" yaml:"-"`
+	RecoveryCodes string     `gorm:"type:VARBINARY(1024);" json:"-" yaml:"-"`
+	LastCounter   int64      `json:"-" yaml:"-"`
+	VerifiedAt    *time.Time `json:"VerifiedAt,omitempty" yaml:"VerifiedAt,omitempty"`
+	CreatedAt     time.Time  `json:"CreatedAt" yaml:"-"`
+	UpdatedAt     time.Time  `json:"UpdatedAt" yaml:"-"`
+}
+
+// TableName returns the entity table name.
+func (UserPasscode) TableName() string {
+	return "auth_users_passcodes"
+}
+
+// NewUserPasscode creates a new, not yet verified passcode with a random secret.
+func NewUserPasscode(userUid string) (*UserPasscode, error) {
+	if rnd.InvalidUID(userUid, UserUID) {
+		return nil, fmt.Errorf("invalid user uid")
+	}
+
+	m := &UserPasscode{
+		UserUID:   userUid,
+		KeyType:   PasscodeTypeTOTP,
+		KeySecret: totp.NewSecret(),
+		CreatedAt: TimeStamp(),
+		UpdatedAt: TimeStamp(),
+	}
+
+	return m, nil
+}
+
+// FindUserPasscode returns the passcode of a user or nil if it was not found.
+func FindUserPasscode(userUid string) *UserPasscode {
+	if rnd.InvalidUID(userUid, UserUID) {
+		return nil
+	}
+
+	m := &UserPasscode{}
+
+	// Find matching record.
+	if UnscopedDb().First(m, "user_uid = ?", userUid).RecordNotFound() {
+		return nil
+	}
+
+	return m
+}
+
+// Save updates the record in the database or inserts a new record if it does not already exist.
+func (m *UserPasscode) Save() error {
+	return UnscopedDb().Save(m).Error
+}
+
+// Delete permanently deletes the passcode, which disables two-factor authentication.
+func (m *UserPasscode) Delete() error {
+	if m.UserUID == "" {
+		return fmt.Errorf("user uid is missing")
+	}
+
+	return UnscopedDb().Delete(m, "user_uid = ?", m.UserUID).Error
+}
+
+// Updates changes multiple record values.
+func (m *UserPasscode) Updates(values interface{}) error {
+	return UnscopedDb().Model(m).Updates(values).Error
+}
+
+// Secret returns the base32 encoded secret for manual entry in an authenticator app.
+func (m *UserPasscode) Secret() string {
+	return m.KeySecret
+}
+
+// URL returns the key URI for enrolling the secret in an authenticator app.
+func (m *UserPasscode) URL(issuer, account string) string {
+	return totp.URL(issuer, account, m.KeySecret)
+}
+
+// QrCode returns the key URI as PNG encoded QR code.
+func (m *UserPasscode) QrCode(issuer, account string) ([]byte, error) {
+	code, err := qrcode.Encode(m.URL(issuer, account))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return code.PNG(4)
+}
+
+// Verified checks if the passcode has been verified and is therefore required to log in.
+func (m *UserPasscode) Verified() bool {
+	return m.VerifiedAt != nil
+}
+
+// Activate verifies the code and enables the passcode, returning new recovery codes
+// that are stored as hashes and can therefore only be displayed once.
+func (m *UserPasscode) Activate(code string) (recoveryCodes []string, err error) {
+	counter, ok := totp.Validate(code, m.KeySecret, TimeStamp())
+
+	if !ok {
+		return nil, ErrInvalidPasscode
+	}
+
+	verifiedAt := TimeStamp()
+	m.VerifiedAt = &verifiedAt
+	m.LastCounter = counter
+	recoveryCodes = m.NewRecoveryCodes()
+
+	if err = m.Save(); err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// NewRecoveryCodes replaces the recovery codes and returns them.
+func (m *UserPasscode) NewRecoveryCodes() []string {
+	codes := make([]string, RecoveryCodeCount)
+	hashes := make([]string, RecoveryCodeCount)
+
+	for i := range codes {
+		s := rnd.Base36(10)
+		codes[i] = s[:5] + "-" + s[5:]
+		hashes[i] = TokenHash(s)
+	}
+
+	m.RecoveryCodes = strings.Join(hashes, " ")
+
+	return codes
+}
+
+// RecoveryCodesLeft returns the number of unused recovery codes.
+func (m *UserPasscode) RecoveryCodesLeft() int {
+	return len(strings.Fields(m.RecoveryCodes))
+}
+
+// Verify checks a verification code or, alternatively, a recovery code, which can only be used once.
+// Verification codes cannot be reused either.
+func (m *UserPasscode) Verify(code string) (recovery bool, err error) {
+	if counter, ok := totp.Validate(code, m.KeySecret, TimeStamp()); ok {
+		if counter <= m.LastCounter {
+			return false, ErrInvalidPasscode
+		}
+
+		m.LastCounter = counter
+
+		return false, m.Updates(Values{"LastCounter": m.LastCounter})
+	}
+
+	// Normalize recovery code and compare hash.
+	code = strings.ToLower(strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+
+		return r
+	}, code))
+
+	if code == "" {
+		return false, ErrInvalidPasscode
+	}
+
+	hash := TokenHash(code)
+	hashes := strings.Fields(m.RecoveryCodes)
+
+	for i := range hashes {
+		if subtle.ConstantTimeCompare([]byte(hashes[i]), []byte(hash)) == 1 {
+			m.RecoveryCodes = strings.Join(append(hashes[:i], hashes[i+1:]...), " ")
+			return true, m.Updates(Values{"RecoveryCodes": m.RecoveryCodes})
+		}
+	}
+
+	return false, ErrInvalidPasscode
+}
+
+// String returns the user uid for use in logs.
+func (m *UserPasscode) String() string {
+	return m.UserUID
+}
+
+// Passcode returns the passcode of the user, or nil if two-factor authentication has not been set up.
+func (m *User) Passcode() *UserPasscode {
+	return FindUserPasscode(m.UserUID)
+}
+
+// HasPasscode checks if the user must provide a verification code to log in.
+func (m *User) HasPasscode() bool {
+	if p := m.Passcode(); p != nil {
+		return p.Verified()
+	}
+
+	return false
+}
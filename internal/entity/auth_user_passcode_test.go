@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/pkg/totp"
+)
+
+func TestNewUserPasscode(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		m, err := NewUserPasscode("uqxqg7i1kperxvu7")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, PasscodeTypeTOTP, m.KeyType)
+		assert.Len(t, m.Secret(), 32)
+		assert.False(t, m.Verified())
+		assert.Contains(t, m.URL("PhotoPrism", "friend"), "otpauth://totp/PhotoPrism:friend?")
+	})
+	t.Run("InvalidUser", func(t *testing.T) {
+		m, err := NewUserPasscode("foo")
+		assert.Error(t, err)
+		assert.Nil(t, m)
+	})
+}
+
+func TestUserPasscode_QrCode(t *testing.T) {
+	m, err := NewUserPasscode("uqxqg7i1kperxvu7")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := m.QrCode("PhotoPrism", "friend")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "\x89PNG", string(b[:4]))
+}
+
+func TestUserPasscode_Activate(t *testing.T) {
+	m, err := NewUserPasscode("uqxqg7i1kperxvu7")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer m.Delete()
+
+	assert.NoError(t, m.Save())
+	assert.False(t, FindUserByUID("uqxqg7i1kperxvu7").HasPasscode())
+
+	// Invalid code.
+	_, err = m.Activate("000000x")
+	assert.Equal(t, ErrInvalidPasscode, err)
+
+	code, err := totp.Code(m.Secret(), TimeStamp())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codes, err := m.Activate(code)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, codes, RecoveryCodeCount)
+	assert.Len(t, codes[0], 11)
+	assert.True(t, m.Verified())
+	assert.Equal(t, RecoveryCodeCount, m.RecoveryCodesLeft())
+	assert.True(t, FindUserByUID("uqxqg7i1kperxvu7").HasPasscode())
+
+	t.Run("Verify", func(t *testing.T) {
+		found := FindUserPasscode("uqxqg7i1kperxvu7")
+
+		if found == nil {
+			t.Fatal("passcode not found")
+		}
+
+		// Codes cannot be reused.
+		_, err = found.Verify(code)
+		assert.Equal(t, ErrInvalidPasscode, err)
+
+		next, _ := totp.Code(found.Secret(), TimeStamp().Add(totp.Period))
+		recovery, err := found.Verify(next)
+		assert.NoError(t, err)
+		assert.False(t, recovery)
+	})
+	t.Run("RecoveryCode", func(t *testing.T) {
+		found := FindUserPasscode("uqxqg7i1kperxvu7")
+
+		recovery, err := found.Verify(" " + codes[3] + " ")
+		assert.NoError(t, err)
+		assert.True(t, recovery)
+		assert.Equal(t, RecoveryCodeCount-1, FindUserPasscode("uqxqg7i1kperxvu7").RecoveryCodesLeft())
+
+		// Recovery codes can only be used once.
+		_, err = found.Verify(codes[3])
+		assert.Equal(t, ErrInvalidPasscode, err)
+		_, err = found.Verify("")
+		assert.Equal(t, ErrInvalidPasscode, err)
+	})
+	t.Run("Delete", func(t *testing.T) {
+		assert.NoError(t, m.Delete())
+		assert.Nil(t, FindUserPasscode("uqxqg7i1kperxvu7"))
+		assert.Error(t, (&UserPasscode{}).Delete())
+	})
+}
@@ -0,0 +1,168 @@
+package entity
+
+import (
+	"fmt"
+
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// Authentication providers other than the local user database.
+const (
+	AuthProviderDefault = ""
+	AuthProviderOIDC    = "oidc"
+	AuthProviderLDAP    = "ldap"
+)
+
+// ProviderAccount represents a user account authenticated by an external provider.
+type ProviderAccount struct {
+	AuthID      string
+	UserName    string
+	UserEmail   string
+	DisplayName string
+	UserRole    string
+}
+
+// LdapAuth authenticates users against a directory server if LDAP is enabled, see config.Propagate().
+var LdapAuth func(name, password string) (*ProviderAccount, error)
+
+// FindUserByAuthID returns the user with the specified external provider, domain, and account id,
+// or nil if it was not found. The domain is the issuer for OpenID Connect and empty otherwise.
+func FindUserByAuthID(provider, domain, authId string) *User {
+	if provider == "" || authId == "" {
+		return nil
+	} else if provider == AuthProviderOIDC && domain == "" {
+		// OpenID Connect accounts are identified by issuer and subject.
+		return nil
+	}
+
+	m := &User{}
+
+	// Find matching record.
+	if err := UnscopedDb().Where("auth_provider = ? AND auth_domain = ? AND auth_id = ?", provider, domain, authId).First(m).Error; err != nil {
+		return nil
+	}
+
+	// Fetch related records.
+	return m.LoadRelated()
+}
+
+// AddProviderUser creates a new user account that authenticates with an external provider.
+func AddProviderUser(provider, domain, authId string, frm form.User) (*User, error) {
+	if provider == "" || authId == "" {
+		return nil, fmt.Errorf("auth provider and id must not be empty")
+	} else if provider == AuthProviderOIDC && domain == "" {
+		return nil, fmt.Errorf("issuer must not be empty")
+	}
+
+	m := NewUser().SetFormValues(frm)
+	m.AuthProvider = provider
+	m.AuthDomain = domain
+	m.AuthID = authId
+
+	if err := m.Validate(); err != nil {
+		return nil, err
+	} else if err = m.Create(); err != nil {
+		return nil, err
+	}
+
+	log.Infof("successfully added %s user %s", provider, clean.LogQuote(m.Name()))
+
+	return m, nil
+}
+
+// SyncProviderUser returns the user account for an authenticated provider account, and either
+// creates it or updates its name, email, and role so that they match the provider.
+func SyncProviderUser(provider string, account *ProviderAccount) (*User, error) {
+	if account == nil {
+		return nil, fmt.Errorf("account must not be nil")
+	}
+
+	m := FindUserByAuthID(provider, "", account.AuthID)
+
+	// Find user by name if the account id has changed, e.g. because it was moved in the directory.
+	if m == nil {
+		if u := FindUserByName(account.UserName); u != nil && u.AuthProvider == provider {
+			m = u
+		}
+	}
+
+	if m == nil {
+		return AddProviderUser(provider, "", account.AuthID, form.User{
+			UserName:    account.UserName,
+			UserEmail:   clean.Email(account.UserEmail),
+			DisplayName: clean.Name(account.DisplayName),
+			UserRole:    account.UserRole,
+			CanLogin:    true,
+		})
+	}
+
+	values := Values{}
+
+	if m.AuthID != account.AuthID {
+		m.AuthID = account.AuthID
+		values["AuthID"] = m.AuthID
+	}
+
+	if email := clean.Email(account.UserEmail); email != m.UserEmail {
+		m.UserEmail = email
+		values["UserEmail"] = m.UserEmail
+	}
+
+	if name := clean.Name(account.DisplayName); name != "" && name != m.DisplayName {
+		m.DisplayName = name
+		values["DisplayName"] = m.DisplayName
+	}
+
+	if role := clean.Role(account.UserRole); role != m.UserRole {
+		event.AuditInfo([]string{"user %s", "role changed from %s to %s"}, m.RefID, clean.Log(m.UserRole), clean.Log(role))
+		m.UserRole = role
+		values["UserRole"] = m.UserRole
+	}
+
+	if len(values) == 0 {
+		return m, nil
+	}
+
+	return m, m.Updates(values)
+}
+
+// ExternalPassword checks if the password is managed by a directory server and can therefore not be changed locally.
+func (m *User) ExternalPassword() bool {
+	return m.AuthProvider == AuthProviderLDAP
+}
+
+// VerifyPassword checks the password with the directory server if the account is managed by LDAP,
+// or with the local user database otherwise.
+func (m *User) VerifyPassword(s string) bool {
+	if m.AuthProvider != AuthProviderLDAP {
+		return m.HasPassword(s)
+	} else if LdapAuth == nil || s == "" || !m.CanLogIn() {
+		return false
+	}
+
+	account, err := LdapAuth(m.Name(), s)
+
+	if err != nil {
+		log.Debugf("ldap: %s", err)
+		return false
+	}
+
+	return account.AuthID == m.AuthID
+}
+
+// SetRole changes the user role and saves it to the database if it has changed.
+func (m *User) SetRole(role string) error {
+	role = clean.Role(role)
+
+	if m.UserRole == role {
+		return nil
+	}
+
+	event.AuditInfo([]string{"user %s", "role changed from %s to %s"}, m.RefID, clean.Log(m.UserRole), clean.Log(role))
+
+	m.UserRole = role
+
+	return m.Updates(Values{"UserRole": m.UserRole})
+}
@@ -0,0 +1,158 @@
+package entity
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/form"
+)
+
+func TestAddProviderUser(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		m, err := AddProviderUser(AuthProviderOIDC, "https://oidc.example.com", "a5c1e9f0-oidc-test", form.User{
+			UserName:    "oidc-jane",
+			UserEmail:   "jane@oidc.example.com",
+			DisplayName: "Jane Doe",
+			UserRole:    acl.RoleVisitor.String(),
+			CanLogin:    true,
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, AuthProviderOIDC, m.AuthProvider)
+		assert.Equal(t, "https://oidc.example.com", m.AuthDomain)
+		assert.Equal(t, "a5c1e9f0-oidc-test", m.AuthID)
+		assert.Nil(t, FindUserByAuthID(AuthProviderOIDC, "https://other.example.com", "a5c1e9f0-oidc-test"))
+
+		found := FindUserByAuthID(AuthProviderOIDC, "https://oidc.example.com", "a5c1e9f0-oidc-test")
+
+		if found == nil {
+			t.Fatal("user not found")
+		}
+
+		assert.Equal(t, m.UserUID, found.UserUID)
+		assert.Equal(t, "oidc-jane", found.Name())
+		assert.Equal(t, acl.RoleVisitor, found.AclRole())
+
+		assert.NoError(t, found.SetRole("admin"))
+		assert.Equal(t, acl.RoleAdmin, FindUserByAuthID(AuthProviderOIDC, "https://oidc.example.com", "a5c1e9f0-oidc-test").AclRole())
+	})
+	t.Run("DuplicateName", func(t *testing.T) {
+		_, err := AddProviderUser(AuthProviderOIDC, "https://oidc.example.com", "duplicate", form.User{UserName: "oidc-jane", UserRole: "admin"})
+		assert.Error(t, err)
+	})
+	t.Run("NoAuthID", func(t *testing.T) {
+		_, err := AddProviderUser(AuthProviderOIDC, "https://oidc.example.com", "", form.User{UserName: "nobody", UserRole: "admin"})
+		assert.Error(t, err)
+	})
+	t.Run("NoIssuer", func(t *testing.T) {
+		_, err := AddProviderUser(AuthProviderOIDC, "", "a5c1e9f0-no-issuer", form.User{UserName: "oidc-no-issuer", UserRole: "admin"})
+		assert.Error(t, err)
+	})
+}
+
+func TestFindUserByAuthID(t *testing.T) {
+	assert.Nil(t, FindUserByAuthID(AuthProviderOIDC, "https://oidc.example.com", "unknown"))
+	assert.Nil(t, FindUserByAuthID(AuthProviderDefault, "", ""))
+
+	// OpenID Connect accounts cannot be found without issuer.
+	assert.Nil(t, FindUserByAuthID(AuthProviderOIDC, "", "a5c1e9f0-oidc-test"))
+}
+
+func TestSyncProviderUser(t *testing.T) {
+	account := &ProviderAccount{
+		AuthID:      "uid=ldap-jane,ou=people,dc=example,dc=com",
+		UserName:    "ldap-jane",
+		UserEmail:   "jane@ldap.example.com",
+		DisplayName: "Jane Doe",
+		UserRole:    acl.RoleVisitor.String(),
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		m, err := SyncProviderUser(AuthProviderLDAP, account)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, AuthProviderLDAP, m.AuthProvider)
+		assert.Equal(t, account.AuthID, m.AuthID)
+		assert.Equal(t, "Jane Doe", m.DisplayName)
+		assert.Equal(t, acl.RoleVisitor, m.AclRole())
+		assert.True(t, m.CanLogin)
+		assert.True(t, m.ExternalPassword())
+		assert.Error(t, m.SetPassword("Jane123!"))
+	})
+	t.Run("Update", func(t *testing.T) {
+		changed := *account
+		changed.AuthID = "uid=ldap-jane,ou=staff,dc=example,dc=com"
+		changed.UserEmail = "jane.doe@ldap.example.com"
+		changed.DisplayName = "Jane Smith"
+		changed.UserRole = acl.RoleAdmin.String()
+
+		m, err := SyncProviderUser(AuthProviderLDAP, &changed)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		found := FindUserByAuthID(AuthProviderLDAP, "", changed.AuthID)
+
+		if found == nil {
+			t.Fatal("user not found")
+		}
+
+		assert.Equal(t, m.UserUID, found.UserUID)
+		assert.Equal(t, "jane.doe@ldap.example.com", found.UserEmail)
+		assert.Equal(t, "Jane Smith", found.DisplayName)
+		assert.Equal(t, acl.RoleAdmin, found.AclRole())
+		assert.Nil(t, FindUserByAuthID(AuthProviderLDAP, "", account.AuthID))
+	})
+	t.Run("LocalUser", func(t *testing.T) {
+		_, err := SyncProviderUser(AuthProviderLDAP, &ProviderAccount{AuthID: "uid=alice,dc=example,dc=com", UserName: "alice", UserRole: "admin"})
+		assert.Error(t, err)
+	})
+	t.Run("Nil", func(t *testing.T) {
+		_, err := SyncProviderUser(AuthProviderLDAP, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestUser_VerifyPassword(t *testing.T) {
+	ldapAuth := LdapAuth
+	defer func() { LdapAuth = ldapAuth }()
+
+	LdapAuth = func(name, password string) (*ProviderAccount, error) {
+		if name != "ldap-bob" || password != "secret" {
+			return nil, fmt.Errorf("invalid credentials")
+		}
+
+		return &ProviderAccount{AuthID: "uid=ldap-bob,ou=people,dc=example,dc=com", UserName: name}, nil
+	}
+
+	t.Run("Local", func(t *testing.T) {
+		m := FindUserByName("alice")
+
+		if m == nil {
+			t.Fatal("user not found")
+		}
+
+		assert.True(t, m.VerifyPassword("Alice123!"))
+		assert.False(t, m.VerifyPassword("secret"))
+	})
+	t.Run("LDAP", func(t *testing.T) {
+		m := &User{ID: 1000100, UserName: "ldap-bob", UserRole: acl.RoleAdmin.String(), AuthProvider: AuthProviderLDAP, AuthID: "uid=ldap-bob,ou=people,dc=example,dc=com", CanLogin: true}
+
+		assert.True(t, m.VerifyPassword("secret"))
+		assert.False(t, m.VerifyPassword("Alice123!"))
+		assert.False(t, m.VerifyPassword(""))
+
+		m.AuthID = "uid=other,ou=people,dc=example,dc=com"
+		assert.False(t, m.VerifyPassword("secret"))
+	})
+}
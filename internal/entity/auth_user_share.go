@@ -22,6 +22,20 @@ const (
 	PermAll
 )
 
+// Share levels for albums shared with other users.
+const (
+	ShareViewer      = "viewer"
+	ShareContributor = "contributor"
+	ShareEditor      = "editor"
+)
+
+// ShareLevels maps share levels to the permissions they grant.
+var ShareLevels = map[string]uint{
+	ShareViewer:      PermView,
+	ShareContributor: PermUpload,
+	ShareEditor:      PermEdit,
+}
+
 // SharePrefix for RefID.
 const (
 	SharePrefix = "share"
@@ -127,6 +141,23 @@ func FindUserShares(userUid string) UserShares {
 	return found
 }
 
+// FindShareUsers finds all users with access to the shared uid.
+func FindShareUsers(shareUid string) UserShares {
+	found := UserShares{}
+
+	if rnd.InvalidUID(shareUid, 0) {
+		return found
+	}
+
+	// Find matching records.
+	if err := UnscopedDb().Order("created_at").Find(&found, "share_uid = ? AND (expires_at IS NULL OR expires_at > ?)", shareUid, TimeStamp()).Error; err != nil {
+		event.AuditWarn([]string{"share %s", "find users", "%s"}, clean.Log(shareUid), err)
+		return nil
+	}
+
+	return found
+}
+
 // HasID tests if the entity has a valid uid.
 func (m *UserShare) HasID() bool {
 	return rnd.IsUID(m.UserUID, UserUID) && rnd.IsUID(m.ShareUID, 0)
@@ -142,6 +173,15 @@ func (m *UserShare) Save() error {
 	return Db().Save(m).Error
 }
 
+// Delete permanently deletes the share.
+func (m *UserShare) Delete() error {
+	if !m.HasID() {
+		return fmt.Errorf("invalid share")
+	}
+
+	return UnscopedDb().Delete(m, "user_uid = ? AND share_uid = ?", m.UserUID, m.ShareUID).Error
+}
+
 // Updates changes multiple record values.
 func (m *UserShare) Updates(values interface{}) error {
 	return UnscopedDb().Model(m).Updates(values).Error
@@ -168,3 +208,24 @@ func (m *UserShare) UpdateLink(link Link) error {
 
 	return m.Updates(values)
 }
+
+// Allow checks if the share grants at least the specified permission.
+func (m *UserShare) Allow(perm uint) bool {
+	if m == nil {
+		return false
+	}
+
+	return m.Perm >= perm
+}
+
+// Level returns the share level based on the permissions granted.
+func (m *UserShare) Level() string {
+	switch {
+	case m.Allow(PermEdit):
+		return ShareEditor
+	case m.Allow(PermUpload):
+		return ShareContributor
+	default:
+		return ShareViewer
+	}
+}
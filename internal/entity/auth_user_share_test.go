@@ -75,3 +75,41 @@ func TestFindUserShares(t *testing.T) {
 	assert.Equal(t, expected.UserUID, m.UserUID)
 	assert.Equal(t, expected.ShareUID, m.ShareUID)
 }
+
+func TestFindShareUsers(t *testing.T) {
+	found := FindShareUsers("at9lxuqxpogaaba9")
+	assert.Len(t, found, 1)
+	assert.Equal(t, "uqxetse3cy5eo9z2", found[0].UserUID)
+	assert.Empty(t, FindShareUsers("xxx"))
+}
+
+func TestUserShare_Level(t *testing.T) {
+	assert.Equal(t, ShareViewer, NewUserShare(Admin.UID(), "at9lxuqxpogaaba8", PermView, nil).Level())
+	assert.Equal(t, ShareViewer, NewUserShare(Admin.UID(), "at9lxuqxpogaaba8", PermDefault, nil).Level())
+	assert.Equal(t, ShareContributor, NewUserShare(Admin.UID(), "at9lxuqxpogaaba8", PermUpload, nil).Level())
+	assert.Equal(t, ShareEditor, NewUserShare(Admin.UID(), "at9lxuqxpogaaba8", PermEdit, nil).Level())
+	assert.Equal(t, ShareEditor, UserShareFixtures.Pointer("AliceAlbum").Level())
+}
+
+func TestUserShare_Allow(t *testing.T) {
+	m := NewUserShare(Admin.UID(), "at9lxuqxpogaaba8", ShareLevels[ShareContributor], nil)
+	assert.True(t, m.Allow(PermView))
+	assert.True(t, m.Allow(PermUpload))
+	assert.False(t, m.Allow(PermEdit))
+
+	var empty *UserShare
+	assert.False(t, empty.Allow(PermView))
+}
+
+func TestUserShare_Delete(t *testing.T) {
+	m := NewUserShare(UserFixtures.Pointer("bob").UID(), "at9lxuqxpogaaba7", PermView, nil)
+
+	if err := m.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NotNil(t, FindUserShare(*m))
+	assert.NoError(t, m.Delete())
+	assert.Nil(t, FindUserShare(*m))
+	assert.Error(t, (&UserShare{}).Delete())
+}
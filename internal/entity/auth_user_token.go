@@ -0,0 +1,202 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/rnd"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// TokenUID is the unique ID prefix of access tokens.
+const (
+	TokenUID    = byte('t')
+	TokenPrefix = "token"
+)
+
+// AuthMethodToken is the session auth method when an access token is used.
+const AuthMethodToken = "token"
+
+// TokenUsedInterval specifies how often the last used timestamp is updated.
+var TokenUsedInterval = time.Minute
+
+// UserTokens represents a list of access tokens.
+type UserTokens []UserToken
+
+// UserToken represents a personal access token that can be used instead of a session, e.g. by apps.
+type UserToken struct {
+	TokenUID   string     `gorm:"type:VARBINARY(42);primary_key;auto_increment:false;" json:"UID" yaml:"UID"`
+	UserUID    string     `gorm:"type:VARBINARY(42);index;" json:"UserUID" yaml:"UserUID"`
+	TokenName  string     `gorm:"size:160;" json:"Name" yaml:"Name"`
+	TokenHash  string     `gorm:"type:VARBINARY(64);unique_index;" json:"-" yaml:"-"`
+	TokenScope string     `gorm:"size:1024;default:'';" json:"Scope" yaml:"Scope,omitempty"`
+	LastUsedAt *time.Time `json:"LastUsedAt,omitempty" yaml:"LastUsedAt,omitempty"`
+	ExpiresAt  *time.Time `sql:"index" json:"ExpiresAt,omitempty" yaml:"ExpiresAt,omitempty"`
+	RefID      string     `gorm:"type:VARBINARY(16);" json:"-" yaml:"-"`
+	CreatedAt  time.Time  `json:"CreatedAt" yaml:"-"`
+	UpdatedAt  time.Time  `json:"UpdatedAt" yaml:"-"`
+}
+
+// TableName returns the entity table name.
+func (UserToken) TableName() string {
+	return "auth_users_tokens"
+}
+
+// TokenHash returns the hash under which a token secret is stored.
+func TokenHash(secret string) string {
+	h := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(h[:])
+}
+
+// NewUserToken creates a new access token and returns it along with the secret,
+// which is not stored and can therefore only be displayed once.
+func NewUserToken(userUid, name, scope string, expires *time.Time) (m *UserToken, secret string, err error) {
+	if rnd.InvalidUID(userUid, UserUID) {
+		return nil, "", fmt.Errorf("invalid user uid")
+	}
+
+	s, err := acl.ParseScope(scope)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	secret = rnd.SessionID()
+
+	m = &UserToken{
+		TokenUID:   rnd.GenerateUID(TokenUID),
+		UserUID:    userUid,
+		TokenName:  txt.Clip(clean.Name(name), txt.ClipName),
+		TokenHash:  TokenHash(secret),
+		TokenScope: s.String(),
+		ExpiresAt:  expires,
+		RefID:      rnd.RefID(TokenPrefix),
+		CreatedAt:  TimeStamp(),
+		UpdatedAt:  TimeStamp(),
+	}
+
+	return m, secret, nil
+}
+
+// FindUserToken returns the access token matching the secret or nil if it was not found.
+func FindUserToken(secret string) *UserToken {
+	if !rnd.IsSessionID(secret) {
+		return nil
+	}
+
+	m := &UserToken{}
+
+	// Find matching record.
+	if UnscopedDb().First(m, "token_hash = ?", TokenHash(secret)).RecordNotFound() {
+		return nil
+	}
+
+	return m
+}
+
+// FindUserTokenByUID returns the access token with the specified uid or nil if it was not found.
+func FindUserTokenByUID(uid string) *UserToken {
+	if rnd.InvalidUID(uid, TokenUID) {
+		return nil
+	}
+
+	m := &UserToken{}
+
+	// Find matching record.
+	if UnscopedDb().First(m, "token_uid = ?", uid).RecordNotFound() {
+		return nil
+	}
+
+	return m
+}
+
+// FindUserTokens returns the access tokens of a user.
+func FindUserTokens(userUid string) UserTokens {
+	found := UserTokens{}
+
+	if rnd.InvalidUID(userUid, UserUID) {
+		return found
+	}
+
+	// Find matching records.
+	if err := UnscopedDb().Order("created_at").Find(&found, "user_uid = ?", userUid).Error; err != nil {
+		event.AuditWarn([]string{"user %s", "find tokens", "%s"}, clean.Log(userUid), err)
+		return nil
+	}
+
+	return found
+}
+
+// Create inserts a new record into the database.
+func (m *UserToken) Create() error {
+	return Db().Create(m).Error
+}
+
+// Delete revokes the access token by permanently deleting it.
+func (m *UserToken) Delete() error {
+	if m.TokenUID == "" {
+		return fmt.Errorf("token uid is missing")
+	}
+
+	return UnscopedDb().Delete(m, "token_uid = ?", m.TokenUID).Error
+}
+
+// Updates changes multiple record values.
+func (m *UserToken) Updates(values interface{}) error {
+	return UnscopedDb().Model(m).Updates(values).Error
+}
+
+// User returns the user the token belongs to, or nil if it was not found.
+func (m *UserToken) User() *User {
+	return FindUserByUID(m.UserUID)
+}
+
+// Scope returns the parsed token scope.
+func (m *UserToken) Scope() acl.Scope {
+	if s, err := acl.ParseScope(m.TokenScope); err != nil {
+		// Deny access if the scope is invalid.
+		return acl.Scope{acl.ResourceDefault: acl.Grant{}}
+	} else {
+		return s
+	}
+}
+
+// Expired checks if the token has expired.
+func (m *UserToken) Expired() bool {
+	return m.ExpiresAt != nil && m.ExpiresAt.Before(TimeStamp())
+}
+
+// UpdateLastUsed sets the last used timestamp, but at most once per TokenUsedInterval.
+func (m *UserToken) UpdateLastUsed() error {
+	now := TimeStamp()
+
+	if m.LastUsedAt != nil && now.Sub(*m.LastUsedAt) < TokenUsedInterval {
+		return nil
+	}
+
+	m.LastUsedAt = &now
+
+	return m.Updates(Values{"last_used_at": m.LastUsedAt})
+}
+
+// Session returns a new, non-persistent session for authenticating a request with the token.
+func (m *UserToken) Session(u *User) *Session {
+	s := NewSession(0, 0)
+	s.AuthMethod = AuthMethodToken
+	s.AuthID = m.TokenUID
+	s.AuthScope = m.TokenScope
+	s.RefID = m.RefID
+	s.SetUser(u)
+
+	return s
+}
+
+// String returns the token uid for use in logs.
+func (m *UserToken) String() string {
+	return m.TokenUID
+}
@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/photoprism/photoprism/pkg/rnd"
+)
+
+type UserTokenMap map[string]UserToken
+
+// Get returns a fixture for use in tests.
+func (m UserTokenMap) Get(name string) UserToken {
+	if result, ok := m[name]; ok {
+		return result
+	}
+
+	return UserToken{}
+}
+
+// Pointer returns a fixture pointer for use in tests.
+func (m UserTokenMap) Pointer(name string) *UserToken {
+	if result, ok := m[name]; ok {
+		return &result
+	}
+
+	return &UserToken{}
+}
+
+// UserTokenSecrets specifies the secrets of the token fixtures for use in tests.
+var UserTokenSecrets = map[string]string{
+	"alice":   "a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718",
+	"photos":  "b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718a1",
+	"expired": "c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718a1b2",
+}
+
+var tokenExpiresAt = time.Date(2022, 10, 3, 12, 0, 0, 0, time.UTC)
+
+// UserTokenFixtures specifies fixtures for use in tests.
+var UserTokenFixtures = UserTokenMap{
+	"alice": {
+		TokenUID:   "tqxetse3cy5eo9z2",
+		UserUID:    "uqxetse3cy5eo9z2",
+		TokenName:  "Alice's App",
+		TokenHash:  TokenHash(UserTokenSecrets["alice"]),
+		TokenScope: "*",
+		RefID:      rnd.RefID(TokenPrefix),
+		CreatedAt:  time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:  time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC),
+	},
+	"photos": {
+		TokenUID:   "tqxetse3cy5eo9z3",
+		UserUID:    "uqxetse3cy5eo9z2",
+		TokenName:  "Photo Frame",
+		TokenHash:  TokenHash(UserTokenSecrets["photos"]),
+		TokenScope: "photos:search,view,download",
+		RefID:      rnd.RefID(TokenPrefix),
+		CreatedAt:  time.Date(2022, 10, 2, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:  time.Date(2022, 10, 2, 12, 0, 0, 0, time.UTC),
+	},
+	"expired": {
+		TokenUID:   "tqxc08w3d0ej2283",
+		UserUID:    "uqxc08w3d0ej2283",
+		TokenName:  "Expired",
+		TokenHash:  TokenHash(UserTokenSecrets["expired"]),
+		TokenScope: "*",
+		ExpiresAt:  &tokenExpiresAt,
+		RefID:      rnd.RefID(TokenPrefix),
+		CreatedAt:  time.Date(2022, 10, 2, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:  time.Date(2022, 10, 2, 12, 0, 0, 0, time.UTC),
+	},
+}
+
+// CreateUserTokenFixtures creates the fixtures specified above.
+func CreateUserTokenFixtures() {
+	for _, entity := range UserTokenFixtures {
+		Db().Create(&entity)
+	}
+}
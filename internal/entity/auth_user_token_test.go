@@ -0,0 +1,136 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/pkg/rnd"
+)
+
+func TestTokenHash(t *testing.T) {
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", TokenHash("hello"))
+	assert.True(t, rnd.IsSHA256(TokenHash(UserTokenSecrets["alice"])))
+}
+
+func TestNewUserToken(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		expires := TimeStamp().Add(time.Hour * 48)
+		m, secret, err := NewUserToken(Admin.UID(), "  My App ", "albums photos:view", &expires)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, rnd.IsUID(m.TokenUID, TokenUID))
+		assert.True(t, rnd.IsRefID(m.RefID))
+		assert.True(t, rnd.IsSessionID(secret))
+		assert.Equal(t, TokenHash(secret), m.TokenHash)
+		assert.Equal(t, "My App", m.TokenName)
+		assert.Equal(t, "albums photos:view", m.TokenScope)
+		assert.Equal(t, expires, *m.ExpiresAt)
+		assert.False(t, m.Expired())
+	})
+	t.Run("InvalidUser", func(t *testing.T) {
+		m, secret, err := NewUserToken("foo", "My App", "", nil)
+		assert.Error(t, err)
+		assert.Nil(t, m)
+		assert.Equal(t, "", secret)
+	})
+	t.Run("InvalidScope", func(t *testing.T) {
+		m, _, err := NewUserToken(Admin.UID(), "My App", "photos:fly", nil)
+		assert.EqualError(t, err, "invalid permission fly")
+		assert.Nil(t, m)
+	})
+}
+
+func TestFindUserToken(t *testing.T) {
+	t.Run("Alice", func(t *testing.T) {
+		m := FindUserToken(UserTokenSecrets["alice"])
+
+		if m == nil {
+			t.Fatal("token not found")
+		}
+
+		assert.Equal(t, "tqxetse3cy5eo9z2", m.TokenUID)
+		assert.Equal(t, "uqxetse3cy5eo9z2", m.User().UserUID)
+		assert.True(t, m.Scope().Unrestricted())
+	})
+	t.Run("Photos", func(t *testing.T) {
+		m := FindUserToken(UserTokenSecrets["photos"])
+
+		if m == nil {
+			t.Fatal("token not found")
+		}
+
+		assert.True(t, m.Scope().Allow(acl.ResourcePhotos, acl.ActionView))
+		assert.False(t, m.Scope().Allow(acl.ResourceAlbums, acl.ActionView))
+	})
+	t.Run("Expired", func(t *testing.T) {
+		m := FindUserToken(UserTokenSecrets["expired"])
+
+		if m == nil {
+			t.Fatal("token not found")
+		}
+
+		assert.True(t, m.Expired())
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		assert.Nil(t, FindUserToken(rnd.SessionID()))
+		assert.Nil(t, FindUserToken("foo"))
+	})
+}
+
+func TestFindUserTokenByUID(t *testing.T) {
+	assert.NotNil(t, FindUserTokenByUID("tqxetse3cy5eo9z2"))
+	assert.Nil(t, FindUserTokenByUID("tqxetse3cy5eo9z9"))
+	assert.Nil(t, FindUserTokenByUID("uqxetse3cy5eo9z2"))
+}
+
+func TestFindUserTokens(t *testing.T) {
+	assert.Len(t, FindUserTokens("uqxetse3cy5eo9z2"), 2)
+	assert.Len(t, FindUserTokens("uqxc08w3d0ej2283"), 1)
+	assert.Len(t, FindUserTokens("foo"), 0)
+}
+
+func TestUserToken_Create(t *testing.T) {
+	m, secret, err := NewUserToken("uqxqg7i1kperxvu7", "Test", "", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, m.Create())
+	assert.Nil(t, m.LastUsedAt)
+	assert.NoError(t, m.UpdateLastUsed())
+	assert.NotNil(t, m.LastUsedAt)
+
+	found := FindUserToken(secret)
+
+	if found == nil {
+		t.Fatal("token not found")
+	}
+
+	assert.NotNil(t, found.LastUsedAt)
+	assert.NoError(t, found.Delete())
+	assert.Nil(t, FindUserToken(secret))
+	assert.Error(t, (&UserToken{}).Delete())
+}
+
+func TestUserToken_UpdateLastUsed(t *testing.T) {
+	m := UserTokenFixtures.Pointer("photos")
+	now := TimeStamp()
+	m.LastUsedAt = &now
+
+	// Not updated again within the interval.
+	assert.NoError(t, m.UpdateLastUsed())
+	assert.Equal(t, now, *m.LastUsedAt)
+}
+
+func TestUserToken_Scope(t *testing.T) {
+	m := UserToken{TokenScope: "foo:bar"}
+	assert.False(t, m.Scope().Unrestricted())
+	assert.False(t, m.Scope().Allow(acl.ResourcePhotos, acl.ActionView))
+}
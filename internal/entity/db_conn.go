@@ -7,12 +7,14 @@ import (
 
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/mysql"
+	_ "github.com/jinzhu/gorm/dialects/postgres"
 	_ "github.com/jinzhu/gorm/dialects/sqlite"
 )
 
 // Supported test databases.
 const (
 	MySQL           = "mysql"
+	Postgres        = "postgres"
 	SQLite3         = "sqlite3"
 	SQLiteTestDB    = ".test.db"
 	SQLiteMemoryDSN = ":memory:"
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+// sqlTypePattern matches a column type with an optional size, e.g. "VARBINARY(42)".
+var sqlTypePattern = regexp.MustCompile(`^([A-Za-z]+)(?:\((\d+)\))?(.*)$`)
+
+// postgresDefault is the PostgreSQL dialect provided by GORM.
+var postgresDefault, _ = gorm.GetDialect(Postgres)
+
+// postgresDialect extends the default PostgreSQL dialect so that the MySQL column
+// types used in entity struct tags are translated to compatible PostgreSQL types.
+type postgresDialect struct {
+	gorm.Dialect
+}
+
+func init() {
+	if postgresDefault != nil {
+		gorm.RegisterDialect(Postgres, &postgresDialect{})
+	}
+}
+
+// SetDB creates a new default dialect for the database connection, see gorm.Open().
+func (d *postgresDialect) SetDB(db gorm.SQLCommon) {
+	d.Dialect = reflect.New(reflect.TypeOf(postgresDefault).Elem()).Interface().(gorm.Dialect)
+	d.Dialect.SetDB(db)
+}
+
+// DataTypeOf returns the PostgreSQL column type of a struct field.
+func (d *postgresDialect) DataTypeOf(field *gorm.StructField) string {
+	return PostgresType(d.Dialect.DataTypeOf(field))
+}
+
+// PostgresType translates the MySQL column types used in entity struct tags to compatible PostgreSQL types.
+func PostgresType(sqlType string) string {
+	m := sqlTypePattern.FindStringSubmatch(strings.TrimSpace(sqlType))
+
+	if m == nil {
+		return sqlType
+	}
+
+	name, size, rest := strings.ToUpper(m[1]), m[2], m[3]
+
+	switch name {
+	case "VARBINARY", "VARCHAR", "CHAR", "BINARY":
+		if size == "" {
+			return "TEXT" + rest
+		}
+
+		return fmt.Sprintf("VARCHAR(%s)%s", size, rest)
+	case "TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB":
+		return "BYTEA" + rest
+	case "TINYTEXT", "MEDIUMTEXT", "LONGTEXT":
+		return "TEXT" + rest
+	case "DATETIME":
+		return "TIMESTAMP" + rest
+	case "FLOAT":
+		return "REAL" + rest
+	case "DOUBLE":
+		return "DOUBLE PRECISION" + rest
+	case "TINYINT":
+		return "SMALLINT" + rest
+	default:
+		return sqlType
+	}
+}
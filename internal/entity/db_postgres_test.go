@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresType(t *testing.T) {
+	t.Run("VarBinary", func(t *testing.T) {
+		assert.Equal(t, "VARCHAR(42)", PostgresType("VARBINARY(42)"))
+		assert.Equal(t, "VARCHAR(2048)", PostgresType("varbinary(2048)"))
+	})
+	t.Run("VarChar", func(t *testing.T) {
+		assert.Equal(t, "VARCHAR(255)", PostgresType("VARCHAR(255)"))
+	})
+	t.Run("Blob", func(t *testing.T) {
+		assert.Equal(t, "BYTEA", PostgresType("MEDIUMBLOB"))
+	})
+	t.Run("DateTime", func(t *testing.T) {
+		assert.Equal(t, "TIMESTAMP", PostgresType("DATETIME"))
+	})
+	t.Run("Float", func(t *testing.T) {
+		assert.Equal(t, "REAL", PostgresType("FLOAT"))
+	})
+	t.Run("SmallInt", func(t *testing.T) {
+		assert.Equal(t, "SMALLINT", PostgresType("SMALLINT"))
+	})
+	t.Run("Unknown", func(t *testing.T) {
+		assert.Equal(t, "JSONB", PostgresType("JSONB"))
+	})
+}
+
+func TestPostgresDialect(t *testing.T) {
+	d, ok := gorm.GetDialect(Postgres)
+
+	if !ok {
+		t.Fatal("dialect not registered")
+	}
+
+	assert.IsType(t, &postgresDialect{}, d)
+
+	// Each connection gets its own default dialect, see gorm.Open().
+	conn := &postgresDialect{}
+	conn.SetDB(nil)
+
+	assert.Equal(t, Postgres, conn.GetName())
+	assert.Equal(t, "VARCHAR(42)", conn.DataTypeOf(&gorm.StructField{
+		Struct:      reflect.StructField{Type: reflect.TypeOf("")},
+		TagSettings: map[string]string{"TYPE": "VARBINARY(42)"},
+	}))
+}
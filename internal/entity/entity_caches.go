@@ -0,0 +1,10 @@
+package entity
+
+// FlushCaches resets all entity caches, e.g. after the index has been restored from a backup.
+func FlushCaches() {
+	FlushAlbumCache()
+	FlushCameraCache()
+	FlushLensCache()
+	FlushCountryCache()
+	FlushSessionCache()
+}
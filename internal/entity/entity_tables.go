@@ -16,6 +16,7 @@ type Tables map[string]interface{}
 var Entities = Tables{
 	migrate.Migration{}.TableName(): &migrate.Migration{},
 	Error{}.TableName():             &Error{},
+	AuditEvent{}.TableName():        &AuditEvent{},
 	Password{}.TableName():          &Password{},
 	User{}.TableName():              &User{},
 	UserDetails{}.TableName():       &UserDetails{},
@@ -49,6 +50,12 @@ var Entities = Tables{
 	Marker{}.TableName():            &Marker{},
 	Reaction{}.TableName():          &Reaction{},
 	UserShare{}.TableName():         &UserShare{},
+	UserToken{}.TableName():         &UserToken{},
+	UserPasscode{}.TableName():      &UserPasscode{},
+	Webhook{}.TableName():           &Webhook{},
+	WebhookDelivery{}.TableName():   &WebhookDelivery{},
+	Job{}.TableName():               &Job{},
+	TrashFile{}.TableName():         &TrashFile{},
 }
 
 // WaitForMigration waits for the database migration to be successful.
@@ -87,7 +94,7 @@ func (list Tables) Truncate(db *gorm.DB) {
 	}()
 
 	for name = range list {
-		if err := db.Exec(fmt.Sprintf("DELETE FROM %s WHERE 1", name)).Error; err == nil {
+		if err := db.Exec(fmt.Sprintf("DELETE FROM %s WHERE 1 = 1", name)).Error; err == nil {
 			// log.Debugf("entity: removed all data from %s", name)
 			break
 		} else if err.Error() != "record not found" {
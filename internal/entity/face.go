@@ -244,7 +244,7 @@ func (m *Face) MatchMarkers(faceIds []string) error {
 	var markers Markers
 
 	err := Db().
-		Where("marker_invalid = 0 AND marker_type = ? AND face_id IN (?)", MarkerFace, faceIds).
+		Where("marker_invalid = FALSE AND marker_type = ? AND face_id IN (?)", MarkerFace, faceIds).
 		Find(&markers).Error
 
 	if err != nil {
@@ -277,7 +277,7 @@ func (m *Face) SetSubjectUID(subjUid string) (err error) {
 		Where("face_id = ?", m.ID).
 		Where("subj_src = ?", SrcAuto).
 		Where("subj_uid <> ?", m.SubjUID).
-		Where("marker_invalid = 0").
+		Where("marker_invalid = FALSE").
 		UpdateColumns(Values{"subj_uid": m.SubjUID, "marker_review": false}).Error; err != nil {
 		return err
 	}
@@ -417,7 +417,7 @@ func ValidFaceCount(fileUid string) (c int) {
 
 	if err := Db().Model(Marker{}).
 		Where("file_uid = ? AND marker_type = ?", fileUid, MarkerFace).
-		Where("marker_invalid = 0").
+		Where("marker_invalid = FALSE").
 		Count(&c).Error; err != nil {
 		log.Errorf("file: %s (count faces)", err)
 		return 0
@@ -130,19 +130,31 @@ func (m File) RegenerateIndex() {
 				gorm.Expr(photosTable), updateWhere).Error)
 
 		Log("files", "regenerate media_id",
-			Db().Exec("UPDATE files SET media_id = CASE WHEN file_missing = 0 AND deleted_at IS NULL THEN CONCAT((10000000000 - photo_id), '-', 1 + file_sidecar - file_primary, '-', file_uid) ELSE NULL END WHERE ?",
+			Db().Exec("UPDATE files SET media_id = CASE WHEN file_missing = FALSE AND deleted_at IS NULL THEN CONCAT((10000000000 - photo_id), '-', 1 + file_sidecar - file_primary, '-', file_uid) ELSE NULL END WHERE ?",
 				updateWhere).Error)
 
 		Log("files", "regenerate time_index",
 			Db().Exec("UPDATE files SET time_index = CASE WHEN media_id IS NOT NULL AND photo_taken_at IS NOT NULL THEN CONCAT(100000000000000 - CAST(photo_taken_at AS UNSIGNED), '-', media_id) ELSE NULL END WHERE ?",
 				updateWhere).Error)
+	case Postgres:
+		Log("files", "regenerate photo_taken_at",
+			Db().Exec("UPDATE files SET photo_taken_at = p.taken_at_local FROM ? p WHERE p.id = files.photo_id AND ?",
+				gorm.Expr(photosTable), updateWhere).Error)
+
+		Log("files", "regenerate media_id",
+			Db().Exec("UPDATE files SET media_id = CASE WHEN file_missing = FALSE AND deleted_at IS NULL THEN ((10000000000 - photo_id) || '-' || (1 + file_sidecar::integer - file_primary::integer) || '-' || file_uid) ELSE NULL END WHERE ?",
+				updateWhere).Error)
+
+		Log("files", "regenerate time_index",
+			Db().Exec("UPDATE files SET time_index = CASE WHEN media_id IS NOT NULL AND photo_taken_at IS NOT NULL THEN ((100000000000000 - CAST(TO_CHAR(photo_taken_at, 'YYYYMMDDHH24MISS') AS BIGINT)) || '-' || media_id) ELSE NULL END WHERE ?",
+				updateWhere).Error)
 	case SQLite3:
 		Log("files", "regenerate photo_taken_at",
 			Db().Exec("UPDATE files SET photo_taken_at = (SELECT p.taken_at_local FROM ? p WHERE p.id = photo_id) WHERE ?",
 				gorm.Expr(photosTable), updateWhere).Error)
 
 		Log("files", "regenerate media_id",
-			Db().Exec("UPDATE files SET media_id = CASE WHEN file_missing = 0 AND deleted_at IS NULL THEN ((10000000000 - photo_id) || '-' || (1 + file_sidecar - file_primary) || '-' || file_uid) ELSE NULL END WHERE ?",
+			Db().Exec("UPDATE files SET media_id = CASE WHEN file_missing = FALSE AND deleted_at IS NULL THEN ((10000000000 - photo_id) || '-' || (1 + file_sidecar - file_primary) || '-' || file_uid) ELSE NULL END WHERE ?",
 				updateWhere).Error)
 
 		Log("files", "regenerate time_index",
@@ -180,7 +192,7 @@ func FirstFileByHash(fileHash string) (File, error) {
 func PrimaryFile(photoUid string) (*File, error) {
 	file := File{}
 
-	res := Db().Unscoped().First(&file, "file_primary = 1 AND photo_uid = ?", photoUid)
+	res := Db().Unscoped().First(&file, "file_primary = TRUE AND photo_uid = ?", photoUid)
 
 	return &file, res.Error
 }
@@ -377,14 +389,14 @@ func (m *File) Purge() error {
 	m.FileMissing = true
 	m.FilePrimary = false
 	m.DeletedAt = &deletedAt
-	return UnscopedDb().Exec("UPDATE files SET file_missing = 1, file_primary = 0, deleted_at = ? WHERE id = ?", &deletedAt, m.ID).Error
+	return UnscopedDb().Exec("UPDATE files SET file_missing = TRUE, file_primary = FALSE, deleted_at = ? WHERE id = ?", &deletedAt, m.ID).Error
 }
 
 // Found restores a previously purged file.
 func (m *File) Found() error {
 	m.FileMissing = false
 	m.DeletedAt = nil
-	return UnscopedDb().Exec("UPDATE files SET file_missing = 0, deleted_at = NULL WHERE id = ?", m.ID).Error
+	return UnscopedDb().Exec("UPDATE files SET file_missing = FALSE, deleted_at = NULL WHERE id = ?", m.ID).Error
 }
 
 // AllFilesMissing returns true, if all files for the photo of this file are missing.
@@ -392,7 +404,7 @@ func (m *File) AllFilesMissing() bool {
 	count := 0
 
 	if err := Db().Model(&File{}).
-		Where("photo_id = ? AND file_missing = 0", m.PhotoID).
+		Where("photo_id = ? AND file_missing = FALSE", m.PhotoID).
 		Count(&count).Error; err != nil {
 		log.Errorf("file: %s", err.Error())
 	}
@@ -465,7 +477,7 @@ func (m *File) UpdateVideoInfos() error {
 		return err
 	}
 
-	return Db().Model(File{}).Where("photo_id = ? AND file_video = 1", m.PhotoID).Updates(values).Error
+	return Db().Model(File{}).Where("photo_id = ? AND file_video = TRUE", m.PhotoID).Updates(values).Error
 }
 
 // Update updates a column in the database.
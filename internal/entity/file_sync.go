@@ -1,7 +1,10 @@
 package entity
 
 import (
+	"os"
 	"time"
+
+	"github.com/photoprism/photoprism/pkg/fs"
 )
 
 const (
@@ -10,16 +13,30 @@ const (
 	FileSyncExists     = "exists"
 	FileSyncDownloaded = "downloaded"
 	FileSyncUploaded   = "uploaded"
+	FileSyncPending    = "pending"
 )
 
+// MaxRetryDelay is the maximum delay before a failed transfer is retried.
+const MaxRetryDelay = 24 * time.Hour
+
 // FileSync represents a one-to-many relation between File and Account for syncing with remote services.
+//
+// Field Descriptions:
+// - RemoteDate, RemoteSize, and RemoteETag describe the remote version, see RemoteChanged.
+// - LocalDate and LocalSize describe the local version when it was last synced, see LocalChanged.
+// - Conflict holds the policy that was applied if both versions have been changed, see SyncConflictKeep.
+// - Error and Errors contain the last error and the number of failed transfers, see RetryAfter.
 type FileSync struct {
 	RemoteName string `gorm:"primary_key;auto_increment:false;type:VARBINARY(255)"`
 	ServiceID  uint   `gorm:"primary_key;auto_increment:false"`
 	FileID     uint   `gorm:"index;"`
 	RemoteDate time.Time
 	RemoteSize int64
+	RemoteETag string `gorm:"type:VARBINARY(255);column:remote_etag;"`
+	LocalDate  time.Time
+	LocalSize  int64
 	Status     string `gorm:"type:VARBINARY(16);"`
+	Conflict   string `gorm:"type:VARBINARY(16);"`
 	Error      string `gorm:"type:VARBINARY(512);"`
 	Errors     int
 	File       *File
@@ -44,6 +61,63 @@ func NewFileSync(accountID uint, remoteName string) *FileSync {
 	return result
 }
 
+// SetRemote updates the remote version info.
+func (m *FileSync) SetRemote(info fs.FileInfo) {
+	m.RemoteDate = info.Date
+	m.RemoteSize = info.Size
+	m.RemoteETag = info.ETag
+}
+
+// SetLocal updates the local version info after the file has been synced.
+func (m *FileSync) SetLocal(info os.FileInfo) {
+	m.LocalDate = info.ModTime().UTC()
+	m.LocalSize = info.Size()
+}
+
+// Synced checks if the local version info has been recorded before.
+func (m *FileSync) Synced() bool {
+	return !m.LocalDate.IsZero()
+}
+
+// RemoteChanged checks if the remote file differs from the recorded remote version. Entity tags are
+// compared if available, since not all services preserve the modification time.
+func (m *FileSync) RemoteChanged(info fs.FileInfo) bool {
+	if m.RemoteETag != "" && info.ETag != "" {
+		return m.RemoteETag != info.ETag
+	}
+
+	return m.RemoteSize != info.Size || !m.RemoteDate.Equal(info.Date)
+}
+
+// LocalChanged checks if the local file has been modified since it was last synced.
+func (m *FileSync) LocalChanged(info os.FileInfo) bool {
+	if !m.Synced() {
+		return false
+	}
+
+	return m.LocalSize != info.Size() || !m.LocalDate.Equal(info.ModTime().UTC())
+}
+
+// RetryAfter returns the time after which a failed transfer should be retried. The delay starts at one
+// minute and doubles with each failed attempt up to MaxRetryDelay.
+func (m *FileSync) RetryAfter() time.Time {
+	if m.Errors <= 0 {
+		return m.UpdatedAt
+	}
+
+	delay := time.Minute
+
+	for i := 1; i < m.Errors && delay < MaxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > MaxRetryDelay {
+		delay = MaxRetryDelay
+	}
+
+	return m.UpdatedAt.Add(delay)
+}
+
 // Updates multiple columns in the database.
 func (m *FileSync) Updates(values interface{}) error {
 	return UnscopedDb().Model(m).UpdateColumns(values).Error
@@ -64,6 +138,28 @@ func (m *FileSync) Create() error {
 	return Db().Create(m).Error
 }
 
+// FindFileSync returns the existing row for a remote file name, or nil if it does not exist.
+func FindFileSync(serviceID uint, remoteName string) *FileSync {
+	result := FileSync{}
+
+	if err := Db().Where("service_id = ? AND remote_name = ?", serviceID, remoteName).First(&result).Error; err != nil {
+		return nil
+	}
+
+	return &result
+}
+
+// FindPendingUpload returns the row of a previously failed upload of a file, or nil if it does not exist.
+func FindPendingUpload(serviceID, fileID uint) *FileSync {
+	result := FileSync{}
+
+	if err := Db().Where("service_id = ? AND file_id = ? AND status = ?", serviceID, fileID, FileSyncPending).First(&result).Error; err != nil {
+		return nil
+	}
+
+	return &result
+}
+
 // FirstOrCreateFileSync returns the existing row, inserts a new row or nil in case of errors.
 func FirstOrCreateFileSync(m *FileSync) *FileSync {
 	result := FileSync{}
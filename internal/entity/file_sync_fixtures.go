@@ -65,6 +65,23 @@ var FileSyncFixtures = FileSyncMap{
 		CreatedAt:  time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
 		UpdatedAt:  time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
 	},
+	"FileSync4": {
+		FileID:     0,
+		ServiceID:  1000001,
+		RemoteName: "/20200706-092527-Conflict-2020.jpg",
+		Status:     "downloaded",
+		Conflict:   "keep",
+		Error:      "",
+		Errors:     0,
+		Account:    &ServiceFixtureWebdavDummy2,
+		RemoteDate: time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC),
+		RemoteSize: int64(920),
+		RemoteETag: `"c3ab8ff13720e8ad9047dd39466b3c89"`,
+		LocalDate:  time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC),
+		LocalSize:  int64(880),
+		CreatedAt:  time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt:  time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC),
+	},
 }
 
 // CreateFileSyncFixtures inserts known entities into the database for testing.
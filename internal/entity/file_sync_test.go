@@ -1,9 +1,13 @@
 package entity
 
 import (
+	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/pkg/fs"
 )
 
 func TestFileSync_TableName(t *testing.T) {
@@ -104,3 +108,120 @@ func TestFileSync_Save(t *testing.T) {
 		assert.True(t, afterDate.After(initialDate))
 	})
 }
+
+type testFileInfo struct {
+	size    int64
+	modTime time.Time
+}
+
+func (f testFileInfo) Name() string       { return "test.jpg" }
+func (f testFileInfo) Size() int64        { return f.size }
+func (f testFileInfo) Mode() os.FileMode  { return 0o644 }
+func (f testFileInfo) ModTime() time.Time { return f.modTime }
+func (f testFileInfo) IsDir() bool        { return false }
+func (f testFileInfo) Sys() interface{}   { return nil }
+
+func TestFileSync_RemoteChanged(t *testing.T) {
+	date := time.Date(2022, 10, 18, 12, 30, 0, 0, time.UTC)
+
+	t.Run("Unchanged", func(t *testing.T) {
+		m := FileSync{RemoteDate: date, RemoteSize: 100}
+		assert.False(t, m.RemoteChanged(fs.FileInfo{Date: date, Size: 100}))
+	})
+	t.Run("Date", func(t *testing.T) {
+		m := FileSync{RemoteDate: date, RemoteSize: 100}
+		assert.True(t, m.RemoteChanged(fs.FileInfo{Date: date.Add(time.Second), Size: 100}))
+	})
+	t.Run("Size", func(t *testing.T) {
+		m := FileSync{RemoteDate: date, RemoteSize: 100}
+		assert.True(t, m.RemoteChanged(fs.FileInfo{Date: date, Size: 101}))
+	})
+	t.Run("ETag", func(t *testing.T) {
+		m := FileSync{RemoteDate: date, RemoteSize: 100, RemoteETag: `"a"`}
+		assert.False(t, m.RemoteChanged(fs.FileInfo{Date: date.Add(time.Hour), Size: 100, ETag: `"a"`}))
+		assert.True(t, m.RemoteChanged(fs.FileInfo{Date: date, Size: 100, ETag: `"b"`}))
+	})
+}
+
+func TestFileSync_LocalChanged(t *testing.T) {
+	date := time.Date(2022, 10, 18, 12, 30, 0, 0, time.UTC)
+
+	t.Run("NotSynced", func(t *testing.T) {
+		m := FileSync{}
+		assert.False(t, m.Synced())
+		assert.False(t, m.LocalChanged(testFileInfo{size: 100, modTime: date}))
+	})
+	t.Run("Unchanged", func(t *testing.T) {
+		m := FileSync{}
+		m.SetLocal(testFileInfo{size: 100, modTime: date})
+		assert.True(t, m.Synced())
+		assert.False(t, m.LocalChanged(testFileInfo{size: 100, modTime: date}))
+	})
+	t.Run("Changed", func(t *testing.T) {
+		m := FileSync{}
+		m.SetLocal(testFileInfo{size: 100, modTime: date})
+		assert.True(t, m.LocalChanged(testFileInfo{size: 100, modTime: date.Add(time.Minute)}))
+		assert.True(t, m.LocalChanged(testFileInfo{size: 200, modTime: date}))
+	})
+}
+
+func TestFileSync_RetryAfter(t *testing.T) {
+	updated := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("NoErrors", func(t *testing.T) {
+		m := FileSync{UpdatedAt: updated}
+		assert.Equal(t, updated, m.RetryAfter())
+	})
+	t.Run("Backoff", func(t *testing.T) {
+		m := FileSync{UpdatedAt: updated, Errors: 1}
+		assert.Equal(t, updated.Add(time.Minute), m.RetryAfter())
+
+		m.Errors = 2
+		assert.Equal(t, updated.Add(2*time.Minute), m.RetryAfter())
+
+		m.Errors = 4
+		assert.Equal(t, updated.Add(8*time.Minute), m.RetryAfter())
+	})
+	t.Run("Max", func(t *testing.T) {
+		m := FileSync{UpdatedAt: updated, Errors: 100}
+		assert.Equal(t, updated.Add(MaxRetryDelay), m.RetryAfter())
+	})
+}
+
+func TestFindFileSync(t *testing.T) {
+	t.Run("Found", func(t *testing.T) {
+		fixture := FileSyncFixtures.Get("FileSync1", 0, "")
+		result := FindFileSync(fixture.ServiceID, fixture.RemoteName)
+
+		if assert.NotNil(t, result) {
+			assert.Equal(t, fixture.FileID, result.FileID)
+		}
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		assert.Nil(t, FindFileSync(1000000, "/foo/bar.jpg"))
+	})
+}
+
+func TestFindPendingUpload(t *testing.T) {
+	m := NewFileSync(1000000, "/pending/IMG_0001.jpg")
+	m.Status = FileSyncPending
+	m.FileID = 1000999
+	m.Errors = 1
+	m.Error = "connection lost"
+
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Found", func(t *testing.T) {
+		result := FindPendingUpload(1000000, 1000999)
+
+		if assert.NotNil(t, result) {
+			assert.Equal(t, "/pending/IMG_0001.jpg", result.RemoteName)
+			assert.Equal(t, 1, result.Errors)
+		}
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		assert.Nil(t, FindPendingUpload(1000000, 1000000))
+	})
+}
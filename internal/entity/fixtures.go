@@ -32,4 +32,9 @@ func CreateTestFixtures() {
 	CreateReactionFixtures()
 	CreatePasswordFixtures()
 	CreateUserShareFixtures()
+	CreateUserTokenFixtures()
+	CreateAuditEventFixtures()
+	CreateWebhookFixtures()
+	CreateJobFixtures()
+	CreateTrashFileFixtures()
 }
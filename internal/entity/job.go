@@ -0,0 +1,256 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/photoprism/photoprism/pkg/rnd"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// JobUID is the unique ID prefix of background jobs.
+const JobUID = byte('q')
+
+// Background job types.
+const (
+	JobTypeIndex   = "index"
+	JobTypeImport  = "import"
+	JobTypeFaces   = "faces"
+	JobTypeConvert = "convert"
+	JobTypeMoments = "moments"
+	JobTypePlaces  = "places"
+	JobTypeCleanup = "cleanup"
+	JobTypeThumbs  = "thumbs"
+	JobTypeTrash   = "trash"
+	JobTypeBackup  = "backup"
+)
+
+// Background job states.
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+	JobCanceled  = "canceled"
+)
+
+// Jobs represents a list of background jobs.
+type Jobs []Job
+
+// Job represents a queued, running, or finished background job, e.g. indexing or importing files.
+type Job struct {
+	JobUID      string     `gorm:"type:VARBINARY(42);primary_key;auto_increment:false;" json:"UID" yaml:"UID"`
+	JobType     string     `gorm:"type:VARBINARY(64);index;" json:"Type" yaml:"Type"`
+	JobOptions  string     `gorm:"type:VARBINARY(2048);" json:"Options" yaml:"Options,omitempty"`
+	JobState    string     `gorm:"type:VARBINARY(32);index;" json:"State" yaml:"State"`
+	JobProgress int        `json:"Progress" yaml:"Progress,omitempty"`
+	JobStep     string     `gorm:"type:VARCHAR(255);" json:"Step" yaml:"Step,omitempty"`
+	JobError    string     `gorm:"type:VARCHAR(512);" json:"Error" yaml:"Error,omitempty"`
+	CreatedBy   string     `gorm:"type:VARBINARY(42);" json:"CreatedBy,omitempty" yaml:"CreatedBy,omitempty"`
+	CreatedAt   time.Time  `json:"CreatedAt" yaml:"-"`
+	UpdatedAt   time.Time  `json:"UpdatedAt" yaml:"-"`
+	StartedAt   *time.Time `json:"StartedAt" yaml:"-"`
+	FinishedAt  *time.Time `json:"FinishedAt" yaml:"-"`
+}
+
+// TableName returns the entity table name.
+func (Job) TableName() string {
+	return "jobs"
+}
+
+// NewJob creates a new queued job with the options serialized as JSON.
+func NewJob(jobType string, opt interface{}, createdBy string) (*Job, error) {
+	if jobType == "" {
+		return nil, fmt.Errorf("job type is missing")
+	}
+
+	m := &Job{
+		JobUID:    rnd.GenerateUID(JobUID),
+		JobType:   jobType,
+		JobState:  JobQueued,
+		CreatedBy: createdBy,
+		CreatedAt: TimeStamp(),
+		UpdatedAt: TimeStamp(),
+	}
+
+	if opt != nil {
+		if b, err := json.Marshal(opt); err != nil {
+			return nil, err
+		} else if len(b) > 2048 {
+			return nil, fmt.Errorf("job options are too long")
+		} else {
+			m.JobOptions = string(b)
+		}
+	}
+
+	return m, nil
+}
+
+// FindJob returns the job with the specified uid or nil if it was not found.
+func FindJob(uid string) *Job {
+	if rnd.InvalidUID(uid, JobUID) {
+		return nil
+	}
+
+	m := &Job{}
+
+	// Find matching record.
+	if UnscopedDb().First(m, "job_uid = ?", uid).RecordNotFound() {
+		return nil
+	}
+
+	return m
+}
+
+// FindJobs returns jobs with the specified state and type, newest first. Empty values match all jobs.
+func FindJobs(state, jobType string, limit, offset int) (result Jobs, err error) {
+	stmt := UnscopedDb().Order("created_at DESC, job_uid DESC")
+
+	if state != "" {
+		stmt = stmt.Where("job_state = ?", state)
+	}
+
+	if jobType != "" {
+		stmt = stmt.Where("job_type = ?", jobType)
+	}
+
+	if limit > 0 {
+		stmt = stmt.Limit(limit).Offset(offset)
+	}
+
+	err = stmt.Find(&result).Error
+
+	return result, err
+}
+
+// NextJob returns the job that has been queued first, or nil if there are none.
+func NextJob() *Job {
+	m := &Job{}
+
+	if UnscopedDb().Where("job_state = ?", JobQueued).Order("created_at, job_uid").First(m).RecordNotFound() {
+		return nil
+	}
+
+	return m
+}
+
+// ResetJobs queues jobs of the resumable types that were interrupted while running, e.g. by a restart,
+// and marks all other interrupted jobs as failed.
+func ResetJobs(resumable []string) (resumed, failed int64, err error) {
+	now := TimeStamp()
+
+	if len(resumable) > 0 {
+		res := UnscopedDb().Model(&Job{}).
+			Where("job_state = ? AND job_type IN (?)", JobRunning, resumable).
+			Updates(Values{"job_state": JobQueued, "job_step": "", "started_at": nil, "updated_at": now})
+
+		if res.Error != nil {
+			return 0, 0, res.Error
+		}
+
+		resumed = res.RowsAffected
+	}
+
+	res := UnscopedDb().Model(&Job{}).
+		Where("job_state = ?", JobRunning).
+		Updates(Values{"job_state": JobFailed, "job_error": "interrupted", "finished_at": now, "updated_at": now})
+
+	return resumed, res.RowsAffected, res.Error
+}
+
+// Create inserts a new record into the database.
+func (m *Job) Create() error {
+	return Db().Create(m).Error
+}
+
+// Save updates the record in the database or inserts a new record if it does not already exist.
+func (m *Job) Save() error {
+	m.UpdatedAt = TimeStamp()
+
+	return Db().Save(m).Error
+}
+
+// Options unmarshals the job options into the value pointed to by opt.
+func (m *Job) Options(opt interface{}) error {
+	if m.JobOptions == "" {
+		return nil
+	}
+
+	return json.Unmarshal([]byte(m.JobOptions), opt)
+}
+
+// Start marks the job as running.
+func (m *Job) Start() error {
+	now := TimeStamp()
+
+	m.JobState = JobRunning
+	m.JobProgress = 0
+	m.JobStep = ""
+	m.JobError = ""
+	m.StartedAt = &now
+	m.FinishedAt = nil
+
+	return m.Save()
+}
+
+// SetProgress updates the number of processed items and the current step.
+func (m *Job) SetProgress(progress int, step string) error {
+	m.JobProgress = progress
+	m.JobStep = txt.Clip(step, txt.ClipDefault)
+	m.UpdatedAt = TimeStamp()
+
+	return UnscopedDb().Model(m).Updates(Values{"job_progress": m.JobProgress, "job_step": m.JobStep, "updated_at": m.UpdatedAt}).Error
+}
+
+// Finish marks the job as completed, or as failed if an error is passed.
+func (m *Job) Finish(err error) error {
+	now := TimeStamp()
+
+	if err != nil {
+		m.JobState = JobFailed
+		m.JobError = txt.Clip(err.Error(), 512)
+	} else {
+		m.JobState = JobCompleted
+		m.JobError = ""
+	}
+
+	m.FinishedAt = &now
+
+	return m.Save()
+}
+
+// Cancel marks the job as canceled.
+func (m *Job) Cancel() error {
+	now := TimeStamp()
+
+	m.JobState = JobCanceled
+	m.FinishedAt = &now
+
+	return m.Save()
+}
+
+// Queued checks if the job waits to be run.
+func (m *Job) Queued() bool {
+	return m.JobState == JobQueued
+}
+
+// Running checks if the job is currently running.
+func (m *Job) Running() bool {
+	return m.JobState == JobRunning
+}
+
+// Finished checks if the job has completed, failed, or been canceled.
+func (m *Job) Finished() bool {
+	switch m.JobState {
+	case JobCompleted, JobFailed, JobCanceled:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the job uid for use in logs.
+func (m *Job) String() string {
+	return m.JobUID
+}
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"time"
+)
+
+type JobMap map[string]Job
+
+// Get returns a fixture for use in tests.
+func (m JobMap) Get(name string) Job {
+	if result, ok := m[name]; ok {
+		return result
+	}
+
+	return Job{}
+}
+
+// Pointer returns a fixture pointer for use in tests.
+func (m JobMap) Pointer(name string) *Job {
+	if result, ok := m[name]; ok {
+		return &result
+	}
+
+	return &Job{}
+}
+
+var jobStarted = time.Date(2022, 10, 8, 10, 0, 0, 0, time.UTC)
+var jobFinished = time.Date(2022, 10, 8, 10, 5, 0, 0, time.UTC)
+
+// JobFixtures specifies fixtures for use in tests.
+var JobFixtures = JobMap{
+	"index-completed": {
+		JobUID:      "qrhn1bzi0fw2x7ma",
+		JobType:     JobTypeIndex,
+		JobOptions:  `{"path":"/","rescan":false}`,
+		JobState:    JobCompleted,
+		JobProgress: 1250,
+		CreatedBy:   "uqxetse3cy5eo9z2",
+		CreatedAt:   jobStarted,
+		UpdatedAt:   jobFinished,
+		StartedAt:   &jobStarted,
+		FinishedAt:  &jobFinished,
+	},
+	"faces-failed": {
+		JobUID:     "qrhn1bzi0fw2x7mb",
+		JobType:    JobTypeFaces,
+		JobOptions: `{"force":true}`,
+		JobState:   JobFailed,
+		JobError:   "face recognition is disabled",
+		CreatedBy:  "uqxetse3cy5eo9z2",
+		CreatedAt:  jobStarted.Add(time.Minute),
+		UpdatedAt:  jobStarted.Add(time.Minute),
+		StartedAt:  &jobStarted,
+		FinishedAt: &jobStarted,
+	},
+	"import-canceled": {
+		JobUID:     "qrhn1bzi0fw2x7mc",
+		JobType:    JobTypeImport,
+		JobOptions: `{"path":"/2022","move":true}`,
+		JobState:   JobCanceled,
+		CreatedBy:  "uqxetse3cy5eo9z2",
+		CreatedAt:  jobStarted.Add(2 * time.Minute),
+		UpdatedAt:  jobFinished,
+		FinishedAt: &jobFinished,
+	},
+}
+
+// CreateJobFixtures creates the fixtures specified above.
+func CreateJobFixtures() {
+	for _, entity := range JobFixtures {
+		Db().Create(&entity)
+	}
+}
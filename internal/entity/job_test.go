@@ -0,0 +1,162 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/pkg/rnd"
+)
+
+func TestNewJob(t *testing.T) {
+	t.Run("Ok", func(t *testing.T) {
+		m, err := NewJob("index", map[string]interface{}{"path": "/2022", "rescan": true}, Admin.UID())
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, rnd.IsUID(m.JobUID, JobUID))
+		assert.Equal(t, "index", m.JobType)
+		assert.Equal(t, `{"path":"/2022","rescan":true}`, m.JobOptions)
+		assert.True(t, m.Queued())
+		assert.False(t, m.Running())
+		assert.False(t, m.Finished())
+	})
+	t.Run("NoOptions", func(t *testing.T) {
+		m, err := NewJob("faces", nil, "")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "", m.JobOptions)
+
+		var opt struct{ Force bool }
+		assert.NoError(t, m.Options(&opt))
+		assert.False(t, opt.Force)
+	})
+	t.Run("TypeMissing", func(t *testing.T) {
+		_, err := NewJob("", nil, "")
+		assert.Error(t, err)
+	})
+}
+
+func TestFindJob(t *testing.T) {
+	t.Run("Found", func(t *testing.T) {
+		m := FindJob("qrhn1bzi0fw2x7ma")
+
+		if m == nil {
+			t.Fatal("job not found")
+		}
+
+		assert.Equal(t, "index", m.JobType)
+		assert.Equal(t, 1250, m.JobProgress)
+		assert.True(t, m.Finished())
+
+		var opt struct {
+			Path   string `json:"path"`
+			Rescan bool   `json:"rescan"`
+		}
+
+		assert.NoError(t, m.Options(&opt))
+		assert.Equal(t, "/", opt.Path)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		assert.Nil(t, FindJob("qrhn1bzi0fw2x7zz"))
+		assert.Nil(t, FindJob("wrhn0cz3gzecxmxy"))
+	})
+}
+
+func TestFindJobs(t *testing.T) {
+	t.Run("All", func(t *testing.T) {
+		result, err := FindJobs("", "", 0, 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.GreaterOrEqual(t, len(result), 3)
+	})
+	t.Run("State", func(t *testing.T) {
+		result, err := FindJobs(JobFailed, "faces", 10, 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.Len(t, result, 1) {
+			assert.Equal(t, "qrhn1bzi0fw2x7mb", result[0].JobUID)
+		}
+	})
+}
+
+func TestJob_Lifecycle(t *testing.T) {
+	m, err := NewJob("import", map[string]string{"path": "/"}, "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, m.Create())
+
+	if next := NextJob(); assert.NotNil(t, next) {
+		assert.Equal(t, m.JobUID, next.JobUID)
+	}
+
+	assert.NoError(t, m.Start())
+	assert.True(t, m.Running())
+	assert.NotNil(t, m.StartedAt)
+	assert.Nil(t, NextJob())
+
+	assert.NoError(t, m.SetProgress(5, "IMG_1234.jpg"))
+
+	if found := FindJob(m.JobUID); assert.NotNil(t, found) {
+		assert.Equal(t, 5, found.JobProgress)
+		assert.Equal(t, "IMG_1234.jpg", found.JobStep)
+		assert.Equal(t, JobRunning, found.JobState)
+	}
+
+	assert.NoError(t, m.Finish(errors.New("disk full")))
+	assert.Equal(t, JobFailed, m.JobState)
+	assert.Equal(t, "disk full", m.JobError)
+	assert.True(t, m.Finished())
+
+	assert.NoError(t, m.Finish(nil))
+	assert.Equal(t, JobCompleted, m.JobState)
+	assert.Equal(t, "", m.JobError)
+
+	assert.NoError(t, m.Cancel())
+	assert.Equal(t, JobCanceled, m.JobState)
+}
+
+func TestResetJobs(t *testing.T) {
+	index, _ := NewJob("index", nil, "")
+	faces, _ := NewJob("faces", nil, "")
+
+	for _, m := range []*Job{index, faces} {
+		assert.NoError(t, m.Create())
+		assert.NoError(t, m.Start())
+	}
+
+	resumed, failed, err := ResetJobs([]string{"index", "import"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, int64(1), resumed)
+	assert.Equal(t, int64(1), failed)
+
+	if m := FindJob(index.JobUID); assert.NotNil(t, m) {
+		assert.True(t, m.Queued())
+		assert.Nil(t, m.StartedAt)
+		assert.NoError(t, m.Cancel())
+	}
+
+	if m := FindJob(faces.JobUID); assert.NotNil(t, m) {
+		assert.Equal(t, JobFailed, m.JobState)
+		assert.Equal(t, "interrupted", m.JobError)
+	}
+}
@@ -2,11 +2,13 @@ package entity
 
 import (
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/jinzhu/gorm"
 
 	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/i18n"
 	"github.com/photoprism/photoprism/pkg/clean"
 	"github.com/photoprism/photoprism/pkg/rnd"
 	"github.com/photoprism/photoprism/pkg/txt"
@@ -59,7 +61,10 @@ func (m *Link) BeforeCreate(scope *gorm.Scope) error {
 
 // NewLink creates a sharing link.
 func NewLink(shareUid string, canComment, canEdit bool) Link {
-	return NewUserLink(shareUid, OwnerUnknown)
+	result := NewUserLink(shareUid, OwnerUnknown)
+	result.SetPerm(canComment, canEdit)
+
+	return result
 }
 
 // NewUserLink creates a sharing link owned by a user.
@@ -78,7 +83,30 @@ func NewUserLink(shareUid, userUid string) Link {
 	return result
 }
 
-// Redeem increases the number of link visitors by one.
+// SetPerm sets the permissions granted to visitors in addition to viewing the shared content.
+func (m *Link) SetPerm(canComment, canEdit bool) {
+	m.Perm = PermView
+
+	if canComment {
+		m.Perm |= PermComment
+	}
+
+	if canEdit {
+		m.Perm |= PermEdit
+	}
+}
+
+// CanComment checks if visitors may comment on the shared content.
+func (m *Link) CanComment() bool {
+	return m.Perm&PermComment != 0
+}
+
+// CanEdit checks if visitors may edit the shared content.
+func (m *Link) CanEdit() bool {
+	return m.Perm&PermEdit != 0
+}
+
+// Redeem increases the number of link views by one, see Links.Redeem.
 func (m *Link) Redeem() *Link {
 	m.LinkViews += 1
 
@@ -101,9 +129,14 @@ func (m *Link) ExpiresAt() *time.Time {
 	return &expires
 }
 
+// Exhausted checks if the share link has reached its maximum number of views.
+func (m *Link) Exhausted() bool {
+	return m.MaxViews > 0 && m.LinkViews >= m.MaxViews
+}
+
 // Expired checks if the share link has expired.
 func (m *Link) Expired() bool {
-	if m.MaxViews > 0 && m.LinkViews >= m.MaxViews {
+	if m.Exhausted() {
 		return true
 	}
 
@@ -140,8 +173,9 @@ func (m *Link) InvalidPassword(password string) bool {
 
 	pw := FindPassword(m.LinkUID)
 
+	// Reject all passwords if the link is protected, but no password is stored.
 	if pw == nil {
-		return password != ""
+		return true
 	}
 
 	return pw.IsWrong(password)
@@ -253,6 +287,65 @@ func FindValidLinks(token, shared string) (found Links) {
 	return found
 }
 
+// VerifyLinks returns the non-expired links for a token and share UID, or an error
+// with a matching HTTP status code if none of them may be used anymore.
+func VerifyLinks(token, shared string) (valid Links, status int, err error) {
+	valid = Links{}
+	found := FindLinks(token, shared)
+
+	if len(found) == 0 {
+		return valid, http.StatusNotFound, i18n.Error(i18n.ErrInvalidLink)
+	}
+
+	exhausted := false
+
+	for _, link := range found {
+		if link.Exhausted() {
+			exhausted = true
+		} else if !link.Expired() {
+			valid = append(valid, link)
+		}
+	}
+
+	if len(valid) > 0 {
+		return valid, http.StatusOK, nil
+	} else if exhausted {
+		return valid, http.StatusForbidden, i18n.Error(i18n.ErrLinkViewLimit)
+	}
+
+	return valid, http.StatusGone, i18n.Error(i18n.ErrLinkExpired)
+}
+
+// HasPassword checks if any of the links requires a password.
+func (m Links) HasPassword() bool {
+	for _, link := range m {
+		if link.HasPassword {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Redeem increases the view counter of each link. It must be called whenever
+// the share token is redeemed, so that repeated visits are counted as well.
+func (m Links) Redeem() {
+	for i := range m {
+		m[i].Redeem()
+	}
+}
+
+// InvalidPassword checks if the password is invalid for any of the links.
+func (m Links) InvalidPassword(password string) bool {
+	for i := range m {
+		if m[i].InvalidPassword(password) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // String returns a human-readable identifier for use in logs.
 func (m *Link) String() string {
 	return clean.Log(m.LinkUID)
@@ -67,6 +67,42 @@ var LinkFixtures = LinkMap{
 		CreatedAt:   time.Date(2020, 3, 6, 2, 6, 51, 0, time.UTC),
 		ModifiedAt:  time.Date(2020, 3, 6, 2, 6, 51, 0, time.UTC),
 	},
+	"3jxf3jfn2k": {
+		LinkUID:     "sqn3xpryd1ob3gtf",
+		ShareUID:    "at9lxuqxpogaaba9",
+		ShareSlug:   "protected",
+		LinkToken:   "3jxf3jfn2k",
+		LinkExpires: 0,
+		LinkViews:   0,
+		MaxViews:    0,
+		HasPassword: true,
+		CreatedAt:   time.Date(2020, 3, 6, 2, 6, 51, 0, time.UTC),
+		ModifiedAt:  time.Date(2020, 3, 6, 2, 6, 51, 0, time.UTC),
+	},
+	"8jxf3jfn2k": {
+		LinkUID:     "sqn8xpryd1ob8gtf",
+		ShareUID:    "at9lxuqxpogaaba9",
+		ShareSlug:   "expired",
+		LinkToken:   "8jxf3jfn2k",
+		LinkExpires: 3600,
+		LinkViews:   0,
+		MaxViews:    0,
+		HasPassword: false,
+		CreatedAt:   time.Date(2020, 3, 6, 2, 6, 51, 0, time.UTC),
+		ModifiedAt:  time.Date(2020, 3, 6, 2, 6, 51, 0, time.UTC),
+	},
+	"9jxf3jfn2k": {
+		LinkUID:     "sqn9xpryd1ob1gtg",
+		ShareUID:    "at9lxuqxpogaaba9",
+		ShareSlug:   "exhausted",
+		LinkToken:   "9jxf3jfn2k",
+		LinkExpires: 0,
+		LinkViews:   3,
+		MaxViews:    3,
+		HasPassword: false,
+		CreatedAt:   time.Date(2020, 3, 6, 2, 6, 51, 0, time.UTC),
+		ModifiedAt:  time.Date(2020, 3, 6, 2, 6, 51, 0, time.UTC),
+	},
 }
 
 // CreateLinkFixtures inserts known entities into the database for testing.
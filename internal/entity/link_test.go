@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/photoprism/photoprism/pkg/rnd"
@@ -12,6 +13,8 @@ func TestNewLink(t *testing.T) {
 	assert.Equal(t, "st9lxuqxpogaaba1", link.ShareUID)
 	assert.Equal(t, 10, len(link.LinkToken))
 	assert.Equal(t, 16, len(link.LinkUID))
+	assert.True(t, link.CanComment())
+	assert.False(t, link.CanEdit())
 }
 
 func TestLink_Expired(t *testing.T) {
@@ -43,6 +46,22 @@ func TestLink_Expired(t *testing.T) {
 	assert.True(t, link.Expired())
 }
 
+func TestLink_Exhausted(t *testing.T) {
+	link := NewLink("st9lxuqxpogaaba1", false, false)
+
+	assert.False(t, link.Exhausted())
+
+	link.MaxViews = 2
+	link.LinkViews = 1
+
+	assert.False(t, link.Exhausted())
+
+	link.LinkViews = 2
+
+	assert.True(t, link.Exhausted())
+	assert.True(t, link.Expired())
+}
+
 func TestLink_Redeem(t *testing.T) {
 	link := NewLink(rnd.GenerateUID(AlbumUID), false, false)
 
@@ -101,6 +120,12 @@ func TestLink_InvalidPassword(t *testing.T) {
 		}
 		assert.True(t, link.InvalidPassword("123"))
 	})
+	t.Run("password missing", func(t *testing.T) {
+		link := Link{LinkUID: "sqn2xpryd1ob7xxx", HasPassword: true}
+
+		assert.True(t, link.InvalidPassword(""))
+		assert.True(t, link.InvalidPassword("123"))
+	})
 }
 
 func TestLink_Save(t *testing.T) {
@@ -186,6 +211,48 @@ func TestFindValidLinksLinks(t *testing.T) {
 	})
 }
 
+func TestVerifyLinks(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		r, status, err := VerifyLinks("1jxf3jfn2k", "")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Len(t, r, 1)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		r, status, err := VerifyLinks("lkjh", "")
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.Empty(t, r)
+	})
+	t.Run("Expired", func(t *testing.T) {
+		r, status, err := VerifyLinks("8jxf3jfn2k", "")
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusGone, status)
+		assert.Empty(t, r)
+	})
+	t.Run("ViewLimit", func(t *testing.T) {
+		r, status, err := VerifyLinks("9jxf3jfn2k", "exhausted")
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusForbidden, status)
+		assert.Empty(t, r)
+	})
+}
+
+func TestLinks_InvalidPassword(t *testing.T) {
+	t.Run("Protected", func(t *testing.T) {
+		r := FindLinks("3jxf3jfn2k", "")
+		assert.True(t, r.HasPassword())
+		assert.True(t, r.InvalidPassword(""))
+		assert.True(t, r.InvalidPassword("wrong"))
+		assert.False(t, r.InvalidPassword("Secret123!"))
+	})
+	t.Run("NotProtected", func(t *testing.T) {
+		r := FindLinks("1jxf3jfn2k", "")
+		assert.False(t, r.HasPassword())
+		assert.False(t, r.InvalidPassword(""))
+	})
+}
+
 func TestLink_String(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		link := NewLink("jhgko", false, false)
@@ -23,6 +23,7 @@ var PasswordFixtures = PasswordMap{
 	"bob":    NewPassword("uqxc08w3d0ej2283", "Bobbob123!"),
 	"friend": NewPassword("uqxqg7i1kperxvu7", "!Friend321"),
 	"fowler": NewPassword("urinotv3d6jedvlm", "PleaseChange$42"),
+	"link":   NewPassword("sqn3xpryd1ob3gtf", "Secret123!"),
 }
 
 // CreatePasswordFixtures inserts known entities into the database for testing.
@@ -102,6 +102,13 @@ func (m *Photo) EstimateLocation(force bool) {
 			Where("taken_src <> '' AND taken_at BETWEEN CAST(? AS DATETIME) AND CAST(? AS DATETIME)", rangeMin, rangeMax).
 			Order(gorm.Expr("ABS(TIMESTAMPDIFF(SECOND, taken_at, ?))", m.TakenAt)).Limit(2).
 			Preload("Place").Find(&mostRecent).Error
+	case Postgres:
+		err = UnscopedDb().
+			Where("photo_lat <> 0 AND photo_lng <> 0").
+			Where("place_src <> '' AND place_src <> ? AND place_id IS NOT NULL AND place_id <> '' AND place_id <> 'zz'", SrcEstimate).
+			Where("taken_src <> '' AND taken_at BETWEEN CAST(? AS TIMESTAMP) AND CAST(? AS TIMESTAMP)", rangeMin, rangeMax).
+			Order(gorm.Expr("ABS(EXTRACT(EPOCH FROM (taken_at - CAST(? AS TIMESTAMP))))", m.TakenAt)).Limit(2).
+			Preload("Place").Find(&mostRecent).Error
 	case SQLite3:
 		err = UnscopedDb().
 			Where("photo_lat <> 0 AND photo_lng <> 0").
@@ -66,6 +66,30 @@ CREATE TABLE `albums_users` (
 /*!40101 SET character_set_client = @saved_cs_client */;
 /*!40101 SET @saved_cs_client     = @@character_set_client */;
 /*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `audit_events` (
+  `id` int(10) unsigned NOT NULL,
+  `event_time` datetime DEFAULT NULL,
+  `event_level` varbinary(32) DEFAULT NULL,
+  `client_ip` varchar(64) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `session_ref` varbinary(16) DEFAULT NULL,
+  `user_uid` varbinary(42) DEFAULT NULL,
+  `user_name` varchar(64) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `action` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `resource_uid` varbinary(42) DEFAULT NULL,
+  `outcome` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `message` varbinary(2048) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_audit_events_event_time` (`event_time`),
+  KEY `idx_audit_events_event_level` (`event_level`),
+  KEY `idx_audit_events_client_ip` (`client_ip`),
+  KEY `idx_audit_events_session_ref` (`session_ref`),
+  KEY `idx_audit_events_user_uid` (`user_uid`),
+  KEY `idx_audit_events_user_name` (`user_name`),
+  KEY `idx_audit_events_resource_uid` (`resource_uid`)
+);
+/*!40101 SET character_set_client = @saved_cs_client */;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
 CREATE TABLE `auth_sessions` (
   `id` varbinary(2048) NOT NULL,
   `client_ip` varchar(64) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
@@ -210,6 +234,20 @@ CREATE TABLE `auth_users_settings` (
 /*!40101 SET character_set_client = @saved_cs_client */;
 /*!40101 SET @saved_cs_client     = @@character_set_client */;
 /*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `auth_users_passcodes` (
+  `user_uid` varbinary(42) NOT NULL,
+  `key_type` varbinary(64) DEFAULT '',
+  `key_secret` varbinary(255) DEFAULT NULL,
+  `recovery_codes` varbinary(1024) DEFAULT NULL,
+  `last_counter` bigint(20) DEFAULT NULL,
+  `verified_at` datetime DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`user_uid`)
+);
+/*!40101 SET character_set_client = @saved_cs_client */;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
 CREATE TABLE `auth_users_shares` (
   `user_uid` varbinary(42) NOT NULL,
   `share_uid` varbinary(42) NOT NULL,
@@ -227,6 +265,25 @@ CREATE TABLE `auth_users_shares` (
 /*!40101 SET character_set_client = @saved_cs_client */;
 /*!40101 SET @saved_cs_client     = @@character_set_client */;
 /*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `auth_users_tokens` (
+  `token_uid` varbinary(42) NOT NULL,
+  `user_uid` varbinary(42) DEFAULT NULL,
+  `token_name` varchar(160) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `token_hash` varbinary(64) DEFAULT NULL,
+  `token_scope` varchar(1024) COLLATE utf8mb4_unicode_ci DEFAULT '',
+  `last_used_at` datetime DEFAULT NULL,
+  `expires_at` datetime DEFAULT NULL,
+  `ref_id` varbinary(16) DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`token_uid`),
+  UNIQUE KEY `uix_auth_users_tokens_token_hash` (`token_hash`),
+  KEY `idx_auth_users_tokens_user_uid` (`user_uid`),
+  KEY `idx_auth_users_tokens_expires_at` (`expires_at`)
+);
+/*!40101 SET character_set_client = @saved_cs_client */;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
 CREATE TABLE `cameras` (
   `id` int(10) unsigned NOT NULL,
   `camera_slug` varbinary(160) DEFAULT NULL,
@@ -435,7 +492,11 @@ CREATE TABLE `files_sync` (
   `file_id` int(10) unsigned DEFAULT NULL,
   `remote_date` datetime DEFAULT NULL,
   `remote_size` bigint(20) DEFAULT NULL,
+  `remote_etag` varbinary(255) DEFAULT NULL,
+  `local_date` datetime DEFAULT NULL,
+  `local_size` bigint(20) DEFAULT NULL,
   `status` varbinary(16) DEFAULT NULL,
+  `conflict` varbinary(16) DEFAULT NULL,
   `error` varbinary(512) DEFAULT NULL,
   `errors` int(11) DEFAULT NULL,
   `created_at` datetime DEFAULT NULL,
@@ -478,6 +539,26 @@ CREATE TABLE `folders` (
 /*!40101 SET character_set_client = @saved_cs_client */;
 /*!40101 SET @saved_cs_client     = @@character_set_client */;
 /*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `jobs` (
+  `job_uid` varbinary(42) NOT NULL,
+  `job_type` varbinary(64) DEFAULT NULL,
+  `job_options` varbinary(2048) DEFAULT NULL,
+  `job_state` varbinary(32) DEFAULT NULL,
+  `job_progress` bigint(20) DEFAULT NULL,
+  `job_step` varchar(255) DEFAULT NULL,
+  `job_error` varchar(512) DEFAULT NULL,
+  `created_by` varbinary(42) DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  `started_at` datetime DEFAULT NULL,
+  `finished_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`job_uid`),
+  KEY `idx_jobs_job_type` (`job_type`),
+  KEY `idx_jobs_job_state` (`job_state`)
+);
+/*!40101 SET character_set_client = @saved_cs_client */;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
 CREATE TABLE `keywords` (
   `id` int(10) unsigned NOT NULL,
   `keyword` varchar(64) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
@@ -777,6 +858,12 @@ CREATE TABLE `services` (
   `acc_key` varbinary(255) DEFAULT NULL,
   `acc_user` varbinary(255) DEFAULT NULL,
   `acc_pass` varbinary(255) DEFAULT NULL,
+  `acc_bucket` varbinary(255) DEFAULT NULL,
+  `acc_prefix` varbinary(1024) DEFAULT NULL,
+  `acc_region` varbinary(64) DEFAULT NULL,
+  `acc_private_key` varbinary(4096) DEFAULT NULL,
+  `acc_host_key` varbinary(255) DEFAULT NULL,
+  `acc_bandwidth` int(11) DEFAULT NULL,
   `acc_timeout` varbinary(16) DEFAULT NULL,
   `acc_error` varbinary(512) DEFAULT NULL,
   `acc_errors` int(11) DEFAULT NULL,
@@ -794,6 +881,7 @@ CREATE TABLE `services` (
   `sync_download` tinyint(1) DEFAULT NULL,
   `sync_filenames` tinyint(1) DEFAULT NULL,
   `sync_raw` tinyint(1) DEFAULT NULL,
+  `sync_conflict` varbinary(16) DEFAULT NULL,
   `created_at` datetime DEFAULT NULL,
   `updated_at` datetime DEFAULT NULL,
   `deleted_at` datetime DEFAULT NULL,
@@ -834,6 +922,27 @@ CREATE TABLE `subjects` (
 /*!40101 SET character_set_client = @saved_cs_client */;
 /*!40101 SET @saved_cs_client     = @@character_set_client */;
 /*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `trash_files` (
+  `id` int(10) unsigned NOT NULL,
+  `photo_uid` varbinary(42) DEFAULT NULL,
+  `file_uid` varbinary(42) DEFAULT NULL,
+  `file_root` varbinary(16) DEFAULT '/',
+  `file_name` varbinary(1024) DEFAULT NULL,
+  `file_size` bigint(20) DEFAULT NULL,
+  `trash_name` varbinary(1024) DEFAULT NULL,
+  `photo_deleted` tinyint(1) DEFAULT NULL,
+  `photo_archived` tinyint(1) DEFAULT NULL,
+  `deleted_by` varbinary(42) DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `expires_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_trash_files_photo_uid` (`photo_uid`),
+  KEY `idx_trash_files_file_uid` (`file_uid`),
+  KEY `idx_trash_files_expires_at` (`expires_at`)
+);
+/*!40101 SET character_set_client = @saved_cs_client */;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
 CREATE TABLE `users` (
   `id` int(11) NOT NULL,
   `address_id` int(11) DEFAULT 1,
@@ -897,6 +1006,38 @@ CREATE TABLE `users` (
   KEY `idx_users_deleted_at` (`deleted_at`)
 );
 /*!40101 SET character_set_client = @saved_cs_client */;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `webhooks` (
+  `webhook_uid` varbinary(42) NOT NULL,
+  `webhook_url` varbinary(512) DEFAULT NULL,
+  `webhook_topics` varbinary(1024) DEFAULT NULL,
+  `webhook_secret` varbinary(255) DEFAULT NULL,
+  `webhook_enabled` tinyint(1) DEFAULT NULL,
+  `created_by` varbinary(42) DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`webhook_uid`)
+);
+/*!40101 SET character_set_client = @saved_cs_client */;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `webhooks_deliveries` (
+  `id` int(10) unsigned NOT NULL,
+  `webhook_uid` varbinary(42) DEFAULT NULL,
+  `delivery_id` varbinary(42) DEFAULT NULL,
+  `event_topic` varbinary(128) DEFAULT NULL,
+  `attempt` int(11) DEFAULT NULL,
+  `status_code` int(11) DEFAULT NULL,
+  `error` varbinary(512) DEFAULT NULL,
+  `duration` bigint(20) DEFAULT NULL,
+  `delivered_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_webhooks_deliveries_webhook_uid` (`webhook_uid`),
+  KEY `idx_webhooks_deliveries_delivery_id` (`delivery_id`),
+  KEY `idx_webhooks_deliveries_delivered_at` (`delivered_at`)
+);
+/*!40101 SET character_set_client = @saved_cs_client */;
 /*!40103 SET TIME_ZONE=@OLD_TIME_ZONE */;
 
 /*!40101 SET SQL_MODE=@OLD_SQL_MODE */;
@@ -13,6 +13,10 @@ import (
 	"github.com/photoprism/photoprism/internal/remote/webdav"
 	"github.com/photoprism/photoprism/pkg/fs"
 	"github.com/photoprism/photoprism/pkg/txt"
+
+	// Register remote storage backends.
+	_ "github.com/photoprism/photoprism/internal/remote/s3"
+	_ "github.com/photoprism/photoprism/internal/remote/sftp"
 )
 
 const (
@@ -22,15 +26,27 @@ const (
 	SyncStatusSynced   = "synced"
 )
 
+// Sync conflict policies, applied if a file has been changed both locally and remotely.
+const (
+	SyncConflictKeep   = "keep"   // Keep both versions, the other version is stored with a conflict suffix.
+	SyncConflictLocal  = "local"  // Keep the local version.
+	SyncConflictRemote = "remote" // Replace the local version with the remote version.
+)
+
 type Services []Service
 
 // Service represents a remote service, e.g. for uploading, downloading or syncing media files.
 //
 // Field Descriptions:
+// - AccBucket, AccPrefix, and AccRegion configure the bucket, key prefix, and region of S3-compatible services.
+// - AccPrivateKey holds an optional SSH private key for SFTP services, it is never returned by the API.
+// - AccHostKey holds the server host key fingerprint of SFTP services, as confirmed by the user.
+// - AccBandwidth limits the transfer rate in KiB/s, 0 means unlimited.
 // - AccTimeout configures the timeout for requests, options: "", high, medium, low, none.
 // - AccErrors holds the number of connection errors since the last reset.
 // - AccShare enables manual upload, see SharePath, ShareSize, and ShareExpires.
 // - AccSync enables automatic file synchronization, see SyncDownload and SyncUpload.
+// - SyncConflict configures how conflicting changes are resolved, options: "", keep, local, remote.
 // - RetryLimit specifies the number of retry attempts, a negative value disables the limit.
 type Service struct {
 	ID            uint   `gorm:"primary_key"`
@@ -41,6 +57,12 @@ type Service struct {
 	AccKey        string `gorm:"type:VARBINARY(255);"`
 	AccUser       string `gorm:"type:VARBINARY(255);"`
 	AccPass       string `gorm:"type:VARBINARY(255);"`
+	AccBucket     string `gorm:"type:VARBINARY(255);"`
+	AccPrefix     string `gorm:"type:VARBINARY(1024);"`
+	AccRegion     string `gorm:"type:VARBINARY(64);"`
+	AccPrivateKey string `gorm:"type:VARBINARY(4096);" json:"-"`
+	AccHostKey    string `gorm:"type:VARBINARY(255);"`
+	AccBandwidth  int
 	AccTimeout    string `gorm:"type:VARBINARY(16);"`
 	AccError      string `gorm:"type:VARBINARY(512);"`
 	AccErrors     int
@@ -58,6 +80,7 @@ type Service struct {
 	SyncDownload  bool
 	SyncFilenames bool
 	SyncRaw       bool
+	SyncConflict  string     `gorm:"type:VARBINARY(16);"`
 	CreatedAt     time.Time  `deepcopier:"skip"`
 	UpdatedAt     time.Time  `deepcopier:"skip"`
 	DeletedAt     *time.Time `deepcopier:"skip" sql:"index"`
@@ -139,8 +162,8 @@ func (m *Service) SaveForm(form form.Service) error {
 		return err
 	}
 
-	// TODO: Support for other remote services in addition to WebDAV.
-	if m.AccType != remote.ServiceWebDAV {
+	// Disable sharing and syncing for services without storage backend.
+	if !remote.Supported(m.AccType) {
 		m.AccShare = false // Disable manual upload.
 		m.AccSync = false  // Disable background sync.
 	}
@@ -150,6 +173,9 @@ func (m *Service) SaveForm(form form.Service) error {
 		m.SyncUpload = false
 	}
 
+	// Keep both versions if the conflict policy is unknown.
+	m.SyncConflict = m.ConflictPolicy()
+
 	// Set default manual upload folder if empty.
 	if m.SharePath == "" {
 		m.SharePath = "/"
@@ -160,6 +186,11 @@ func (m *Service) SaveForm(form form.Service) error {
 		m.SyncPath = "/"
 	}
 
+	// Bandwidth must not be negative.
+	if m.AccBandwidth < 0 {
+		m.AccBandwidth = 0 // Unlimited.
+	}
+
 	// Number of remote request retry attempts.
 	if m.RetryLimit < -1 {
 		m.RetryLimit = -1 // Disabled.
@@ -192,9 +223,13 @@ func (m *Service) Delete() error {
 
 // Directories returns a list of directories or albums in an account.
 func (m *Service) Directories() (result fs.FileInfos, err error) {
-	if m.AccType == remote.ServiceWebDAV {
-		client := webdav.New(m.AccURL, m.AccUser, m.AccPass, webdav.Timeout(m.AccTimeout))
-		result, err = client.Directories("/", true, 0)
+	if remote.Supported(m.AccType) {
+		var s remote.Storage
+
+		if s, err = m.Storage(); err == nil {
+			result, err = remote.Directories(s, "/", true, m.Config().RequestTimeout())
+			remote.Close(s)
+		}
 	}
 
 	// Sort directory list.
@@ -208,6 +243,38 @@ func (m *Service) Directories() (result fs.FileInfos, err error) {
 	return result, err
 }
 
+// Config returns the remote storage settings of the service.
+func (m *Service) Config() remote.Config {
+	return remote.Config{
+		URL:        m.AccURL,
+		User:       m.AccUser,
+		Pass:       m.AccPass,
+		Key:        m.AccKey,
+		Bucket:     m.AccBucket,
+		Prefix:     m.AccPrefix,
+		Region:     m.AccRegion,
+		PrivateKey: m.AccPrivateKey,
+		HostKey:    m.AccHostKey,
+		Bandwidth:  m.AccBandwidth,
+		Timeout:    m.AccTimeout,
+	}
+}
+
+// Storage returns the remote storage backend registered for the service type.
+func (m *Service) Storage() (remote.Storage, error) {
+	return remote.New(m.AccType, m.Config())
+}
+
+// ConflictPolicy returns the sync conflict policy, see SyncConflictKeep.
+func (m *Service) ConflictPolicy() string {
+	switch m.SyncConflict {
+	case SyncConflictLocal, SyncConflictRemote:
+		return m.SyncConflict
+	default:
+		return SyncConflictKeep
+	}
+}
+
 // Updates multiple columns in the database.
 func (m *Service) Updates(values interface{}) error {
 	return UnscopedDb().Model(m).UpdateColumns(values).Error
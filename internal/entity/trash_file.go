@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/rnd"
+)
+
+// TrashFiles represents a list of files in the trash.
+type TrashFiles []TrashFile
+
+// TrashFile represents a deleted file that has been moved to the trash, so that it can be restored
+// until the retention period has expired. If a photo without files was deleted, FileUID is empty.
+type TrashFile struct {
+	ID            uint      `gorm:"primary_key" json:"ID" yaml:"-"`
+	PhotoUID      string    `gorm:"type:VARBINARY(42);index;" json:"PhotoUID" yaml:"PhotoUID"`
+	FileUID       string    `gorm:"type:VARBINARY(42);index;" json:"FileUID" yaml:"FileUID,omitempty"`
+	FileRoot      string    `gorm:"type:VARBINARY(16);default:'/';" json:"FileRoot" yaml:"FileRoot,omitempty"`
+	FileName      string    `gorm:"type:VARBINARY(1024);" json:"FileName" yaml:"FileName,omitempty"`
+	FileSize      int64     `json:"FileSize" yaml:"FileSize,omitempty"`
+	TrashName     string    `gorm:"type:VARBINARY(1024);" json:"-" yaml:"TrashName,omitempty"`
+	PhotoDeleted  bool      `json:"PhotoDeleted" yaml:"PhotoDeleted,omitempty"`
+	PhotoArchived bool      `json:"PhotoArchived" yaml:"PhotoArchived,omitempty"`
+	DeletedBy     string    `gorm:"type:VARBINARY(42);" json:"DeletedBy,omitempty" yaml:"DeletedBy,omitempty"`
+	CreatedAt     time.Time `json:"CreatedAt" yaml:"CreatedAt"`
+	ExpiresAt     time.Time `gorm:"index;" json:"ExpiresAt" yaml:"ExpiresAt"`
+}
+
+// TableName returns the entity table name.
+func (TrashFile) TableName() string {
+	return "trash_files"
+}
+
+// Create inserts a new row to the database.
+func (m *TrashFile) Create() error {
+	if rnd.InvalidUID(m.PhotoUID, PhotoUID) {
+		return fmt.Errorf("invalid photo uid %s", clean.Log(m.PhotoUID))
+	}
+
+	return Db().Create(m).Error
+}
+
+// Delete permanently removes the entry from the trash.
+func (m *TrashFile) Delete() error {
+	if m.ID < 1 {
+		return fmt.Errorf("invalid trash file id %d", m.ID)
+	}
+
+	return UnscopedDb().Delete(m).Error
+}
+
+// Expired checks if the retention period has expired.
+func (m *TrashFile) Expired(now time.Time) bool {
+	return !m.ExpiresAt.After(now)
+}
+
+// FindTrashFiles returns the trashed files of a photo.
+func FindTrashFiles(photoUid string) (result TrashFiles, err error) {
+	if rnd.InvalidUID(photoUid, PhotoUID) {
+		return result, fmt.Errorf("invalid photo uid %s", clean.Log(photoUid))
+	}
+
+	err = UnscopedDb().Where("photo_uid = ?", photoUid).Order("id").Find(&result).Error
+
+	return result, err
+}
+
+// ListTrashFiles returns the files in the trash, most recently deleted first.
+func ListTrashFiles(limit, offset int) (result TrashFiles, err error) {
+	stmt := UnscopedDb().Order("created_at DESC, id DESC")
+
+	if limit > 0 {
+		stmt = stmt.Limit(limit).Offset(offset)
+	}
+
+	err = stmt.Find(&result).Error
+
+	return result, err
+}
+
+// ExpiredTrashFiles returns the files in the trash whose retention period has expired.
+func ExpiredTrashFiles(now time.Time) (result TrashFiles, err error) {
+	err = UnscopedDb().Where("expires_at <= ?", now).Order("photo_uid, id").Find(&result).Error
+
+	return result, err
+}
+
+// Expired returns the files whose retention period has expired.
+func (m TrashFiles) Expired(now time.Time) TrashFiles {
+	result := make(TrashFiles, 0, len(m))
+
+	for _, f := range m {
+		if f.Expired(now) {
+			result = append(result, f)
+		}
+	}
+
+	return result
+}
+
+// PhotoUIDs returns the unique photo UIDs of the trashed files.
+func (m TrashFiles) PhotoUIDs() []string {
+	result := make([]string, 0, len(m))
+	found := make(map[string]bool, len(m))
+
+	for _, f := range m {
+		if !found[f.PhotoUID] {
+			found[f.PhotoUID] = true
+			result = append(result, f.PhotoUID)
+		}
+	}
+
+	return result
+}
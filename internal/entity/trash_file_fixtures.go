@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"time"
+)
+
+type TrashFileMap map[string]TrashFile
+
+// Get returns a fixture for use in tests.
+func (m TrashFileMap) Get(name string) TrashFile {
+	if result, ok := m[name]; ok {
+		return result
+	}
+
+	return TrashFile{}
+}
+
+// Pointer returns a fixture pointer for use in tests.
+func (m TrashFileMap) Pointer(name string) *TrashFile {
+	if result, ok := m[name]; ok {
+		return &result
+	}
+
+	return &TrashFile{}
+}
+
+var trashDeleted = time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+// TrashFileFixtures specifies fixtures for use in tests.
+var TrashFileFixtures = TrashFileMap{
+	"expired": {
+		ID:           1000000,
+		PhotoUID:     "prhn1bzi0fw2x7tr",
+		FileUID:      "frhn1bzi0fw2x7tr",
+		FileRoot:     RootOriginals,
+		FileName:     "2022/10/expired.jpg",
+		FileSize:     1024,
+		TrashName:    "prhn1bzi0fw2x7tr/originals/2022/10/expired.jpg",
+		PhotoDeleted: true,
+		DeletedBy:    "uqxetse3cy5eo9z2",
+		CreatedAt:    trashDeleted,
+		ExpiresAt:    trashDeleted.AddDate(0, 0, 30),
+	},
+	"pending": {
+		ID:            1000001,
+		PhotoUID:      "prhn1bzi0fw2x7ts",
+		FileUID:       "frhn1bzi0fw2x7ts",
+		FileRoot:      RootOriginals,
+		FileName:      "2022/10/pending.jpg",
+		FileSize:      2048,
+		TrashName:     "prhn1bzi0fw2x7ts/originals/2022/10/pending.jpg",
+		PhotoDeleted:  true,
+		PhotoArchived: true,
+		DeletedBy:     "uqxetse3cy5eo9z2",
+		CreatedAt:     trashDeleted.Add(time.Hour),
+		ExpiresAt:     time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+	},
+}
+
+// CreateTrashFileFixtures creates the fixtures specified above.
+func CreateTrashFileFixtures() {
+	for _, entity := range TrashFileFixtures {
+		Db().Create(&entity)
+	}
+}
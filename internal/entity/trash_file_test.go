@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrashFile_Create(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		m := &TrashFile{
+			PhotoUID:  "prhn1bzi0fw2x7tt",
+			FileUID:   "frhn1bzi0fw2x7tt",
+			FileRoot:  RootOriginals,
+			FileName:  "2022/10/created.jpg",
+			TrashName: "prhn1bzi0fw2x7tt/originals/2022/10/created.jpg",
+			CreatedAt: TimeStamp(),
+			ExpiresAt: TimeStamp().AddDate(0, 0, 30),
+		}
+
+		assert.NoError(t, m.Create())
+		assert.NotEmpty(t, m.ID)
+
+		found, err := FindTrashFiles("prhn1bzi0fw2x7tt")
+
+		assert.NoError(t, err)
+		assert.Len(t, found, 1)
+
+		assert.NoError(t, m.Delete())
+
+		found, err = FindTrashFiles("prhn1bzi0fw2x7tt")
+
+		assert.NoError(t, err)
+		assert.Len(t, found, 0)
+	})
+	t.Run("InvalidPhotoUID", func(t *testing.T) {
+		m := &TrashFile{PhotoUID: "foo"}
+		assert.Error(t, m.Create())
+	})
+}
+
+func TestTrashFile_Expired(t *testing.T) {
+	now := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.True(t, TrashFileFixtures.Pointer("expired").Expired(now))
+	assert.False(t, TrashFileFixtures.Pointer("pending").Expired(now))
+}
+
+func TestTrashFiles_Expired(t *testing.T) {
+	now := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	files := TrashFiles{*TrashFileFixtures.Pointer("expired"), *TrashFileFixtures.Pointer("pending")}
+
+	result := files.Expired(now)
+
+	assert.Len(t, result, 1)
+	assert.Equal(t, TrashFileFixtures.Pointer("expired").PhotoUID, result[0].PhotoUID)
+}
+
+func TestFindTrashFiles(t *testing.T) {
+	t.Run("Found", func(t *testing.T) {
+		result, err := FindTrashFiles("prhn1bzi0fw2x7ts")
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, "2022/10/pending.jpg", result[0].FileName)
+	})
+	t.Run("InvalidUID", func(t *testing.T) {
+		_, err := FindTrashFiles("foo")
+		assert.Error(t, err)
+	})
+}
+
+func TestListTrashFiles(t *testing.T) {
+	result, err := ListTrashFiles(10, 0)
+
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, len(result), 2)
+}
+
+func TestExpiredTrashFiles(t *testing.T) {
+	result, err := ExpiredTrashFiles(time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Contains(t, result.PhotoUIDs(), "prhn1bzi0fw2x7tr")
+	assert.NotContains(t, result.PhotoUIDs(), "prhn1bzi0fw2x7ts")
+}
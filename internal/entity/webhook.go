@@ -0,0 +1,222 @@
+package entity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/rnd"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// WebhookUID is the unique ID prefix of webhooks.
+const WebhookUID = byte('w')
+
+// Webhooks represents a list of webhooks.
+type Webhooks []Webhook
+
+// Webhook represents an HTTP endpoint that receives library events matching its topics.
+type Webhook struct {
+	WebhookUID     string    `gorm:"type:VARBINARY(42);primary_key;auto_increment:false;" json:"UID" yaml:"UID"`
+	WebhookURL     string    `gorm:"type:VARBINARY(512);" json:"URL" yaml:"URL"`
+	WebhookTopics  string    `gorm:"type:VARBINARY(1024);" json:"Topics" yaml:"Topics"`
+	WebhookSecret  string    `gorm:"type:VARBINARY(255);" json:"-" yaml:"-"`
+	WebhookEnabled bool      `json:"Enabled" yaml:"Enabled"`
+	CreatedBy      string    `gorm:"type:VARBINARY(42);" json:"CreatedBy,omitempty" yaml:"CreatedBy,omitempty"`
+	CreatedAt      time.Time `json:"CreatedAt" yaml:"-"`
+	UpdatedAt      time.Time `json:"UpdatedAt" yaml:"-"`
+}
+
+// TableName returns the entity table name.
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// NewWebhook creates a new webhook and returns it along with the secret used to sign payloads.
+func NewWebhook(endpoint, topics, createdBy string) (m *Webhook, secret string, err error) {
+	m = &Webhook{
+		WebhookUID:     rnd.GenerateUID(WebhookUID),
+		WebhookEnabled: true,
+		CreatedBy:      createdBy,
+		CreatedAt:      TimeStamp(),
+		UpdatedAt:      TimeStamp(),
+	}
+
+	if err = m.SetURL(endpoint); err != nil {
+		return nil, "", err
+	} else if err = m.SetTopics(topics); err != nil {
+		return nil, "", err
+	}
+
+	secret = rnd.SessionID()
+	m.WebhookSecret = secret
+
+	return m, secret, nil
+}
+
+// FindWebhook returns the webhook with the specified uid or nil if it was not found.
+func FindWebhook(uid string) *Webhook {
+	if rnd.InvalidUID(uid, WebhookUID) {
+		return nil
+	}
+
+	m := &Webhook{}
+
+	// Find matching record.
+	if UnscopedDb().First(m, "webhook_uid = ?", uid).RecordNotFound() {
+		return nil
+	}
+
+	return m
+}
+
+// FindWebhooks returns all webhooks, or only the enabled ones.
+func FindWebhooks(enabledOnly bool) Webhooks {
+	found := Webhooks{}
+
+	stmt := UnscopedDb().Order("created_at")
+
+	if enabledOnly {
+		stmt = stmt.Where("webhook_enabled = ?", true)
+	}
+
+	if err := stmt.Find(&found).Error; err != nil {
+		log.Errorf("webhook: %s (find)", err)
+		return nil
+	}
+
+	return found
+}
+
+// Create inserts a new record into the database.
+func (m *Webhook) Create() error {
+	return Db().Create(m).Error
+}
+
+// Save updates the record in the database or inserts a new record if it does not already exist.
+func (m *Webhook) Save() error {
+	m.UpdatedAt = TimeStamp()
+
+	return Db().Save(m).Error
+}
+
+// Delete permanently deletes the webhook and its delivery log.
+func (m *Webhook) Delete() error {
+	if m.WebhookUID == "" {
+		return fmt.Errorf("webhook uid is missing")
+	}
+
+	if err := UnscopedDb().Delete(WebhookDelivery{}, "webhook_uid = ?", m.WebhookUID).Error; err != nil {
+		return err
+	}
+
+	return UnscopedDb().Delete(m, "webhook_uid = ?", m.WebhookUID).Error
+}
+
+// SaveForm updates the webhook based on the form values.
+func (m *Webhook) SaveForm(f form.Webhook) error {
+	if err := m.SetURL(f.URL); err != nil {
+		return err
+	} else if err = m.SetTopics(f.Topics); err != nil {
+		return err
+	}
+
+	m.WebhookEnabled = f.Enabled
+
+	return m.Save()
+}
+
+// SetURL validates and sets the endpoint URL.
+func (m *Webhook) SetURL(endpoint string) error {
+	endpoint = strings.TrimSpace(endpoint)
+
+	if u, err := url.Parse(endpoint); err != nil || u.Host == "" {
+		return fmt.Errorf("invalid webhook url")
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("webhook url must start with http:// or https://")
+	} else if len(endpoint) > txt.ClipURL {
+		return fmt.Errorf("webhook url is too long")
+	}
+
+	m.WebhookURL = endpoint
+
+	return nil
+}
+
+// SetTopics validates and sets the event topics, e.g. "photos.created albums.*".
+func (m *Webhook) SetTopics(topics string) error {
+	list := strings.FieldsFunc(strings.ToLower(topics), func(r rune) bool {
+		return r == ' ' || r == ',' || r == ';'
+	})
+
+	if len(list) == 0 {
+		return fmt.Errorf("webhook topics are missing")
+	}
+
+	for _, t := range list {
+		if t != "*" && !strings.Contains(t, event.TopicSep) {
+			return fmt.Errorf("invalid webhook topic %s", clean.Log(t))
+		}
+	}
+
+	m.WebhookTopics = txt.Clip(strings.Join(list, " "), txt.ClipShortText)
+
+	return nil
+}
+
+// Topics returns the event topic patterns the webhook is subscribed to.
+func (m *Webhook) Topics() []string {
+	return strings.Fields(m.WebhookTopics)
+}
+
+// Matches checks if the webhook is subscribed to the event topic, e.g. "photos.created".
+// Patterns may contain "*" as a wildcard for a single topic segment, or consist of "*" only to match all topics.
+func (m *Webhook) Matches(topic string) bool {
+	segments := strings.Split(topic, event.TopicSep)
+
+	for _, pattern := range m.Topics() {
+		if pattern == "*" {
+			return true
+		}
+
+		p := strings.Split(pattern, event.TopicSep)
+
+		if len(p) != len(segments) {
+			continue
+		}
+
+		match := true
+
+		for i := range p {
+			if p[i] != "*" && p[i] != segments[i] {
+				match = false
+				break
+			}
+		}
+
+		if match {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Sign returns the hex encoded HMAC-SHA256 signature of the payload.
+func (m *Webhook) Sign(payload []byte) string {
+	h := hmac.New(sha256.New, []byte(m.WebhookSecret))
+	h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// String returns the webhook uid for use in logs.
+func (m *Webhook) String() string {
+	return m.WebhookUID
+}
@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// WebhookDeliveries represents a list of webhook deliveries.
+type WebhookDeliveries []WebhookDelivery
+
+// WebhookDelivery represents a single attempt to deliver an event to a webhook.
+type WebhookDelivery struct {
+	ID          uint      `gorm:"primary_key" json:"ID" yaml:"ID"`
+	WebhookUID  string    `gorm:"type:VARBINARY(42);index;" json:"WebhookUID" yaml:"WebhookUID"`
+	DeliveryID  string    `gorm:"type:VARBINARY(42);index;" json:"DeliveryID" yaml:"DeliveryID"`
+	EventTopic  string    `gorm:"type:VARBINARY(128);" json:"Topic" yaml:"Topic"`
+	Attempt     int       `json:"Attempt" yaml:"Attempt"`
+	StatusCode  int       `json:"StatusCode" yaml:"StatusCode"`
+	Error       string    `gorm:"type:VARBINARY(512);" json:"Error,omitempty" yaml:"Error,omitempty"`
+	Duration    int64     `json:"Duration" yaml:"Duration"`
+	DeliveredAt time.Time `sql:"index" json:"DeliveredAt" yaml:"DeliveredAt"`
+}
+
+// TableName returns the entity table name.
+func (WebhookDelivery) TableName() string {
+	return "webhooks_deliveries"
+}
+
+// NewWebhookDelivery creates a new delivery log entry.
+func NewWebhookDelivery(webhookUid, deliveryId, topic string, attempt, status int, err error, duration time.Duration) *WebhookDelivery {
+	m := &WebhookDelivery{
+		WebhookUID:  webhookUid,
+		DeliveryID:  deliveryId,
+		EventTopic:  txt.Clip(topic, 128),
+		Attempt:     attempt,
+		StatusCode:  status,
+		Duration:    duration.Milliseconds(),
+		DeliveredAt: TimeStamp(),
+	}
+
+	if err != nil {
+		m.Error = txt.Clip(err.Error(), txt.ClipURL)
+	}
+
+	return m
+}
+
+// FindWebhookDeliveries returns the delivery log of a webhook, newest first.
+func FindWebhookDeliveries(webhookUid string, limit, offset int) (result WebhookDeliveries, err error) {
+	err = UnscopedDb().
+		Where("webhook_uid = ?", webhookUid).
+		Order("delivered_at DESC, id DESC").
+		Limit(limit).Offset(offset).
+		Find(&result).Error
+
+	return result, err
+}
+
+// Create inserts a new record into the database.
+func (m *WebhookDelivery) Create() error {
+	return UnscopedDb().Create(m).Error
+}
+
+// Success checks if the endpoint accepted the event.
+func (m *WebhookDelivery) Success() bool {
+	return m.Error == "" && m.StatusCode >= 200 && m.StatusCode < 300
+}
@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"time"
+)
+
+type WebhookMap map[string]Webhook
+
+// Get returns a fixture for use in tests.
+func (m WebhookMap) Get(name string) Webhook {
+	if result, ok := m[name]; ok {
+		return result
+	}
+
+	return Webhook{}
+}
+
+// Pointer returns a fixture pointer for use in tests.
+func (m WebhookMap) Pointer(name string) *Webhook {
+	if result, ok := m[name]; ok {
+		return &result
+	}
+
+	return &Webhook{}
+}
+
+// WebhookFixtures specifies fixtures for use in tests.
+var WebhookFixtures = WebhookMap{
+	"bot": {
+		WebhookUID:     "wrhn0cz3gzecxmxy",
+		WebhookURL:     "https://bot.example.com/photoprism",
+		WebhookTopics:  "photos.created albums.*",
+		WebhookSecret:  "9e5ab0fd1d0f27e3b1b5a5b2f6fd8e71c3c1e0f5b2a2d9c8",
+		WebhookEnabled: true,
+		CreatedBy:      "uqxetse3cy5eo9z2",
+		CreatedAt:      time.Date(2022, 10, 5, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:      time.Date(2022, 10, 5, 12, 0, 0, 0, time.UTC),
+	},
+	"disabled": {
+		WebhookUID:     "wrhn0cz3gzecxmxz",
+		WebhookURL:     "http://home.example.com:8123/api/webhook/photoprism",
+		WebhookTopics:  "*",
+		WebhookSecret:  "0f27e3b1b5a5b2f6fd8e71c3c1e0f5b2a2d9c89e5ab0fd1d",
+		WebhookEnabled: false,
+		CreatedBy:      "uqxetse3cy5eo9z2",
+		CreatedAt:      time.Date(2022, 10, 6, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:      time.Date(2022, 10, 6, 12, 0, 0, 0, time.UTC),
+	},
+}
+
+// WebhookDeliveryFixtures specifies fixtures for use in tests.
+var WebhookDeliveryFixtures = []WebhookDelivery{
+	{
+		ID:          1000001,
+		WebhookUID:  "wrhn0cz3gzecxmxy",
+		DeliveryID:  "drhn0d0sb1l4dvfb",
+		EventTopic:  "photos.created",
+		Attempt:     1,
+		StatusCode:  503,
+		Duration:    120,
+		DeliveredAt: time.Date(2022, 10, 7, 12, 0, 0, 0, time.UTC),
+	},
+	{
+		ID:          1000002,
+		WebhookUID:  "wrhn0cz3gzecxmxy",
+		DeliveryID:  "drhn0d0sb1l4dvfb",
+		EventTopic:  "photos.created",
+		Attempt:     2,
+		StatusCode:  200,
+		Duration:    80,
+		DeliveredAt: time.Date(2022, 10, 7, 12, 0, 2, 0, time.UTC),
+	},
+}
+
+// CreateWebhookFixtures creates the fixtures specified above.
+func CreateWebhookFixtures() {
+	for _, entity := range WebhookFixtures {
+		Db().Create(&entity)
+	}
+
+	for _, entity := range WebhookDeliveryFixtures {
+		Db().Create(&entity)
+	}
+}
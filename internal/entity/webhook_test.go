@@ -0,0 +1,112 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/pkg/rnd"
+)
+
+func TestNewWebhook(t *testing.T) {
+	t.Run("Ok", func(t *testing.T) {
+		m, secret, err := NewWebhook(" https://chat.example.com/hook ", "photos.created, albums.*", Admin.UID())
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, rnd.IsUID(m.WebhookUID, WebhookUID))
+		assert.Equal(t, "https://chat.example.com/hook", m.WebhookURL)
+		assert.Equal(t, "photos.created albums.*", m.WebhookTopics)
+		assert.Equal(t, secret, m.WebhookSecret)
+		assert.True(t, rnd.IsSessionID(secret))
+		assert.True(t, m.WebhookEnabled)
+	})
+	t.Run("InvalidURL", func(t *testing.T) {
+		_, _, err := NewWebhook("ftp://chat.example.com/hook", "photos.created", "")
+		assert.Error(t, err)
+
+		_, _, err = NewWebhook("chat.example.com", "photos.created", "")
+		assert.Error(t, err)
+	})
+	t.Run("InvalidTopics", func(t *testing.T) {
+		_, _, err := NewWebhook("https://chat.example.com/hook", "", "")
+		assert.Error(t, err)
+
+		_, _, err = NewWebhook("https://chat.example.com/hook", "photos", "")
+		assert.Error(t, err)
+	})
+}
+
+func TestWebhook_Matches(t *testing.T) {
+	m := WebhookFixtures.Get("bot")
+
+	assert.True(t, m.Matches("photos.created"))
+	assert.True(t, m.Matches("albums.updated"))
+	assert.False(t, m.Matches("photos.updated"))
+	assert.False(t, m.Matches("labels.created"))
+	assert.False(t, m.Matches("albums.updated.extra"))
+	assert.True(t, WebhookFixtures.Pointer("disabled").Matches("index.folder"))
+}
+
+func TestWebhook_Sign(t *testing.T) {
+	m := WebhookFixtures.Get("bot")
+
+	s := m.Sign([]byte(`{"event":"photos.created"}`))
+
+	assert.Len(t, s, 64)
+	assert.Equal(t, s, m.Sign([]byte(`{"event":"photos.created"}`)))
+	assert.NotEqual(t, s, m.Sign([]byte(`{"event":"photos.updated"}`)))
+}
+
+func TestFindWebhooks(t *testing.T) {
+	assert.GreaterOrEqual(t, len(FindWebhooks(false)), 2)
+
+	for _, m := range FindWebhooks(true) {
+		assert.True(t, m.WebhookEnabled)
+	}
+}
+
+func TestWebhook_SaveForm(t *testing.T) {
+	m, _, err := NewWebhook("https://chat.example.com/hook", "photos.*", "")
+
+	if err != nil {
+		t.Fatal(err)
+	} else if err = m.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = m.SaveForm(form.Webhook{URL: "https://chat.example.com/v2", Topics: "labels.*", Enabled: false}); err != nil {
+		t.Fatal(err)
+	}
+
+	found := FindWebhook(m.WebhookUID)
+
+	if assert.NotNil(t, found) {
+		assert.Equal(t, "https://chat.example.com/v2", found.WebhookURL)
+		assert.Equal(t, "labels.*", found.WebhookTopics)
+		assert.False(t, found.WebhookEnabled)
+	}
+
+	if err = m.Delete(); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Nil(t, FindWebhook(m.WebhookUID))
+}
+
+func TestFindWebhookDeliveries(t *testing.T) {
+	result, err := FindWebhookDeliveries("wrhn0cz3gzecxmxy", 10, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.GreaterOrEqual(t, len(result), 2) {
+		assert.Equal(t, 2, result[0].Attempt)
+		assert.True(t, result[0].Success())
+		assert.False(t, result[1].Success())
+	}
+}
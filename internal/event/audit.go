@@ -2,9 +2,12 @@ package event
 
 import (
 	"fmt"
+	"net"
 	"strings"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/photoprism/photoprism/pkg/rnd"
 )
 
 // AuditLog optionally logs security events.
@@ -34,14 +37,72 @@ func Audit(level logrus.Level, ev []string, args ...interface{}) {
 
 	// Publish event if log level is info or higher.
 	if level <= logrus.InfoLevel {
-		Publish(
-			"audit."+level.String(),
-			Data{
-				"time":    TimeStamp(),
-				"level":   level.String(),
-				"message": message,
-			},
-		)
+		data := AuditData(ev, args...)
+
+		data["time"] = TimeStamp()
+		data["level"] = level.String()
+		data["message"] = message
+
+		Publish("audit."+level.String(), data)
+	}
+}
+
+// AuditData returns the client IP, session, user, action, resource UID, and outcome of an audit event,
+// e.g. []string{"192.0.2.1", "session %s", "access photo %s as %s", "denied"}.
+func AuditData(ev []string, args ...interface{}) Data {
+	var ip, session, user, uid string
+
+	parts := make([]string, 0, len(ev))
+	n := 0
+
+	for i, s := range ev {
+		end := n + strings.Count(s, "%") - 2*strings.Count(s, "%%")
+
+		if end > len(args) {
+			end = len(args)
+		}
+
+		values := args[n:end]
+		n = end
+
+		if i == 0 && len(values) == 0 && (s == "" || net.ParseIP(s) != nil) {
+			ip = s
+			continue
+		} else if len(values) == 1 && s == "session %s" {
+			session = fmt.Sprint(values[0])
+			continue
+		} else if len(values) == 1 && s == "user %s" {
+			user = fmt.Sprint(values[0])
+			continue
+		}
+
+		// Use the first argument that looks like a UID as resource ID.
+		for _, v := range values {
+			if str, ok := v.(string); ok && uid == "" && rnd.IsUID(str, 0) {
+				uid = str
+			}
+		}
+
+		parts = append(parts, fmt.Sprintf(s, values...))
+	}
+
+	var action, outcome string
+
+	// The last part describes the outcome if there is more than one.
+	if len(parts) == 1 {
+		action = parts[0]
+	} else if len(parts) > 1 {
+		action = strings.Join(parts[:len(parts)-1], AuditMessageSep)
+		outcome = parts[len(parts)-1]
+	}
+
+	return Data{
+		"ip":      ip,
+		"session": session,
+		"user":    user,
+		"action":  action,
+		"uid":     uid,
+		"outcome": outcome,
 	}
 }
 
@@ -20,3 +20,37 @@ func TestFormat(t *testing.T) {
 
 	t.Log(result)
 }
+
+func TestAuditData(t *testing.T) {
+	t.Run("Denied", func(t *testing.T) {
+		data := AuditData([]string{"192.0.2.1", "session %s", "access photo %s as %s", "denied"}, "sessxkkcabce", "pt9jtdre2lvl0yh7", "visitor")
+
+		assert.Equal(t, "192.0.2.1", data["ip"])
+		assert.Equal(t, "sessxkkcabce", data["session"])
+		assert.Equal(t, "", data["user"])
+		assert.Equal(t, "access photo pt9jtdre2lvl0yh7 as visitor", data["action"])
+		assert.Equal(t, "pt9jtdre2lvl0yh7", data["uid"])
+		assert.Equal(t, "denied", data["outcome"])
+	})
+	t.Run("User", func(t *testing.T) {
+		data := AuditData([]string{"", "user %s", "two-factor authentication enabled"}, "alice")
+
+		assert.Equal(t, "", data["ip"])
+		assert.Equal(t, "alice", data["user"])
+		assert.Equal(t, "two-factor authentication enabled", data["action"])
+		assert.Equal(t, "", data["outcome"])
+	})
+	t.Run("NoClientIP", func(t *testing.T) {
+		data := AuditData([]string{"user", "failed to create", "%s"}, "name too short")
+
+		assert.Equal(t, "", data["ip"])
+		assert.Equal(t, "user › failed to create", data["action"])
+		assert.Equal(t, "name too short", data["outcome"])
+	})
+	t.Run("Empty", func(t *testing.T) {
+		data := AuditData(nil)
+
+		assert.Equal(t, "", data["action"])
+		assert.Equal(t, "", data["outcome"])
+	})
+}
@@ -0,0 +1,9 @@
+package form
+
+// AlbumShare represents a form for sharing an album with another user.
+type AlbumShare struct {
+	UserUID  string `json:"UserUID"`
+	UserName string `json:"UserName"`
+	Level    string `json:"Level"`
+	Comment  string `json:"Comment"`
+}
@@ -0,0 +1,7 @@
+package form
+
+// ConvertOptions represents file conversion job options.
+type ConvertOptions struct {
+	Path  string `json:"path"`
+	Force bool   `json:"force"`
+}
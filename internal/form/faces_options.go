@@ -0,0 +1,6 @@
+package form
+
+// FacesOptions represents face recognition job options.
+type FacesOptions struct {
+	Force bool `json:"force"`
+}
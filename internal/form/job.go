@@ -0,0 +1,9 @@
+package form
+
+import "encoding/json"
+
+// Job represents a request to queue a background job.
+type Job struct {
+	Type    string          `json:"Type"`
+	Options json.RawMessage `json:"Options"`
+}
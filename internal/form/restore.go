@@ -0,0 +1,6 @@
+package form
+
+// Restore represents a request to restore a backup.
+type Restore struct {
+	DryRun bool `json:"DryRun"`
+}
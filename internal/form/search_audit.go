@@ -0,0 +1,35 @@
+package form
+
+import "time"
+
+// SearchAudit represents search form fields for "/api/v1/audit".
+type SearchAudit struct {
+	Query   string    `form:"q"`
+	Level   string    `form:"level"`
+	IP      string    `form:"ip"`
+	Session string    `form:"session"`
+	User    string    `form:"user"`
+	UID     string    `form:"uid"`
+	Outcome string    `form:"outcome"`
+	Before  time.Time `form:"before" time_format:"2006-01-02"`
+	After   time.Time `form:"after" time_format:"2006-01-02"`
+	Count   int       `form:"count" binding:"required" serialize:"-"`
+	Offset  int       `form:"offset" serialize:"-"`
+	Order   string    `form:"order" serialize:"-"`
+}
+
+func (f *SearchAudit) GetQuery() string {
+	return f.Query
+}
+
+func (f *SearchAudit) SetQuery(q string) {
+	f.Query = q
+}
+
+func (f *SearchAudit) ParseQueryString() error {
+	return ParseQueryString(f)
+}
+
+func NewSearchAudit(query string) SearchAudit {
+	return SearchAudit{Query: query}
+}
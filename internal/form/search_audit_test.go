@@ -0,0 +1,29 @@
+package form
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQueryStringAudit(t *testing.T) {
+	t.Run("Ok", func(t *testing.T) {
+		form := &SearchAudit{Query: "level:warning user:alice uid:pt9jtdre2lvl0yh7 deleted"}
+
+		if err := form.ParseQueryString(); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "warning", form.Level)
+		assert.Equal(t, "alice", form.User)
+		assert.Equal(t, "pt9jtdre2lvl0yh7", form.UID)
+		assert.Equal(t, "deleted", form.Query)
+		assert.Equal(t, 0, form.Count)
+	})
+}
+
+func TestNewSearchAudit(t *testing.T) {
+	r := NewSearchAudit("deleted")
+	assert.IsType(t, SearchAudit{}, r)
+	assert.Equal(t, "deleted", r.Query)
+}
@@ -1,9 +1,16 @@
 package form
 
 import (
+	"fmt"
+	"net/url"
+	"time"
+
 	"github.com/ulule/deepcopier"
 
 	"github.com/photoprism/photoprism/internal/remote"
+	"github.com/photoprism/photoprism/internal/remote/s3"
+	"github.com/photoprism/photoprism/internal/remote/sftp"
+	"github.com/photoprism/photoprism/pkg/txt"
 )
 
 // Service represents a remote service form for uploading, downloading or syncing media files.
@@ -15,7 +22,13 @@ type Service struct {
 	AccKey        string `json:"AccKey"`
 	AccUser       string `json:"AccUser"`
 	AccPass       string `json:"AccPass"`
-	AccTimeout    string `json:"AccTimeout"` // Request timeout: default, high, medium, low, none
+	AccBucket     string `json:"AccBucket"`     // Bucket name for S3-compatible services.
+	AccPrefix     string `json:"AccPrefix"`     // Optional key prefix for S3-compatible services.
+	AccRegion     string `json:"AccRegion"`     // Optional region for S3-compatible services.
+	AccPrivateKey string `json:"AccPrivateKey"` // Optional SSH private key for SFTP services.
+	AccHostKey    string `json:"AccHostKey"`    // Pinned SSH host key fingerprint for SFTP services.
+	AccBandwidth  int    `json:"AccBandwidth"`  // Maximum transfer rate in KiB/s, 0 for unlimited.
+	AccTimeout    string `json:"AccTimeout"`    // Request timeout: default, high, medium, low, none
 	AccError      string `json:"AccError"`
 	AccShare      bool   `json:"AccShare"`   // Manual upload enabled, see SharePath, ShareSize, and ShareExpires.
 	AccSync       bool   `json:"AccSync"`    // Background sync enabled, see SyncDownload and SyncUpload.
@@ -29,6 +42,17 @@ type Service struct {
 	SyncDownload  bool   `json:"SyncDownload"`
 	SyncFilenames bool   `json:"SyncFilenames"`
 	SyncRaw       bool   `json:"SyncRaw"`
+	SyncConflict  string `json:"SyncConflict"` // Conflict policy: keep, local, remote
+}
+
+// HostKeyError is returned if the host key of an SFTP server must be confirmed by the user before it is pinned.
+type HostKeyError struct {
+	Fingerprint string
+}
+
+// Error implements the error interface.
+func (e *HostKeyError) Error() string {
+	return fmt.Sprintf("sftp: host key %s must be confirmed", e.Fingerprint)
 }
 
 // NewService creates a new service form.
@@ -40,6 +64,16 @@ func NewService(m interface{}) (f Service, err error) {
 
 // Discovery performs automatic service discovery.
 func (f *Service) Discovery() error {
+	// S3-compatible services cannot be discovered, so only check if the bucket can be accessed.
+	if f.AccType == remote.ServiceS3 {
+		return f.checkS3()
+	}
+
+	// SFTP services cannot be discovered either, so only check if the server can be accessed with the confirmed host key.
+	if f.AccType == remote.ServiceSFTP {
+		return f.checkSFTP()
+	}
+
 	acc, err := remote.Discover(f.AccURL, f.AccUser, f.AccPass)
 
 	if err != nil {
@@ -50,3 +84,54 @@ func (f *Service) Discovery() error {
 
 	return err
 }
+
+// checkS3 verifies that the bucket of an S3-compatible service can be accessed.
+func (f *Service) checkS3() error {
+	client, err := s3.New(f.AccURL, f.AccRegion, f.AccBucket, f.AccPrefix, f.AccUser, f.AccPass, time.Minute)
+
+	if err != nil {
+		return err
+	} else if err = client.Check(); err != nil {
+		return err
+	}
+
+	if f.AccName == "" {
+		f.AccName = txt.Title(f.AccBucket)
+	}
+
+	return nil
+}
+
+// checkSFTP verifies that an SFTP server can be accessed. If no host key has been confirmed yet, a
+// HostKeyError with the fingerprint presented by the server is returned so that the user can verify it.
+func (f *Service) checkSFTP() error {
+	if f.AccHostKey == "" {
+		fingerprint, err := sftp.HostKey(f.AccURL, time.Minute)
+
+		if err != nil {
+			return err
+		}
+
+		return &HostKeyError{Fingerprint: fingerprint}
+	}
+
+	client, err := sftp.New(f.AccURL, f.AccUser, f.AccPass, f.AccPrivateKey, f.AccHostKey, time.Minute)
+
+	if err != nil {
+		return err
+	}
+
+	defer client.Close()
+
+	if err = client.Check(); err != nil {
+		return err
+	}
+
+	if f.AccName == "" {
+		if u, err := url.Parse(f.AccURL); err == nil {
+			f.AccName = u.Hostname()
+		}
+	}
+
+	return nil
+}
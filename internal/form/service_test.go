@@ -4,6 +4,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/remote/s3/s3test"
+	"github.com/photoprism/photoprism/internal/remote/sftp/sftptest"
 )
 
 func TestNewService(t *testing.T) {
@@ -55,3 +58,73 @@ func TestService_Discovery(t *testing.T) {
 		assert.Equal(t, "service URL is empty", err.Error())
 	})
 }
+
+func TestService_DiscoveryS3(t *testing.T) {
+	srv := s3test.NewServer("photos")
+	defer srv.Close()
+
+	t.Run("Success", func(t *testing.T) {
+		service := Service{AccURL: srv.URL, AccType: "s3", AccBucket: "photos", AccPrefix: "backup", AccUser: s3test.AccessKey, AccPass: s3test.SecretKey}
+
+		err := service.Discovery()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Photos", service.AccName)
+		assert.Equal(t, "s3", service.AccType)
+		assert.Equal(t, srv.URL, service.AccURL)
+	})
+	t.Run("NoSuchBucket", func(t *testing.T) {
+		service := Service{AccURL: srv.URL, AccType: "s3", AccBucket: "foo", AccUser: s3test.AccessKey, AccPass: s3test.SecretKey}
+
+		err := service.Discovery()
+
+		assert.Error(t, err)
+	})
+}
+
+func TestService_DiscoverySFTP(t *testing.T) {
+	srv, err := sftptest.NewServer()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer srv.Close()
+
+	t.Run("Success", func(t *testing.T) {
+		service := Service{AccURL: srv.URL, AccType: "sftp", AccUser: sftptest.User, AccPass: sftptest.Password, AccHostKey: srv.HostKey}
+
+		err := service.Discovery()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "127.0.0.1", service.AccName)
+		assert.Equal(t, srv.HostKey, service.AccHostKey)
+	})
+	t.Run("HostKeyUnconfirmed", func(t *testing.T) {
+		service := Service{AccURL: srv.URL, AccType: "sftp", AccUser: sftptest.User, AccPass: sftptest.Password}
+
+		err := service.Discovery()
+
+		var hostKeyErr *HostKeyError
+
+		if assert.ErrorAs(t, err, &hostKeyErr) {
+			assert.Equal(t, srv.HostKey, hostKeyErr.Fingerprint)
+		}
+
+		assert.Equal(t, "", service.AccHostKey)
+	})
+	t.Run("PrivateKey", func(t *testing.T) {
+		service := Service{AccURL: srv.URL, AccType: "sftp", AccUser: sftptest.User, AccPrivateKey: srv.PrivateKey, AccHostKey: srv.HostKey}
+
+		err := service.Discovery()
+
+		assert.NoError(t, err)
+	})
+	t.Run("HostKeyMismatch", func(t *testing.T) {
+		service := Service{AccURL: srv.URL, AccType: "sftp", AccUser: sftptest.User, AccPass: sftptest.Password, AccHostKey: "SHA256:foo"}
+
+		err := service.Discovery()
+
+		assert.Error(t, err)
+	})
+}
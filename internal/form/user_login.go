@@ -10,6 +10,7 @@ type Login struct {
 	UserEmail string `json:"email,omitempty"`
 	Password  string `json:"password,omitempty"`
 	AuthToken string `json:"token,omitempty"`
+	Passcode  string `json:"passcode,omitempty"`
 }
 
 // Name returns the sanitized username in lowercase.
@@ -35,6 +36,20 @@ func (f Login) HasPassword() bool {
 	return f.Password != "" && len(f.Password) <= 255
 }
 
+// SharePassword returns the password for a protected share link, if no username is set.
+func (f Login) SharePassword() string {
+	if f.HasName() {
+		return ""
+	}
+
+	return f.Password
+}
+
+// HasPasscode checks if a verification or recovery code is set.
+func (f Login) HasPasscode() bool {
+	return f.Passcode != "" && len(f.Passcode) <= 32
+}
+
 // HasToken checks if an auth token is set.
 func (f Login) HasToken() bool {
 	return f.AuthToken != ""
@@ -0,0 +1,7 @@
+package form
+
+// UserPasscode represents a two-factor authentication setup form.
+type UserPasscode struct {
+	Password string `json:"Password,omitempty"`
+	Code     string `json:"Code,omitempty"`
+}
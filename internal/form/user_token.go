@@ -0,0 +1,8 @@
+package form
+
+// UserToken represents an access token form.
+type UserToken struct {
+	Name    string `json:"Name"`
+	Scope   string `json:"Scope"`
+	Expires int    `json:"Expires"`
+}
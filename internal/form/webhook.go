@@ -0,0 +1,8 @@
+package form
+
+// Webhook represents a webhook subscription form.
+type Webhook struct {
+	URL     string `json:"URL"`
+	Topics  string `json:"Topics"`
+	Enabled bool   `json:"Enabled"`
+}
@@ -0,0 +1,43 @@
+package get
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/oidc"
+)
+
+var oidcMutex sync.Mutex
+var oidcProvider *oidc.Provider
+
+// OIDC returns the configured OpenID Connect provider, discovering it on first use.
+func OIDC() (*oidc.Provider, error) {
+	c := Config()
+
+	if !c.OIDCEnabled() {
+		return nil, errors.New("single sign-on is not configured")
+	}
+
+	oidcMutex.Lock()
+	defer oidcMutex.Unlock()
+
+	// Reuse provider if the configuration has not changed.
+	if p := oidcProvider; p != nil && p.Issuer == c.OIDCUri() && p.ClientID == c.OIDCClient() && p.ClientSecret == c.OIDCSecret() && p.RedirectUri == c.OIDCRedirectUri() {
+		return p, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	p, err := oidc.NewProvider(ctx, c.OIDCUri(), c.OIDCClient(), c.OIDCSecret(), c.OIDCRedirectUri(), c.OIDCScopes())
+
+	if err != nil {
+		return nil, err
+	}
+
+	oidcProvider = p
+
+	return p, nil
+}
@@ -39,6 +39,19 @@ const (
 	ErrBusy
 	ErrWakeupInterval
 	ErrAccountConnect
+	ErrLinkExpired
+	ErrLinkViewLimit
+	ErrInvalidToken
+	ErrInvalidScope
+	ErrPasscodeRequired
+	ErrInvalidPasscode
+	ErrInvalidWebhook
+	ErrInvalidJob
+	ErrMaintenance
+	ErrBackupNotFound
+	ErrNotInTrash
+	ErrLoginRequired
+	ErrConfirmHostKey
 
 	MsgChangesSaved
 	MsgAlbumCreated
@@ -81,6 +94,10 @@ const (
 	MsgZipCreatedIn
 	MsgPermanentlyDeleted
 	MsgRestored
+	MsgRestoringBackup
+	MsgBackupRestoredIn
+	MsgMovedToTrash
+	MsgTrashEmptied
 )
 
 var Messages = MessageMap{
@@ -123,6 +140,19 @@ var Messages = MessageMap{
 	ErrBusy:               gettext("Busy, please try again later"),
 	ErrWakeupInterval:     gettext("The wakeup interval is %s, but must be 1h or less"),
 	ErrAccountConnect:     gettext("Your account could not be connected"),
+	ErrLinkExpired:        gettext("Link has expired"),
+	ErrLinkViewLimit:      gettext("Link has reached its view limit"),
+	ErrInvalidToken:       gettext("Invalid access token"),
+	ErrInvalidScope:       gettext("Invalid scope"),
+	ErrPasscodeRequired:   gettext("Please enter your verification code"),
+	ErrInvalidPasscode:    gettext("Invalid verification code"),
+	ErrInvalidWebhook:     gettext("Invalid webhook"),
+	ErrInvalidJob:         gettext("Invalid job"),
+	ErrMaintenance:        gettext("Not available during maintenance"),
+	ErrBackupNotFound:     gettext("Backup not found"),
+	ErrNotInTrash:         gettext("Not found in trash"),
+	ErrLoginRequired:      gettext("Please log in again to confirm"),
+	ErrConfirmHostKey:     gettext("Please verify and confirm the host key %s"),
 
 	// Info and confirmation messages:
 	MsgChangesSaved:          gettext("Changes successfully saved"),
@@ -166,4 +196,8 @@ var Messages = MessageMap{
 	MsgZipCreatedIn:          gettext("Zip created in %d s"),
 	MsgPermanentlyDeleted:    gettext("Permanently deleted"),
 	MsgRestored:              gettext("%s has been restored"),
+	MsgRestoringBackup:       gettext("Restoring backup %s..."),
+	MsgBackupRestoredIn:      gettext("Backup restored in %d s"),
+	MsgMovedToTrash:          gettext("Moved to trash"),
+	MsgTrashEmptied:          gettext("Trash emptied"),
 }
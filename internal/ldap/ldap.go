@@ -0,0 +1,39 @@
+/*
+Package ldap authenticates users against a directory server such as OpenLDAP or Active Directory.
+
+Copyright (c) 2018 - 2022 PhotoPrism UG. All rights reserved.
+
+	This program is free software: you can redistribute it and/or modify
+	it under Version 3 of the GNU Affero General Public License (the "AGPL"):
+	<https://docs.photoprism.app/license/agpl>
+
+	This program is distributed in the hope that it will be useful,
+	but WITHOUT ANY WARRANTY; without even the implied warranty of
+	MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+	GNU Affero General Public License for more details.
+
+	The AGPL is supplemented by our Trademark and Brand Guidelines,
+	which describe how our Brand Assets may be used:
+	<https://photoprism.app/trademark>
+
+Feel free to send an email to hello@photoprism.app if you have questions,
+want to support our work, or just want to say hello.
+
+Additional information can be found in our Developer Guide:
+<https://docs.photoprism.app/developer-guide/>
+*/
+package ldap
+
+import (
+	"errors"
+	"time"
+)
+
+// Timeout is the default network timeout if the context has no deadline.
+var Timeout = 30 * time.Second
+
+// ErrAccountNotFound is returned if the username does not match exactly one directory entry.
+var ErrAccountNotFound = errors.New("account not found")
+
+// ErrInvalidCredentials is returned if the password is incorrect.
+var ErrInvalidCredentials = errors.New("invalid credentials")
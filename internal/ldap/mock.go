@@ -0,0 +1,380 @@
+package ldap
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+// StartTLSOID is the name of the extended operation that upgrades a connection to TLS, see RFC 4511 section 4.14.
+const StartTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// Entry represents a directory entry served by MockServer.
+type Entry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+// NewEntry returns a new directory entry.
+func NewEntry(dn string, attr map[string][]string) *Entry {
+	if attr == nil {
+		attr = make(map[string][]string)
+	}
+
+	return &Entry{DN: dn, Attributes: attr}
+}
+
+// Values returns all values of an attribute, ignoring the case of its name.
+func (e *Entry) Values(name string) []string {
+	if e == nil {
+		return nil
+	}
+
+	for k, v := range e.Attributes {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// Get returns the first value of an attribute, or an empty string if it has no value.
+func (e *Entry) Get(name string) string {
+	if v := e.Values(name); len(v) > 0 {
+		return v[0]
+	}
+
+	return ""
+}
+
+// MockServer is a minimal in-process directory server for use in tests. It supports
+// StartTLS with a self-signed certificate, simple binds, and subtree searches with
+// and, or, not, equality, and presence filters.
+type MockServer struct {
+	Entries   []*Entry
+	Passwords map[string]string
+	tls       *tls.Config
+	listener  net.Listener
+	conns     map[net.Conn]struct{}
+	mutex     sync.Mutex
+	wg        sync.WaitGroup
+}
+
+// NewMockServer starts a new mock directory server with the specified entries and
+// passwords by DN. Call Close() when it is no longer needed.
+func NewMockServer(entries []*Entry, passwords map[string]string) *MockServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		panic(err)
+	}
+
+	m := &MockServer{
+		Entries:   entries,
+		Passwords: passwords,
+		tls:       mockTLSConfig(),
+		listener:  l,
+		conns:     make(map[net.Conn]struct{}),
+	}
+
+	m.wg.Add(1)
+	go m.serve()
+
+	return m
+}
+
+// Uri returns the server URI.
+func (m *MockServer) Uri() string {
+	return "ldap://" + m.listener.Addr().String()
+}
+
+// Close stops the server and closes all client connections.
+func (m *MockServer) Close() {
+	_ = m.listener.Close()
+
+	m.mutex.Lock()
+	for c := range m.conns {
+		_ = c.Close()
+	}
+	m.mutex.Unlock()
+
+	m.wg.Wait()
+}
+
+// serve accepts new client connections.
+func (m *MockServer) serve() {
+	defer m.wg.Done()
+
+	for {
+		c, err := m.listener.Accept()
+
+		if err != nil {
+			return
+		}
+
+		m.mutex.Lock()
+		m.conns[c] = struct{}{}
+		m.mutex.Unlock()
+
+		m.wg.Add(1)
+		go m.handle(c)
+	}
+}
+
+// handle responds to the requests of a client until it unbinds or disconnects.
+func (m *MockServer) handle(c net.Conn) {
+	conn := c
+
+	defer func() {
+		m.mutex.Lock()
+		delete(m.conns, c)
+		m.mutex.Unlock()
+
+		_ = conn.Close()
+		m.wg.Done()
+	}()
+
+	for {
+		msg, err := ber.ReadPacket(conn)
+
+		if err != nil || len(msg.Children) < 2 {
+			return
+		}
+
+		id, op := msg.Children[0].Value, msg.Children[1]
+
+		var responses []*ber.Packet
+		var startTLS bool
+
+		switch {
+		case mockIs(op, goldap.ApplicationBindRequest):
+			responses = append(responses, m.bind(op))
+		case mockIs(op, goldap.ApplicationSearchRequest):
+			responses = m.search(op)
+		case mockIs(op, goldap.ApplicationExtendedRequest) && len(op.Children) > 0 && mockString(op.Children[0]) == StartTLSOID:
+			responses = append(responses, mockResult(goldap.ApplicationExtendedResponse, goldap.LDAPResultSuccess, ""))
+			startTLS = true
+		default:
+			return
+		}
+
+		for _, res := range responses {
+			envelope := ber.NewSequence("LDAP Response")
+			envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "MessageID"))
+			envelope.AppendChild(res)
+
+			if _, err = conn.Write(envelope.Bytes()); err != nil {
+				return
+			}
+		}
+
+		// Continue with an encrypted connection after StartTLS.
+		if startTLS {
+			tlsConn := tls.Server(c, m.tls)
+
+			if err = tlsConn.Handshake(); err != nil {
+				return
+			}
+
+			conn = tlsConn
+		}
+	}
+}
+
+// bind returns the response to a bind request.
+func (m *MockServer) bind(op *ber.Packet) *ber.Packet {
+	if len(op.Children) < 3 {
+		return mockResult(goldap.ApplicationBindResponse, goldap.LDAPResultProtocolError, "invalid request")
+	}
+
+	dn, password := mockString(op.Children[1]), mockString(op.Children[2])
+
+	if dn == "" && password == "" {
+		return mockResult(goldap.ApplicationBindResponse, goldap.LDAPResultSuccess, "")
+	}
+
+	if p, ok := m.Passwords[dn]; ok && password != "" && p == password {
+		return mockResult(goldap.ApplicationBindResponse, goldap.LDAPResultSuccess, "")
+	}
+
+	return mockResult(goldap.ApplicationBindResponse, goldap.LDAPResultInvalidCredentials, "invalid credentials")
+}
+
+// search returns the responses to a search request.
+func (m *MockServer) search(op *ber.Packet) []*ber.Packet {
+	if len(op.Children) < 8 {
+		return []*ber.Packet{mockResult(goldap.ApplicationSearchResultDone, goldap.LDAPResultProtocolError, "invalid request")}
+	}
+
+	base := strings.ToLower(mockString(op.Children[0]))
+	filter := op.Children[6]
+
+	var attributes []string
+
+	for _, a := range op.Children[7].Children {
+		attributes = append(attributes, mockString(a))
+	}
+
+	var responses []*ber.Packet
+
+	for _, e := range m.Entries {
+		dn := strings.ToLower(e.DN)
+
+		if dn != base && !strings.HasSuffix(dn, ","+base) || !mockMatch(filter, e) {
+			continue
+		}
+
+		attr := ber.NewSequence("Attributes")
+
+		for name, values := range e.Attributes {
+			if !mockRequested(name, attributes) {
+				continue
+			}
+
+			set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+
+			for _, v := range values {
+				set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "Value"))
+			}
+
+			a := ber.NewSequence("Attribute")
+			a.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Name"))
+			a.AppendChild(set)
+			attr.AppendChild(a)
+		}
+
+		entry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, goldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+		entry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.DN, "DN"))
+		entry.AppendChild(attr)
+
+		responses = append(responses, entry)
+	}
+
+	return append(responses, mockResult(goldap.ApplicationSearchResultDone, goldap.LDAPResultSuccess, ""))
+}
+
+// mockMatch checks if the entry matches an encoded search filter. Unsupported filter types never match.
+func mockMatch(f *ber.Packet, e *Entry) bool {
+	if f.ClassType != ber.ClassContext {
+		return false
+	}
+
+	switch f.Tag {
+	case goldap.FilterAnd:
+		for _, c := range f.Children {
+			if !mockMatch(c, e) {
+				return false
+			}
+		}
+
+		return true
+	case goldap.FilterOr:
+		for _, c := range f.Children {
+			if mockMatch(c, e) {
+				return true
+			}
+		}
+
+		return false
+	case goldap.FilterNot:
+		return len(f.Children) == 1 && !mockMatch(f.Children[0], e)
+	case goldap.FilterEqualityMatch:
+		if len(f.Children) != 2 {
+			return false
+		}
+
+		value := mockString(f.Children[1])
+
+		for _, v := range e.Values(mockString(f.Children[0])) {
+			if strings.EqualFold(v, value) {
+				return true
+			}
+		}
+
+		return false
+	case goldap.FilterPresent:
+		return len(e.Values(mockString(f))) > 0
+	default:
+		return false
+	}
+}
+
+// mockIs checks if the packet is an application-specific operation with the specified tag.
+func mockIs(p *ber.Packet, tag ber.Tag) bool {
+	return p.ClassType == ber.ClassApplication && p.Tag == tag
+}
+
+// mockString returns the value of a packet as string.
+func mockString(p *ber.Packet) string {
+	if s, ok := p.Value.(string); ok {
+		return s
+	} else if p.Data != nil {
+		return p.Data.String()
+	}
+
+	return ""
+}
+
+// mockRequested checks if an attribute was requested.
+func mockRequested(name string, attributes []string) bool {
+	if len(attributes) == 0 {
+		return true
+	}
+
+	for _, a := range attributes {
+		if a == "*" || strings.EqualFold(a, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mockResult returns an LDAP result with the specified operation and code.
+func mockResult(op ber.Tag, code uint16, message string) *ber.Packet {
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, op, nil, "Result")
+	p.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, message, "Message"))
+
+	return p
+}
+
+// mockTLSConfig returns a server TLS configuration with a new self-signed certificate for 127.0.0.1.
+func mockTLSConfig() *tls.Config {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		panic(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+}
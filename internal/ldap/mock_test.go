@@ -0,0 +1,112 @@
+package ldap
+
+import (
+	"crypto/tls"
+	"testing"
+
+	goldap "github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockEntries returns the directory entries used in tests.
+func mockEntries() []*Entry {
+	return []*Entry{
+		NewEntry("uid=alice,ou=people,dc=example,dc=com", map[string][]string{
+			"uid":         {"alice"},
+			"mail":        {"alice@example.com"},
+			"displayName": {"Alice Smith"},
+			"memberOf":    {"cn=admins,ou=groups,dc=example,dc=com"},
+		}),
+		NewEntry("uid=bob,ou=people,dc=example,dc=com", map[string][]string{
+			"uid":  {"bob"},
+			"mail": {"bob@example.com"},
+			"cn":   {"Bob"},
+		}),
+		NewEntry("cn=users,ou=groups,dc=example,dc=com", map[string][]string{
+			"cn":     {"users"},
+			"member": {"uid=bob,ou=people,dc=example,dc=com"},
+		}),
+		NewEntry("cn=service,dc=example,dc=com", nil),
+	}
+}
+
+// mockPasswords returns the passwords of the directory entries used in tests.
+func mockPasswords() map[string]string {
+	return map[string]string{
+		"uid=alice,ou=people,dc=example,dc=com": "Alice123!",
+		"uid=bob,ou=people,dc=example,dc=com":   "Bobbob123!",
+		"cn=service,dc=example,dc=com":          "service",
+	}
+}
+
+func TestEntry_Get(t *testing.T) {
+	e := NewEntry("uid=alice", map[string][]string{"mail": {"alice@example.com", "alice@example.org"}})
+	assert.Equal(t, "alice@example.com", e.Get("Mail"))
+	assert.Len(t, e.Values("MAIL"), 2)
+	assert.Equal(t, "", e.Get("cn"))
+	assert.Nil(t, (*Entry)(nil).Values("mail"))
+}
+
+func TestMockServer(t *testing.T) {
+	srv := NewMockServer(mockEntries(), mockPasswords())
+	defer srv.Close()
+
+	conn, err := goldap.DialURL(srv.Uri())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	t.Run("StartTLS", func(t *testing.T) {
+		assert.NoError(t, conn.StartTLS(&tls.Config{InsecureSkipVerify: true}))
+
+		_, ok := conn.TLSConnectionState()
+		assert.True(t, ok)
+	})
+	t.Run("Bind", func(t *testing.T) {
+		assert.NoError(t, conn.UnauthenticatedBind(""))
+		assert.NoError(t, conn.Bind("cn=service,dc=example,dc=com", "service"))
+
+		err := conn.Bind("cn=service,dc=example,dc=com", "wrong")
+		assert.True(t, goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials))
+	})
+	t.Run("Search", func(t *testing.T) {
+		req := goldap.NewSearchRequest("ou=people,dc=example,dc=com", goldap.ScopeWholeSubtree, goldap.NeverDerefAliases,
+			0, 0, false, "(uid=alice)", []string{"mail", "memberOf"}, nil)
+
+		res, err := conn.Search(req)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(res.Entries) != 1 {
+			t.Fatalf("expected one entry, found %d", len(res.Entries))
+		}
+
+		assert.Equal(t, "uid=alice,ou=people,dc=example,dc=com", res.Entries[0].DN)
+		assert.Equal(t, "alice@example.com", res.Entries[0].GetAttributeValue("mail"))
+		assert.Equal(t, "cn=admins,ou=groups,dc=example,dc=com", res.Entries[0].GetAttributeValue("memberOf"))
+		assert.Equal(t, "", res.Entries[0].GetAttributeValue("uid"))
+	})
+	t.Run("SearchBase", func(t *testing.T) {
+		req := goldap.NewSearchRequest("ou=groups,dc=example,dc=com", goldap.ScopeWholeSubtree, goldap.NeverDerefAliases,
+			0, 0, false, "(uid=*)", nil, nil)
+
+		res, err := conn.Search(req)
+		assert.NoError(t, err)
+		assert.Len(t, res.Entries, 0)
+
+		req.BaseDN = "dc=example,dc=com"
+		res, err = conn.Search(req)
+		assert.NoError(t, err)
+		assert.Len(t, res.Entries, 2)
+
+		req.Filter = "(&(uid=*)(!(uid=bob)))"
+		res, err = conn.Search(req)
+		assert.NoError(t, err)
+		assert.Len(t, res.Entries, 1)
+	})
+}
@@ -0,0 +1,179 @@
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+// DefaultUserFilter is used to find user entries if no other filter was configured.
+const DefaultUserFilter = "(uid=%s)"
+
+// UserAttributes specifies the attributes requested when searching a user.
+var UserAttributes = []string{"uid", "mail", "displayName", "cn", "memberOf"}
+
+// Provider authenticates users against a directory server.
+type Provider struct {
+	Uri          string
+	BindDN       string
+	BindPassword string
+	BaseDN       string
+	UserFilter   string
+	Insecure     bool
+}
+
+// Account represents an authenticated directory user.
+type Account struct {
+	DN       string
+	Username string
+	Email    string
+	Name     string
+	Groups   []string
+}
+
+// Filter returns the search filter for the specified username.
+func (p *Provider) Filter(username string) string {
+	filter := p.UserFilter
+
+	if filter == "" {
+		filter = DefaultUserFilter
+	}
+
+	return strings.ReplaceAll(filter, "%s", goldap.EscapeFilter(username))
+}
+
+// Dial connects to the directory server. Connections to ldap:// URIs are upgraded with StartTLS
+// so that passwords are never sent in plain text, and fail if the server does not support it.
+func (p *Provider) Dial(ctx context.Context) (*goldap.Conn, error) {
+	u, err := url.Parse(p.Uri)
+
+	if err != nil {
+		return nil, err
+	} else if u.Hostname() == "" {
+		return nil, fmt.Errorf("ldap: host is missing")
+	} else if u.Scheme != "ldap" && u.Scheme != "ldaps" {
+		return nil, fmt.Errorf("ldap: unsupported scheme %s", u.Scheme)
+	}
+
+	deadline, ok := ctx.Deadline()
+
+	if !ok {
+		deadline = time.Now().Add(Timeout)
+	}
+
+	// Skipping certificate verification is optional, e.g. for self-signed certificates.
+	tlsConfig := &tls.Config{ServerName: u.Hostname(), InsecureSkipVerify: p.Insecure}
+
+	conn, err := goldap.DialURL(p.Uri, goldap.DialWithTLSDialer(tlsConfig, &net.Dialer{Deadline: deadline}))
+
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetTimeout(time.Until(deadline))
+
+	if u.Scheme == "ldaps" {
+		return conn, nil
+	}
+
+	if err = conn.StartTLS(tlsConfig); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("ldap: starttls failed: %w", err)
+	}
+
+	return conn, nil
+}
+
+// Authenticate verifies the username and password, and returns the account details
+// including the distinguished names of the groups the user is a member of.
+func (p *Provider) Authenticate(ctx context.Context, username, password string) (*Account, error) {
+	username = strings.TrimSpace(username)
+
+	// Servers typically accept a bind with an empty password as anonymous bind.
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := p.Dial(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	// Bind with the service account, if configured.
+	if p.BindDN != "" {
+		if err = conn.Bind(p.BindDN, p.BindPassword); err != nil {
+			return nil, fmt.Errorf("bind as %s failed: %w", p.BindDN, err)
+		}
+	}
+
+	// Find user entry.
+	entries, err := p.search(conn, p.Filter(username), UserAttributes)
+
+	if err != nil {
+		return nil, err
+	} else if len(entries) != 1 {
+		return nil, ErrAccountNotFound
+	}
+
+	entry := entries[0]
+
+	account := &Account{
+		DN:       entry.DN,
+		Username: username,
+		Email:    entry.GetEqualFoldAttributeValue("mail"),
+		Name:     entry.GetEqualFoldAttributeValue("displayName"),
+		Groups:   entry.GetEqualFoldAttributeValues("memberOf"),
+	}
+
+	if account.Name == "" {
+		account.Name = entry.GetEqualFoldAttributeValue("cn")
+	}
+
+	// Search groups if the server does not provide the memberOf attribute.
+	if len(account.Groups) == 0 {
+		filter := fmt.Sprintf("(|(member=%s)(uniqueMember=%s)(memberUid=%s))",
+			goldap.EscapeFilter(entry.DN), goldap.EscapeFilter(entry.DN), goldap.EscapeFilter(username))
+
+		groups, err := p.search(conn, filter, []string{"cn"})
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, g := range groups {
+			account.Groups = append(account.Groups, g.DN)
+		}
+	}
+
+	// Finally, verify the password by binding as the user.
+	if err = conn.Bind(entry.DN, password); goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) {
+		return nil, ErrInvalidCredentials
+	} else if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// search returns the entries below the base DN that match the filter.
+func (p *Provider) search(conn *goldap.Conn, filter string, attributes []string) ([]*goldap.Entry, error) {
+	req := goldap.NewSearchRequest(p.BaseDN, goldap.ScopeWholeSubtree, goldap.NeverDerefAliases,
+		0, 0, false, filter, attributes, nil)
+
+	res, err := conn.Search(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Entries, nil
+}
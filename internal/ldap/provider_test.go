@@ -0,0 +1,94 @@
+package ldap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvider_Filter(t *testing.T) {
+	assert.Equal(t, "(uid=alice)", (&Provider{}).Filter("alice"))
+	assert.Equal(t, "(uid=\\2a)", (&Provider{}).Filter("*"))
+	assert.Equal(t, "(&(objectClass=person)(|(uid=bob)(mail=bob)))", (&Provider{UserFilter: "(&(objectClass=person)(|(uid=%s)(mail=%s)))"}).Filter("bob"))
+}
+
+func TestProvider_Dial(t *testing.T) {
+	t.Run("InvalidScheme", func(t *testing.T) {
+		_, err := (&Provider{Uri: "http://localhost"}).Dial(context.Background())
+		assert.EqualError(t, err, "ldap: unsupported scheme http")
+	})
+	t.Run("MissingHost", func(t *testing.T) {
+		_, err := (&Provider{Uri: "ldap://"}).Dial(context.Background())
+		assert.EqualError(t, err, "ldap: host is missing")
+	})
+}
+
+func TestProvider_Authenticate(t *testing.T) {
+	srv := NewMockServer(mockEntries(), mockPasswords())
+	defer srv.Close()
+
+	p := &Provider{
+		Uri:          srv.Uri(),
+		BindDN:       "cn=service,dc=example,dc=com",
+		BindPassword: "service",
+		BaseDN:       "dc=example,dc=com",
+		Insecure:     true,
+	}
+
+	ctx := context.Background()
+
+	t.Run("MemberOf", func(t *testing.T) {
+		account, err := p.Authenticate(ctx, "alice", "Alice123!")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "uid=alice,ou=people,dc=example,dc=com", account.DN)
+		assert.Equal(t, "alice", account.Username)
+		assert.Equal(t, "alice@example.com", account.Email)
+		assert.Equal(t, "Alice Smith", account.Name)
+		assert.Equal(t, []string{"cn=admins,ou=groups,dc=example,dc=com"}, account.Groups)
+	})
+	t.Run("GroupSearch", func(t *testing.T) {
+		account, err := p.Authenticate(ctx, "bob", "Bobbob123!")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "Bob", account.Name)
+		assert.Equal(t, []string{"cn=users,ou=groups,dc=example,dc=com"}, account.Groups)
+	})
+	t.Run("InvalidPassword", func(t *testing.T) {
+		account, err := p.Authenticate(ctx, "alice", "wrong")
+		assert.Equal(t, ErrInvalidCredentials, err)
+		assert.Nil(t, account)
+	})
+	t.Run("EmptyPassword", func(t *testing.T) {
+		_, err := p.Authenticate(ctx, "alice", "")
+		assert.Equal(t, ErrInvalidCredentials, err)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := p.Authenticate(ctx, "eve", "Eve123!")
+		assert.Equal(t, ErrAccountNotFound, err)
+
+		_, err = p.Authenticate(ctx, "*", "Alice123!")
+		assert.Equal(t, ErrAccountNotFound, err)
+	})
+	t.Run("InvalidBindPassword", func(t *testing.T) {
+		invalid := *p
+		invalid.BindPassword = "wrong"
+
+		_, err := invalid.Authenticate(ctx, "alice", "Alice123!")
+		assert.EqualError(t, err, "bind as cn=service,dc=example,dc=com failed: LDAP Result Code 49 \"Invalid Credentials\": invalid credentials")
+	})
+	t.Run("UntrustedCertificate", func(t *testing.T) {
+		untrusted := *p
+		untrusted.Insecure = false
+
+		_, err := untrusted.Authenticate(ctx, "alice", "Alice123!")
+		assert.ErrorContains(t, err, "ldap: starttls failed")
+	})
+}
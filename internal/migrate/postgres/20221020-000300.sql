@@ -0,0 +1,4 @@
+CREATE INDEX IF NOT EXISTS idx_albums_album_filter ON albums (album_filter);
+CREATE INDEX IF NOT EXISTS idx_files_missing_root ON files (file_missing, file_root);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_files_search_media ON files (media_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_files_search_timeline ON files (time_index);
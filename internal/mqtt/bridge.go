@@ -0,0 +1,235 @@
+package mqtt
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sync"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/leandro-lugaresi/hub"
+
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// Bridge publishes events to an MQTT broker.
+type Bridge struct {
+	Uri      string
+	ClientID string
+	Username string
+	Password string
+	Prefix   string
+	QoS      byte
+	Insecure bool
+	CAFile   string
+
+	client  paho.Client
+	sub     hub.Subscription
+	queue   chan hub.Message
+	quit    chan struct{}
+	wg      sync.WaitGroup
+	dropped int
+	mutex   sync.Mutex
+}
+
+// Start connects to the broker and starts forwarding events.
+func (b *Bridge) Start() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.client != nil {
+		return fmt.Errorf("mqtt: bridge already running")
+	} else if b.QoS > 2 {
+		return fmt.Errorf("mqtt: invalid qos %d", b.QoS)
+	}
+
+	opt, err := b.options()
+
+	if err != nil {
+		return err
+	}
+
+	client := paho.NewClient(opt)
+
+	// The client keeps trying to connect in the background if the broker is not reachable yet.
+	if t := client.Connect(); !t.WaitTimeout(Timeout) {
+		log.Warnf("mqtt: %s is not reachable, retrying every %s", clean.Log(b.host()), RetryInterval)
+	} else if err = t.Error(); err != nil {
+		return fmt.Errorf("mqtt: %s", err)
+	}
+
+	b.client = client
+	b.sub = event.Subscribe(Topics...)
+	b.queue = make(chan hub.Message, QueueSize)
+	b.quit = make(chan struct{})
+	b.dropped = 0
+
+	b.wg.Add(2)
+	go b.forward(b.sub, b.queue)
+	go b.publish(b.queue, b.quit)
+
+	log.Infof("mqtt: publishing events to %s", clean.Log(b.host()))
+
+	return nil
+}
+
+// Stop stops forwarding events and disconnects from the broker.
+func (b *Bridge) Stop() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.client == nil {
+		return
+	}
+
+	// Events waiting for publishing are discarded.
+	close(b.quit)
+	event.Unsubscribe(b.sub)
+	b.wg.Wait()
+
+	b.client.Disconnect(uint(Timeout.Milliseconds()))
+	b.client = nil
+}
+
+// Publish sends an event to the broker.
+func (b *Bridge) Publish(name string, data event.Data) error {
+	payload, err := json.Marshal(data)
+
+	if err != nil {
+		return err
+	}
+
+	t := b.client.Publish(Topic(b.Prefix, name), b.QoS, false, payload)
+
+	// Messages with QoS 0 are not acknowledged.
+	if b.QoS == 0 {
+		return nil
+	} else if !t.WaitTimeout(Timeout) {
+		return fmt.Errorf("publishing %s timed out", name)
+	}
+
+	return t.Error()
+}
+
+// forward adds events to the queue until the subscription is closed. It never blocks, so that
+// events are not dropped by the event hub if the broker is slow or unavailable.
+func (b *Bridge) forward(s hub.Subscription, queue chan hub.Message) {
+	defer b.wg.Done()
+	defer close(queue)
+
+	for msg := range s.Receiver {
+		if !b.client.IsConnectionOpen() {
+			b.drop(msg.Name, "broker is not connected")
+			continue
+		}
+
+		select {
+		case queue <- msg:
+			b.recovered()
+		default:
+			b.drop(msg.Name, "queue is full")
+		}
+	}
+}
+
+// publish sends queued events to the broker until the queue is closed or the bridge is stopped.
+func (b *Bridge) publish(queue chan hub.Message, quit chan struct{}) {
+	defer b.wg.Done()
+
+	for {
+		select {
+		case <-quit:
+			return
+		case msg, ok := <-queue:
+			if !ok {
+				return
+			} else if err := b.Publish(msg.Name, msg.Fields); err != nil {
+				log.Warnf("mqtt: %s", err)
+			}
+		}
+	}
+}
+
+// drop logs the first event that could not be published and counts the following ones.
+func (b *Bridge) drop(name, reason string) {
+	if b.dropped == 0 {
+		log.Warnf("mqtt: %s, dropping %s", reason, clean.Log(name))
+	}
+
+	b.dropped++
+}
+
+// recovered logs the number of dropped events once events can be published again.
+func (b *Bridge) recovered() {
+	if b.dropped > 1 {
+		log.Warnf("mqtt: dropped %d events", b.dropped)
+	}
+
+	b.dropped = 0
+}
+
+// options returns the client options based on the bridge configuration.
+func (b *Bridge) options() (*paho.ClientOptions, error) {
+	u, err := url.Parse(b.Uri)
+
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("mqtt: invalid broker uri")
+	}
+
+	opt := paho.NewClientOptions()
+	opt.AddBroker(b.Uri)
+	opt.SetClientID(b.ClientID)
+	opt.SetUsername(b.Username)
+	opt.SetPassword(b.Password)
+	opt.SetConnectTimeout(Timeout)
+	opt.SetWriteTimeout(Timeout)
+	opt.SetConnectRetry(true)
+	opt.SetConnectRetryInterval(RetryInterval)
+	opt.SetAutoReconnect(true)
+	opt.SetCleanSession(true)
+	opt.SetOrderMatters(false)
+
+	opt.SetConnectionLostHandler(func(c paho.Client, err error) {
+		log.Warnf("mqtt: connection lost (%s)", err)
+	})
+
+	opt.SetOnConnectHandler(func(c paho.Client) {
+		log.Debugf("mqtt: connected to %s", clean.Log(b.host()))
+	})
+
+	switch u.Scheme {
+	case "ssl", "tls", "mqtts", "wss":
+		conf := &tls.Config{InsecureSkipVerify: b.Insecure}
+
+		if b.CAFile != "" {
+			pem, err := os.ReadFile(b.CAFile)
+
+			if err != nil {
+				return nil, fmt.Errorf("mqtt: %s", err)
+			}
+
+			conf.RootCAs = x509.NewCertPool()
+
+			if !conf.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("mqtt: no valid certificates in %s", clean.Log(b.CAFile))
+			}
+		}
+
+		opt.SetTLSConfig(conf)
+	}
+
+	return opt, nil
+}
+
+// host returns the broker address for use in logs.
+func (b *Bridge) host() string {
+	if u, err := url.Parse(b.Uri); err == nil {
+		return u.Host
+	}
+
+	return ""
+}
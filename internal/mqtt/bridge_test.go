@@ -0,0 +1,172 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/leandro-lugaresi/hub"
+	broker "github.com/mochi-co/mqtt/server"
+	"github.com/mochi-co/mqtt/server/events"
+	"github.com/mochi-co/mqtt/server/listeners"
+	"github.com/mochi-co/mqtt/server/listeners/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/photoprism/photoprism/internal/event"
+)
+
+// testAddr returns a free local address.
+func testAddr(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	return addr
+}
+
+// testBroker starts an embedded broker on a free local port and returns its address
+// along with a channel that receives the published messages.
+func testBroker(t *testing.T) (string, chan events.Packet) {
+	addr := testAddr(t)
+	return addr, startBroker(t, addr)
+}
+
+// startBroker starts an embedded broker on the address and returns a channel that receives the published messages.
+func startBroker(t *testing.T, addr string) chan events.Packet {
+	server := broker.NewServer(nil)
+	require.NoError(t, server.AddListener(listeners.NewTCP("t1", addr), &listeners.Config{Auth: new(auth.Allow)}))
+
+	received := make(chan events.Packet, 10)
+
+	server.Events.OnMessage = func(cl events.Client, pk events.Packet) (events.Packet, error) {
+		received <- pk
+		return pk, nil
+	}
+
+	require.NoError(t, server.Serve())
+
+	t.Cleanup(func() {
+		_ = server.Close()
+	})
+
+	return received
+}
+
+func TestTopic(t *testing.T) {
+	assert.Equal(t, "photoprism/photos/updated", Topic("photoprism", "photos.updated"))
+	assert.Equal(t, "home/photoprism/index/file", Topic("/home/photoprism/", "index.file"))
+	assert.Equal(t, "notify/info", Topic("", "notify.info"))
+}
+
+func TestBridge_Start(t *testing.T) {
+	t.Run("InvalidUri", func(t *testing.T) {
+		b := &Bridge{Uri: "foo", ClientID: "test"}
+		assert.Error(t, b.Start())
+	})
+	t.Run("InvalidQoS", func(t *testing.T) {
+		b := &Bridge{Uri: "tcp://127.0.0.1:1883", ClientID: "test", QoS: 3}
+		assert.Error(t, b.Start())
+	})
+	t.Run("Publish", func(t *testing.T) {
+		addr, received := testBroker(t)
+
+		for _, qos := range []byte{0, 1} {
+			b := &Bridge{Uri: "tcp://" + addr, ClientID: "photoprism-test", Prefix: "test", QoS: qos}
+
+			require.NoError(t, b.Start())
+			assert.Error(t, b.Start())
+
+			event.Publish("photos.updated", event.Data{"uid": "pqbcf5j446s0futy"})
+			event.Publish("session.created", event.Data{"uid": "sess6ey1ykya3qmw"})
+
+			select {
+			case pk := <-received:
+				assert.Equal(t, "test/photos/updated", pk.TopicName)
+				assert.Equal(t, qos, pk.FixedHeader.Qos)
+
+				var data event.Data
+				require.NoError(t, json.Unmarshal(pk.Payload, &data))
+				assert.Equal(t, "pqbcf5j446s0futy", data["uid"])
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout")
+			}
+
+			b.Stop()
+			b.Stop()
+
+			// Session events are not forwarded.
+			select {
+			case pk := <-received:
+				t.Fatalf("unexpected message %s", pk.TopicName)
+			default:
+			}
+		}
+	})
+	t.Run("Retry", func(t *testing.T) {
+		timeout, interval := Timeout, RetryInterval
+		Timeout, RetryInterval = 500*time.Millisecond, 100*time.Millisecond
+		defer func() { Timeout, RetryInterval = timeout, interval }()
+
+		// The bridge starts even if the broker is not reachable yet.
+		addr := testAddr(t)
+		b := &Bridge{Uri: "tcp://" + addr, ClientID: "photoprism-retry", Prefix: "test", QoS: 1}
+
+		require.NoError(t, b.Start())
+		defer b.Stop()
+
+		received := startBroker(t, addr)
+
+		deadline := time.After(10 * time.Second)
+
+		for {
+			event.Publish("photos.updated", event.Data{"uid": "pqbcf5j446s0futy"})
+
+			select {
+			case pk := <-received:
+				assert.Equal(t, "test/photos/updated", pk.TopicName)
+				return
+			case <-time.After(100 * time.Millisecond):
+			case <-deadline:
+				t.Fatal("timeout")
+			}
+		}
+	})
+}
+
+func TestBridge_Forward(t *testing.T) {
+	size := QueueSize
+	QueueSize = 1
+	defer func() { QueueSize = size }()
+
+	addr, _ := testBroker(t)
+	b := &Bridge{Uri: "tcp://" + addr, ClientID: "photoprism-forward"}
+
+	opt, err := b.options()
+	require.NoError(t, err)
+
+	b.client = paho.NewClient(opt)
+	require.True(t, b.client.Connect().WaitTimeout(Timeout))
+	defer b.client.Disconnect(0)
+
+	// Events are dropped instead of blocking if the queue is full.
+	receiver := make(chan hub.Message, 3)
+	queue := make(chan hub.Message, QueueSize)
+
+	receiver <- hub.Message{Name: "photos.updated"}
+	receiver <- hub.Message{Name: "photos.updated"}
+	receiver <- hub.Message{Name: "photos.deleted"}
+	close(receiver)
+
+	b.wg.Add(1)
+	b.forward(hub.Subscription{Receiver: receiver}, queue)
+
+	assert.Equal(t, 2, b.dropped)
+	assert.Equal(t, "photos.updated", (<-queue).Name)
+
+	_, ok := <-queue
+	assert.False(t, ok)
+}
@@ -0,0 +1,82 @@
+/*
+Package mqtt forwards published events to an MQTT broker.
+
+Copyright (c) 2018 - 2022 PhotoPrism UG. All rights reserved.
+
+	This program is free software: you can redistribute it and/or modify
+	it under Version 3 of the GNU Affero General Public License (the "AGPL"):
+	<https://docs.photoprism.app/license/agpl>
+
+	This program is distributed in the hope that it will be useful,
+	but WITHOUT ANY WARRANTY; without even the implied warranty of
+	MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+	GNU Affero General Public License for more details.
+
+	The AGPL is supplemented by our Trademark and Brand Guidelines,
+	which describe how our Brand Assets may be used:
+	<https://photoprism.app/trademark>
+
+Feel free to send an email to hello@photoprism.app if you have questions,
+want to support our work, or just want to say hello.
+
+Additional information can be found in our Developer Guide:
+<https://docs.photoprism.app/developer-guide/>
+*/
+package mqtt
+
+import (
+	"strings"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/event"
+)
+
+var log = event.Log
+
+// DefaultPrefix is the default topic prefix.
+const DefaultPrefix = "photoprism"
+
+// DefaultClientID is the default MQTT client id.
+const DefaultClientID = "photoprism"
+
+// Timeout specifies the time limit for connecting to the broker, publishing an event, and disconnecting.
+var Timeout = 10 * time.Second
+
+// RetryInterval specifies how long to wait before trying again if the broker is not reachable.
+var RetryInterval = 30 * time.Second
+
+// QueueSize specifies how many events can wait for publishing before new events are dropped.
+var QueueSize = 100
+
+// Topics specifies the events that are forwarded to the broker.
+var Topics = []string{
+	"log.fatal",
+	"log.error",
+	"log.warning",
+	"notify.*",
+	"index.*",
+	"upload.*",
+	"import.*",
+	"config.*",
+	"count.*",
+	"photos.*",
+	"cameras.*",
+	"lenses.*",
+	"countries.*",
+	"albums.*",
+	"labels.*",
+	"subjects.*",
+	"people.*",
+	"sync.*",
+}
+
+// Topic returns the MQTT topic for an event, e.g. "photoprism/photos/updated" for "photos.updated".
+func Topic(prefix, name string) string {
+	name = strings.ReplaceAll(name, event.TopicSep, "/")
+
+	if prefix = strings.Trim(prefix, "/"); prefix == "" {
+		return name
+	}
+
+	return prefix + "/" + name
+}
@@ -29,6 +29,11 @@ func (b *Activity) Start() error {
 		return errors.New("still running")
 	}
 
+	// Other workers must not run while the index is restored from a backup.
+	if b != &RestoreWorker && RestoreWorker.Running() {
+		return errors.New("backup is being restored")
+	}
+
 	if b.busy {
 		return errors.New("already running")
 	}
@@ -36,6 +36,17 @@ func TestActivity_Start(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		b := Activity{}
 
+		assert.Nil(t, b.Start())
+	})
+	t.Run("restoring", func(t *testing.T) {
+		assert.Nil(t, RestoreWorker.Start())
+
+		b := Activity{}
+
+		assert.Error(t, b.Start(), "backup is being restored")
+
+		RestoreWorker.Stop()
+
 		assert.Nil(t, b.Start())
 	})
 }
@@ -0,0 +1,88 @@
+package oidc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Claims represents the claims of a verified ID token.
+type Claims map[string]interface{}
+
+// String returns the string value of a claim, or an empty string if it is missing.
+func (c Claims) String(name string) string {
+	switch v := c[name].(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	case bool:
+		return fmt.Sprintf("%t", v)
+	default:
+		return ""
+	}
+}
+
+// Strings returns the values of a claim that may contain a single string or a list of strings.
+func (c Claims) Strings(name string) []string {
+	switch v := c[name].(type) {
+	case string:
+		return strings.Fields(strings.ReplaceAll(v, ",", " "))
+	case []interface{}:
+		result := make([]string, 0, len(v))
+
+		for i := range v {
+			if s, ok := v[i].(string); ok && s != "" {
+				result = append(result, s)
+			}
+		}
+
+		return result
+	default:
+		return []string{}
+	}
+}
+
+// Time returns the time value of a numeric date claim.
+func (c Claims) Time(name string) time.Time {
+	if v, ok := c[name].(float64); ok {
+		return time.Unix(int64(v), 0).UTC()
+	}
+
+	return time.Time{}
+}
+
+// Subject returns the unique user identifier at the issuer.
+func (c Claims) Subject() string {
+	return c.String("sub")
+}
+
+// Issuer returns the issuer identifier.
+func (c Claims) Issuer() string {
+	return c.String("iss")
+}
+
+// Audience returns the audience the ID token is intended for.
+func (c Claims) Audience() []string {
+	return c.Strings("aud")
+}
+
+// Nonce returns the nonce value of the authorization request.
+func (c Claims) Nonce() string {
+	return c.String("nonce")
+}
+
+// Username returns the preferred username.
+func (c Claims) Username() string {
+	return c.String("preferred_username")
+}
+
+// Email returns the email address of the user.
+func (c Claims) Email() string {
+	return c.String("email")
+}
+
+// Name returns the full name of the user.
+func (c Claims) Name() string {
+	return c.String("name")
+}
@@ -0,0 +1,35 @@
+package oidc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaims(t *testing.T) {
+	c := Claims{
+		"sub":                "123",
+		"iss":                "https://example.com",
+		"aud":                []interface{}{"photoprism", "other"},
+		"preferred_username": "jane",
+		"email":              "jane@example.com",
+		"name":               "Jane Doe",
+		"role":               "admin, visitor",
+		"exp":                float64(1665403200),
+		"count":              float64(42),
+	}
+
+	assert.Equal(t, "123", c.Subject())
+	assert.Equal(t, "https://example.com", c.Issuer())
+	assert.Equal(t, []string{"photoprism", "other"}, c.Audience())
+	assert.Equal(t, "jane", c.Username())
+	assert.Equal(t, "jane@example.com", c.Email())
+	assert.Equal(t, "Jane Doe", c.Name())
+	assert.Equal(t, "", c.Nonce())
+	assert.Equal(t, "42", c.String("count"))
+	assert.Equal(t, []string{"admin", "visitor"}, c.Strings("role"))
+	assert.Equal(t, []string{}, c.Strings("groups"))
+	assert.Equal(t, time.Date(2022, 10, 10, 12, 0, 0, 0, time.UTC), c.Time("exp"))
+	assert.True(t, c.Time("iat").IsZero())
+}
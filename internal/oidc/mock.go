@@ -0,0 +1,202 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/photoprism/photoprism/pkg/rnd"
+)
+
+// mockKeyID is the key id of the signing key used by the mock provider.
+const mockKeyID = "mock"
+
+// MockIssuer is a minimal OpenID Connect identity provider for use in tests.
+type MockIssuer struct {
+	*httptest.Server
+	ClientID     string
+	ClientSecret string
+	Claims       Claims
+	key          *rsa.PrivateKey
+	codes        map[string]mockCode
+	mutex        sync.Mutex
+}
+
+// tokenResponse represents the values of a token endpoint response.
+type tokenResponse struct {
+	AccessToken string `json:"access_token,omitempty"`
+	TokenType   string `json:"token_type,omitempty"`
+	IdToken     string `json:"id_token,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// mockCode represents an authorization code issued by the mock provider.
+type mockCode struct {
+	RedirectUri string
+	Nonce       string
+	Challenge   string
+}
+
+// NewMockIssuer starts a new mock identity provider, which authenticates users with the
+// specified claims without asking for credentials. Call Close() when it is no longer needed.
+func NewMockIssuer(clientId, clientSecret string, claims Claims) *MockIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+
+	if err != nil {
+		panic(err)
+	}
+
+	m := &MockIssuer{
+		ClientID:     clientId,
+		ClientSecret: clientSecret,
+		Claims:       claims,
+		key:          key,
+		codes:        make(map[string]mockCode),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(DiscoveryPath, m.discovery)
+	mux.HandleFunc("/authorize", m.authorize)
+	mux.HandleFunc("/token", m.token)
+	mux.HandleFunc("/keys", m.keys)
+
+	m.Server = httptest.NewServer(mux)
+
+	return m
+}
+
+// discovery serves the provider configuration document.
+func (m *MockIssuer) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, Discovery{
+		Issuer:                m.URL,
+		AuthorizationEndpoint: m.URL + "/authorize",
+		TokenEndpoint:         m.URL + "/token",
+		JwksUri:               m.URL + "/keys",
+		ScopesSupported:       []string{"openid", "email", "profile"},
+		CodeChallengeMethods:  []string{ChallengeMethod},
+	})
+}
+
+// authorize immediately redirects back to the client with a new authorization code.
+func (m *MockIssuer) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	if q.Get("client_id") != m.ClientID || q.Get("response_type") != "code" || q.Get("code_challenge_method") != ChallengeMethod {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	code := rnd.Base62(24)
+
+	m.mutex.Lock()
+	m.codes[code] = mockCode{RedirectUri: q.Get("redirect_uri"), Nonce: q.Get("nonce"), Challenge: q.Get("code_challenge")}
+	m.mutex.Unlock()
+
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+
+	if err != nil {
+		http.Error(w, "invalid redirect uri", http.StatusBadRequest)
+		return
+	}
+
+	v := redirect.Query()
+	v.Set("code", code)
+	v.Set("state", q.Get("state"))
+	redirect.RawQuery = v.Encode()
+
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// token redeems an authorization code and returns a signed ID token.
+func (m *MockIssuer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, tokenResponse{Error: "invalid_request"})
+		return
+	}
+
+	if id, secret, ok := r.BasicAuth(); m.ClientSecret != "" && (!ok || id != m.ClientID || secret != m.ClientSecret) {
+		writeJSON(w, http.StatusUnauthorized, tokenResponse{Error: "invalid_client"})
+		return
+	}
+
+	m.mutex.Lock()
+	c, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mutex.Unlock()
+
+	if !ok || c.RedirectUri != r.PostForm.Get("redirect_uri") || c.Challenge != Challenge(r.PostForm.Get("code_verifier")) {
+		writeJSON(w, http.StatusBadRequest, tokenResponse{Error: "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+
+	claims := Claims{
+		"iss":   m.URL,
+		"aud":   m.ClientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"nonce": c.Nonce,
+	}
+
+	for k, v := range m.Claims {
+		claims[k] = v
+	}
+
+	idToken, err := m.IdToken(claims)
+
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, tokenResponse{Error: "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tokenResponse{AccessToken: rnd.Base62(32), TokenType: "Bearer", IdToken: idToken})
+}
+
+// keys serves the public signing key.
+func (m *MockIssuer) keys(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{
+		Key:       &m.key.PublicKey,
+		KeyID:     mockKeyID,
+		Algorithm: string(jose.RS256),
+		Use:       "sig",
+	}}})
+}
+
+// IdToken returns an ID token with the specified claims signed by the mock provider.
+func (m *MockIssuer) IdToken(claims Claims) (string, error) {
+	payload, err := json.Marshal(claims)
+
+	if err != nil {
+		return "", err
+	}
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: m.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", mockKeyID))
+
+	if err != nil {
+		return "", err
+	}
+
+	sig, err := signer.Sign(payload)
+
+	if err != nil {
+		return "", err
+	}
+
+	return sig.CompactSerialize()
+}
+
+// writeJSON sends a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,22 @@
+package oidc
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+
+	"github.com/photoprism/photoprism/pkg/rnd"
+)
+
+// ChallengeMethod is the PKCE code challenge method used for authorization requests.
+const ChallengeMethod = "S256"
+
+// NewVerifier returns a random PKCE code verifier.
+func NewVerifier() string {
+	return rnd.Base62(64)
+}
+
+// Challenge returns the S256 code challenge for the verifier.
+func Challenge(verifier string) string {
+	h := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
@@ -0,0 +1,17 @@
+package oidc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewVerifier(t *testing.T) {
+	assert.Len(t, NewVerifier(), 64)
+	assert.NotEqual(t, NewVerifier(), NewVerifier())
+}
+
+func TestChallenge(t *testing.T) {
+	// Example from RFC 7636, Appendix B.
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", Challenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
@@ -0,0 +1,146 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// Timeout specifies the time limit for requests to the identity provider.
+var Timeout = 30 * time.Second
+
+// Provider represents an OpenID Connect identity provider and the client credentials used with it.
+type Provider struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectUri  string
+	Scopes       []string
+	provider     *gooidc.Provider
+	verifier     *gooidc.IDTokenVerifier
+	client       *http.Client
+}
+
+// NewProvider fetches the configuration document of the issuer and returns a new Provider.
+// The issuer must exactly match the value in the configuration document.
+func NewProvider(ctx context.Context, issuer, clientId, clientSecret, redirectUri string, scopes []string) (*Provider, error) {
+	issuer = strings.TrimSpace(issuer)
+
+	if issuer == "" {
+		return nil, errors.New("issuer is missing")
+	} else if clientId == "" {
+		return nil, errors.New("client id is missing")
+	}
+
+	p := &Provider{
+		Issuer:       issuer,
+		ClientID:     clientId,
+		ClientSecret: clientSecret,
+		RedirectUri:  redirectUri,
+		Scopes:       scopes,
+		client:       &http.Client{Timeout: Timeout},
+	}
+
+	provider, err := gooidc.NewProvider(p.context(ctx), issuer)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover issuer %s (%s)", clean.Log(issuer), err)
+	}
+
+	p.provider = provider
+	p.verifier = provider.Verifier(&gooidc.Config{ClientID: clientId})
+
+	return p, nil
+}
+
+// context returns a new context that uses the HTTP client of the provider.
+func (p *Provider) context(ctx context.Context) context.Context {
+	return gooidc.ClientContext(ctx, p.client)
+}
+
+// config returns the OAuth 2.0 client configuration.
+func (p *Provider) config() *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     p.ClientID,
+		ClientSecret: p.ClientSecret,
+		Endpoint:     p.provider.Endpoint(),
+		RedirectURL:  p.RedirectUri,
+		Scopes:       p.Scopes,
+	}
+}
+
+// AuthUrl returns the URL of the authorization endpoint to which the user should be redirected.
+func (p *Provider) AuthUrl(state, nonce, verifier string) string {
+	return p.config().AuthCodeURL(state,
+		gooidc.Nonce(nonce),
+		oauth2.SetAuthURLParam("code_challenge", Challenge(verifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", ChallengeMethod),
+	)
+}
+
+// Exchange redeems the authorization code and returns the claims of the verified ID token.
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (Claims, error) {
+	if code == "" {
+		return nil, errors.New("authorization code is missing")
+	}
+
+	token, err := p.config().Exchange(p.context(ctx), code, oauth2.SetAuthURLParam("code_verifier", verifier))
+
+	if err != nil {
+		var retrieveErr *oauth2.RetrieveError
+
+		if errors.As(err, &retrieveErr) {
+			var result tokenResponse
+
+			if json.Unmarshal(retrieveErr.Body, &result) == nil && result.Error != "" {
+				return nil, fmt.Errorf("token request failed (%s)", clean.Log(result.Error))
+			}
+
+			return nil, fmt.Errorf("token request failed (status %d)", retrieveErr.Response.StatusCode)
+		}
+
+		return nil, fmt.Errorf("token request failed (%s)", err)
+	}
+
+	idToken, ok := token.Extra("id_token").(string)
+
+	if !ok || idToken == "" {
+		return nil, errors.New("token response contains no id token")
+	}
+
+	return p.Verify(ctx, idToken, nonce)
+}
+
+// Verify checks the signature and the standard claims of an ID token and returns its claims.
+func (p *Provider) Verify(ctx context.Context, rawIdToken, nonce string) (Claims, error) {
+	idToken, err := p.verifier.Verify(p.context(ctx), rawIdToken)
+
+	var expired *gooidc.TokenExpiredError
+
+	if errors.As(err, &expired) {
+		return nil, errors.New("id token has expired")
+	} else if err != nil {
+		return nil, fmt.Errorf("invalid id token (%s)", err)
+	} else if nonce != "" && idToken.Nonce != nonce {
+		return nil, errors.New("id token nonce does not match")
+	} else if idToken.Subject == "" {
+		return nil, errors.New("id token subject is missing")
+	}
+
+	claims := Claims{}
+
+	if err = idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
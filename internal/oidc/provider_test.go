@@ -0,0 +1,196 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// authorize requests an authorization code from the provider and returns it along with the state.
+func authorize(t *testing.T, authUrl string) (code, state string) {
+	client := &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	resp, err := client.Get(authUrl)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("unexpected status %d", resp.StatusCode)
+	}
+
+	u, err := url.Parse(resp.Header.Get("Location"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return u.Query().Get("code"), u.Query().Get("state")
+}
+
+func TestNewProvider(t *testing.T) {
+	issuer := NewMockIssuer("photoprism", "secret", Claims{"sub": "123"})
+	defer issuer.Close()
+
+	t.Run("Success", func(t *testing.T) {
+		p, err := NewProvider(context.Background(), issuer.URL, "photoprism", "secret", "http://localhost/redirect", []string{"openid"})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, issuer.URL, p.Issuer)
+		assert.True(t, strings.HasPrefix(p.AuthUrl("state", "nonce", NewVerifier()), issuer.URL+"/authorize?"))
+	})
+	t.Run("IssuerMismatch", func(t *testing.T) {
+		_, err := NewProvider(context.Background(), issuer.URL+"/", "photoprism", "secret", "http://localhost/redirect", []string{"openid"})
+		assert.Error(t, err)
+	})
+	t.Run("NoIssuer", func(t *testing.T) {
+		_, err := NewProvider(context.Background(), "", "photoprism", "", "", nil)
+		assert.EqualError(t, err, "issuer is missing")
+	})
+	t.Run("NoClient", func(t *testing.T) {
+		_, err := NewProvider(context.Background(), issuer.URL, "", "", "", nil)
+		assert.EqualError(t, err, "client id is missing")
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := NewProvider(context.Background(), issuer.URL+"/foo", "photoprism", "", "", nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	issuer := NewMockIssuer("photoprism", "secret", Claims{"sub": "123", "preferred_username": "jane", "groups": []string{"admin"}})
+	defer issuer.Close()
+
+	p, err := NewProvider(context.Background(), issuer.URL, "photoprism", "secret", "http://localhost/redirect", []string{"openid", "profile"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		r := NewAuthRequest()
+		authUrl := p.AuthUrl(r.State, r.Nonce, r.Verifier)
+
+		assert.Contains(t, authUrl, "code_challenge_method=S256")
+		assert.Contains(t, authUrl, "scope=openid+profile")
+
+		code, state := authorize(t, authUrl)
+		assert.Equal(t, r.State, state)
+
+		claims, err := p.Exchange(context.Background(), code, r.Verifier, r.Nonce)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "123", claims.Subject())
+		assert.Equal(t, "jane", claims.Username())
+		assert.Equal(t, []string{"admin"}, claims.Strings("groups"))
+
+		// Codes can only be used once.
+		_, err = p.Exchange(context.Background(), code, r.Verifier, r.Nonce)
+		assert.Error(t, err)
+	})
+	t.Run("WrongVerifier", func(t *testing.T) {
+		r := NewAuthRequest()
+		code, _ := authorize(t, p.AuthUrl(r.State, r.Nonce, r.Verifier))
+		_, err := p.Exchange(context.Background(), code, NewVerifier(), r.Nonce)
+		assert.EqualError(t, err, "token request failed (invalid_grant)")
+	})
+	t.Run("WrongNonce", func(t *testing.T) {
+		r := NewAuthRequest()
+		code, _ := authorize(t, p.AuthUrl(r.State, r.Nonce, r.Verifier))
+		_, err := p.Exchange(context.Background(), code, r.Verifier, "foo")
+		assert.EqualError(t, err, "id token nonce does not match")
+	})
+	t.Run("WrongSecret", func(t *testing.T) {
+		wrong, err := NewProvider(context.Background(), issuer.URL, "photoprism", "wrong", "http://localhost/redirect", []string{"openid"})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := NewAuthRequest()
+		code, _ := authorize(t, wrong.AuthUrl(r.State, r.Nonce, r.Verifier))
+		_, err = wrong.Exchange(context.Background(), code, r.Verifier, r.Nonce)
+		assert.EqualError(t, err, "token request failed (invalid_client)")
+	})
+}
+
+func TestProvider_Verify(t *testing.T) {
+	issuer := NewMockIssuer("photoprism", "", Claims{"sub": "123"})
+	defer issuer.Close()
+
+	p, err := NewProvider(context.Background(), issuer.URL, "photoprism", "", "http://localhost/redirect", []string{"openid"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+
+	claims := func(values Claims) Claims {
+		result := Claims{"iss": issuer.URL, "aud": "photoprism", "sub": "123", "iat": now.Unix(), "exp": now.Add(time.Hour).Unix()}
+
+		for k, v := range values {
+			result[k] = v
+		}
+
+		return result
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		token, _ := issuer.IdToken(claims(Claims{"aud": []string{"other", "photoprism"}}))
+		result, err := p.Verify(context.Background(), token, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "123", result.Subject())
+	})
+	t.Run("Expired", func(t *testing.T) {
+		token, _ := issuer.IdToken(claims(Claims{"exp": now.Add(-time.Hour).Unix()}))
+		_, err := p.Verify(context.Background(), token, "")
+		assert.EqualError(t, err, "id token has expired")
+	})
+	t.Run("WrongAudience", func(t *testing.T) {
+		token, _ := issuer.IdToken(claims(Claims{"aud": "other"}))
+		_, err := p.Verify(context.Background(), token, "")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "expected audience")
+	})
+	t.Run("WrongIssuer", func(t *testing.T) {
+		token, _ := issuer.IdToken(claims(Claims{"iss": "https://example.com"}))
+		_, err := p.Verify(context.Background(), token, "")
+		assert.Error(t, err)
+	})
+	t.Run("NoSubject", func(t *testing.T) {
+		token, _ := issuer.IdToken(claims(Claims{"sub": ""}))
+		_, err := p.Verify(context.Background(), token, "")
+		assert.EqualError(t, err, "id token subject is missing")
+	})
+	t.Run("InvalidSignature", func(t *testing.T) {
+		other := NewMockIssuer("photoprism", "", nil)
+		defer other.Close()
+
+		token, _ := other.IdToken(claims(nil))
+		_, err := p.Verify(context.Background(), token, "")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to verify signature")
+	})
+	t.Run("Malformed", func(t *testing.T) {
+		_, err := p.Verify(context.Background(), "foo.bar", "")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid id token")
+	})
+}
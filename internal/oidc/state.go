@@ -0,0 +1,99 @@
+package oidc
+
+import (
+	"sync"
+	"time"
+
+	gc "github.com/patrickmn/go-cache"
+
+	"github.com/photoprism/photoprism/pkg/rnd"
+)
+
+// StateExpires specifies how long users have to complete the login at the identity provider.
+var StateExpires = 10 * time.Minute
+
+// states caches pending authorization requests by state.
+var states = gc.New(StateExpires, time.Minute)
+
+// AuthRequest represents a pending authorization request.
+type AuthRequest struct {
+	State    string
+	Nonce    string
+	Verifier string
+}
+
+// NewAuthRequest creates and remembers a new authorization request.
+func NewAuthRequest() AuthRequest {
+	r := AuthRequest{
+		State:    rnd.Base62(32),
+		Nonce:    rnd.Base62(32),
+		Verifier: NewVerifier(),
+	}
+
+	states.SetDefault(r.State, r)
+
+	return r
+}
+
+// FindAuthRequest returns and removes the pending authorization request with the specified state.
+func FindAuthRequest(state string) (r AuthRequest, ok bool) {
+	if state == "" {
+		return r, false
+	}
+
+	if cached, found := states.Get(state); !found {
+		return r, false
+	} else if r, ok = cached.(AuthRequest); ok {
+		states.Delete(state)
+	}
+
+	return r, ok
+}
+
+// logins caches logins that must be completed with a verification code by token.
+var logins = gc.New(StateExpires, time.Minute)
+var loginMutex = sync.Mutex{}
+
+// PendingLogin represents a login that must be completed with a verification code,
+// because the user has enabled two-factor authentication.
+type PendingLogin struct {
+	Token    string
+	ClientIP string
+	UserUID  string
+	Issuer   string
+	Subject  string
+}
+
+// NewPendingLogin creates and remembers a new pending login.
+func NewPendingLogin(clientIp, userUid, issuer, subject string) PendingLogin {
+	l := PendingLogin{
+		Token:    rnd.Base62(32),
+		ClientIP: clientIp,
+		UserUID:  userUid,
+		Issuer:   issuer,
+		Subject:  subject,
+	}
+
+	logins.SetDefault(l.Token, l)
+
+	return l
+}
+
+// TakePendingLogin returns and removes the pending login with the specified token,
+// so that it can only be used once, even if there are concurrent requests.
+func TakePendingLogin(token string) (l PendingLogin, ok bool) {
+	if token == "" {
+		return l, false
+	}
+
+	loginMutex.Lock()
+	defer loginMutex.Unlock()
+
+	if cached, found := logins.Get(token); !found {
+		return l, false
+	} else if l, ok = cached.(PendingLogin); ok {
+		logins.Delete(token)
+	}
+
+	return l, ok
+}
@@ -0,0 +1,42 @@
+package oidc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindAuthRequest(t *testing.T) {
+	r := NewAuthRequest()
+
+	assert.Len(t, r.State, 32)
+	assert.NotEqual(t, r.State, r.Nonce)
+
+	found, ok := FindAuthRequest(r.State)
+	assert.True(t, ok)
+	assert.Equal(t, r, found)
+
+	// Requests can only be used once.
+	_, ok = FindAuthRequest(r.State)
+	assert.False(t, ok)
+
+	_, ok = FindAuthRequest("")
+	assert.False(t, ok)
+}
+
+func TestTakePendingLogin(t *testing.T) {
+	l := NewPendingLogin("127.0.0.1", "uqxetse3cy5eo9z2", "https://example.com", "jane")
+
+	assert.Len(t, l.Token, 32)
+
+	found, ok := TakePendingLogin(l.Token)
+	assert.True(t, ok)
+	assert.Equal(t, l, found)
+
+	// Pending logins can only be used once.
+	_, ok = TakePendingLogin(l.Token)
+	assert.False(t, ok)
+
+	_, ok = TakePendingLogin("")
+	assert.False(t, ok)
+}
@@ -0,0 +1,189 @@
+package photoprism
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/dustin/go-humanize/english"
+
+	"github.com/photoprism/photoprism/internal/backup"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// IndexBackupPath returns the default index backup path for the configured database driver.
+func IndexBackupPath() string {
+	c := Config()
+
+	return filepath.Join(c.BackupPath(), c.DatabaseDriver())
+}
+
+// IndexBackupFile returns the default index backup filename for the current date.
+func IndexBackupFile(indexPath string) string {
+	return filepath.Join(indexPath, time.Now().UTC().Format("2006-01-02")+".sql")
+}
+
+// BackupIndex writes an SQL dump of the index database to out.
+func BackupIndex(out io.Writer) error {
+	c := Config()
+
+	var cmd *exec.Cmd
+
+	switch c.DatabaseDriver() {
+	case config.MySQL, config.MariaDB:
+		cmd = exec.Command(
+			c.MysqldumpBin(),
+			"--protocol", "tcp",
+			"-h", c.DatabaseHost(),
+			"-P", c.DatabasePortString(),
+			"-u", c.DatabaseUser(),
+			"-p"+c.DatabasePassword(),
+			c.DatabaseName(),
+		)
+	case config.Postgres:
+		cmd = exec.Command(
+			c.PgDumpBin(),
+			"-h", c.DatabaseHost(),
+			"-p", c.DatabasePortString(),
+			"-U", c.DatabaseUser(),
+			"--clean",
+			"--if-exists",
+			"--no-owner",
+			c.DatabaseName(),
+		)
+		cmd.Env = append(os.Environ(), "PGPASSWORD="+c.DatabasePassword())
+	case config.SQLite3:
+		cmd = exec.Command(
+			c.SqliteBin(),
+			c.DatabaseDsn(),
+			".dump",
+		)
+	default:
+		return fmt.Errorf("unsupported database type: %s", c.DatabaseDriver())
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stdout = out
+	cmd.Stderr = &stderr
+
+	log.Trace(cmd.String())
+
+	if err := cmd.Run(); err != nil {
+		if stderr.String() != "" {
+			return errors.New(stderr.String())
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// BackupIndexFile writes an SQL dump of the index database to the specified file.
+func BackupIndexFile(fileName string, force bool) error {
+	if _, err := os.Stat(fileName); err == nil && !force {
+		return fmt.Errorf("SQL dump already exists: %s", fileName)
+	} else if err == nil {
+		log.Warnf("replacing existing SQL dump")
+	}
+
+	if dir := filepath.Dir(fileName); dir != "." {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+
+	log.Infof("writing SQL dump to %s", clean.Log(fileName))
+
+	var out bytes.Buffer
+
+	if err := BackupIndex(&out); err != nil {
+		return err
+	}
+
+	return os.WriteFile(fileName, out.Bytes(), os.ModePerm)
+}
+
+// BackupArchive creates a backup archive with an SQL dump of the index, album and sidecar YAML files,
+// and the settings, and then removes outdated archives based on the configured retention policy.
+func BackupArchive() (a backup.Archive, err error) {
+	c := Config()
+	dir := c.BackupArchivePath()
+
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return a, err
+	}
+
+	w, err := backup.NewWriter(dir, c.BackupCompression(), time.Now())
+
+	if err != nil {
+		return a, err
+	}
+
+	defer func() {
+		if err != nil {
+			w.Abort()
+		}
+	}()
+
+	log.Infof("backup: creating archive %s", clean.Log(w.Name()))
+
+	w.Manifest.Driver = c.DatabaseDriver()
+	w.Manifest.AppVersion = c.Version()
+
+	// Add SQL dump of the index.
+	f, err := w.Create(backup.IndexFile)
+
+	if err != nil {
+		return a, err
+	} else if err = BackupIndex(f); err != nil {
+		f.Close()
+		return a, err
+	} else if err = f.Close(); err != nil {
+		return a, err
+	}
+
+	// Add album YAML files.
+	if _, err = BackupAlbums(c.AlbumsPath(), true); err != nil {
+		return a, err
+	} else if _, err = w.AddDir(backup.AlbumsDir, c.AlbumsPath(), fs.ExtYAML); err != nil {
+		return a, err
+	}
+
+	// Add sidecar YAML files.
+	if n, err := w.AddDir(backup.SidecarDir, c.SidecarPath(), fs.ExtYAML); err != nil {
+		return a, err
+	} else {
+		log.Debugf("backup: added %s", english.Plural(n, "sidecar file", "sidecar files"))
+	}
+
+	// Add settings.
+	if fileName := c.SettingsYaml(); fs.FileExists(fileName) {
+		if err = w.AddFile(path.Join(backup.SettingsDir, filepath.Base(fileName)), fileName); err != nil {
+			return a, err
+		}
+	}
+
+	if a, err = w.Commit(); err != nil {
+		return a, err
+	}
+
+	log.Infof("backup: archive %s created", clean.Log(a.Name))
+
+	// Remove outdated archives.
+	if removed, err := backup.Prune(dir, c.BackupRetention()); err != nil {
+		log.Warnf("backup: %s (remove outdated archives)", err)
+	} else if len(removed) > 0 {
+		log.Infof("backup: removed %s", english.Plural(len(removed), "outdated archive", "outdated archives"))
+	}
+
+	return a, nil
+}
@@ -0,0 +1,256 @@
+package photoprism
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// LibraryFormat identifies portable library archives.
+const LibraryFormat = "photoprism-library"
+
+// LibraryVersion is the version of the library archive format.
+const LibraryVersion = 1
+
+// Library archive entry names.
+const (
+	LibraryManifest  = "manifest.json"
+	LibraryLabels    = "labels.yml"
+	LibrarySubjects  = "people/subjects.yml"
+	LibraryMarkers   = "people/markers"
+	LibraryLinks     = "links.yml"
+	LibraryAlbums    = "albums"
+	LibraryOriginals = "originals"
+	LibrarySidecar   = "sidecar"
+)
+
+// LibraryBatchSize is the maximum number of photos or markers read from the database at once.
+var LibraryBatchSize = 1000
+
+// LibraryMaxDocSize is the maximum size of a label, people, marker, album, or link document in a library archive.
+var LibraryMaxDocSize int64 = 64 * 1024 * 1024
+
+// LibraryMaxDocsSize is the maximum total size of all documents in a library archive, since they are kept in memory
+// until the original files have been indexed.
+var LibraryMaxDocsSize int64 = 512 * 1024 * 1024
+
+// LibraryFile represents an original file in a library archive.
+type LibraryFile struct {
+	Name string `json:"name"`
+	Hash string `json:"hash"`
+	Size int64  `json:"size"`
+}
+
+// LibraryPhoto represents a photo in a library archive. The SHA1 hash of its primary file is used to find
+// the photo after importing, since its UID may change if it conflicts with an existing photo.
+type LibraryPhoto struct {
+	UID   string        `json:"uid"`
+	Path  string        `json:"path"`
+	Name  string        `json:"name"`
+	Hash  string        `json:"hash"`
+	Files []LibraryFile `json:"files"`
+}
+
+// Validate returns an error if the photo path or the name of one of its files is absolute
+// or refers to a parent directory, so that files cannot be extracted outside the library.
+func (p LibraryPhoto) Validate() error {
+	if !librarySafeName(p.Path, true) {
+		return fmt.Errorf("invalid photo path %s", clean.Log(p.Path))
+	} else if !librarySafeName(p.Name, false) || strings.Contains(p.Name, "/") {
+		return fmt.Errorf("invalid photo name %s", clean.Log(p.Name))
+	}
+
+	for _, f := range p.Files {
+		if !librarySafeName(f.Name, false) {
+			return fmt.Errorf("invalid file name %s", clean.Log(f.Name))
+		}
+	}
+
+	return nil
+}
+
+// SidecarName returns the archive entry name of the photo's sidecar YAML file.
+func (p LibraryPhoto) SidecarName() string {
+	return path.Join(LibrarySidecar, p.Path, p.Name+".yml")
+}
+
+// LibraryArchive describes the contents of a portable library archive.
+type LibraryArchive struct {
+	Format     string         `json:"format"`
+	Version    int            `json:"version"`
+	CreatedAt  time.Time      `json:"createdAt"`
+	AppVersion string         `json:"appVersion,omitempty"`
+	Photos     []LibraryPhoto `json:"photos"`
+}
+
+// LibraryPhotoLabel represents a label assigned to a photo.
+type LibraryPhotoLabel struct {
+	PhotoUID    string `yaml:"PhotoUID"`
+	LabelUID    string `yaml:"LabelUID"`
+	Src         string `yaml:"Src,omitempty"`
+	Uncertainty int    `yaml:"Uncertainty"`
+}
+
+// LibraryLabelList lists labels and their assignments to photos.
+type LibraryLabelList struct {
+	Labels []entity.Label      `yaml:"Labels"`
+	Photos []LibraryPhotoLabel `yaml:"Photos"`
+}
+
+// LibrarySubjectList lists people and other subjects.
+type LibrarySubjectList struct {
+	Subjects []entity.Subject `yaml:"Subjects"`
+}
+
+// LibraryMarker represents a face or other marker along with the SHA1 hash of the file it belongs to.
+type LibraryMarker struct {
+	UID        string  `yaml:"UID"`
+	FileHash   string  `yaml:"FileHash"`
+	Type       string  `yaml:"Type"`
+	Src        string  `yaml:"Src,omitempty"`
+	Name       string  `yaml:"Name,omitempty"`
+	Review     bool    `yaml:"Review,omitempty"`
+	Invalid    bool    `yaml:"Invalid,omitempty"`
+	SubjUID    string  `yaml:"SubjUID,omitempty"`
+	SubjSrc    string  `yaml:"SubjSrc,omitempty"`
+	Embeddings string  `yaml:"Embeddings,omitempty"`
+	Landmarks  string  `yaml:"Landmarks,omitempty"`
+	X          float32 `yaml:"X"`
+	Y          float32 `yaml:"Y"`
+	W          float32 `yaml:"W"`
+	H          float32 `yaml:"H"`
+	Q          int     `yaml:"Q,omitempty"`
+	Size       int     `yaml:"Size,omitempty"`
+	Score      int     `yaml:"Score,omitempty"`
+	Thumb      string  `yaml:"Thumb,omitempty"`
+}
+
+// NewLibraryMarker creates a library archive marker from an entity.
+func NewLibraryMarker(m entity.Marker, fileHash string) LibraryMarker {
+	return LibraryMarker{
+		UID:        m.MarkerUID,
+		FileHash:   fileHash,
+		Type:       m.MarkerType,
+		Src:        m.MarkerSrc,
+		Name:       m.MarkerName,
+		Review:     m.MarkerReview,
+		Invalid:    m.MarkerInvalid,
+		SubjUID:    m.SubjUID,
+		SubjSrc:    m.SubjSrc,
+		Embeddings: string(m.EmbeddingsJSON),
+		Landmarks:  string(m.LandmarksJSON),
+		X:          m.X,
+		Y:          m.Y,
+		W:          m.W,
+		H:          m.H,
+		Q:          m.Q,
+		Size:       m.Size,
+		Score:      m.Score,
+		Thumb:      m.Thumb,
+	}
+}
+
+// Entity returns a new marker entity for the specified file.
+func (m LibraryMarker) Entity(fileUid string) *entity.Marker {
+	return &entity.Marker{
+		MarkerUID:      m.UID,
+		FileUID:        fileUid,
+		MarkerType:     m.Type,
+		MarkerSrc:      m.Src,
+		MarkerName:     m.Name,
+		MarkerReview:   m.Review,
+		MarkerInvalid:  m.Invalid,
+		SubjUID:        m.SubjUID,
+		SubjSrc:        m.SubjSrc,
+		FaceDist:       -1,
+		EmbeddingsJSON: []byte(m.Embeddings),
+		LandmarksJSON:  []byte(m.Landmarks),
+		X:              m.X,
+		Y:              m.Y,
+		W:              m.W,
+		H:              m.H,
+		Q:              m.Q,
+		Size:           m.Size,
+		Score:          m.Score,
+		Thumb:          m.Thumb,
+	}
+}
+
+// LibraryMarkerList lists markers, which are split into multiple archive entries.
+type LibraryMarkerList struct {
+	Markers []LibraryMarker `yaml:"Markers"`
+}
+
+// LibraryLinkList lists share links along with the password hashes of protected links.
+type LibraryLinkList struct {
+	Links     []entity.Link     `yaml:"Links"`
+	Passwords map[string]string `yaml:"Passwords,omitempty"`
+}
+
+// OpenLibraryArchive returns a tar reader for a library archive, which may be gzip compressed.
+func OpenLibraryArchive(r io.Reader) (*tar.Reader, error) {
+	br := bufio.NewReader(r)
+
+	// Detect gzip compression based on the magic number.
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return tar.NewReader(zr), nil
+	}
+
+	return tar.NewReader(br), nil
+}
+
+// librarySafeName checks if a slash-separated name from a library archive is relative
+// and does not refer to a parent directory. Empty names are only allowed if specified.
+func librarySafeName(name string, allowEmpty bool) bool {
+	if name == "" {
+		return allowEmpty
+	} else if strings.HasPrefix(name, "/") || strings.Contains(name, "\\") || filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return false
+	}
+
+	for _, elem := range strings.Split(name, "/") {
+		if elem == ".." {
+			return false
+		}
+	}
+
+	return true
+}
+
+// libraryOriginalName returns the archive entry name of an original file.
+func libraryOriginalName(fileName string) string {
+	return path.Join(LibraryOriginals, filepath.ToSlash(fileName))
+}
+
+// librarySuffixName adds a numeric suffix to the base name of a file, keeping the photo name prefix
+// and extensions intact so that related files are still grouped, e.g. IMG_1234_1.jpg.
+func librarySuffixName(fileName, photoName string, n int) string {
+	dir, base := path.Split(fileName)
+	suffix := "_" + strconv.Itoa(n)
+
+	if photoName != "" && strings.HasPrefix(base, photoName) {
+		return dir + photoName + suffix + base[len(photoName):]
+	}
+
+	if i := strings.Index(base, "."); i > 0 {
+		return dir + base[:i] + suffix + base[i:]
+	}
+
+	return dir + base + suffix
+}
@@ -0,0 +1,313 @@
+package photoprism
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// ExportLibrary writes a portable library archive with originals, sidecar YAML files, labels, people,
+// face markers, albums, and share links to out. The archive is not compressed.
+func ExportLibrary(out io.Writer) (result LibraryArchive, err error) {
+	// Find photos with original files.
+	photos, fileHashes, err := libraryPhotos()
+
+	if err != nil {
+		return result, err
+	}
+
+	return exportLibrary(out, photos, fileHashes)
+}
+
+// exportLibrary writes a portable library archive with the specified photos to out.
+func exportLibrary(out io.Writer, photos []libraryPhoto, fileHashes map[string]string) (result LibraryArchive, err error) {
+	c := Config()
+
+	result = LibraryArchive{
+		Format:     LibraryFormat,
+		Version:    LibraryVersion,
+		CreatedAt:  entity.TimeStamp(),
+		AppVersion: c.Version(),
+		Photos:     []LibraryPhoto{},
+	}
+
+	for _, p := range photos {
+		result.Photos = append(result.Photos, p.archive)
+	}
+
+	tw := tar.NewWriter(out)
+
+	// The manifest comes first so that conflicts can be resolved before files are extracted.
+	if data, err := json.MarshalIndent(result, "", "  "); err != nil {
+		return result, err
+	} else if err = libraryWriteEntry(tw, LibraryManifest, data); err != nil {
+		return result, err
+	}
+
+	// Add original files.
+	originalsPath := c.OriginalsPath()
+
+	for _, p := range photos {
+		for _, f := range p.archive.Files {
+			if err = libraryWriteFile(tw, libraryOriginalName(f.Name), filepath.Join(originalsPath, f.Name)); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	log.Infof("export: added original files of %d photos", len(photos))
+
+	// Add photo metadata.
+	for _, p := range photos {
+		if data, err := yaml.Marshal(p.photo); err != nil {
+			return result, err
+		} else if err = libraryWriteEntry(tw, p.archive.SidecarName(), data); err != nil {
+			return result, err
+		}
+	}
+
+	// Add labels.
+	if err = libraryExportLabels(tw); err != nil {
+		return result, fmt.Errorf("labels: %s", err)
+	}
+
+	// Add people and face markers.
+	if err = libraryExportPeople(tw, fileHashes); err != nil {
+		return result, fmt.Errorf("people: %s", err)
+	}
+
+	// Add albums.
+	if err = libraryExportAlbums(tw); err != nil {
+		return result, fmt.Errorf("albums: %s", err)
+	}
+
+	// Add share links.
+	if err = libraryExportLinks(tw); err != nil {
+		return result, fmt.Errorf("links: %s", err)
+	}
+
+	return result, tw.Close()
+}
+
+// libraryPhoto combines a photo entity with its library archive description.
+type libraryPhoto struct {
+	photo   entity.Photo
+	archive LibraryPhoto
+}
+
+// libraryPhotos returns all photos with original files, and a map of file UIDs to file hashes.
+func libraryPhotos() (result []libraryPhoto, fileHashes map[string]string, err error) {
+	fileHashes = make(map[string]string)
+
+	for offset := 0; ; offset += LibraryBatchSize {
+		var photos entity.Photos
+
+		if err = entity.UnscopedDb().
+			Preload("Details").
+			Preload("Files", "file_root = ? AND file_missing = FALSE AND file_hash <> ''", entity.RootOriginals).
+			Order("id").Offset(offset).Limit(LibraryBatchSize).
+			Find(&photos).Error; err != nil {
+			return result, fileHashes, err
+		} else if len(photos) == 0 {
+			break
+		}
+
+		for _, photo := range photos {
+			if len(photo.Files) == 0 {
+				continue
+			}
+
+			p := LibraryPhoto{
+				UID:   photo.PhotoUID,
+				Path:  photo.PhotoPath,
+				Name:  photo.PhotoName,
+				Hash:  photo.Files[0].FileHash,
+				Files: make([]LibraryFile, 0, len(photo.Files)),
+			}
+
+			for _, f := range photo.Files {
+				if f.FilePrimary {
+					p.Hash = f.FileHash
+				}
+
+				fileHashes[f.FileUID] = f.FileHash
+				p.Files = append(p.Files, LibraryFile{Name: f.FileName, Hash: f.FileHash, Size: f.FileSize})
+			}
+
+			photo.Files = nil
+
+			result = append(result, libraryPhoto{photo: photo, archive: p})
+		}
+	}
+
+	return result, fileHashes, nil
+}
+
+// libraryExportLabels adds labels and their assignments to photos.
+func libraryExportLabels(tw *tar.Writer) error {
+	list := LibraryLabelList{}
+
+	if err := entity.Db().Order("id").Find(&list.Labels).Error; err != nil {
+		return err
+	}
+
+	if err := entity.UnscopedDb().Table("photos_labels").
+		Select("photos.photo_uid, labels.label_uid, photos_labels.label_src AS src, photos_labels.uncertainty").
+		Joins("JOIN photos ON photos.id = photos_labels.photo_id").
+		Joins("JOIN labels ON labels.id = photos_labels.label_id").
+		Where("labels.deleted_at IS NULL").
+		Scan(&list.Photos).Error; err != nil {
+		return err
+	}
+
+	return libraryWriteYaml(tw, LibraryLabels, list)
+}
+
+// libraryExportPeople adds subjects and the markers of exported files.
+func libraryExportPeople(tw *tar.Writer, fileHashes map[string]string) error {
+	subjects := LibrarySubjectList{}
+
+	if err := entity.Db().Order("subj_uid").Find(&subjects.Subjects).Error; err != nil {
+		return err
+	} else if err = libraryWriteYaml(tw, LibrarySubjects, subjects); err != nil {
+		return err
+	}
+
+	for batch, offset := 1, 0; ; batch, offset = batch+1, offset+LibraryBatchSize {
+		var markers entity.Markers
+
+		if err := entity.UnscopedDb().Order("marker_uid").Offset(offset).Limit(LibraryBatchSize).Find(&markers).Error; err != nil {
+			return err
+		} else if len(markers) == 0 {
+			break
+		}
+
+		list := LibraryMarkerList{Markers: make([]LibraryMarker, 0, len(markers))}
+
+		for _, m := range markers {
+			if hash, ok := fileHashes[m.FileUID]; ok {
+				list.Markers = append(list.Markers, NewLibraryMarker(m, hash))
+			}
+		}
+
+		if err := libraryWriteYaml(tw, fmt.Sprintf("%s-%04d.yml", LibraryMarkers, batch), list); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// libraryExportAlbums adds album YAML files including the photos they contain.
+func libraryExportAlbums(tw *tar.Writer) error {
+	albums, err := query.Albums(0, 1000000)
+
+	if err != nil {
+		return err
+	}
+
+	for _, a := range albums {
+		if a.DeletedAt != nil {
+			continue
+		}
+
+		if data, err := a.Yaml(); err != nil {
+			return err
+		} else if err = libraryWriteEntry(tw, path.Join(LibraryAlbums, a.AlbumType, a.AlbumUID+".yml"), data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// libraryExportLinks adds share links along with the password hashes of protected links.
+func libraryExportLinks(tw *tar.Writer) error {
+	list := LibraryLinkList{Passwords: make(map[string]string)}
+
+	if err := entity.UnscopedDb().Order("created_at").Find(&list.Links).Error; err != nil {
+		return err
+	}
+
+	for _, l := range list.Links {
+		if !l.HasPassword {
+			continue
+		} else if p := entity.FindPassword(l.LinkUID); p != nil {
+			list.Passwords[l.LinkUID] = p.Hash
+		}
+	}
+
+	return libraryWriteYaml(tw, LibraryLinks, list)
+}
+
+// libraryWriteYaml adds a YAML encoded value to the archive.
+func libraryWriteYaml(tw *tar.Writer, name string, v interface{}) error {
+	data, err := yaml.Marshal(v)
+
+	if err != nil {
+		return err
+	}
+
+	return libraryWriteEntry(tw, name, data)
+}
+
+// libraryWriteEntry adds a file with the specified content to the archive.
+func libraryWriteEntry(tw *tar.Writer, name string, data []byte) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	_, err := tw.Write(data)
+
+	return err
+}
+
+// libraryWriteFile adds an existing file to the archive.
+func libraryWriteFile(tw *tar.Writer, name, fileName string) error {
+	f, err := os.Open(fileName)
+
+	if err != nil {
+		return fmt.Errorf("cannot read %s (%s)", clean.Log(fileName), err)
+	}
+
+	defer f.Close()
+
+	info, err := f.Stat()
+
+	if err != nil {
+		return err
+	}
+
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}
+
+	if err = tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tw, f)
+
+	return err
+}
@@ -0,0 +1,637 @@
+package photoprism
+
+import (
+	"archive/tar"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// ErrNotLibraryArchive is returned if a file is not a portable library archive.
+var ErrNotLibraryArchive = errors.New("not a library archive")
+
+// LibraryImportResult represents the outcome of a library import.
+type LibraryImportResult struct {
+	Photos   int
+	Files    int
+	Existing int
+	Renamed  int
+	Labels   int
+	Subjects int
+	Markers  int
+	Albums   int
+	Links    int
+}
+
+// LibraryImport imports portable library archives, e.g. to move a library to another instance.
+type LibraryImport struct {
+	conf  *config.Config
+	index *Index
+}
+
+// NewLibraryImport returns a new library importer.
+func NewLibraryImport(conf *config.Config, index *Index) *LibraryImport {
+	return &LibraryImport{conf: conf, index: index}
+}
+
+// libraryPlan specifies where the files of an archived photo are extracted to.
+type libraryPlan struct {
+	photo    LibraryPhoto
+	name     string
+	files    map[string]string
+	existing bool
+}
+
+// Start imports a library archive read from r. Original files are extracted to the originals folder and indexed,
+// restoring photo metadata from the archived sidecar files. Files that already exist with the same content are
+// skipped, while conflicting files are renamed. Labels, people, albums, and share links are merged with existing
+// entities of the same name, preserving their UIDs if possible.
+func (imp *LibraryImport) Start(r io.Reader) (result LibraryImportResult, err error) {
+	c := imp.conf
+
+	if c.ReadOnly() {
+		return result, fmt.Errorf("cannot import library in read-only mode")
+	}
+
+	tr, err := OpenLibraryArchive(r)
+
+	if err != nil {
+		return result, err
+	}
+
+	// The manifest must come first.
+	hdr, err := tr.Next()
+
+	if err != nil || hdr.Name != LibraryManifest {
+		return result, ErrNotLibraryArchive
+	}
+
+	var archive LibraryArchive
+
+	if err = json.NewDecoder(tr).Decode(&archive); err != nil || archive.Format != LibraryFormat {
+		return result, ErrNotLibraryArchive
+	} else if archive.Version > LibraryVersion {
+		return result, fmt.Errorf("unsupported library archive version %d", archive.Version)
+	}
+
+	// Reject absolute file paths and references to parent directories before extracting anything.
+	for _, p := range archive.Photos {
+		if err = p.Validate(); err != nil {
+			return result, fmt.Errorf("%s in library archive", err)
+		}
+	}
+
+	log.Infof("import: library archive with %d photos created at %s", len(archive.Photos), archive.CreatedAt.Format("2006-01-02 15:04:05"))
+
+	// Decide where to extract files before reading them.
+	originals := make(map[string]string)
+	hashes := make(map[string]string)
+	sidecars := make(map[string]*libraryPlan)
+
+	for _, p := range archive.Photos {
+		plan := imp.plan(p)
+
+		if plan.existing {
+			result.Existing++
+		} else if plan.name != p.Name {
+			result.Renamed++
+		}
+
+		for src, dest := range plan.files {
+			originals[libraryOriginalName(src)] = dest
+		}
+
+		for _, f := range p.Files {
+			hashes[libraryOriginalName(f.Name)] = f.Hash
+		}
+
+		sidecars[p.SidecarName()] = plan
+	}
+
+	var docs []*tar.Header
+	var data = make(map[string][]byte)
+	var docsSize int64
+
+	// Extract files.
+	for {
+		hdr, err = tr.Next()
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return result, err
+		} else if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(hdr.Name)
+
+		switch {
+		case strings.HasPrefix(name, LibraryOriginals+"/"):
+			dest, ok := originals[name]
+
+			if !ok || dest == "" {
+				continue
+			} else if err = imp.extractOriginal(tr, hdr, dest, hashes[name]); err != nil {
+				return result, err
+			}
+
+			result.Files++
+		case strings.HasPrefix(name, LibrarySidecar+"/"):
+			if plan, ok := sidecars[name]; !ok || plan.existing {
+				continue
+			} else if err = imp.extractSidecar(tr, plan); err != nil {
+				log.Warnf("import: %s in %s (restore metadata)", err, clean.Log(name))
+			}
+		case libraryDocument(name):
+			if hdr.Size > LibraryMaxDocSize {
+				return result, fmt.Errorf("%s in library archive exceeds %d bytes", clean.Log(name), LibraryMaxDocSize)
+			}
+
+			b, err := io.ReadAll(io.LimitReader(tr, LibraryMaxDocSize))
+
+			if err != nil {
+				return result, err
+			} else if docsSize += int64(len(b)); docsSize > LibraryMaxDocsSize {
+				return result, fmt.Errorf("documents in library archive exceed %d bytes", LibraryMaxDocsSize)
+			}
+
+			docs = append(docs, hdr)
+			data[name] = b
+		default:
+			log.Debugf("import: skipped unknown entry %s", clean.Log(name))
+		}
+	}
+
+	// Index extracted originals, restoring photo metadata from sidecar files.
+	if result.Files > 0 {
+		settings := c.Settings()
+		convert := settings.Index.Convert && c.SidecarWritable()
+		opt := NewIndexOptions(entity.RootPath, false, convert, true, false, false)
+
+		imp.index.Start(opt)
+	}
+
+	// Find photos based on the hash of their primary file.
+	photos := make(map[string]entity.File)
+
+	for _, p := range archive.Photos {
+		if f, err := entity.FirstFileByHash(p.Hash); err != nil {
+			log.Warnf("import: photo %s not found after indexing", clean.Log(p.UID))
+		} else {
+			photos[p.UID] = f
+			result.Photos++
+		}
+	}
+
+	// Restore labels, people, albums, and share links.
+	labels := make(map[string]string)
+	subjects := make(map[string]string)
+	albums := make(map[string]string)
+
+	if b, ok := data[LibraryLabels]; ok {
+		result.Labels = imp.importLabels(b, photos, labels)
+	}
+
+	if b, ok := data[LibrarySubjects]; ok {
+		result.Subjects = imp.importSubjects(b, subjects)
+	}
+
+	for _, hdr := range docs {
+		name := path.Clean(hdr.Name)
+
+		switch {
+		case strings.HasPrefix(name, LibraryMarkers):
+			result.Markers += imp.importMarkers(data[name], subjects)
+		case strings.HasPrefix(name, LibraryAlbums+"/"):
+			if imp.importAlbum(data[name], photos, albums) {
+				result.Albums++
+			}
+		}
+	}
+
+	if b, ok := data[LibraryLinks]; ok {
+		// Share links may refer to photos, labels, or albums.
+		shared := make(map[string]string, len(photos)+len(labels)+len(albums))
+
+		for uid, f := range photos {
+			shared[uid] = f.PhotoUID
+		}
+
+		for _, m := range []map[string]string{labels, albums} {
+			for archived, uid := range m {
+				shared[archived] = uid
+			}
+		}
+
+		result.Links = imp.importLinks(b, shared)
+	}
+
+	if err = entity.UpdateCounts(); err != nil {
+		log.Warnf("import: %s (update counts)", err)
+	}
+
+	return result, nil
+}
+
+// libraryDocument checks if an archive entry contains labels, people, markers, albums, or share links.
+func libraryDocument(name string) bool {
+	switch {
+	case name == LibraryLabels, name == LibrarySubjects, name == LibraryLinks:
+		return true
+	case strings.HasPrefix(name, LibraryMarkers), strings.HasPrefix(name, LibraryAlbums+"/"):
+		return true
+	default:
+		return false
+	}
+}
+
+// plan returns where to extract the files of an archived photo, renaming them if they conflict with existing files.
+func (imp *LibraryImport) plan(p LibraryPhoto) *libraryPlan {
+	originalsPath := imp.conf.OriginalsPath()
+
+	for n := 0; ; n++ {
+		plan := &libraryPlan{photo: p, name: p.Name, files: make(map[string]string), existing: true}
+		conflict := false
+
+		if n > 0 {
+			plan.name = p.Name + "_" + fmt.Sprint(n)
+		}
+
+		for _, f := range p.Files {
+			name := f.Name
+
+			if n > 0 {
+				name = librarySuffixName(filepath.ToSlash(f.Name), p.Name, n)
+			}
+
+			dest := filepath.Join(originalsPath, filepath.FromSlash(name))
+
+			if !fs.FileExists(dest) {
+				plan.files[f.Name] = dest
+				plan.existing = false
+			} else if fs.Hash(dest) == f.Hash {
+				// Skip files that already exist.
+				plan.files[f.Name] = ""
+			} else {
+				conflict = true
+				break
+			}
+		}
+
+		if !conflict {
+			return plan
+		}
+	}
+}
+
+// extractOriginal writes an original file and verifies its checksum.
+func (imp *LibraryImport) extractOriginal(r io.Reader, hdr *tar.Header, dest, hash string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), os.ModePerm); err != nil {
+		return err
+	}
+
+	f, err := os.Create(dest)
+
+	if err != nil {
+		return err
+	}
+
+	h := sha1.New()
+
+	if _, err = io.Copy(io.MultiWriter(f, h), r); err != nil {
+		f.Close()
+		return err
+	} else if err = f.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Chtimes(dest, hdr.ModTime, hdr.ModTime); err != nil {
+		log.Debugf("import: %s", err)
+	}
+
+	relName := fs.RelName(dest, imp.conf.OriginalsPath())
+
+	if sum := hex.EncodeToString(h.Sum(nil)); hash != "" && sum != hash {
+		log.Warnf("import: %s has changed since it was indexed", clean.Log(relName))
+	} else {
+		log.Debugf("import: extracted %s", clean.Log(relName))
+	}
+
+	return nil
+}
+
+// extractSidecar writes the sidecar YAML file of a photo so that its metadata is restored when indexing.
+// The photo UID is removed if it is already used by another photo.
+func (imp *LibraryImport) extractSidecar(r io.Reader, plan *libraryPlan) error {
+	b, err := io.ReadAll(r)
+
+	if err != nil {
+		return err
+	}
+
+	photo := entity.Photo{}
+
+	if err = yaml.Unmarshal(b, &photo); err != nil {
+		return err
+	}
+
+	if photo.PhotoUID != "" && entity.FindPhoto(entity.Photo{PhotoUID: photo.PhotoUID}) != nil {
+		log.Infof("import: uid %s already exists, a new uid will be assigned", clean.Log(photo.PhotoUID))
+
+		photo.PhotoUID = ""
+
+		if b, err = yaml.Marshal(photo); err != nil {
+			return err
+		}
+	}
+
+	fileName := filepath.Join(imp.conf.SidecarPath(), filepath.FromSlash(plan.photo.Path), plan.name+fs.ExtYAML)
+
+	if err = os.MkdirAll(filepath.Dir(fileName), os.ModePerm); err != nil {
+		return err
+	}
+
+	return os.WriteFile(fileName, b, os.ModePerm)
+}
+
+// importLabels restores labels and their assignments to photos, and maps their archived UIDs to existing labels.
+func (imp *LibraryImport) importLabels(b []byte, photos map[string]entity.File, uids map[string]string) (count int) {
+	var list LibraryLabelList
+
+	if err := yaml.Unmarshal(b, &list); err != nil {
+		log.Errorf("import: %s (labels)", err)
+		return 0
+	}
+
+	labels := make(map[string]uint)
+
+	for _, l := range list.Labels {
+		m := entity.NewLabel(l.LabelName, l.LabelPriority)
+		m.LabelFavorite = l.LabelFavorite
+		m.LabelDescription = l.LabelDescription
+		m.LabelNotes = l.LabelNotes
+
+		// Keep uid if not used yet.
+		if entity.UnscopedDb().Where("label_uid = ?", l.LabelUID).First(&entity.Label{}).RecordNotFound() {
+			m.LabelUID = l.LabelUID
+		}
+
+		if found := entity.FirstOrCreateLabel(m); found != nil {
+			labels[l.LabelUID] = found.ID
+			uids[l.LabelUID] = found.LabelUID
+
+			if found.LabelUID == m.LabelUID {
+				count++
+			}
+		}
+	}
+
+	for _, pl := range list.Photos {
+		f, ok := photos[pl.PhotoUID]
+		labelId, found := labels[pl.LabelUID]
+
+		if !ok || !found {
+			continue
+		}
+
+		m := entity.FirstOrCreatePhotoLabel(entity.NewPhotoLabel(f.PhotoID, labelId, pl.Uncertainty, pl.Src))
+
+		// Manually assigned or removed labels take precedence.
+		if m != nil && pl.Src == entity.SrcManual && (m.LabelSrc != pl.Src || m.Uncertainty != pl.Uncertainty) {
+			m.LabelSrc = pl.Src
+			m.Uncertainty = pl.Uncertainty
+
+			if err := m.Save(); err != nil {
+				log.Warnf("import: %s (update label)", err)
+			}
+		}
+	}
+
+	return count
+}
+
+// importSubjects restores people and other subjects, and maps their archived UIDs to existing subjects.
+func (imp *LibraryImport) importSubjects(b []byte, subjects map[string]string) (count int) {
+	var list LibrarySubjectList
+
+	if err := yaml.Unmarshal(b, &list); err != nil {
+		log.Errorf("import: %s (people)", err)
+		return 0
+	}
+
+	for _, s := range list.Subjects {
+		if found := entity.FindSubject(s.SubjUID); found != nil {
+			subjects[s.SubjUID] = found.SubjUID
+			continue
+		} else if found = entity.FindSubjectByName(s.SubjName); found != nil {
+			subjects[s.SubjUID] = found.SubjUID
+			continue
+		}
+
+		m := s
+		m.FileCount = 0
+		m.PhotoCount = 0
+
+		if err := m.Create(); err != nil {
+			log.Warnf("import: %s (add %s)", err, clean.Log(s.SubjName))
+			continue
+		}
+
+		subjects[s.SubjUID] = m.SubjUID
+		count++
+	}
+
+	return count
+}
+
+// importMarkers restores face and other markers. Existing markers of the same area are updated
+// if they have not been assigned to a subject yet.
+func (imp *LibraryImport) importMarkers(b []byte, subjects map[string]string) (count int) {
+	var list LibraryMarkerList
+
+	if err := yaml.Unmarshal(b, &list); err != nil {
+		log.Errorf("import: %s (markers)", err)
+		return 0
+	}
+
+	for _, lm := range list.Markers {
+		f, err := entity.FirstFileByHash(lm.FileHash)
+
+		if err != nil {
+			continue
+		}
+
+		if lm.SubjUID != "" {
+			lm.SubjUID = subjects[lm.SubjUID]
+		}
+
+		existing := entity.Marker{}
+
+		// Update marker created while indexing?
+		if err = entity.Db().Where("file_uid = ? AND marker_type = ? AND thumb = ?", f.FileUID, lm.Type, lm.Thumb).
+			First(&existing).Error; err == nil {
+			if existing.SubjUID != "" || lm.SubjUID == "" && !lm.Invalid && !lm.Review {
+				continue
+			}
+
+			if err = existing.Updates(entity.Values{
+				"subj_uid":       lm.SubjUID,
+				"subj_src":       lm.SubjSrc,
+				"marker_name":    lm.Name,
+				"marker_review":  lm.Review,
+				"marker_invalid": lm.Invalid,
+			}); err != nil {
+				log.Warnf("import: %s (update marker)", err)
+			} else {
+				count++
+			}
+
+			continue
+		}
+
+		m := lm.Entity(f.FileUID)
+
+		// Keep uid if not used yet.
+		if entity.FindMarker(m.MarkerUID) != nil {
+			m.MarkerUID = ""
+		}
+
+		if err = m.Create(); err != nil {
+			log.Warnf("import: %s (add marker)", err)
+		} else {
+			count++
+		}
+	}
+
+	return count
+}
+
+// importAlbum restores an album, or adds the archived photos to an existing album with the same name.
+func (imp *LibraryImport) importAlbum(b []byte, photos map[string]entity.File, albums map[string]string) bool {
+	a := entity.Album{}
+
+	if err := yaml.Unmarshal(b, &a); err != nil {
+		log.Errorf("import: %s (album)", err)
+		return false
+	} else if a.AlbumType == "" || a.AlbumUID == "" {
+		return false
+	}
+
+	archived := a.AlbumUID
+	entries := a.Photos
+	a.Photos = nil
+
+	created := false
+
+	if found := entity.FindAlbum(a); found != nil {
+		a = *found
+	} else if a.AlbumUID = ""; entity.FindAlbum(a) != nil {
+		a = *entity.FindAlbum(a)
+	} else {
+		a.AlbumUID = archived
+
+		// Keep uid if not used yet, or create a new one otherwise.
+		if !entity.UnscopedDb().Where("album_uid = ?", archived).First(&entity.Album{}).RecordNotFound() {
+			a.AlbumUID = ""
+		}
+
+		if err := a.Create(); err != nil {
+			log.Errorf("import: %s (add album %s)", err, clean.Log(a.AlbumTitle))
+			return false
+		}
+
+		created = true
+	}
+
+	albums[archived] = a.AlbumUID
+
+	for _, e := range entries {
+		f, ok := photos[e.PhotoUID]
+
+		if !ok {
+			continue
+		}
+
+		m := entity.NewPhotoAlbum(f.PhotoUID, a.AlbumUID)
+		m.Order = e.Order
+		m.Hidden = e.Hidden
+
+		entity.FirstOrCreatePhotoAlbum(m)
+	}
+
+	return created
+}
+
+// importLinks restores share links of imported photos, labels, and albums.
+func (imp *LibraryImport) importLinks(b []byte, shared map[string]string) (count int) {
+	var list LibraryLinkList
+
+	if err := yaml.Unmarshal(b, &list); err != nil {
+		log.Errorf("import: %s (links)", err)
+		return 0
+	}
+
+	for _, l := range list.Links {
+		shareUid, ok := shared[l.ShareUID]
+
+		if !ok {
+			continue
+		} else if len(entity.FindLinks(l.LinkToken, shareUid)) > 0 {
+			continue
+		}
+
+		archived := l.LinkUID
+		hash := list.Passwords[archived]
+
+		// Skip protected links without password, so that they cannot be used without one.
+		if l.HasPassword && hash == "" {
+			log.Warnf("import: link %s has no password and was skipped", clean.Log(archived))
+			continue
+		}
+
+		l.ShareUID = shareUid
+
+		if entity.FindLink(l.LinkUID) != nil {
+			l.LinkUID = ""
+		}
+
+		if err := l.Save(); err != nil {
+			log.Warnf("import: %s (add link)", err)
+			continue
+		}
+
+		if l.HasPassword {
+			p := entity.Password{UID: l.LinkUID, Hash: hash}
+
+			// Remove the link again if its password cannot be saved.
+			if err := p.Save(); err != nil {
+				log.Warnf("import: %s (link password)", err)
+
+				if err = l.Delete(); err != nil {
+					log.Errorf("import: %s (remove link)", err)
+				}
+
+				continue
+			}
+		}
+
+		count++
+	}
+
+	return count
+}
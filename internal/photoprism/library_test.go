@@ -0,0 +1,331 @@
+package photoprism
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/photoprism/photoprism/internal/classify"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/crop"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/face"
+	"github.com/photoprism/photoprism/internal/nsfw"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestLibraryPhoto_SidecarName(t *testing.T) {
+	p := LibraryPhoto{Path: "2020/10", Name: "IMG_1234"}
+	assert.Equal(t, "sidecar/2020/10/IMG_1234.yml", p.SidecarName())
+}
+
+func TestLibrarySuffixName(t *testing.T) {
+	t.Run("PhotoName", func(t *testing.T) {
+		assert.Equal(t, "2020/10/IMG_1234_1.jpg", librarySuffixName("2020/10/IMG_1234.jpg", "IMG_1234", 1))
+		assert.Equal(t, "2020/10/IMG_1234_2.jpg.xmp", librarySuffixName("2020/10/IMG_1234.jpg.xmp", "IMG_1234", 2))
+	})
+	t.Run("Extension", func(t *testing.T) {
+		assert.Equal(t, "2020/10/foo_3.tar.gz", librarySuffixName("2020/10/foo.tar.gz", "IMG_1234", 3))
+	})
+	t.Run("NoExtension", func(t *testing.T) {
+		assert.Equal(t, "foo_1", librarySuffixName("foo", "", 1))
+	})
+}
+
+func TestLibraryOriginalName(t *testing.T) {
+	assert.Equal(t, "originals/2020/10/IMG_1234.jpg", libraryOriginalName("2020/10/IMG_1234.jpg"))
+}
+
+func TestLibraryPhoto_Validate(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		p := LibraryPhoto{Path: "2020/10", Name: "IMG_1234", Files: []LibraryFile{{Name: "2020/10/IMG_1234.jpg"}}}
+		assert.NoError(t, p.Validate())
+	})
+	t.Run("RootFolder", func(t *testing.T) {
+		p := LibraryPhoto{Path: "", Name: "IMG_1234", Files: []LibraryFile{{Name: "IMG_1234.jpg"}}}
+		assert.NoError(t, p.Validate())
+	})
+	t.Run("ParentPath", func(t *testing.T) {
+		p := LibraryPhoto{Path: "../etc", Name: "IMG_1234", Files: []LibraryFile{{Name: "IMG_1234.jpg"}}}
+		assert.Error(t, p.Validate())
+	})
+	t.Run("ParentName", func(t *testing.T) {
+		p := LibraryPhoto{Path: "2020", Name: "..", Files: []LibraryFile{{Name: "IMG_1234.jpg"}}}
+		assert.Error(t, p.Validate())
+	})
+	t.Run("AbsoluteFileName", func(t *testing.T) {
+		p := LibraryPhoto{Path: "2020", Name: "IMG_1234", Files: []LibraryFile{{Name: "/etc/passwd"}}}
+		assert.Error(t, p.Validate())
+	})
+	t.Run("ParentFileName", func(t *testing.T) {
+		p := LibraryPhoto{Path: "2020", Name: "IMG_1234", Files: []LibraryFile{{Name: "2020/../../IMG_1234.jpg"}}}
+		assert.Error(t, p.Validate())
+	})
+}
+
+func TestLibraryDocument(t *testing.T) {
+	assert.True(t, libraryDocument(LibraryLabels))
+	assert.True(t, libraryDocument(LibraryLinks))
+	assert.True(t, libraryDocument("people/markers-0001.yml"))
+	assert.True(t, libraryDocument("albums/album/at9lxuqxpogaaba7.yml"))
+	assert.False(t, libraryDocument("foo.bin"))
+}
+
+// testLibraryImport returns a new library importer for tests.
+func testLibraryImport(conf *config.Config) *LibraryImport {
+	tf := classify.New(conf.AssetsPath(), conf.DisableTensorFlow())
+	nd := nsfw.New(conf.NSFWModelPath())
+	fn := face.NewNet(conf.FaceNetModelPath(), "", conf.DisableTensorFlow())
+	convert := NewConvert(conf)
+
+	return NewLibraryImport(conf, NewIndex(conf, tf, nd, fn, convert, NewFiles(), NewPhotos()))
+}
+
+// testLibraryArchive returns a library archive without photos that contains the specified documents.
+func testLibraryArchive(t *testing.T, docs map[string][]byte) *bytes.Buffer {
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+
+	manifest, err := json.Marshal(LibraryArchive{Format: LibraryFormat, Version: LibraryVersion, Photos: []LibraryPhoto{}})
+
+	if err != nil {
+		t.Fatal(err)
+	} else if err = libraryWriteEntry(tw, LibraryManifest, manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, data := range docs {
+		if err = libraryWriteEntry(tw, name, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf
+}
+
+func TestLibraryImport_Start(t *testing.T) {
+	t.Run("DocsSize", func(t *testing.T) {
+		conf := config.TestConfig()
+		imp := testLibraryImport(conf)
+
+		maxDocsSize := LibraryMaxDocsSize
+		LibraryMaxDocsSize = 1024
+		defer func() { LibraryMaxDocsSize = maxDocsSize }()
+
+		r := testLibraryArchive(t, map[string][]byte{
+			LibraryLabels: bytes.Repeat([]byte("#"), 600),
+			LibraryLinks:  bytes.Repeat([]byte("#"), 600),
+		})
+
+		_, err := imp.Start(r)
+
+		assert.Error(t, err)
+	})
+	t.Run("RoundTrip", func(t *testing.T) {
+		conf := config.TestConfig()
+
+		if err := conf.InitializeTestData(); err != nil {
+			t.Fatal(err)
+		}
+
+		imp := testLibraryImport(conf)
+
+		// Add a photo with a label, an album, a face marker, and a protected share link.
+		dir := filepath.Join(conf.OriginalsPath(), "library-test")
+		fileName := filepath.Join(dir, "IMG_LIB.jpg")
+
+		t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+		if err := fs.Copy(filepath.Join(conf.ExamplesPath(), "IMG_4120.JPG"), fileName); err != nil {
+			t.Fatal(err)
+		}
+
+		res := imp.index.FileName(fileName, NewIndexOptions("library-test", true, false, true, false, false))
+
+		if res.Err != nil {
+			t.Fatal(res.Err)
+		}
+
+		photo := entity.FindPhoto(entity.Photo{PhotoUID: res.PhotoUID})
+
+		if photo == nil {
+			t.Fatal("photo not found")
+		}
+
+		file, err := entity.FirstFileByHash(fs.Hash(fileName))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		label := entity.FirstOrCreateLabel(entity.NewLabel("Library Test Label", 1))
+
+		if label == nil {
+			t.Fatal("label not created")
+		}
+
+		entity.FirstOrCreatePhotoLabel(entity.NewPhotoLabel(photo.ID, label.ID, 10, entity.SrcManual))
+
+		album := entity.NewAlbum("Library Test Album", entity.AlbumDefault)
+
+		if err = album.Create(); err != nil {
+			t.Fatal(err)
+		}
+
+		album.AddPhotos([]string{photo.PhotoUID})
+
+		subj := entity.NewSubject("Library Test Person", entity.SubjPerson, entity.SrcManual)
+
+		if err = subj.Create(); err != nil {
+			t.Fatal(err)
+		}
+
+		marker := entity.NewMarker(file, crop.NewArea("face", 0.1, 0.1, 0.2, 0.2), subj.SubjUID, entity.SrcManual, entity.MarkerFace, 100, 50)
+
+		if err = marker.Create(); err != nil {
+			t.Fatal(err)
+		}
+
+		link := entity.NewUserLink(album.AlbumUID, "")
+
+		if err = link.SetPassword("library"); err != nil {
+			t.Fatal(err)
+		} else if err = link.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Export the test photo.
+		photos, fileHashes, err := libraryPhotos()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var exported []libraryPhoto
+
+		for _, p := range photos {
+			if p.photo.PhotoUID == photo.PhotoUID {
+				exported = append(exported, p)
+			}
+		}
+
+		assert.Len(t, exported, 1)
+
+		buf := new(bytes.Buffer)
+
+		if _, err = exportLibrary(buf, exported, fileHashes); err != nil {
+			t.Fatal(err)
+		}
+
+		// Remove the exported entities and replace the original with a different file to cause a conflict.
+		_ = os.Remove(photo.YamlFileName(conf.OriginalsPath(), conf.SidecarPath()))
+
+		db := entity.UnscopedDb()
+		db.Delete(&entity.Password{}, "uid = ?", link.LinkUID)
+		db.Delete(&entity.Link{}, "link_uid = ?", link.LinkUID)
+		db.Delete(&entity.Marker{}, "marker_uid = ?", marker.MarkerUID)
+		db.Delete(&entity.Subject{}, "subj_uid = ?", subj.SubjUID)
+		db.Delete(&entity.PhotoAlbum{}, "album_uid = ?", album.AlbumUID)
+		db.Delete(&entity.Album{}, "album_uid = ?", album.AlbumUID)
+		db.Delete(&entity.PhotoLabel{}, "photo_id = ?", photo.ID)
+		db.Delete(&entity.Label{}, "label_uid = ?", label.LabelUID)
+		db.Delete(&entity.Details{}, "photo_id = ?", photo.ID)
+		db.Delete(&entity.File{}, "photo_id = ?", photo.ID)
+		db.Delete(&entity.Photo{}, "photo_uid = ?", photo.PhotoUID)
+
+		if err = fs.Copy(filepath.Join(conf.ExamplesPath(), "beach_wood.jpg"), fileName); err != nil {
+			t.Fatal(err)
+		}
+
+		// Import the archive again.
+		result, err := imp.Start(buf)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 1, result.Photos)
+		assert.Equal(t, 1, result.Renamed)
+
+		// The conflicting file was renamed, while the photo uid was preserved.
+		imported, err := entity.FirstFileByHash(file.FileHash)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "library-test/IMG_LIB_1.jpg", imported.FileName)
+		assert.Equal(t, photo.PhotoUID, imported.PhotoUID)
+
+		// Labels and their assignments are restored.
+		assert.False(t, db.Where("label_uid = ?", label.LabelUID).First(&entity.Label{}).RecordNotFound())
+		assert.False(t, db.Where("photo_id = ? AND label_id = (SELECT id FROM labels WHERE label_uid = ?)", imported.PhotoID, label.LabelUID).
+			First(&entity.PhotoLabel{}).RecordNotFound())
+
+		// Albums and their photos are restored.
+		assert.NotNil(t, entity.FindAlbum(entity.Album{AlbumUID: album.AlbumUID}))
+		assert.False(t, db.Where("album_uid = ? AND photo_uid = ?", album.AlbumUID, photo.PhotoUID).First(&entity.PhotoAlbum{}).RecordNotFound())
+
+		// Markers and subjects are restored.
+		if m := entity.FindMarker(marker.MarkerUID); assert.NotNil(t, m) {
+			assert.Equal(t, imported.FileUID, m.FileUID)
+			assert.Equal(t, subj.SubjUID, m.SubjUID)
+		}
+
+		// Share links are restored with their password.
+		if l := entity.FindLink(link.LinkUID); assert.NotNil(t, l) {
+			assert.Equal(t, album.AlbumUID, l.ShareUID)
+			assert.True(t, l.HasPassword)
+			assert.False(t, l.InvalidPassword("library"))
+			assert.True(t, l.InvalidPassword("wrong"))
+		}
+	})
+}
+
+func TestLibraryImport_ImportLinks(t *testing.T) {
+	conf := config.TestConfig()
+	imp := testLibraryImport(conf)
+
+	t.Run("PasswordMissing", func(t *testing.T) {
+		link := entity.NewUserLink("at9lxuqxpogaaba8", "")
+		link.HasPassword = true
+
+		b, err := yaml.Marshal(LibraryLinkList{Links: []entity.Link{link}})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 0, imp.importLinks(b, map[string]string{"at9lxuqxpogaaba8": "at9lxuqxpogaaba8"}))
+		assert.Nil(t, entity.FindLink(link.LinkUID))
+	})
+	t.Run("Password", func(t *testing.T) {
+		link := entity.NewUserLink("at9lxuqxpogaaba8", "")
+		link.HasPassword = true
+
+		b, err := yaml.Marshal(LibraryLinkList{
+			Links:     []entity.Link{link},
+			Passwords: map[string]string{link.LinkUID: entity.NewPassword(link.LinkUID, "library").Hash},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 1, imp.importLinks(b, map[string]string{"at9lxuqxpogaaba8": "at9lxuqxpogaaba8"}))
+
+		if l := entity.FindLink(link.LinkUID); assert.NotNil(t, l) {
+			assert.False(t, l.InvalidPassword("library"))
+		}
+	})
+}
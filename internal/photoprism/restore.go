@@ -0,0 +1,413 @@
+package photoprism
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize/english"
+
+	"github.com/photoprism/photoprism/internal/backup"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/i18n"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Backup types.
+const (
+	BackupTypeArchive = "archive"
+	BackupTypeDump    = "sql"
+)
+
+// Errors returned when restoring backups.
+var (
+	ErrBackupNotFound = errors.New("backup not found")
+	ErrRestoreBusy    = errors.New("cannot restore backup while other workers are running")
+)
+
+// Backup represents an index backup that can be restored, either a backup archive or an SQL dump.
+type Backup struct {
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Driver    string    `json:"driver,omitempty"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"createdAt"`
+	fileName  string
+}
+
+// Backups returns the backup archives and SQL dumps in the backup path, newest first.
+func Backups() (result []Backup, err error) {
+	c := Config()
+
+	archives, err := backup.List(c.BackupArchivePath())
+
+	if err != nil {
+		return result, err
+	}
+
+	for _, a := range archives {
+		m, err := a.Manifest()
+
+		if err != nil {
+			log.Debugf("restore: %s in archive %s", err, clean.Log(a.Name))
+			continue
+		}
+
+		result = append(result, Backup{
+			Name:      a.Name,
+			Type:      BackupTypeArchive,
+			Driver:    m.Driver,
+			Size:      m.Size(),
+			CreatedAt: a.CreatedAt,
+			fileName:  a.Path,
+		})
+	}
+
+	dumps, err := filepath.Glob(filepath.Join(IndexBackupPath(), "*.sql"))
+
+	if err != nil {
+		return result, err
+	}
+
+	for _, fileName := range dumps {
+		info, err := os.Stat(fileName)
+
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+
+		result = append(result, Backup{
+			Name:      filepath.Base(fileName),
+			Type:      BackupTypeDump,
+			Driver:    c.DatabaseDriver(),
+			Size:      info.Size(),
+			CreatedAt: info.ModTime().UTC(),
+			fileName:  fileName,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
+
+// FindBackup returns the backup with the specified name.
+func FindBackup(name string) (Backup, error) {
+	backups, err := Backups()
+
+	if err != nil {
+		return Backup{}, err
+	}
+
+	for _, b := range backups {
+		if b.Name == name {
+			return b, nil
+		}
+	}
+
+	return Backup{}, ErrBackupNotFound
+}
+
+// RestoreTable compares the number of rows in a table with the number of rows in a backup.
+type RestoreTable struct {
+	Name    string `json:"name"`
+	Current int    `json:"current"`
+	Backup  int    `json:"backup"`
+}
+
+// RestoreReport describes what is changed when a backup is restored.
+type RestoreReport struct {
+	Backup   Backup         `json:"backup"`
+	DryRun   bool           `json:"dryRun"`
+	Tables   []RestoreTable `json:"tables"`
+	Albums   int            `json:"albums"`
+	Sidecar  int            `json:"sidecar"`
+	Settings bool           `json:"settings"`
+}
+
+// PreviewRestore checks if a backup can be restored and reports what would change without modifying anything.
+// The checksums of archive files are verified, and the number of rows is estimated based on the SQL dump.
+func PreviewRestore(b Backup) (report RestoreReport, err error) {
+	c := Config()
+
+	report = RestoreReport{Backup: b, DryRun: true, Tables: []RestoreTable{}}
+
+	if b.Driver != "" && b.Driver != c.DatabaseDriver() {
+		return report, fmt.Errorf("cannot restore %s backup with %s driver", b.Driver, c.DatabaseDriver())
+	}
+
+	if b.Type == BackupTypeArchive {
+		m, err := backup.Verify(backup.Archive{Name: b.Name, Path: b.fileName, CreatedAt: b.CreatedAt})
+
+		if err != nil {
+			return report, err
+		}
+
+		for _, f := range m.Files {
+			switch {
+			case strings.HasPrefix(f.Name, backup.AlbumsDir+"/"):
+				report.Albums++
+			case strings.HasPrefix(f.Name, backup.SidecarDir+"/"):
+				report.Sidecar++
+			case strings.HasPrefix(f.Name, backup.SettingsDir+"/"):
+				report.Settings = true
+			}
+		}
+	}
+
+	r, err := openBackupIndex(b)
+
+	if err != nil {
+		return report, err
+	}
+
+	defer r.Close()
+
+	rows, err := backup.CountRows(r)
+
+	if err != nil {
+		return report, err
+	}
+
+	for name := range entity.Entities {
+		var current int
+
+		if err = c.Db().Table(name).Count(&current).Error; err != nil {
+			log.Debugf("restore: %s in table %s", err, clean.Log(name))
+		}
+
+		report.Tables = append(report.Tables, RestoreTable{Name: name, Current: current, Backup: rows[name]})
+	}
+
+	sort.Slice(report.Tables, func(i, j int) bool {
+		return report.Tables[i].Name < report.Tables[j].Name
+	})
+
+	return report, nil
+}
+
+// RestoreBackup restores the index and, in case of backup archives, the album, sidecar, and settings files
+// from a backup. Maintenance mode is enabled in the meantime, so that the configuration is read-only and
+// requests that modify data are rejected. Afterwards, the database schema is migrated and caches are flushed.
+// Use PreviewRestore to verify the backup first.
+func RestoreBackup(b Backup) (err error) {
+	if err = StartRestore(); err != nil {
+		return err
+	}
+
+	return RunRestore(b)
+}
+
+// StartRestore marks the restore worker as running, so that other workers can no longer be started,
+// and returns ErrRestoreBusy if other workers are still running. Call RunRestore afterwards.
+func StartRestore() error {
+	if mutex.IndexWorkersRunning() || mutex.BackupWorker.Running() {
+		return ErrRestoreBusy
+	} else if err := mutex.RestoreWorker.Start(); err != nil {
+		return ErrRestoreBusy
+	}
+
+	// Another worker may have been started in the meantime.
+	if mutex.IndexWorkersRunning() || mutex.BackupWorker.Running() {
+		mutex.RestoreWorker.Stop()
+		return ErrRestoreBusy
+	}
+
+	return nil
+}
+
+// RunRestore restores a backup after StartRestore was successful and stops the restore worker when done.
+func RunRestore(b Backup) (err error) {
+	defer mutex.RestoreWorker.Stop()
+
+	c := Config()
+	start := time.Now()
+
+	c.SetMaintenance(true)
+	defer c.SetMaintenance(false)
+
+	event.Publish("restore.started", event.Data{"backup": b})
+	event.InfoMsg(i18n.MsgRestoringBackup, clean.Log(b.Name))
+
+	log.Infof("restore: restoring index from %s", clean.Log(b.Name))
+
+	if err = restoreBackup(b); err != nil {
+		log.Errorf("restore: %s", err)
+		event.Publish("restore.failed", event.Data{"backup": b, "error": err.Error()})
+		event.ErrorMsg(i18n.ErrUnexpected)
+		return err
+	}
+
+	log.Infoln("restore: migrating index database schema")
+
+	c.MigrateSchema(false, nil)
+	entity.FlushCaches()
+
+	if err := entity.UpdateCounts(); err != nil {
+		log.Warnf("restore: %s (update counts)", err)
+	}
+
+	elapsed := time.Since(start)
+
+	log.Infof("restore: backup %s restored in %s", clean.Log(b.Name), elapsed)
+
+	event.Publish("restore.completed", event.Data{"backup": b})
+	event.SuccessMsg(i18n.MsgBackupRestoredIn, int(elapsed.Seconds()))
+
+	return nil
+}
+
+// restoreBackup restores the index and files from a backup.
+func restoreBackup(b Backup) error {
+	c := Config()
+
+	r, err := openBackupIndex(b)
+
+	if err != nil {
+		return err
+	}
+
+	defer r.Close()
+
+	if err = RestoreIndex(r); err != nil {
+		return err
+	}
+
+	if b.Type != BackupTypeArchive {
+		return nil
+	}
+
+	a := backup.Archive{Name: b.Name, Path: b.fileName}
+	m, err := a.Manifest()
+
+	if err != nil {
+		return err
+	}
+
+	var count int
+
+	for _, f := range m.Files {
+		var dest string
+
+		switch {
+		case strings.HasPrefix(f.Name, backup.AlbumsDir+"/"):
+			dest = filepath.Join(c.AlbumsPath(), filepath.FromSlash(strings.TrimPrefix(f.Name, backup.AlbumsDir+"/")))
+		case strings.HasPrefix(f.Name, backup.SidecarDir+"/"):
+			dest = filepath.Join(c.SidecarPath(), filepath.FromSlash(strings.TrimPrefix(f.Name, backup.SidecarDir+"/")))
+		case f.Name == backup.SettingsDir+"/"+filepath.Base(c.SettingsYaml()):
+			dest = c.SettingsYaml()
+		default:
+			continue
+		}
+
+		if err = os.MkdirAll(filepath.Dir(dest), os.ModePerm); err != nil {
+			return err
+		} else if err = fs.Copy(a.FileName(f.Name), dest); err != nil {
+			return err
+		}
+
+		count++
+	}
+
+	log.Infof("restore: restored %s", english.Plural(count, "file", "files"))
+
+	return nil
+}
+
+// openBackupIndex opens the SQL dump of a backup, which is decompressed if needed.
+func openBackupIndex(b Backup) (io.ReadCloser, error) {
+	if b.Type != BackupTypeArchive {
+		return os.Open(b.fileName)
+	}
+
+	a := backup.Archive{Name: b.Name, Path: b.fileName}
+	m, err := a.Manifest()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range m.Files {
+		if strings.HasPrefix(f.Name, backup.IndexFile) {
+			return backup.OpenFile(a.FileName(f.Name))
+		}
+	}
+
+	return nil, fmt.Errorf("archive %s does not contain an SQL dump", clean.Log(b.Name))
+}
+
+// RestoreIndex restores the index database from an SQL dump read from in. Existing tables
+// are dropped first when using SQLite, since its dumps do not contain DROP TABLE statements.
+func RestoreIndex(in io.Reader) error {
+	c := Config()
+
+	var cmd *exec.Cmd
+
+	switch c.DatabaseDriver() {
+	case config.MySQL, config.MariaDB:
+		cmd = exec.Command(
+			c.MysqlBin(),
+			"--protocol", "tcp",
+			"-h", c.DatabaseHost(),
+			"-P", c.DatabasePortString(),
+			"-u", c.DatabaseUser(),
+			"-p"+c.DatabasePassword(),
+			"-f",
+			c.DatabaseName(),
+		)
+	case config.Postgres:
+		cmd = exec.Command(
+			c.PsqlBin(),
+			"-h", c.DatabaseHost(),
+			"-p", c.DatabasePortString(),
+			"-U", c.DatabaseUser(),
+			"-q",
+			c.DatabaseName(),
+		)
+		cmd.Env = append(os.Environ(), "PGPASSWORD="+c.DatabasePassword())
+	case config.SQLite3:
+		log.Infoln("dropping existing tables")
+		entity.Entities.Drop(c.Db())
+		cmd = exec.Command(
+			c.SqliteBin(),
+			c.DatabaseDsn(),
+		)
+	default:
+		return fmt.Errorf("unsupported database type: %s", c.DatabaseDriver())
+	}
+
+	// Fetch command output.
+	var out bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdin = in
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	// Log exact command for debugging in trace mode.
+	log.Trace(cmd.String())
+
+	// Run restore command.
+	if err := cmd.Run(); err != nil {
+		if stderr.String() != "" {
+			log.Debugln(stderr.String())
+			return fmt.Errorf("index could not be restored completely")
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package photoprism
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackups(t *testing.T) {
+	backups, err := Backups()
+
+	assert.NoError(t, err)
+
+	for _, b := range backups {
+		assert.NotEmpty(t, b.Name)
+		assert.Contains(t, []string{BackupTypeArchive, BackupTypeDump}, b.Type)
+	}
+}
+
+func TestFindBackup(t *testing.T) {
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := FindBackup("19000101-000000")
+		assert.Equal(t, ErrBackupNotFound, err)
+	})
+}
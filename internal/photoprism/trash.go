@@ -0,0 +1,355 @@
+package photoprism
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/dustin/go-humanize/english"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// ErrNotInTrash is returned if a photo has no files in the trash.
+var ErrNotInTrash = errors.New("not found in trash")
+
+// TrashPhoto moves the files of a photo to the trash and hides it, so that it can be restored until the
+// retention period has expired. If the trash is disabled, the photo and its files are deleted permanently.
+func TrashPhoto(p entity.Photo, deletedBy string) (numFiles int, err error) {
+	c := Config()
+
+	if c.TrashRetention() == 0 {
+		return DeletePhoto(p, true, true)
+	} else if p.ID < 1 || p.PhotoUID == "" {
+		return 0, fmt.Errorf("invalid photo id %d / uid %s", p.ID, clean.Log(p.PhotoUID))
+	}
+
+	now := entity.TimeStamp()
+	expires := now.AddDate(0, 0, c.TrashRetention())
+	trashed := 0
+
+	for _, file := range p.AllFiles() {
+		// Skip files that have already been deleted.
+		if file.DeletedAt != nil {
+			continue
+		}
+
+		m, err := trashFile(file, deletedBy, now, expires)
+
+		if err != nil {
+			return numFiles, err
+		}
+
+		m.PhotoDeleted = true
+		m.PhotoArchived = p.DeletedAt != nil
+
+		if err = m.Create(); err != nil {
+			return numFiles, err
+		}
+
+		if m.TrashName != "" {
+			numFiles++
+		}
+
+		trashed++
+	}
+
+	// Add an entry without file so that the photo can be restored.
+	if trashed == 0 {
+		m := entity.TrashFile{
+			PhotoUID:      p.PhotoUID,
+			PhotoDeleted:  true,
+			PhotoArchived: p.DeletedAt != nil,
+			DeletedBy:     deletedBy,
+			CreatedAt:     now,
+			ExpiresAt:     expires,
+		}
+
+		if err = m.Create(); err != nil {
+			return numFiles, err
+		}
+	}
+
+	// Hide photo.
+	values := entity.Values{"PhotoQuality": -1}
+
+	if p.DeletedAt == nil {
+		values["DeletedAt"] = now
+	}
+
+	if err = p.Updates(values); err != nil {
+		return numFiles, err
+	}
+
+	log.Infof("trash: moved %s of %s to trash", english.Plural(numFiles, "file", "files"), clean.Log(p.PhotoUID))
+
+	return numFiles, nil
+}
+
+// TrashPhotoFile moves a single file of a photo to the trash, so that it can be restored until the retention period
+// has expired. If the trash is disabled, the file is deleted permanently.
+func TrashPhotoFile(file entity.File, deletedBy string) error {
+	c := Config()
+
+	if c.TrashRetention() == 0 {
+		if f, err := NewMediaFile(FileName(file.FileRoot, file.FileName)); err == nil {
+			if err = f.Remove(); err != nil {
+				log.Errorf("files: %s (delete %s)", err, clean.Log(file.FileName))
+			}
+		}
+
+		return file.Delete(true)
+	}
+
+	now := entity.TimeStamp()
+
+	m, err := trashFile(file, deletedBy, now, now.AddDate(0, 0, c.TrashRetention()))
+
+	if err != nil {
+		return err
+	}
+
+	return m.Create()
+}
+
+// trashFile moves a file to the trash, removes it from the index, and returns a new trash entry.
+func trashFile(file entity.File, deletedBy string, now, expires time.Time) (m entity.TrashFile, err error) {
+	m = entity.TrashFile{
+		PhotoUID:  file.PhotoUID,
+		FileUID:   file.FileUID,
+		FileRoot:  file.FileRoot,
+		FileName:  file.FileName,
+		FileSize:  file.FileSize,
+		DeletedBy: deletedBy,
+		CreatedAt: now,
+		ExpiresAt: expires,
+	}
+
+	fileName := FileName(file.FileRoot, file.FileName)
+
+	// Move the file to the trash if it exists.
+	if fs.FileExists(fileName) {
+		m.TrashName = trashName(file.PhotoUID, file.FileRoot, file.FileName)
+
+		if err = moveFile(fileName, filepath.Join(Config().TrashPath(), m.TrashName)); err != nil {
+			return m, err
+		}
+
+		log.Infof("trash: moved %s to trash", clean.Log(file.FileName))
+	}
+
+	return m, file.Delete(false)
+}
+
+// RestoreTrash restores the files of a photo from the trash, as well as the photo itself if it was deleted.
+func RestoreTrash(photoUid string) (numFiles int, err error) {
+	trashed, err := entity.FindTrashFiles(photoUid)
+
+	if err != nil {
+		return 0, err
+	} else if len(trashed) == 0 {
+		return 0, ErrNotInTrash
+	}
+
+	trashPath := Config().TrashPath()
+	photoDeleted := false
+	photoArchived := false
+
+	for _, m := range trashed {
+		if m.PhotoDeleted {
+			photoDeleted = true
+			photoArchived = m.PhotoArchived
+		}
+
+		if m.FileUID != "" {
+			file := entity.File{}
+
+			if err = entity.UnscopedDb().Where("file_uid = ?", m.FileUID).First(&file).Error; err != nil {
+				log.Warnf("trash: file %s not found in index", clean.Log(m.FileUID))
+			} else if m.TrashName == "" {
+				// File did not exist when it was deleted.
+				err = file.Updates(entity.Values{"DeletedAt": nil})
+			} else if err = moveFile(filepath.Join(trashPath, m.TrashName), FileName(m.FileRoot, m.FileName)); err != nil {
+				return numFiles, err
+			} else {
+				numFiles++
+				err = file.Undelete()
+			}
+
+			if err != nil {
+				log.Errorf("trash: %s (restore %s)", err, clean.Log(m.FileName))
+			}
+		}
+
+		if err = m.Delete(); err != nil {
+			return numFiles, err
+		}
+	}
+
+	if photoDeleted {
+		if p := entity.FindPhoto(entity.Photo{PhotoUID: photoUid}); p == nil {
+			log.Warnf("trash: photo %s not found in index", clean.Log(photoUid))
+		} else if !photoArchived {
+			if err = p.Restore(); err != nil {
+				return numFiles, err
+			}
+		}
+
+		if p := entity.FindPhoto(entity.Photo{PhotoUID: photoUid}); p != nil {
+			if err = p.UpdateQuality(); err != nil {
+				log.Warnf("trash: %s (update quality of %s)", err, clean.Log(photoUid))
+			}
+		}
+	}
+
+	removeTrashDir(trashPath, photoUid)
+
+	log.Infof("trash: restored %s of %s", english.Plural(numFiles, "file", "files"), clean.Log(photoUid))
+
+	return numFiles, nil
+}
+
+// EmptyTrash permanently removes files from the trash, or only those whose retention period has expired.
+// Deleted photos are removed from the index once all of their files have been removed.
+func EmptyTrash(expiredOnly bool) (numPhotos, numFiles int, err error) {
+	var trashed entity.TrashFiles
+
+	if expiredOnly {
+		trashed, err = entity.ExpiredTrashFiles(entity.TimeStamp())
+	} else {
+		trashed, err = entity.ListTrashFiles(0, 0)
+	}
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return RemoveTrash(trashed)
+}
+
+// RemoveTrash permanently removes the specified files from the trash.
+// Deleted photos are removed from the index once all of their files have been removed.
+func RemoveTrash(trashed entity.TrashFiles) (numPhotos, numFiles int, err error) {
+	if len(trashed) == 0 {
+		return 0, 0, nil
+	}
+
+	trashPath := Config().TrashPath()
+	photos := make(map[string]bool)
+
+	for _, m := range trashed {
+		if m.TrashName != "" {
+			if err = removeTrashFile(filepath.Join(trashPath, m.TrashName)); err != nil {
+				log.Errorf("trash: %s (remove %s)", err, clean.Log(m.FileName))
+				continue
+			}
+
+			numFiles++
+		}
+
+		if m.PhotoDeleted {
+			photos[m.PhotoUID] = true
+		} else if m.FileUID != "" {
+			file := entity.File{}
+
+			if err = entity.UnscopedDb().Where("file_uid = ?", m.FileUID).First(&file).Error; err == nil {
+				_ = DeleteFiles(entity.Files{file}, false)
+
+				if err = file.DeletePermanently(); err != nil {
+					log.Errorf("trash: %s (remove %s from index)", err, clean.Log(m.FileName))
+				}
+			}
+		}
+
+		if err = m.Delete(); err != nil {
+			return numPhotos, numFiles, err
+		}
+	}
+
+	// Remove deleted photos from the index once all of their files have been removed.
+	for photoUid := range photos {
+		if remaining, err := entity.FindTrashFiles(photoUid); err != nil || len(remaining) > 0 {
+			continue
+		} else if p := entity.FindPhoto(entity.Photo{PhotoUID: photoUid}); p == nil {
+			// Already removed.
+		} else if _, err = DeletePhoto(*p, true, false); err != nil {
+			log.Errorf("trash: %s (remove %s from index)", err, clean.Log(photoUid))
+			continue
+		}
+
+		numPhotos++
+	}
+
+	for _, photoUid := range trashed.PhotoUIDs() {
+		removeTrashDir(trashPath, photoUid)
+	}
+
+	if numPhotos > 0 {
+		if err = entity.UpdateCounts(); err != nil {
+			log.Warnf("trash: %s (update counts)", err)
+		}
+	}
+
+	log.Infof("trash: permanently removed %s and %s", english.Plural(numPhotos, "photo", "photos"), english.Plural(numFiles, "file", "files"))
+
+	return numPhotos, numFiles, nil
+}
+
+// trashName returns the file name relative to the trash path.
+func trashName(photoUid, fileRoot, fileName string) string {
+	if fileRoot == entity.RootOriginals || fileRoot == entity.RootUnknown {
+		fileRoot = "originals"
+	}
+
+	return path.Join(photoUid, fileRoot, filepath.ToSlash(fileName))
+}
+
+// moveFile moves a file along with its JSON sidecar file, if any.
+func moveFile(src, dest string) error {
+	if fs.FileExists(dest) {
+		return fmt.Errorf("%s already exists", clean.Log(filepath.Base(dest)))
+	} else if err := os.MkdirAll(filepath.Dir(dest), os.ModePerm); err != nil {
+		return err
+	} else if err = fs.Move(src, dest); err != nil {
+		return err
+	}
+
+	if jsonFile := src + ".json"; fs.FileExists(jsonFile) {
+		if err := fs.Move(jsonFile, dest+".json"); err != nil {
+			log.Warnf("trash: %s (move %s)", err, clean.Log(filepath.Base(jsonFile)))
+		}
+	}
+
+	return nil
+}
+
+// removeTrashFile permanently removes a file from the trash along with its JSON sidecar file, if any.
+func removeTrashFile(fileName string) error {
+	if jsonFile := fileName + ".json"; fs.FileExists(jsonFile) {
+		_ = os.Remove(jsonFile)
+	}
+
+	if err := os.Remove(fileName); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// removeTrashDir removes the trash folder of a photo if it no longer contains any files.
+func removeTrashDir(trashPath, photoUid string) {
+	if photoUid == "" {
+		return
+	} else if remaining, err := entity.FindTrashFiles(photoUid); err != nil || len(remaining) > 0 {
+		return
+	}
+
+	if err := os.RemoveAll(filepath.Join(trashPath, photoUid)); err != nil {
+		log.Warnf("trash: %s", err)
+	}
+}
@@ -0,0 +1,73 @@
+package photoprism
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+)
+
+func TestTrashName(t *testing.T) {
+	t.Run("Originals", func(t *testing.T) {
+		assert.Equal(t, "pt9jtdre2lvl0yh7/originals/2019/01/photo.jpg", trashName("pt9jtdre2lvl0yh7", entity.RootOriginals, "2019/01/photo.jpg"))
+	})
+	t.Run("Sidecar", func(t *testing.T) {
+		assert.Equal(t, "pt9jtdre2lvl0yh7/sidecar/2019/01/photo.jpg", trashName("pt9jtdre2lvl0yh7", entity.RootSidecar, "2019/01/photo.jpg"))
+	})
+}
+
+func TestRestoreTrash(t *testing.T) {
+	t.Run("NotInTrash", func(t *testing.T) {
+		n, err := RestoreTrash("pt9jtdre2lvl0y11")
+		assert.Equal(t, ErrNotInTrash, err)
+		assert.Equal(t, 0, n)
+	})
+	t.Run("InvalidUID", func(t *testing.T) {
+		_, err := RestoreTrash("xxx")
+		assert.Error(t, err)
+	})
+	t.Run("AfterCleanUp", func(t *testing.T) {
+		conf := config.TestConfig()
+		retention := conf.Options().TrashRetention
+		conf.Options().TrashRetention = 30
+		defer func() { conf.Options().TrashRetention = retention }()
+
+		p := entity.NewPhoto(false)
+
+		if err := p.Create(); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := TrashPhoto(p, "test"); err != nil {
+			t.Fatal(err)
+		}
+
+		// Photos in the trash must not be removed as orphans.
+		if _, _, _, err := NewCleanUp(conf).Start(CleanUpOptions{}); err != nil {
+			t.Fatal(err)
+		}
+
+		if found := entity.FindPhoto(entity.Photo{PhotoUID: p.PhotoUID}); assert.NotNil(t, found) {
+			assert.NotNil(t, found.DeletedAt)
+		}
+
+		_, err := RestoreTrash(p.PhotoUID)
+
+		assert.NoError(t, err)
+
+		if found := entity.FindPhoto(entity.Photo{PhotoUID: p.PhotoUID}); assert.NotNil(t, found) {
+			assert.Nil(t, found.DeletedAt)
+		}
+	})
+}
+
+func TestEmptyTrash(t *testing.T) {
+	t.Run("Expired", func(t *testing.T) {
+		numPhotos, numFiles, err := EmptyTrash(true)
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, numPhotos, 0)
+		assert.Equal(t, 0, numFiles)
+	})
+}
@@ -7,8 +7,8 @@ import (
 
 // AccountUploads a list of files for uploading to a remote account.
 func AccountUploads(a entity.Service, limit int) (results entity.Files, err error) {
-	s := Db().Where("files.file_missing = 0").
-		Where("files.id NOT IN (SELECT file_id FROM files_sync WHERE file_id > 0 AND service_id = ?)", a.ID)
+	s := Db().Where("files.file_missing = FALSE").
+		Where("files.id NOT IN (SELECT file_id FROM files_sync WHERE file_id > 0 AND service_id = ? AND status <> ?)", a.ID, entity.FileSyncPending)
 
 	if !a.SyncRaw {
 		s = s.Where("files.file_type <> ? OR files.file_type IS NULL", fs.ImageRaw)
@@ -44,7 +44,7 @@ func AlbumCoverByUID(uid string, public bool) (file entity.File, err error) {
 			return file, err
 		} else if len(photos) > 0 {
 			for _, photo := range photos {
-				if err := Db().Where("photo_uid = ? AND file_primary = 1", photo.PhotoUID).First(&file).Error; err != nil {
+				if err := Db().Where("photo_uid = ? AND file_primary = TRUE", photo.PhotoUID).First(&file).Error; err != nil {
 					return file, err
 				} else {
 					return file, nil
@@ -67,14 +67,14 @@ func AlbumCoverByUID(uid string, public bool) (file entity.File, err error) {
 	}
 
 	// Build query.
-	stmt := Db().Where("files.file_primary = 1 AND files.file_missing = 0 AND files.file_type = 'jpg' AND files.deleted_at IS NULL").
+	stmt := Db().Where("files.file_primary = TRUE AND files.file_missing = FALSE AND files.file_type = 'jpg' AND files.deleted_at IS NULL").
 		Joins("JOIN albums ON albums.album_uid = ?", uid).
-		Joins("JOIN photos_albums pa ON pa.album_uid = albums.album_uid AND pa.photo_uid = files.photo_uid AND pa.hidden = 0").
+		Joins("JOIN photos_albums pa ON pa.album_uid = albums.album_uid AND pa.photo_uid = files.photo_uid AND pa.hidden = FALSE").
 		Joins("JOIN photos ON photos.id = files.photo_id AND photos.deleted_at IS NULL")
 
 	// Public pictures only?
 	if public {
-		stmt = stmt.Where("photos.photo_private = 0")
+		stmt = stmt.Where("photos.photo_private = FALSE")
 	}
 
 	// Find first picture.
@@ -100,6 +100,13 @@ func UpdateAlbumDates() error {
 			 GROUP BY photo_path) AS p ON albums.album_path = p.photo_path
 		SET albums.album_year = YEAR(taken_max), albums.album_month = MONTH(taken_max), albums.album_day = DAY(taken_max)
 		WHERE albums.album_type = 'folder' AND albums.album_path IS NOT NULL AND p.taken_max IS NOT NULL`).Error
+	case Postgres:
+		return UnscopedDb().Exec(`UPDATE albums
+		SET album_year = EXTRACT(YEAR FROM p.taken_max), album_month = EXTRACT(MONTH FROM p.taken_max), album_day = EXTRACT(DAY FROM p.taken_max)
+		FROM (SELECT photo_path, MAX(taken_at_local) AS taken_max
+			FROM photos WHERE taken_src = 'meta' AND photos.photo_quality >= 3 AND photos.deleted_at IS NULL
+			GROUP BY photo_path) AS p
+		WHERE albums.album_path = p.photo_path AND albums.album_type = 'folder' AND albums.album_path IS NOT NULL AND p.taken_max IS NOT NULL`).Error
 	default:
 		return nil
 	}
@@ -112,7 +119,7 @@ func UpdateMissingAlbumEntries() error {
 
 	switch DbDialect() {
 	default:
-		return UnscopedDb().Exec(`UPDATE photos_albums SET missing = 1 WHERE photo_uid IN
+		return UnscopedDb().Exec(`UPDATE photos_albums SET missing = TRUE WHERE photo_uid IN
 		(SELECT photo_uid FROM photos WHERE deleted_at IS NOT NULL OR photo_quality < 0)`).Error
 	}
 }
@@ -121,7 +128,7 @@ func UpdateMissingAlbumEntries() error {
 func AlbumEntryFound(uid string) error {
 	switch DbDialect() {
 	default:
-		return UnscopedDb().Exec(`UPDATE photos_albums SET missing = 0 WHERE photo_uid = ?`, uid).Error
+		return UnscopedDb().Exec(`UPDATE photos_albums SET missing = FALSE WHERE photo_uid = ?`, uid).Error
 	}
 }
 
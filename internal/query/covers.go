@@ -28,18 +28,18 @@ func UpdateAlbumDefaultCovers() (err error) {
 		res = Db().Exec(`UPDATE albums LEFT JOIN (
     	SELECT p2.album_uid, f.file_hash FROM files f, (
         	SELECT pa.album_uid, max(p.id) AS photo_id FROM photos p
-            JOIN photos_albums pa ON pa.photo_uid = p.photo_uid AND pa.hidden = 0 AND pa.missing = 0
-        	WHERE p.photo_quality > 0 AND p.photo_private = 0 AND p.deleted_at IS NULL
-        	GROUP BY pa.album_uid) p2 WHERE p2.photo_id = f.photo_id AND f.file_primary = 1 AND f.file_error = '' AND f.file_type = 'jpg'
+            JOIN photos_albums pa ON pa.photo_uid = p.photo_uid AND pa.hidden = FALSE AND pa.missing = FALSE
+        	WHERE p.photo_quality > 0 AND p.photo_private = FALSE AND p.deleted_at IS NULL
+        	GROUP BY pa.album_uid) p2 WHERE p2.photo_id = f.photo_id AND f.file_primary = TRUE AND f.file_error = '' AND f.file_type = 'jpg'
 			) b ON b.album_uid = albums.album_uid
 		SET thumb = b.file_hash WHERE ?`, condition)
-	case SQLite3:
+	case Postgres, SQLite3:
 		res = Db().Table(entity.Album{}.TableName()).
 			UpdateColumn("thumb", gorm.Expr(`(
 		SELECT f.file_hash FROM files f 
-			JOIN photos_albums pa ON pa.album_uid = albums.album_uid AND pa.photo_uid = f.photo_uid AND pa.hidden = 0 AND pa.missing = 0
-			JOIN photos p ON p.id = f.photo_id AND p.photo_private = 0 AND p.deleted_at IS NULL AND p.photo_quality > 0
-			WHERE f.deleted_at IS NULL AND f.file_missing = 0 AND f.file_hash <> '' AND f.file_primary = 1 AND f.file_error = '' AND f.file_type = 'jpg' 
+			JOIN photos_albums pa ON pa.album_uid = albums.album_uid AND pa.photo_uid = f.photo_uid AND pa.hidden = FALSE AND pa.missing = FALSE
+			JOIN photos p ON p.id = f.photo_id AND p.photo_private = FALSE AND p.deleted_at IS NULL AND p.photo_quality > 0
+			WHERE f.deleted_at IS NULL AND f.file_missing = FALSE AND f.file_hash <> '' AND f.file_primary = TRUE AND f.file_error = '' AND f.file_type = 'jpg' 
 			ORDER BY p.taken_at DESC LIMIT 1
 		) WHERE ?`, condition))
 	default:
@@ -76,18 +76,18 @@ func UpdateAlbumFolderCovers() (err error) {
 		res = Db().Exec(`UPDATE albums LEFT JOIN (
 		SELECT p2.photo_path, f.file_hash FROM files f, (
 			SELECT p.photo_path, max(p.id) AS photo_id FROM photos p
-			WHERE p.photo_quality > 0 AND p.photo_private = 0 AND p.deleted_at IS NULL
-			GROUP BY p.photo_path) p2 WHERE p2.photo_id = f.photo_id AND f.file_primary = 1 AND f.file_error = '' AND f.file_type = 'jpg'
+			WHERE p.photo_quality > 0 AND p.photo_private = FALSE AND p.deleted_at IS NULL
+			GROUP BY p.photo_path) p2 WHERE p2.photo_id = f.photo_id AND f.file_primary = TRUE AND f.file_error = '' AND f.file_type = 'jpg'
 			) b ON b.photo_path = albums.album_path
 		SET thumb = b.file_hash WHERE ?`, condition)
-	case SQLite3:
+	case Postgres, SQLite3:
 		res = Db().Table(entity.Album{}.TableName()).UpdateColumn("thumb", gorm.Expr(`(
 		SELECT f.file_hash FROM files f,(
 			SELECT p.photo_path, max(p.id) AS photo_id FROM photos p
-			  WHERE p.photo_quality > 0 AND p.photo_private = 0 AND p.deleted_at IS NULL
+			  WHERE p.photo_quality > 0 AND p.photo_private = FALSE AND p.deleted_at IS NULL
 			  GROUP BY p.photo_path
 			) b
-		WHERE f.photo_id = b.photo_id  AND f.file_primary = 1 AND f.file_error = '' AND f.file_type = 'jpg'
+		WHERE f.photo_id = b.photo_id  AND f.file_primary = TRUE AND f.file_error = '' AND f.file_type = 'jpg'
 		AND b.photo_path = albums.album_path LIMIT 1)
 		WHERE ?`, condition))
 	default:
@@ -124,18 +124,18 @@ func UpdateAlbumMonthCovers() (err error) {
 		res = Db().Exec(`UPDATE albums LEFT JOIN (
 		SELECT p2.photo_year, p2.photo_month, f.file_hash FROM files f, (
 			SELECT p.photo_year, p.photo_month, max(p.id) AS photo_id FROM photos p
-			WHERE p.photo_quality > 0 AND p.photo_private = 0 AND p.deleted_at IS NULL
-			GROUP BY p.photo_year, p.photo_month) p2 WHERE p2.photo_id = f.photo_id AND f.file_primary = 1 AND f.file_error = '' AND f.file_type = 'jpg'
+			WHERE p.photo_quality > 0 AND p.photo_private = FALSE AND p.deleted_at IS NULL
+			GROUP BY p.photo_year, p.photo_month) p2 WHERE p2.photo_id = f.photo_id AND f.file_primary = TRUE AND f.file_error = '' AND f.file_type = 'jpg'
 			) b ON b.photo_year = albums.album_year AND b.photo_month = albums.album_month
 		SET thumb = b.file_hash WHERE ?`, condition)
-	case SQLite3:
+	case Postgres, SQLite3:
 		res = Db().Table(entity.Album{}.TableName()).UpdateColumn("thumb", gorm.Expr(`(
 		SELECT f.file_hash FROM files f,(
 			SELECT p.photo_year, p.photo_month, max(p.id) AS photo_id FROM photos p
-			  WHERE p.photo_quality > 0 AND p.photo_private = 0 AND p.deleted_at IS NULL
+			  WHERE p.photo_quality > 0 AND p.photo_private = FALSE AND p.deleted_at IS NULL
 			  GROUP BY p.photo_year, p.photo_month
 			) b
-		WHERE f.photo_id = b.photo_id AND f.file_primary = 1 AND f.file_error = '' AND f.file_type = 'jpg'
+		WHERE f.photo_id = b.photo_id AND f.file_primary = TRUE AND f.file_error = '' AND f.file_type = 'jpg'
 		AND b.photo_year = albums.album_year AND b.photo_month = albums.album_month LIMIT 1)
 		WHERE ?`, condition))
 	default:
@@ -193,23 +193,23 @@ func UpdateLabelCovers() (err error) {
 		SELECT p2.label_id, f.file_hash FROM files f, (
 			SELECT pl.label_id as label_id, max(p.id) AS photo_id FROM photos p
 				JOIN photos_labels pl ON pl.photo_id = p.id AND pl.uncertainty < 100
-			WHERE p.photo_quality > 0 AND p.photo_private = 0 AND p.deleted_at IS NULL
+			WHERE p.photo_quality > 0 AND p.photo_private = FALSE AND p.deleted_at IS NULL
 			GROUP BY pl.label_id
 			UNION
 			SELECT c.category_id as label_id, max(p.id) AS photo_id FROM photos p
 				JOIN photos_labels pl ON pl.photo_id = p.id AND pl.uncertainty < 100
 				JOIN categories c ON c.label_id = pl.label_id
-			WHERE p.photo_quality > 0 AND p.photo_private = 0 AND p.deleted_at IS NULL
+			WHERE p.photo_quality > 0 AND p.photo_private = FALSE AND p.deleted_at IS NULL
 			GROUP BY c.category_id
-			) p2 WHERE p2.photo_id = f.photo_id AND f.file_primary = 1 AND f.file_error = '' AND f.file_type = 'jpg' AND f.file_missing = 0
+			) p2 WHERE p2.photo_id = f.photo_id AND f.file_primary = TRUE AND f.file_error = '' AND f.file_type = 'jpg' AND f.file_missing = FALSE
 		) b ON b.label_id = labels.id
 		SET thumb = b.file_hash WHERE ?`, condition)
-	case SQLite3:
+	case Postgres, SQLite3:
 		res = Db().Table(entity.Label{}.TableName()).UpdateColumn("thumb", gorm.Expr(`(
 		SELECT f.file_hash FROM files f 
 			JOIN photos_labels pl ON pl.label_id = labels.id AND pl.photo_id = f.photo_id AND pl.uncertainty < 100
-			JOIN photos p ON p.id = f.photo_id AND p.photo_private = 0 AND p.deleted_at IS NULL AND p.photo_quality > 0
-			WHERE f.deleted_at IS NULL AND f.file_hash <> '' AND f.file_missing = 0 AND f.file_primary = 1 AND f.file_error = '' AND f.file_type = 'jpg' 
+			JOIN photos p ON p.id = f.photo_id AND p.photo_private = FALSE AND p.deleted_at IS NULL AND p.photo_quality > 0
+			WHERE f.deleted_at IS NULL AND f.file_hash <> '' AND f.file_missing = FALSE AND f.file_primary = TRUE AND f.file_error = '' AND f.file_type = 'jpg' 
 			ORDER BY p.photo_quality DESC, pl.uncertainty ASC, p.taken_at DESC LIMIT 1
 		) WHERE ?`, condition))
 
@@ -218,8 +218,8 @@ func UpdateLabelCovers() (err error) {
 			SELECT f.file_hash FROM files f 
 			JOIN photos_labels pl ON pl.photo_id = f.photo_id AND pl.uncertainty < 100
 			JOIN categories c ON c.label_id = pl.label_id AND c.category_id = labels.id
-			JOIN photos p ON p.id = f.photo_id AND p.photo_private = 0 AND p.deleted_at IS NULL AND p.photo_quality > 0
-			WHERE f.deleted_at IS NULL AND f.file_hash <> '' AND f.file_missing = 0 AND f.file_primary = 1 AND f.file_error = '' AND f.file_type = 'jpg' 
+			JOIN photos p ON p.id = f.photo_id AND p.photo_private = FALSE AND p.deleted_at IS NULL AND p.photo_quality > 0
+			WHERE f.deleted_at IS NULL AND f.file_hash <> '' AND f.file_missing = FALSE AND f.file_primary = TRUE AND f.file_error = '' AND f.file_type = 'jpg' 
 			ORDER BY p.photo_quality DESC, pl.uncertainty ASC, p.taken_at DESC LIMIT 1
 			) WHERE thumb IS NULL`))
 
@@ -266,11 +266,11 @@ func UpdateSubjectCovers() (err error) {
 		res = Db().Exec(`UPDATE ? LEFT JOIN (
     	SELECT m.subj_uid, m.q, MAX(m.thumb) AS marker_thumb FROM ? m
 			WHERE m.subj_uid <> '' AND m.subj_uid IS NOT NULL
-			  AND m.marker_invalid = 0 AND m.thumb IS NOT NULL AND m.thumb <> ''
+			  AND m.marker_invalid = FALSE AND m.thumb IS NOT NULL AND m.thumb <> ''
 			GROUP BY m.subj_uid, m.q
 			) b ON b.subj_uid = subjects.subj_uid
 		SET thumb = marker_thumb WHERE ?`, gorm.Expr(subjTable), gorm.Expr(markerTable), condition)
-	case SQLite3:
+	case Postgres, SQLite3:
 		from := gorm.Expr(fmt.Sprintf("%s m WHERE m.subj_uid = %s.subj_uid ", markerTable, subjTable))
 		res = Db().Table(entity.Subject{}.TableName()).UpdateColumn("thumb", gorm.Expr(`(
 		SELECT m.thumb FROM ? AND m.thumb <> '' ORDER BY m.subj_src DESC, m.q DESC LIMIT 1
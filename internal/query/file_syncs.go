@@ -30,3 +30,20 @@ func FileSyncs(accountId uint, status string, limit int) (result []entity.FileSy
 
 	return result, nil
 }
+
+// FileSyncConflicts returns files of an account for which conflicting changes have been resolved,
+// most recent first.
+func FileSyncConflicts(accountId uint, limit, offset int) (result []entity.FileSync, err error) {
+	s := Db().Where("service_id = ? AND conflict <> ''", accountId).
+		Order("updated_at DESC, remote_name ASC")
+
+	if limit > 0 {
+		s = s.Limit(limit).Offset(offset)
+	}
+
+	if err := s.Find(&result).Error; err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
@@ -31,3 +31,27 @@ func TestFileSyncs(t *testing.T) {
 		}
 	})
 }
+
+func TestFileSyncConflicts(t *testing.T) {
+	t.Run("Found", func(t *testing.T) {
+		r, err := FileSyncConflicts(uint(1000001), 10, 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.Len(t, r, 1) {
+			assert.Equal(t, "/20200706-092527-Conflict-2020.jpg", r[0].RemoteName)
+			assert.Equal(t, entity.SyncConflictKeep, r[0].Conflict)
+		}
+	})
+	t.Run("None", func(t *testing.T) {
+		r, err := FileSyncConflicts(uint(1000000), 10, 0)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Empty(t, r)
+	})
+}
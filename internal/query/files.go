@@ -19,7 +19,7 @@ func FilesByPath(limit, offset int, rootName, pathName string) (files entity.Fil
 	err = Db().
 		Table("files").Select("files.*").
 		Joins("JOIN photos ON photos.id = files.photo_id AND photos.deleted_at IS NULL").
-		Where("files.file_missing = 0 AND files.file_root = ?", rootName).
+		Where("files.file_missing = FALSE AND files.file_root = ?", rootName).
 		Where("photos.photo_path = ?", pathName).
 		Order("files.file_name").
 		Limit(limit).Offset(offset).
@@ -37,7 +37,7 @@ func Files(limit, offset int, pathName string, includeMissing bool) (files entit
 	stmt := Db()
 
 	if !includeMissing {
-		stmt = stmt.Where("file_missing = 0")
+		stmt = stmt.Where("file_missing = FALSE")
 	}
 
 	if pathName != "" {
@@ -51,7 +51,7 @@ func Files(limit, offset int, pathName string, includeMissing bool) (files entit
 
 // FilesByUID finds files for the given UIDs.
 func FilesByUID(u []string, limit int, offset int) (files entity.Files, err error) {
-	if err := Db().Where("(photo_uid IN (?) AND file_primary = 1) OR file_uid IN (?)", u, u).Preload("Photo").Limit(limit).Offset(offset).Find(&files).Error; err != nil {
+	if err := Db().Where("(photo_uid IN (?) AND file_primary = TRUE) OR file_uid IN (?)", u, u).Preload("Photo").Limit(limit).Offset(offset).Find(&files).Error; err != nil {
 		return files, err
 	}
 
@@ -66,7 +66,7 @@ func FileByPhotoUID(photoUID string) (*entity.File, error) {
 		return &f, fmt.Errorf("photo uid required")
 	}
 
-	err := Db().Where("photo_uid = ? AND file_primary = 1", photoUID).Preload("Photo").First(&f).Error
+	err := Db().Where("photo_uid = ? AND file_primary = TRUE", photoUID).Preload("Photo").First(&f).Error
 	return &f, err
 }
 
@@ -78,7 +78,7 @@ func VideoByPhotoUID(photoUID string) (*entity.File, error) {
 		return &f, fmt.Errorf("photo uid required")
 	}
 
-	err := Db().Where("photo_uid = ? AND (file_video = 1 OR file_type = ?)", photoUID, fs.ImageGIF).
+	err := Db().Where("photo_uid = ? AND (file_video = TRUE OR file_type = ?)", photoUID, fs.ImageGIF).
 		Order("file_video DESC, file_duration DESC, file_frames DESC").
 		Preload("Photo").First(&f).Error
 	return &f, err
@@ -115,7 +115,7 @@ func RenameFile(srcRoot, srcName, destRoot, destName string) error {
 		return fmt.Errorf("cannot rename %s/%s to %s/%s", srcRoot, srcName, destRoot, destName)
 	}
 
-	return Db().Exec("UPDATE files SET file_root = ?, file_name = ?, file_missing = 0, deleted_at = NULL WHERE file_root = ? AND file_name = ?", destRoot, destName, srcRoot, srcName).Error
+	return Db().Exec("UPDATE files SET file_root = ?, file_name = ?, file_missing = FALSE, deleted_at = NULL WHERE file_root = ? AND file_name = ?", destRoot, destName, srcRoot, srcName).Error
 }
 
 // SetPhotoPrimary sets a new primary image file for a photo.
@@ -128,7 +128,7 @@ func SetPhotoPrimary(photoUID, fileUID string) (err error) {
 
 	if fileUID != "" {
 		// Do nothing.
-	} else if err := Db().Model(entity.File{}).Where("photo_uid = ? AND file_missing = 0 AND file_type = 'jpg'", photoUID).Order("file_width DESC, file_hdr DESC").Limit(1).Pluck("file_uid", &files).Error; err != nil {
+	} else if err := Db().Model(entity.File{}).Where("photo_uid = ? AND file_missing = FALSE AND file_type = 'jpg'", photoUID).Order("file_width DESC, file_hdr DESC").Limit(1).Pluck("file_uid", &files).Error; err != nil {
 		return err
 	} else if len(files) == 0 {
 		return fmt.Errorf("cannot find primary file for %s", photoUID)
@@ -142,11 +142,11 @@ func SetPhotoPrimary(photoUID, fileUID string) (err error) {
 
 	if err = Db().Model(entity.File{}).
 		Where("photo_uid = ? AND file_uid <> ?", photoUID, fileUID).
-		UpdateColumn("file_primary", 0).Error; err != nil {
+		UpdateColumn("file_primary", false).Error; err != nil {
 		return err
 	} else if err = Db().
 		Model(entity.File{}).Where("photo_uid = ? AND file_uid = ?", photoUID, fileUID).
-		UpdateColumn("file_primary", 1).Error; err != nil {
+		UpdateColumn("file_primary", true).Error; err != nil {
 		return err
 	} else {
 		entity.File{PhotoUID: photoUID}.RegenerateIndex()
@@ -188,7 +188,7 @@ func IndexedFiles() (result FileMap, err error) {
 	// Query indexed files.
 	var files []File
 
-	if err := UnscopedDb().Raw("SELECT file_root, file_name, mod_time FROM files WHERE file_missing = 0 AND deleted_at IS NULL").Scan(&files).Error; err != nil {
+	if err := UnscopedDb().Raw("SELECT file_root, file_name, mod_time FROM files WHERE file_missing = FALSE AND deleted_at IS NULL").Scan(&files).Error; err != nil {
 		return result, err
 	}
 
@@ -41,7 +41,7 @@ func FoldersByPath(rootName, rootPath, path string, recursive bool) (folders ent
 
 // FolderCoverByUID returns a folder cover file based on the uid.
 func FolderCoverByUID(uid string) (file entity.File, err error) {
-	if err := Db().Where("files.file_primary = 1 AND files.file_missing = 0 AND files.file_type = 'jpg' AND files.deleted_at IS NULL").
+	if err := Db().Where("files.file_primary = TRUE AND files.file_missing = FALSE AND files.file_type = 'jpg' AND files.deleted_at IS NULL").
 		Joins("JOIN photos ON photos.id = files.photo_id AND photos.deleted_at IS NULL AND photos.photo_quality > -1").
 		Joins("JOIN folders ON photos.photo_path = folders.path AND folders.folder_uid = ?", uid).
 		Order("photos.photo_quality DESC").
@@ -82,6 +82,13 @@ func UpdateFolderDates() error {
 			GROUP BY photo_path) AS p ON folders.path = p.photo_path
 		SET folders.folder_year = YEAR(taken_max), folders.folder_month = MONTH(taken_max), folders.folder_day = DAY(taken_max)
 		WHERE p.taken_max IS NOT NULL`).Error
+	case Postgres:
+		return UnscopedDb().Exec(`UPDATE folders
+		SET folder_year = EXTRACT(YEAR FROM p.taken_max), folder_month = EXTRACT(MONTH FROM p.taken_max), folder_day = EXTRACT(DAY FROM p.taken_max)
+		FROM (SELECT photo_path, MAX(taken_at_local) AS taken_max
+			FROM photos WHERE taken_src = 'meta' AND photos.photo_quality >= 3 AND photos.deleted_at IS NULL
+			GROUP BY photo_path) AS p
+		WHERE folders.path = p.photo_path AND p.taken_max IS NOT NULL`).Error
 	default:
 		return nil
 	}
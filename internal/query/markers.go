@@ -50,7 +50,7 @@ func Markers(limit, offset int, markerType string, embeddings, subjects bool, ma
 func UnmatchedFaceMarkers(limit, offset int, matchedBefore *time.Time) (result entity.Markers, err error) {
 	db := Db().
 		Where("marker_type = ?", entity.MarkerFace).
-		Where("marker_invalid = 0").
+		Where("marker_invalid = FALSE").
 		Where("embeddings_json <> ''")
 
 	if matchedBefore == nil {
@@ -83,7 +83,7 @@ func Embeddings(single, unclustered bool, size, score int) (result face.Embeddin
 	stmt := Db().
 		Model(&entity.Marker{}).
 		Where("marker_type = ?", entity.MarkerFace).
-		Where("marker_invalid = 0").
+		Where("marker_invalid = FALSE").
 		Where("embeddings_json <> ''").
 		Order("marker_uid")
 
@@ -126,7 +126,7 @@ func Embeddings(single, unclustered bool, size, score int) (result face.Embeddin
 func RemoveInvalidMarkerReferences() (removed int64, err error) {
 	res := Db().
 		Model(&entity.Marker{}).
-		Where("marker_invalid = 1 AND (subj_uid <> '' OR face_id <> '')").
+		Where("marker_invalid = TRUE AND (subj_uid <> '' OR face_id <> '')").
 		UpdateColumns(entity.Values{"subj_uid": "", "face_id": "", "face_dist": -1.0, "matched_at": nil})
 
 	return res.RowsAffected, res.Error
@@ -223,7 +223,7 @@ func ResetFaceMarkerMatches() (removed int64, err error) {
 // CountUnmatchedFaceMarkers counts the number of unmatched face markers in the index.
 func CountUnmatchedFaceMarkers() (n int) {
 	q := Db().Model(&entity.Markers{}).
-		Where("matched_at IS NULL AND marker_invalid = 0 AND embeddings_json <> ''").
+		Where("matched_at IS NULL AND marker_invalid = FALSE AND embeddings_json <> ''").
 		Where("marker_type = ?", entity.MarkerFace)
 
 	if err := q.Count(&n).Error; err != nil {
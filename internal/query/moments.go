@@ -5,8 +5,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/photoprism/photoprism/internal/acl"
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/maps"
+	"github.com/photoprism/photoprism/internal/search"
 	"github.com/photoprism/photoprism/pkg/clean"
 	"github.com/photoprism/photoprism/pkg/txt"
 )
@@ -178,18 +180,28 @@ type Moments []Moment
 
 // MomentsTime counts photos by month and year.
 func MomentsTime(threshold int, public bool) (results Moments, err error) {
+	return UserMomentsTime(threshold, public, nil)
+}
+
+// UserMomentsTime counts photos the session may access by month and year.
+func UserMomentsTime(threshold int, public bool, sess *entity.Session) (results Moments, err error) {
 	db := UnscopedDb().Table("photos").
 		Select("photos.photo_year AS year, photos.photo_month AS month, COUNT(*) AS photo_count").
 		Where("photos.photo_quality >= 3 AND deleted_at IS NULL AND photos.photo_year > 0 AND photos.photo_month > 0")
 
+	// Limit results to own and shared photos?
+	if where, values := search.UserPhotosWhere(acl.ResourceCalendar, sess); where != "" {
+		db = db.Where(where, values...)
+	}
+
 	// Ignore private pictures?
 	if public {
-		db = db.Where("photo_private = 0")
+		db = db.Where("photo_private = FALSE")
 	}
 
 	db = db.Group("photos.photo_year, photos.photo_month").
 		Order("photos.photo_year DESC, photos.photo_month DESC").
-		Having("photo_count >= ?", threshold)
+		Having("COUNT(*) >= ?", threshold)
 
 	if err := db.Scan(&results).Error; err != nil {
 		return results, err
@@ -206,11 +218,11 @@ func MomentsCountries(threshold int, public bool) (results Moments, err error) {
 
 	// Ignore private pictures?
 	if public {
-		db = db.Where("photo_private = 0")
+		db = db.Where("photo_private = FALSE")
 	}
 
 	db = db.Group("photo_country, photo_year").
-		Having("photo_count >= ?", threshold)
+		Having("COUNT(*) >= ?", threshold)
 
 	if err := db.Scan(&results).Error; err != nil {
 		return results, err
@@ -228,11 +240,11 @@ func MomentsStates(threshold int, public bool) (results Moments, err error) {
 
 	// Ignore private pictures?
 	if public {
-		db = db.Where("photo_private = 0")
+		db = db.Where("photo_private = FALSE")
 	}
 
 	db = db.Group("p.place_country, p.place_state").
-		Having("photo_count >= ?", threshold)
+		Having("COUNT(*) >= ?", threshold)
 
 	if err := db.Scan(&results).Error; err != nil {
 		return results, err
@@ -259,11 +271,11 @@ func MomentsLabels(threshold int, public bool) (results Moments, err error) {
 
 	// Ignore private pictures?
 	if public {
-		db = db.Where("photo_private = 0")
+		db = db.Where("photo_private = FALSE")
 	}
 
 	db = db.Group("l.label_slug").
-		Having("photo_count >= ?", threshold)
+		Having("COUNT(*) >= ?", threshold)
 
 	if err := db.Scan(&m).Error; err != nil {
 		return m, err
@@ -76,7 +76,7 @@ func PhotoPreloadByUID(photoUID string) (photo entity.Photo, err error) {
 func PhotosMissing(limit int, offset int) (entities entity.Photos, err error) {
 	err = Db().
 		Select("photos.*").
-		Where("id NOT IN (SELECT photo_id FROM files WHERE file_missing = 0 AND file_root = '/' AND deleted_at IS NULL)").
+		Where("id NOT IN (SELECT photo_id FROM files WHERE file_missing = FALSE AND file_root = '/' AND deleted_at IS NULL)").
 		Where("photos.photo_type <> ?", entity.MediaText).
 		Group("photos.id").
 		Limit(limit).Offset(offset).Find(&entities).Error
@@ -104,13 +104,15 @@ func PhotosMetadataUpdate(limit, offset int, delay, interval time.Duration) (ent
 	return entities, err
 }
 
-// OrphanPhotos finds orphan index entries that may be removed.
+// OrphanPhotos finds orphan index entries that may be removed. Photos in the trash are
+// excluded, so that they can be restored until the retention period has expired.
 func OrphanPhotos() (photos entity.Photos, err error) {
 	err = UnscopedDb().
 		Raw(`SELECT * FROM photos WHERE 
 			deleted_at IS NOT NULL 
 			AND photo_quality = -1 
-			AND id NOT IN (SELECT photo_id FROM files WHERE files.deleted_at IS NULL)`).
+			AND id NOT IN (SELECT photo_id FROM files WHERE files.deleted_at IS NULL)
+			AND photo_uid NOT IN (SELECT photo_uid FROM trash_files)`).
 		Find(&photos).Error
 
 	return photos, err
@@ -127,8 +129,8 @@ func FixPrimaries() error {
 
 	// Remove primary file flag from broken or missing files.
 	if err := UnscopedDb().Table(entity.File{}.TableName()).
-		Where("file_error <> '' OR file_missing = 1").
-		UpdateColumn("file_primary", 0).Error; err != nil {
+		Where("file_error <> '' OR file_missing = TRUE").
+		UpdateColumn("file_primary", false).Error; err != nil {
 		return err
 	}
 
@@ -136,7 +138,7 @@ func FixPrimaries() error {
 	if err := UnscopedDb().
 		Raw(`SELECT * FROM photos 
 			WHERE deleted_at IS NULL 
-			AND id NOT IN (SELECT photo_id FROM files WHERE file_primary = 1)`).
+			AND id NOT IN (SELECT photo_id FROM files WHERE file_primary = TRUE)`).
 		Find(&photos).Error; err != nil {
 		return err
 	}
@@ -168,7 +170,7 @@ func FlagHiddenPhotos() error {
 	start := time.Now()
 
 	res := Db().Table("photos").
-		Where("id NOT IN (SELECT photo_id FROM files WHERE file_primary = 1 AND file_missing = 0 AND file_error = '' AND deleted_at IS NULL)").
+		Where("id NOT IN (SELECT photo_id FROM files WHERE file_primary = TRUE AND file_missing = FALSE AND file_error = '' AND deleted_at IS NULL)").
 		Update("photo_quality", -1)
 
 	switch DbDialect() {
@@ -176,7 +178,7 @@ func FlagHiddenPhotos() error {
 		if res.RowsAffected > 0 {
 			log.Infof("index: flagged %s as hidden or missing [%s]", english.Plural(int(res.RowsAffected), "photo", "photos"), time.Since(start))
 		}
-	case SQLite3:
+	case Postgres, SQLite3:
 		if res.RowsAffected > 0 {
 			log.Debugf("index: flagged %s as hidden or missing [%s]", english.Plural(int(res.RowsAffected), "photo", "photos"), time.Since(start))
 		}
@@ -187,3 +189,14 @@ func FlagHiddenPhotos() error {
 
 	return res.Error
 }
+
+// TogglePhotosPrivate inverts the private flag of the photos with the specified UIDs.
+func TogglePhotosPrivate(photoUids []string) error {
+	if len(photoUids) == 0 {
+		return nil
+	}
+
+	// Negate the flag, so that the statement works with boolean and integer columns alike.
+	return Db().Model(entity.Photo{}).Where("photo_uid IN (?)", photoUids).
+		UpdateColumn("photo_private", gorm.Expr("NOT photo_private")).Error
+}
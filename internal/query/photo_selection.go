@@ -4,12 +4,19 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/photoprism/photoprism/internal/acl"
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/search"
 )
 
 // SelectedPhotos finds photos based on the given selection form, e.g. for adding them to an album.
 func SelectedPhotos(f form.Selection) (results entity.Photos, err error) {
+	return UserSelectedPhotos(f, nil)
+}
+
+// UserSelectedPhotos finds photos based on the given selection form and user session.
+func UserSelectedPhotos(f form.Selection, sess *entity.Session) (results entity.Photos, err error) {
 	if f.Empty() {
 		return results, errors.New("no items selected")
 	}
@@ -26,7 +33,7 @@ func SelectedPhotos(f form.Selection) (results entity.Photos, err error) {
 	switch DbDialect() {
 	case MySQL:
 		concat = "CONCAT(a.path, '/%')"
-	case SQLite3:
+	case Postgres, SQLite3:
 		concat = "a.path || '/%'"
 	default:
 		return results, fmt.Errorf("unknown sql dialect: %s", DbDialect())
@@ -38,7 +45,7 @@ func SelectedPhotos(f form.Selection) (results entity.Photos, err error) {
 		OR photos.photo_path IN (
 			SELECT a.path FROM folders a WHERE a.folder_uid IN (?) UNION
 			SELECT b.path FROM folders a JOIN folders b ON b.path LIKE %s WHERE a.folder_uid IN (?))
-		OR photos.photo_uid IN (SELECT photo_uid FROM photos_albums WHERE hidden = 0 AND album_uid IN (?))
+		OR photos.photo_uid IN (SELECT photo_uid FROM photos_albums WHERE hidden = FALSE AND album_uid IN (?))
 		OR photos.id IN (SELECT f.photo_id FROM files f JOIN %s m ON f.file_uid = m.file_uid WHERE f.deleted_at IS NULL AND m.subj_uid IN (?))
 		OR photos.id IN (SELECT pl.photo_id FROM photos_labels pl JOIN labels l ON pl.label_id = l.id AND l.deleted_at IS NULL WHERE l.label_uid IN (?))
 		OR photos.id IN (SELECT pl.photo_id FROM photos_labels pl JOIN categories c ON c.label_id = pl.label_id JOIN labels lc ON lc.id = c.category_id AND lc.deleted_at IS NULL WHERE lc.label_uid IN (?))`,
@@ -48,9 +55,44 @@ func SelectedPhotos(f form.Selection) (results entity.Photos, err error) {
 		Select("photos.*").
 		Where(where, f.Photos, f.Places, f.Files, f.Files, f.Files, f.Albums, f.Subjects, f.Labels, f.Labels)
 
+	// Limit selection to own and shared photos?
+	if w, v := userSelectionWhere(f, false, sess); w != "" {
+		s = s.Where(w, v...)
+	}
+
 	if result := s.Scan(&results); result.Error != nil {
 		return results, result.Error
 	}
 
 	return results, nil
 }
+
+// userSelectionWhere returns a where condition that limits selected photos to those the session may access,
+// including photos in selected albums that have been shared with the session.
+func userSelectionWhere(f form.Selection, private bool, sess *entity.Session) (where string, values []interface{}) {
+	if where, values = search.UserPhotosWhere(acl.ResourcePhotos, sess); where == "" {
+		return "", nil
+	}
+
+	var shared []string
+
+	for _, uid := range f.Albums {
+		if sess.HasShare(uid) {
+			shared = append(shared, uid)
+		}
+	}
+
+	if len(shared) == 0 {
+		return where, values
+	}
+
+	// Resolve photos in shared smart albums.
+	if photoIds, err := AlbumsPhotoUIDs(shared, false, private); err != nil {
+		log.Warnf("query: %s", err.Error())
+	} else if len(photoIds) > 0 {
+		where += " OR photos.photo_uid IN (?)"
+		values = append(values, photoIds)
+	}
+
+	return where, values
+}
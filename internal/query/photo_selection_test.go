@@ -83,3 +83,27 @@ func TestPhotoSelection(t *testing.T) {
 		assert.IsType(t, entity.Photos{}, r)
 	})
 }
+
+func TestUserSelectedPhotos(t *testing.T) {
+	f := form.Selection{Photos: []string{"pt9jtdre2lvl0yh7", "pt9jtdre2lvl0y24"}}
+
+	t.Run("Admin", func(t *testing.T) {
+		r, err := UserSelectedPhotos(f, entity.SessionFixtures.Pointer("alice"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, r, 2)
+	})
+	t.Run("Visitor", func(t *testing.T) {
+		r, err := UserSelectedPhotos(f, entity.SessionFixtures.Pointer("visitor"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, r, 1)
+		assert.Equal(t, "pt9jtdre2lvl0yh7", r[0].PhotoUID)
+	})
+}
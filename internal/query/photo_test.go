@@ -104,3 +104,40 @@ func TestFlagHiddenPhotos(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestTogglePhotosPrivate(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		before, err := PhotoByUID("pt9jtdre2lvl0yh8")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = TogglePhotosPrivate([]string{"pt9jtdre2lvl0yh8"}); err != nil {
+			t.Fatal(err)
+		}
+
+		toggled, err := PhotoByUID("pt9jtdre2lvl0yh8")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, !before.PhotoPrivate, toggled.PhotoPrivate)
+
+		if err = TogglePhotosPrivate([]string{"pt9jtdre2lvl0yh8"}); err != nil {
+			t.Fatal(err)
+		}
+
+		restored, err := PhotoByUID("pt9jtdre2lvl0yh8")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, before.PhotoPrivate, restored.PhotoPrivate)
+	})
+	t.Run("Empty", func(t *testing.T) {
+		assert.NoError(t, TogglePhotosPrivate(nil))
+	})
+}
@@ -0,0 +1,626 @@
+/*
+Package s3 provides file sharing and synchronization with S3-compatible object storage, e.g. MinIO.
+
+Copyright (c) 2018 - 2022 PhotoPrism UG. All rights reserved.
+
+	This program is free software: you can redistribute it and/or modify
+	it under Version 3 of the GNU Affero General Public License (the "AGPL"):
+	<https://docs.photoprism.app/license/agpl>
+
+	This program is distributed in the hope that it will be useful,
+	but WITHOUT ANY WARRANTY; without even the implied warranty of
+	MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+	GNU Affero General Public License for more details.
+
+	The AGPL is supplemented by our Trademark and Brand Guidelines,
+	which describe how our Brand Assets may be used:
+	<https://photoprism.app/trademark>
+
+Feel free to send an email to hello@photoprism.app if you have questions,
+want to support our work, or just want to say hello.
+
+Additional information can be found in our Developer Guide:
+<https://docs.photoprism.app/developer-guide/>
+*/
+package s3
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/remote"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Global log instance.
+var log = event.Log
+
+// DefaultRegion is used if no region is configured, MinIO accepts any region by default.
+const DefaultRegion = "us-east-1"
+
+// PartSize is the size of the parts in which large files are uploaded, S3 requires at least 5 MiB.
+const PartSize = 16 * 1024 * 1024
+
+// MaxPartRetries is the maximum number of attempts to upload a single part.
+const MaxPartRetries = 3
+
+// partRetryDelay is the delay before a failed part upload is retried, it doubles with each attempt.
+var partRetryDelay = time.Second
+
+func init() {
+	remote.Register(remote.ServiceS3, func(c remote.Config) (remote.Storage, error) {
+		client, err := New(c.URL, c.Region, c.Bucket, c.Prefix, c.User, c.Pass, c.RequestTimeout())
+
+		if err != nil {
+			return client, err
+		}
+
+		client.limiter = remote.NewLimiter(c.Bandwidth)
+
+		return client, nil
+	})
+}
+
+// Client represents an S3 client that implements remote.Storage. It uses path-style requests, so that it
+// works with MinIO and other S3-compatible services without DNS configuration for each bucket.
+type Client struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	prefix    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+	transfer  *http.Client
+	limiter   *rate.Limiter
+	partSize  int64
+}
+
+// New creates a new S3 client. Remote names are relative to the prefix, which may be empty.
+func New(endpoint, region, bucket, prefix, accessKey, secretKey string, timeout time.Duration) (Client, error) {
+	if endpoint == "" {
+		return Client{}, errors.New("s3: endpoint is empty")
+	} else if bucket = strings.Trim(bucket, "/ "); bucket == "" {
+		return Client{}, errors.New("s3: bucket is empty")
+	}
+
+	u, err := url.Parse(endpoint)
+
+	if err != nil {
+		return Client{}, fmt.Errorf("s3: %s", err)
+	} else if u.Scheme == "" || u.Host == "" {
+		return Client{}, fmt.Errorf("s3: invalid endpoint %s", clean.Log(endpoint))
+	}
+
+	// Credentials must not be sent as part of the URL.
+	if u.User != nil {
+		if accessKey == "" {
+			accessKey = u.User.Username()
+		}
+
+		if secretKey == "" {
+			secretKey, _ = u.User.Password()
+		}
+
+		u.User = nil
+	}
+
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawQuery = ""
+
+	if region == "" {
+		region = DefaultRegion
+	}
+
+	if prefix = strings.Trim(prefix, "/ "); prefix != "" {
+		prefix += "/"
+	}
+
+	// File transfers may take longer than the request timeout, so it only limits the time to wait for a response.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = timeout
+
+	return Client{
+		endpoint:  u,
+		region:    region,
+		bucket:    bucket,
+		prefix:    prefix,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		client:    &http.Client{Timeout: timeout},
+		transfer:  &http.Client{Transport: transport},
+		partSize:  PartSize,
+	}, nil
+}
+
+// key returns the object key for a remote name.
+func (c Client) key(name string) string {
+	return c.prefix + strings.TrimLeft(path.Clean("/"+filepath.ToSlash(name)), "/")
+}
+
+// dirKey returns the key prefix for listing the contents of a remote folder.
+func (c Client) dirKey(dir string) string {
+	if k := c.key(dir); k == "" || strings.HasSuffix(k, "/") {
+		return k
+	} else {
+		return k + "/"
+	}
+}
+
+// name returns the remote name for an object key.
+func (c Client) name(key string) string {
+	return "/" + strings.Trim(strings.TrimPrefix(key, c.prefix), "/")
+}
+
+// Check verifies that the bucket exists and can be accessed with the configured credentials.
+func (c Client) Check() error {
+	_, err := c.list(c.prefix, "", 1)
+
+	return err
+}
+
+// List returns the files and folders directly in a folder. Since S3 has no actual folders, they are derived
+// from common key prefixes.
+func (c Client) List(dir string) (result fs.FileInfos, err error) {
+	objects, err := c.listAll(c.dirKey(dir))
+
+	if err != nil {
+		return result, err
+	}
+
+	for _, p := range objects.CommonPrefixes {
+		name := c.name(p.Prefix)
+
+		result = append(result, fs.FileInfo{
+			Name: path.Base(name),
+			Abs:  name,
+			Dir:  true,
+		})
+	}
+
+	for _, obj := range objects.Contents {
+		// Skip folder placeholders.
+		if strings.HasSuffix(obj.Key, "/") {
+			continue
+		}
+
+		name := c.name(obj.Key)
+
+		result = append(result, fs.FileInfo{
+			Name: path.Base(name),
+			Abs:  name,
+			Size: obj.Size,
+			Date: obj.LastModified,
+			ETag: obj.ETag,
+		})
+	}
+
+	return result, nil
+}
+
+// Stat returns information about a remote file or folder.
+func (c Client) Stat(name string) (result fs.FileInfo, err error) {
+	name = remote.CleanName(name)
+	result = fs.FileInfo{Name: path.Base(name), Abs: name}
+
+	// Root folder?
+	if name == "/" {
+		result.Dir = true
+		return result, c.Check()
+	}
+
+	resp, err := c.do(http.MethodHead, c.key(name), nil, nil, -1)
+
+	if err == nil {
+		_ = resp.Body.Close()
+
+		result.Size = resp.ContentLength
+		result.ETag = resp.Header.Get("ETag")
+
+		if modified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+			result.Date = modified.UTC()
+		}
+
+		return result, nil
+	} else if !errors.Is(err, remote.ErrNotFound) {
+		return result, err
+	}
+
+	// Check if the name is a folder.
+	if objects, err := c.list(c.dirKey(name), "", 1); err != nil {
+		return result, err
+	} else if len(objects.Contents) == 0 && len(objects.CommonPrefixes) == 0 {
+		return result, fmt.Errorf("s3: %s %w", clean.Log(name), remote.ErrNotFound)
+	}
+
+	result.Dir = true
+
+	return result, nil
+}
+
+// Download downloads a single file to the given location. Interrupted downloads are resumed with range requests.
+func (c Client) Download(from, to string, force bool) error {
+	info, err := c.Stat(from)
+
+	if err != nil {
+		log.Errorf("s3: %s", clean.Log(err.Error()))
+		return fmt.Errorf("s3: failed downloading %s", clean.Log(from))
+	} else if info.Dir {
+		return fmt.Errorf("s3: %s is a folder", clean.Log(from))
+	}
+
+	if err = remote.DownloadFile(info, to, force, c.limiter, func(offset int64) (io.ReadCloser, error) {
+		header := http.Header{}
+
+		if offset > 0 {
+			header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		}
+
+		resp, err := c.send(c.transfer, http.MethodGet, c.key(from), nil, header, nil, -1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// Skip data that has already been downloaded if the range was ignored.
+		if offset > 0 && resp.StatusCode != http.StatusPartialContent {
+			if _, err = io.CopyN(io.Discard, resp.Body, offset); err != nil {
+				_ = resp.Body.Close()
+				return nil, err
+			}
+		}
+
+		return resp.Body, nil
+	}); err != nil {
+		return fmt.Errorf("s3: %s", err)
+	}
+
+	return nil
+}
+
+// Mkdir does nothing, since folders are created implicitly when files are uploaded.
+func (c Client) Mkdir(dir string) error {
+	return nil
+}
+
+// Upload uploads a single file to the bucket. Files larger than the part size are uploaded in parts.
+func (c Client) Upload(from, to string) error {
+	file, err := os.Open(from)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+
+	if err != nil {
+		return err
+	}
+
+	if c.partSize > 0 && info.Size() > c.partSize {
+		return c.uploadParts(file, c.key(to), info.Size())
+	}
+
+	resp, err := c.send(c.transfer, http.MethodPut, c.key(to), nil, nil, remote.LimitReader(file, c.limiter), info.Size())
+
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// completedPart represents a part in a CompleteMultipartUpload request.
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// uploadParts uploads a file in parts using a multipart upload. Failed parts are retried
+// individually, so that an interrupted transfer does not need to start over.
+func (c Client) uploadParts(file io.ReaderAt, key string, size int64) (err error) {
+	uploadId, err := c.createUpload(key)
+
+	if err != nil {
+		return err
+	}
+
+	// Abort the upload on failure, so that the bucket is not cluttered with incomplete parts.
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		query := url.Values{}
+		query.Set("uploadId", uploadId)
+
+		if resp, abortErr := c.do(http.MethodDelete, key, query, nil, -1); abortErr != nil {
+			log.Debugf("s3: %s (abort upload)", abortErr)
+		} else {
+			_ = resp.Body.Close()
+		}
+	}()
+
+	var parts []completedPart
+
+	for offset, number := int64(0), 1; offset < size; offset, number = offset+c.partSize, number+1 {
+		partSize := c.partSize
+
+		if offset+partSize > size {
+			partSize = size - offset
+		}
+
+		etag, err := c.uploadPart(io.NewSectionReader(file, offset, partSize), key, uploadId, number, partSize)
+
+		if err != nil {
+			return err
+		}
+
+		parts = append(parts, completedPart{PartNumber: number, ETag: etag})
+	}
+
+	return c.completeUpload(key, uploadId, parts)
+}
+
+// createUpload initiates a multipart upload and returns its id.
+func (c Client) createUpload(key string) (string, error) {
+	query := url.Values{}
+	query.Set("uploads", "")
+
+	resp, err := c.do(http.MethodPost, key, query, nil, -1)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	var result struct {
+		UploadId string `xml:"UploadId"`
+	}
+
+	if err = xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("s3: invalid response (%s)", err)
+	} else if result.UploadId == "" {
+		return "", errors.New("s3: missing upload id")
+	}
+
+	return result.UploadId, nil
+}
+
+// uploadPart uploads a single part and returns its entity tag. Failed attempts are retried with
+// an increasing delay.
+func (c Client) uploadPart(part *io.SectionReader, key, uploadId string, number int, size int64) (etag string, err error) {
+	query := url.Values{}
+	query.Set("partNumber", fmt.Sprintf("%d", number))
+	query.Set("uploadId", uploadId)
+
+	delay := partRetryDelay
+
+	for attempt := 1; attempt <= MaxPartRetries; attempt++ {
+		if attempt > 1 {
+			log.Debugf("s3: retrying upload of part %d in %s (%s)", number, delay, err)
+			time.Sleep(delay)
+			delay *= 2
+		}
+
+		if _, err = part.Seek(0, io.SeekStart); err != nil {
+			return "", err
+		}
+
+		var resp *http.Response
+
+		if resp, err = c.send(c.transfer, http.MethodPut, key, query, nil, remote.LimitReader(part, c.limiter), size); err != nil {
+			continue
+		}
+
+		etag = resp.Header.Get("ETag")
+
+		if err = resp.Body.Close(); err != nil {
+			continue
+		}
+
+		return etag, nil
+	}
+
+	return "", fmt.Errorf("s3: failed uploading part %d (%s)", number, strings.TrimPrefix(err.Error(), "s3: "))
+}
+
+// completeUpload completes a multipart upload by assembling the uploaded parts.
+func (c Client) completeUpload(key, uploadId string, parts []completedPart) error {
+	data, err := xml.Marshal(struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{Parts: parts})
+
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("uploadId", uploadId)
+
+	resp, err := c.do(http.MethodPost, key, query, bytes.NewReader(data), int64(len(data)))
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	// The request may still fail after the response status has been sent.
+	var e apiError
+
+	if body, err := io.ReadAll(resp.Body); err != nil {
+		return fmt.Errorf("s3: %s", err)
+	} else if xml.Unmarshal(body, &e) == nil && e.Code != "" {
+		return fmt.Errorf("s3: %s (%s)", strings.TrimRight(e.Message, "."), e.Code)
+	}
+
+	return nil
+}
+
+// Delete deletes a single file from the bucket.
+func (c Client) Delete(name string) error {
+	resp, err := c.do(http.MethodDelete, c.key(name), nil, nil, -1)
+
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// listResult represents the response of a ListObjectsV2 request.
+type listResult struct {
+	XMLName        xml.Name `xml:"ListBucketResult"`
+	Contents       []object `xml:"Contents"`
+	CommonPrefixes []struct {
+		Prefix string `xml:"Prefix"`
+	} `xml:"CommonPrefixes"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// object represents an object in a bucket listing.
+type object struct {
+	Key          string    `xml:"Key"`
+	LastModified time.Time `xml:"LastModified"`
+	ETag         string    `xml:"ETag"`
+	Size         int64     `xml:"Size"`
+}
+
+// listAll returns the objects and common prefixes directly below a key prefix.
+func (c Client) listAll(prefix string) (result listResult, err error) {
+	token := ""
+
+	for {
+		page, err := c.list(prefix, token, 1000)
+
+		if err != nil {
+			return result, err
+		}
+
+		result.Contents = append(result.Contents, page.Contents...)
+		result.CommonPrefixes = append(result.CommonPrefixes, page.CommonPrefixes...)
+
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return result, nil
+		}
+
+		token = page.NextContinuationToken
+	}
+}
+
+// list performs a single ListObjectsV2 request.
+func (c Client) list(prefix, token string, maxKeys int) (result listResult, err error) {
+	query := url.Values{}
+	query.Set("list-type", "2")
+	query.Set("delimiter", "/")
+	query.Set("prefix", prefix)
+	query.Set("max-keys", fmt.Sprintf("%d", maxKeys))
+
+	if token != "" {
+		query.Set("continuation-token", token)
+	}
+
+	resp, err := c.do(http.MethodGet, "", query, nil, -1)
+
+	if err != nil {
+		return result, err
+	}
+
+	defer resp.Body.Close()
+
+	if err = xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return result, fmt.Errorf("s3: invalid response (%s)", err)
+	}
+
+	return result, nil
+}
+
+// apiError represents an error response.
+type apiError struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// do sends a signed request for an object key, or the bucket if the key is empty, and returns
+// an error if the response status is not successful.
+func (c Client) do(method, key string, query url.Values, body io.Reader, size int64) (*http.Response, error) {
+	return c.send(c.client, method, key, query, nil, body, size)
+}
+
+// send sends a signed request with optional headers using the specified HTTP client.
+func (c Client) send(client *http.Client, method, key string, query url.Values, header http.Header, body io.Reader, size int64) (*http.Response, error) {
+	u := *c.endpoint
+	u.Path = u.Path + "/" + c.bucket
+
+	if key != "" {
+		u.Path += "/" + key
+	}
+
+	u.RawPath = encodePath(u.Path)
+
+	if query != nil {
+		u.RawQuery = encodeQuery(query)
+	}
+
+	req, err := http.NewRequest(method, u.String(), body)
+
+	if err != nil {
+		return nil, fmt.Errorf("s3: %s", err)
+	}
+
+	if size >= 0 && body != nil {
+		req.ContentLength = size
+	}
+
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	sign(req, c.accessKey, c.secretKey, c.region, time.Now())
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		return nil, fmt.Errorf("s3: %s", err)
+	} else if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+
+	var e apiError
+
+	if err = xml.NewDecoder(resp.Body).Decode(&e); err != nil || e.Code == "" {
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("s3: %s %w", clean.Log(key), remote.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("s3: %s", strings.ToLower(http.StatusText(resp.StatusCode)))
+	} else if e.Code == "NoSuchKey" {
+		return nil, fmt.Errorf("s3: %s %w", clean.Log(key), remote.ErrNotFound)
+	} else if e.Message == "" {
+		return nil, fmt.Errorf("s3: %s", e.Code)
+	}
+
+	return nil, fmt.Errorf("s3: %s (%s)", strings.TrimRight(e.Message, "."), e.Code)
+}
@@ -0,0 +1,439 @@
+/*
+Package s3test provides an in-memory S3 server for testing.
+
+Copyright (c) 2018 - 2022 PhotoPrism UG. All rights reserved.
+
+	This program is free software: you can redistribute it and/or modify
+	it under Version 3 of the GNU Affero General Public License (the "AGPL"):
+	<https://docs.photoprism.app/license/agpl>
+
+	This program is distributed in the hope that it will be useful,
+	but WITHOUT ANY WARRANTY; without even the implied warranty of
+	MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+	GNU Affero General Public License for more details.
+
+	The AGPL is supplemented by our Trademark and Brand Guidelines,
+	which describe how our Brand Assets may be used:
+	<https://photoprism.app/trademark>
+
+Feel free to send an email to hello@photoprism.app if you have questions,
+want to support our work, or just want to say hello.
+
+Additional information can be found in our Developer Guide:
+<https://docs.photoprism.app/developer-guide/>
+*/
+package s3test
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default credentials accepted by the test server.
+const (
+	AccessKey = "photoprism"
+	SecretKey = "photoprism-secret"
+)
+
+// Object represents a stored object.
+type Object struct {
+	Data     []byte
+	Modified time.Time
+}
+
+// ETag returns the entity tag of the object.
+func (o Object) ETag() string {
+	sum := md5.Sum(o.Data)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// upload represents an incomplete multipart upload.
+type upload struct {
+	key   string
+	parts map[int][]byte
+}
+
+// Server represents an in-memory S3 server that supports the requests used for file sharing and
+// synchronization, i.e. ListObjectsV2, multipart uploads, as well as getting, checking, putting, and deleting objects.
+type Server struct {
+	*httptest.Server
+	Bucket   string
+	mu       sync.RWMutex
+	objects  map[string]Object
+	uploads  map[string]*upload
+	uploadId int
+	parts    int
+	failures int
+}
+
+// NewServer starts a new test server with an empty bucket. Call Close when done.
+func NewServer(bucket string) *Server {
+	s := &Server{
+		Bucket:  bucket,
+		objects: make(map[string]Object),
+		uploads: make(map[string]*upload),
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+
+	return s
+}
+
+// Put stores an object.
+func (s *Server) Put(key string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.objects[key] = Object{Data: data, Modified: time.Now().UTC().Truncate(time.Second)}
+}
+
+// Get returns an object and whether it exists.
+func (s *Server) Get(key string) (Object, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	o, ok := s.objects[key]
+
+	return o, ok
+}
+
+// Fail makes the next n part uploads fail with an internal error.
+func (s *Server) Fail(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = n
+}
+
+// Parts returns the number of parts that have been uploaded successfully.
+func (s *Server) Parts() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.parts
+}
+
+// Uploads returns the number of incomplete multipart uploads.
+func (s *Server) Uploads() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.uploads)
+}
+
+// Keys returns the sorted keys of all stored objects.
+func (s *Server) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.objects))
+
+	for k := range s.objects {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// handle serves API requests.
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if !strings.Contains(r.Header.Get("Authorization"), "Credential="+AccessKey+"/") {
+		writeError(w, http.StatusForbidden, "AccessDenied", "Access Denied.")
+		return
+	}
+
+	var bucket, key string
+
+	if parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2); len(parts) == 2 {
+		bucket, key = parts[0], parts[1]
+	} else {
+		bucket = parts[0]
+	}
+
+	if bucket != s.Bucket {
+		writeError(w, http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist")
+		return
+	}
+
+	q := r.URL.Query()
+
+	switch {
+	case key != "" && r.Method == http.MethodPost && q.Has("uploads"):
+		s.createUpload(w, key)
+	case key != "" && q.Get("uploadId") != "":
+		s.handleUpload(w, r, key, q.Get("uploadId"))
+	case key == "" && r.Method == http.MethodGet:
+		s.list(w, r)
+	case key == "":
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "The specified method is not allowed against this resource.")
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		o, ok := s.Get(key)
+
+		if !ok && r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		} else if !ok {
+			writeError(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+			return
+		}
+
+		data := o.Data
+		status := http.StatusOK
+
+		// Only open-ended ranges like "bytes=100-" are supported.
+		if rng := r.Header.Get("Range"); rng != "" && r.Method == http.MethodGet {
+			start, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+
+			if err != nil || start < 0 || start >= len(data) {
+				writeError(w, http.StatusRequestedRangeNotSatisfiable, "InvalidRange", "The requested range is not satisfiable")
+				return
+			}
+
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(data)-1, len(data)))
+			data = data[start:]
+			status = http.StatusPartialContent
+		}
+
+		w.Header().Set("ETag", o.ETag())
+		w.Header().Set("Last-Modified", o.Modified.Format(http.TimeFormat))
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(status)
+
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	case r.Method == http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+			return
+		}
+
+		s.Put(key, data)
+		o, _ := s.Get(key)
+		w.Header().Set("ETag", o.ETag())
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodDelete:
+		s.mu.Lock()
+		delete(s.objects, key)
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "The specified method is not allowed against this resource.")
+	}
+}
+
+// createUpload responds to CreateMultipartUpload requests.
+func (s *Server) createUpload(w http.ResponseWriter, key string) {
+	s.mu.Lock()
+	s.uploadId++
+	uploadId := strconv.Itoa(s.uploadId)
+	s.uploads[uploadId] = &upload{key: key, parts: make(map[int][]byte)}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(struct {
+		XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+		Bucket   string   `xml:"Bucket"`
+		Key      string   `xml:"Key"`
+		UploadId string   `xml:"UploadId"`
+	}{Bucket: s.Bucket, Key: key, UploadId: uploadId})
+}
+
+// handleUpload responds to UploadPart, CompleteMultipartUpload, and AbortMultipartUpload requests.
+func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request, key, uploadId string) {
+	s.mu.RLock()
+	u, ok := s.uploads[uploadId]
+	s.mu.RUnlock()
+
+	if !ok || u.key != key {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", "The specified multipart upload does not exist.")
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		number, err := strconv.Atoi(r.URL.Query().Get("partNumber"))
+
+		if err != nil || number < 1 {
+			writeError(w, http.StatusBadRequest, "InvalidArgument", "Part number must be a positive integer.")
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.failures > 0 {
+			s.failures--
+			writeError(w, http.StatusInternalServerError, "InternalError", "We encountered an internal error. Please try again.")
+			return
+		}
+
+		u.parts[number] = data
+		s.parts++
+
+		w.Header().Set("ETag", Object{Data: data}.ETag())
+		w.WriteHeader(http.StatusOK)
+	case http.MethodPost:
+		var req struct {
+			Parts []struct {
+				PartNumber int    `xml:"PartNumber"`
+				ETag       string `xml:"ETag"`
+			} `xml:"Part"`
+		}
+
+		if err = xml.Unmarshal(data, &req); err != nil || len(req.Parts) == 0 {
+			writeError(w, http.StatusBadRequest, "MalformedXML", "The XML you provided was not well-formed.")
+			return
+		}
+
+		var result []byte
+
+		for i, p := range req.Parts {
+			part, ok := u.parts[p.PartNumber]
+
+			if !ok || p.PartNumber != i+1 || (Object{Data: part}).ETag() != p.ETag {
+				writeError(w, http.StatusBadRequest, "InvalidPart", "One or more of the specified parts could not be found.")
+				return
+			}
+
+			result = append(result, part...)
+		}
+
+		s.Put(key, result)
+
+		s.mu.Lock()
+		delete(s.uploads, uploadId)
+		s.mu.Unlock()
+
+		o, _ := s.Get(key)
+
+		w.Header().Set("Content-Type", "application/xml")
+		_ = xml.NewEncoder(w).Encode(struct {
+			XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+			Key     string   `xml:"Key"`
+			ETag    string   `xml:"ETag"`
+		}{Key: key, ETag: o.ETag()})
+	case http.MethodDelete:
+		s.mu.Lock()
+		delete(s.uploads, uploadId)
+		s.mu.Unlock()
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "The specified method is not allowed against this resource.")
+	}
+}
+
+type listContents struct {
+	Key          string    `xml:"Key"`
+	LastModified time.Time `xml:"LastModified"`
+	ETag         string    `xml:"ETag"`
+	Size         int       `xml:"Size"`
+}
+
+type listPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+type listResult struct {
+	XMLName               xml.Name       `xml:"ListBucketResult"`
+	Name                  string         `xml:"Name"`
+	Prefix                string         `xml:"Prefix"`
+	KeyCount              int            `xml:"KeyCount"`
+	MaxKeys               int            `xml:"MaxKeys"`
+	IsTruncated           bool           `xml:"IsTruncated"`
+	NextContinuationToken string         `xml:"NextContinuationToken,omitempty"`
+	Contents              []listContents `xml:"Contents"`
+	CommonPrefixes        []listPrefix   `xml:"CommonPrefixes"`
+}
+
+// list responds to ListObjectsV2 requests. Continuation tokens are the last key returned.
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	if q.Get("list-type") != "2" {
+		writeError(w, http.StatusNotImplemented, "NotImplemented", "Only ListObjectsV2 is supported.")
+		return
+	}
+
+	prefix := q.Get("prefix")
+	delimiter := q.Get("delimiter")
+	token := q.Get("continuation-token")
+	maxKeys, err := strconv.Atoi(q.Get("max-keys"))
+
+	if err != nil || maxKeys <= 0 || maxKeys > 1000 {
+		maxKeys = 1000
+	}
+
+	result := listResult{Name: s.Bucket, Prefix: prefix, MaxKeys: maxKeys}
+	seen := make(map[string]bool)
+
+	for _, key := range s.Keys() {
+		if !strings.HasPrefix(key, prefix) || token != "" && key <= token {
+			continue
+		}
+
+		if result.KeyCount >= maxKeys {
+			result.IsTruncated = true
+			break
+		}
+
+		if i := strings.Index(key[len(prefix):], delimiter); delimiter != "" && i >= 0 {
+			p := key[:len(prefix)+i+len(delimiter)]
+
+			if !seen[p] {
+				seen[p] = true
+				result.CommonPrefixes = append(result.CommonPrefixes, listPrefix{Prefix: p})
+				result.KeyCount++
+			}
+
+			result.NextContinuationToken = key
+
+			continue
+		}
+
+		o, _ := s.Get(key)
+		result.Contents = append(result.Contents, listContents{Key: key, LastModified: o.Modified, ETag: o.ETag(), Size: len(o.Data)})
+		result.KeyCount++
+		result.NextContinuationToken = key
+	}
+
+	if !result.IsTruncated {
+		result.NextContinuationToken = ""
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(result)
+}
+
+// writeError writes an error response.
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_ = xml.NewEncoder(w).Encode(struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string   `xml:"Code"`
+		Message string   `xml:"Message"`
+	}{Code: code, Message: message})
+}
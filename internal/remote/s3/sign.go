@@ -0,0 +1,138 @@
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	signAlgorithm   = "AWS4-HMAC-SHA256"
+	signService     = "s3"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	emptyPayload    = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	amzDateFormat   = "20060102T150405Z"
+	amzDayFormat    = "20060102"
+)
+
+// sign adds an AWS Signature Version 4 authorization header to the request. The payload of uploads is not
+// signed, so that files can be streamed without reading them twice.
+func sign(req *http.Request, accessKey, secretKey, region string, now time.Time) {
+	payload := emptyPayload
+
+	if req.Body != nil && req.ContentLength != 0 {
+		payload = unsignedPayload
+	}
+
+	now = now.UTC()
+	amzDate := now.Format(amzDateFormat)
+	day := now.Format(amzDayFormat)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payload)
+
+	// Anonymous access?
+	if accessKey == "" {
+		return
+	}
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host + "\n" +
+			"x-amz-content-sha256:" + payload + "\n" +
+			"x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		payload,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", day, region, signService)
+
+	stringToSign := strings.Join([]string{
+		signAlgorithm,
+		amzDate,
+		scope,
+		hashHex(canonicalRequest),
+	}, "\n")
+
+	key := hmacSum([]byte("AWS4"+secretKey), day)
+	key = hmacSum(key, region)
+	key = hmacSum(key, signService)
+	key = hmacSum(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSum(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signAlgorithm, accessKey, scope, signedHeaders, signature))
+}
+
+// hashHex returns the hex encoded SHA256 hash of s.
+func hashHex(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+// hmacSum returns the HMAC-SHA256 of data using the specified key.
+func hmacSum(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// encodePath URI-encodes each segment of a path as required for signing.
+func encodePath(p string) string {
+	segments := strings.Split(p, "/")
+
+	for i, s := range segments {
+		segments[i] = uriEncode(s)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// encodeQuery returns the canonical query string with sorted and URI-encoded parameters.
+func encodeQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+
+	for k := range values {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	var params []string
+
+	for _, k := range keys {
+		for _, v := range values[k] {
+			params = append(params, uriEncode(k)+"="+uriEncode(v))
+		}
+	}
+
+	return strings.Join(params, "&")
+}
+
+// uriEncode encodes all characters except unreserved ones, see RFC 3986.
+func uriEncode(s string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,510 @@
+/*
+Package sftp provides file sharing and synchronization with SSH File Transfer Protocol (SFTP) servers.
+
+Copyright (c) 2018 - 2022 PhotoPrism UG. All rights reserved.
+
+	This program is free software: you can redistribute it and/or modify
+	it under Version 3 of the GNU Affero General Public License (the "AGPL"):
+	<https://docs.photoprism.app/license/agpl>
+
+	This program is distributed in the hope that it will be useful,
+	but WITHOUT ANY WARRANTY; without even the implied warranty of
+	MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+	GNU Affero General Public License for more details.
+
+	The AGPL is supplemented by our Trademark and Brand Guidelines,
+	which describe how our Brand Assets may be used:
+	<https://photoprism.app/trademark>
+
+Feel free to send an email to hello@photoprism.app if you have questions,
+want to support our work, or just want to say hello.
+
+Additional information can be found in our Developer Guide:
+<https://docs.photoprism.app/developer-guide/>
+*/
+package sftp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	sftplib "github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/time/rate"
+
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/remote"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Global log instance.
+var log = event.Log
+
+// DefaultPort is used if the service URL does not contain a port.
+const DefaultPort = "22"
+
+// ErrHostKeyNotPinned is returned if no host key has been configured for the server.
+var ErrHostKeyNotPinned = errors.New("sftp: host key is not pinned")
+
+func init() {
+	remote.Register(remote.ServiceSFTP, func(c remote.Config) (remote.Storage, error) {
+		client, err := New(c.URL, c.User, c.Pass, c.PrivateKey, c.HostKey, c.RequestTimeout())
+
+		if err != nil {
+			return nil, err
+		}
+
+		client.limiter = remote.NewLimiter(c.Bandwidth)
+
+		return client, nil
+	})
+}
+
+// Client represents an SFTP client that implements remote.Storage. The connection is established with the
+// first request and reused until Close is called or the server closes it.
+type Client struct {
+	addr    string
+	root    string
+	config  *ssh.ClientConfig
+	mu      sync.Mutex
+	conn    *ssh.Client
+	client  *sftplib.Client
+	limiter *rate.Limiter
+}
+
+// New creates a new SFTP client for a service URL like "sftp://nas.local:22/photos". Remote names are
+// relative to the URL path. The user is authenticated with the private key, if any, and the password.
+// If the private key is encrypted, the password is used as its passphrase instead.
+func New(rawUrl, user, pass, privateKey, hostKey string, timeout time.Duration) (*Client, error) {
+	u, err := url.Parse(rawUrl)
+
+	if err != nil {
+		return nil, fmt.Errorf("sftp: %s", err)
+	} else if u.Scheme != "sftp" && u.Scheme != "ssh" || u.Hostname() == "" {
+		return nil, fmt.Errorf("sftp: invalid service url %s", clean.Log(rawUrl))
+	}
+
+	if u.User != nil {
+		if user == "" {
+			user = u.User.Username()
+		}
+
+		if pass == "" {
+			pass, _ = u.User.Password()
+		}
+	}
+
+	if user == "" {
+		return nil, errors.New("sftp: user is empty")
+	}
+
+	callback, err := hostKeyCallback(hostKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var auth []ssh.AuthMethod
+
+	if privateKey = strings.TrimSpace(privateKey); privateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(privateKey))
+
+		var missing *ssh.PassphraseMissingError
+
+		if errors.As(err, &missing) && pass != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(privateKey), []byte(pass))
+			pass = ""
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("sftp: invalid private key (%s)", err)
+		}
+
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+
+	if pass != "" {
+		auth = append(auth, ssh.Password(pass))
+	}
+
+	if len(auth) == 0 {
+		return nil, errors.New("sftp: password or private key required")
+	}
+
+	port := u.Port()
+
+	if port == "" {
+		port = DefaultPort
+	}
+
+	return &Client{
+		addr: net.JoinHostPort(u.Hostname(), port),
+		root: path.Clean("/" + u.Path),
+		config: &ssh.ClientConfig{
+			User:            user,
+			Auth:            auth,
+			HostKeyCallback: callback,
+			Timeout:         timeout,
+		},
+	}, nil
+}
+
+// hostKeyCallback returns a callback that only accepts the pinned host key, which can either be specified
+// as SHA256 fingerprint or in authorized_keys format.
+func hostKeyCallback(hostKey string) (ssh.HostKeyCallback, error) {
+	if hostKey = strings.TrimSpace(hostKey); hostKey == "" {
+		return nil, ErrHostKeyNotPinned
+	}
+
+	fingerprint := hostKey
+
+	if !strings.HasPrefix(hostKey, "SHA256:") {
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(hostKey))
+
+		if err != nil {
+			return nil, fmt.Errorf("sftp: invalid host key (%s)", err)
+		}
+
+		fingerprint = ssh.FingerprintSHA256(key)
+	}
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		if ssh.FingerprintSHA256(key) != fingerprint {
+			return fmt.Errorf("sftp: host key mismatch, %s does not match the pinned key", ssh.FingerprintSHA256(key))
+		}
+
+		return nil
+	}, nil
+}
+
+// HostKey connects to the server without authentication and returns the SHA256 fingerprint of its host key,
+// so that it can be pinned after the user has verified it.
+func HostKey(rawUrl string, timeout time.Duration) (string, error) {
+	u, err := url.Parse(rawUrl)
+
+	if err != nil {
+		return "", fmt.Errorf("sftp: %s", err)
+	} else if u.Hostname() == "" {
+		return "", fmt.Errorf("sftp: invalid service url %s", clean.Log(rawUrl))
+	}
+
+	port := u.Port()
+
+	if port == "" {
+		port = DefaultPort
+	}
+
+	var fingerprint string
+
+	config := &ssh.ClientConfig{
+		User: "photoprism",
+		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			fingerprint = ssh.FingerprintSHA256(key)
+			return nil
+		},
+		Timeout: timeout,
+	}
+
+	// Authentication is expected to fail, since no credentials are provided.
+	if conn, err := ssh.Dial("tcp", net.JoinHostPort(u.Hostname(), port), config); err == nil {
+		_ = conn.Close()
+	} else if fingerprint == "" {
+		return "", fmt.Errorf("sftp: %s", err)
+	}
+
+	return fingerprint, nil
+}
+
+// session returns the SFTP session, and connects to the server if needed.
+func (c *Client) session() (*sftplib.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client != nil {
+		return c.client, nil
+	}
+
+	conn, err := ssh.Dial("tcp", c.addr, c.config)
+
+	if err != nil {
+		return nil, fmt.Errorf("sftp: %s", err)
+	}
+
+	client, err := sftplib.NewClient(conn)
+
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("sftp: %s", err)
+	}
+
+	c.conn = conn
+	c.client = client
+
+	return client, nil
+}
+
+// Close closes the connection to the server, if any.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client == nil {
+		return nil
+	}
+
+	_ = c.client.Close()
+	err := c.conn.Close()
+
+	c.client = nil
+	c.conn = nil
+
+	return err
+}
+
+// Check verifies that the server can be accessed with the configured credentials.
+func (c *Client) Check() error {
+	_, err := c.Stat("/")
+
+	return err
+}
+
+// abs returns the absolute server path for a remote name.
+func (c *Client) abs(name string) string {
+	return path.Join(c.root, remote.CleanName(filepath.ToSlash(name)))
+}
+
+// error returns a descriptive error, and resets the connection if it was lost.
+func (c *Client) error(name string, err error) error {
+	if err == nil {
+		return nil
+	} else if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("sftp: %s %w", clean.Log(name), remote.ErrNotFound)
+	} else if errors.Is(err, sftplib.ErrSSHFxConnectionLost) || errors.Is(err, io.EOF) {
+		_ = c.Close()
+	}
+
+	return fmt.Errorf("sftp: %s (%s)", err, clean.Log(name))
+}
+
+// List returns the files and folders directly in a folder.
+func (c *Client) List(dir string) (result fs.FileInfos, err error) {
+	client, err := c.session()
+
+	if err != nil {
+		return result, err
+	}
+
+	dir = remote.CleanName(dir)
+
+	files, err := client.ReadDir(c.abs(dir))
+
+	if err != nil {
+		return result, c.error(dir, err)
+	}
+
+	if dir == "/" {
+		dir = ""
+	}
+
+	for _, file := range files {
+		if !file.Mode().IsRegular() && !file.IsDir() {
+			continue
+		}
+
+		result = append(result, fs.NewFileInfo(file, dir))
+	}
+
+	return result, nil
+}
+
+// Stat returns information about a remote file or folder.
+func (c *Client) Stat(name string) (result fs.FileInfo, err error) {
+	client, err := c.session()
+
+	if err != nil {
+		return result, err
+	}
+
+	name = remote.CleanName(name)
+
+	info, err := client.Stat(c.abs(name))
+
+	if err != nil {
+		return result, c.error(name, err)
+	}
+
+	return fs.FileInfo{
+		Name: path.Base(name),
+		Abs:  name,
+		Size: info.Size(),
+		Date: info.ModTime(),
+		Dir:  info.IsDir(),
+	}, nil
+}
+
+// Download downloads a single file to the given location. Interrupted downloads are resumed on the next attempt.
+func (c *Client) Download(from, to string, force bool) error {
+	info, err := c.Stat(from)
+
+	if err != nil {
+		log.Errorf("%s", err)
+		return fmt.Errorf("sftp: failed downloading %s", clean.Log(from))
+	} else if info.Dir {
+		return fmt.Errorf("sftp: %s is a folder", clean.Log(from))
+	}
+
+	client, err := c.session()
+
+	if err != nil {
+		return err
+	}
+
+	if err = remote.DownloadFile(info, to, force, c.limiter, func(offset int64) (io.ReadCloser, error) {
+		src, err := client.Open(c.abs(from))
+
+		if err != nil {
+			return nil, c.error(from, err)
+		}
+
+		if _, err = src.Seek(offset, io.SeekStart); err != nil {
+			_ = src.Close()
+			return nil, c.error(from, err)
+		}
+
+		return src, nil
+	}); err != nil {
+		return fmt.Errorf("sftp: %s", strings.TrimPrefix(err.Error(), "sftp: "))
+	}
+
+	return nil
+}
+
+// Upload uploads a single file, the remote folder must already exist. The data is first written to a hidden
+// part file, so that an interrupted upload can be resumed on the next attempt as long as the local file is unchanged.
+func (c *Client) Upload(from, to string) error {
+	src, err := os.Open(from)
+
+	if err != nil {
+		return err
+	}
+
+	defer src.Close()
+
+	info, err := src.Stat()
+
+	if err != nil {
+		return err
+	}
+
+	client, err := c.session()
+
+	if err != nil {
+		return err
+	}
+
+	fileName := c.abs(to)
+	partName := filepath.ToSlash(remote.PartName(fileName, info.Size(), info.ModTime(), ""))
+
+	var offset int64
+
+	// Resume previous upload?
+	if partInfo, err := client.Stat(partName); err == nil && partInfo.Size() <= info.Size() {
+		offset = partInfo.Size()
+		log.Infof("sftp: resuming upload of %s at %d bytes", clean.Log(to), offset)
+	}
+
+	f, err := client.OpenFile(partName, os.O_WRONLY|os.O_CREATE)
+
+	if err != nil {
+		return c.error(to, err)
+	}
+
+	if err = f.Truncate(offset); err != nil {
+		_ = f.Close()
+		return c.error(to, err)
+	} else if _, err = f.Seek(offset, io.SeekStart); err != nil {
+		_ = f.Close()
+		return c.error(to, err)
+	} else if _, err = src.Seek(offset, io.SeekStart); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if _, err = io.Copy(f, remote.LimitReader(src, c.limiter)); err != nil {
+		_ = f.Close()
+		return c.error(to, err)
+	} else if err = f.Close(); err != nil {
+		return c.error(to, err)
+	}
+
+	// Replace existing files atomically if the server supports it.
+	if err = client.PosixRename(partName, fileName); err == nil {
+		return nil
+	}
+
+	if err = client.Remove(fileName); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return c.error(to, err)
+	}
+
+	return c.error(to, client.Rename(partName, fileName))
+}
+
+// Delete deletes a remote file or folder including its contents.
+func (c *Client) Delete(name string) error {
+	client, err := c.session()
+
+	if err != nil {
+		return err
+	}
+
+	if name = remote.CleanName(name); name == "/" {
+		return errors.New("sftp: cannot delete root folder")
+	}
+
+	info, err := client.Stat(c.abs(name))
+
+	if err != nil {
+		return c.error(name, err)
+	} else if !info.IsDir() {
+		return c.error(name, client.Remove(c.abs(name)))
+	}
+
+	files, err := client.ReadDir(c.abs(name))
+
+	if err != nil {
+		return c.error(name, err)
+	}
+
+	for _, file := range files {
+		if err = c.Delete(path.Join(name, file.Name())); err != nil {
+			return err
+		}
+	}
+
+	return c.error(name, client.RemoveDirectory(c.abs(name)))
+}
+
+// Mkdir creates a remote folder including its parents if needed.
+func (c *Client) Mkdir(dir string) error {
+	if dir = remote.CleanName(dir); dir == "/" {
+		return nil
+	}
+
+	client, err := c.session()
+
+	if err != nil {
+		return err
+	}
+
+	return c.error(dir, client.MkdirAll(c.abs(dir)))
+}
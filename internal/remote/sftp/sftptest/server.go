@@ -0,0 +1,220 @@
+/*
+Package sftptest provides an in-process SFTP server for testing.
+
+Copyright (c) 2018 - 2022 PhotoPrism UG. All rights reserved.
+
+	This program is free software: you can redistribute it and/or modify
+	it under Version 3 of the GNU Affero General Public License (the "AGPL"):
+	<https://docs.photoprism.app/license/agpl>
+
+	This program is distributed in the hope that it will be useful,
+	but WITHOUT ANY WARRANTY; without even the implied warranty of
+	MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+	GNU Affero General Public License for more details.
+
+	The AGPL is supplemented by our Trademark and Brand Guidelines,
+	which describe how our Brand Assets may be used:
+	<https://photoprism.app/trademark>
+
+Feel free to send an email to hello@photoprism.app if you have questions,
+want to support our work, or just want to say hello.
+
+Additional information can be found in our Developer Guide:
+<https://docs.photoprism.app/developer-guide/>
+*/
+package sftptest
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// Default credentials accepted by the test server.
+const (
+	User     = "photoprism"
+	Password = "photoprism-secret"
+)
+
+// Server represents an in-process SFTP server that serves the local file system.
+type Server struct {
+	URL        string // URL is the service url of the server, e.g. "sftp://127.0.0.1:2222".
+	HostKey    string // HostKey is the SHA256 fingerprint of the server host key.
+	PublicKey  string // PublicKey is the server host key in authorized_keys format.
+	PrivateKey string // PrivateKey is a PEM encoded client key that is authorized to connect.
+	listener   net.Listener
+	config     *ssh.ServerConfig
+	wg         sync.WaitGroup
+	mu         sync.Mutex
+	conns      map[net.Conn]bool
+}
+
+// NewServer starts a new test server that accepts password and public key authentication. Call Close when done.
+func NewServer() (*Server, error) {
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	clientPub, clientKey, err := ed25519.GenerateKey(rand.Reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	authorizedKey, err := ssh.NewPublicKey(clientPub)
+
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(clientKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if c.User() == User && string(pass) == Password {
+				return nil, nil
+			}
+
+			return nil, fmt.Errorf("password rejected for %s", c.User())
+		},
+		PublicKeyCallback: func(c ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if c.User() == User && ssh.FingerprintSHA256(key) == ssh.FingerprintSHA256(authorizedKey) {
+				return nil, nil
+			}
+
+			return nil, fmt.Errorf("public key rejected for %s", c.User())
+		},
+	}
+
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		URL:        "sftp://" + listener.Addr().String(),
+		HostKey:    ssh.FingerprintSHA256(hostSigner.PublicKey()),
+		PublicKey:  string(ssh.MarshalAuthorizedKey(hostSigner.PublicKey())),
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		listener:   listener,
+		config:     config,
+		conns:      make(map[net.Conn]bool),
+	}
+
+	s.wg.Add(1)
+
+	go s.serve()
+
+	return s, nil
+}
+
+// Close stops the server, closes all connections, and waits until they have been handled.
+func (s *Server) Close() {
+	_ = s.listener.Close()
+
+	s.mu.Lock()
+
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// serve accepts new connections until the listener is closed.
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = true
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+// handle performs the SSH handshake and serves SFTP subsystem requests.
+func (s *Server) handle(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+
+		_ = conn.Close()
+	}()
+
+	_, chans, reqs, err := ssh.NewServerConn(conn, s.config)
+
+	if err != nil {
+		return
+	}
+
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+
+		channel, requests, err := newChannel.Accept()
+
+		if err != nil {
+			return
+		}
+
+		go func(in <-chan *ssh.Request) {
+			for req := range in {
+				// The payload of subsystem requests is the length-prefixed subsystem name.
+				ok := req.Type == "subsystem" && len(req.Payload) > 4 && string(req.Payload[4:]) == "sftp"
+				_ = req.Reply(ok, nil)
+			}
+		}(requests)
+
+		server, err := sftp.NewServer(channel)
+
+		if err != nil {
+			_ = channel.Close()
+			continue
+		}
+
+		_ = server.Serve()
+		_ = server.Close()
+	}
+}
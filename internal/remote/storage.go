@@ -0,0 +1,206 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// MaxRequestDuration is the maximum request duration e.g. for recursive retrieval of large remote folder structures.
+const MaxRequestDuration = 30 * time.Minute
+
+// ErrNotFound is returned if a remote file or folder does not exist.
+var ErrNotFound = errors.New("not found")
+
+// Storage represents a remote service that supports file sharing and synchronization.
+// Remote names are slash-separated paths relative to the service root, e.g. "/Photos/2022/IMG_0001.jpg".
+type Storage interface {
+	// List returns the files and folders directly in a folder.
+	List(dir string) (fs.FileInfos, error)
+	// Stat returns information about a remote file or folder, or an error wrapping ErrNotFound.
+	Stat(name string) (fs.FileInfo, error)
+	// Download downloads a remote file to a local file name, existing files are only replaced if force is true.
+	Download(from, to string, force bool) error
+	// Upload uploads a local file, replacing an existing remote file.
+	Upload(from, to string) error
+	// Delete deletes a remote file or folder.
+	Delete(name string) error
+	// Mkdir creates a remote folder including its parents if needed.
+	Mkdir(dir string) error
+}
+
+// Config represents the settings of a remote service account, see entity.Service.
+// Bandwidth limits the transfer rate in KiB/s, 0 means unlimited.
+type Config struct {
+	URL        string
+	User       string
+	Pass       string
+	Key        string
+	Bucket     string
+	Prefix     string
+	Region     string
+	PrivateKey string
+	HostKey    string
+	Bandwidth  int
+	Timeout    string
+}
+
+// Timeouts maps the request timeout options to durations.
+var Timeouts = map[string]time.Duration{
+	"high":   120 * time.Second,
+	"":       60 * time.Second,
+	"medium": 60 * time.Second,
+	"low":    30 * time.Second,
+	"none":   0,
+}
+
+// RequestTimeout returns the request timeout duration.
+func (c Config) RequestTimeout() time.Duration {
+	if d, ok := Timeouts[c.Timeout]; ok {
+		return d
+	}
+
+	return Timeouts[""]
+}
+
+// Factory returns a new Storage for the specified config.
+type Factory func(c Config) (Storage, error)
+
+var (
+	backends   = make(map[string]Factory)
+	backendsMu sync.RWMutex
+)
+
+// Register registers a Storage backend for a service type, usually from the init function of the package
+// that implements it. It panics if a backend is registered twice for the same service type.
+func Register(serviceType string, factory Factory) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+
+	if factory == nil {
+		panic("remote: register factory is nil")
+	} else if _, dup := backends[serviceType]; dup {
+		panic("remote: register called twice for " + serviceType)
+	}
+
+	backends[serviceType] = factory
+}
+
+// Supported checks if a Storage backend is registered for the service type.
+func Supported(serviceType string) bool {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+
+	_, ok := backends[serviceType]
+
+	return ok
+}
+
+// Backends returns the sorted service types for which a Storage backend is registered.
+func Backends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+
+	result := make([]string, 0, len(backends))
+
+	for serviceType := range backends {
+		result = append(result, serviceType)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// New returns a new Storage for the service type.
+func New(serviceType string, c Config) (Storage, error) {
+	backendsMu.RLock()
+	factory, ok := backends[serviceType]
+	backendsMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unsupported service type %s", clean.Log(serviceType))
+	}
+
+	return factory(c)
+}
+
+// Close closes the connection of a Storage backend if it keeps one open, see io.Closer.
+func Close(s Storage) {
+	if c, ok := s.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			log.Debugf("remote: %s (close)", err)
+		}
+	}
+}
+
+// Files returns the files in a remote folder.
+func Files(s Storage, dir string) (result fs.FileInfos, err error) {
+	list, err := s.List(dir)
+
+	if err != nil {
+		return result, err
+	}
+
+	for _, info := range list {
+		if !info.Dir {
+			result = append(result, info)
+		}
+	}
+
+	return result, nil
+}
+
+// Directories returns the subfolders of a remote folder, recursively until the timeout is reached if requested.
+func Directories(s Storage, root string, recursive bool, timeout time.Duration) (result fs.FileInfos, err error) {
+	start := time.Now()
+
+	result, err = fetchDirs(s, root, recursive, start, timeout)
+
+	if timeout > 0 && time.Since(start) >= timeout {
+		log.Warnf("remote: read dir timeout reached")
+	}
+
+	return result, err
+}
+
+// fetchDirs recursively fetches all folders until the timeout is reached.
+func fetchDirs(s Storage, root string, recursive bool, start time.Time, timeout time.Duration) (result fs.FileInfos, err error) {
+	list, err := s.List(root)
+
+	if err != nil {
+		return result, err
+	}
+
+	for _, info := range list {
+		if !info.Dir {
+			continue
+		}
+
+		result = append(result, info)
+
+		if recursive && (timeout < time.Second || time.Since(start) < timeout) {
+			subDirs, err := fetchDirs(s, info.Abs, true, start, timeout)
+
+			if err != nil {
+				return result, err
+			}
+
+			result = append(result, subDirs...)
+		}
+	}
+
+	return result, nil
+}
+
+// CleanName returns a normalized remote name that starts with a slash.
+func CleanName(name string) string {
+	return path.Clean("/" + name)
+}
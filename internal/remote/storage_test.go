@@ -0,0 +1,125 @@
+package remote
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// testStorage represents an in-memory folder tree for testing.
+type testStorage map[string]fs.FileInfos
+
+func (s testStorage) List(dir string) (fs.FileInfos, error) {
+	if list, ok := s[CleanName(dir)]; ok {
+		return list, nil
+	}
+
+	return nil, ErrNotFound
+}
+
+func (s testStorage) Stat(name string) (fs.FileInfo, error) {
+	return fs.FileInfo{}, ErrNotFound
+}
+
+func (s testStorage) Download(from, to string, force bool) error {
+	return errors.New("not implemented")
+}
+
+func (s testStorage) Upload(from, to string) error {
+	return errors.New("not implemented")
+}
+
+func (s testStorage) Delete(name string) error {
+	return errors.New("not implemented")
+}
+
+func (s testStorage) Mkdir(dir string) error {
+	return nil
+}
+
+var testTree = testStorage{
+	"/": {
+		{Name: "Photos", Abs: "/Photos", Dir: true},
+		{Name: "README.txt", Abs: "/README.txt"},
+	},
+	"/Photos": {
+		{Name: "2022", Abs: "/Photos/2022", Dir: true},
+		{Name: "IMG_0001.jpg", Abs: "/Photos/IMG_0001.jpg"},
+	},
+	"/Photos/2022": {
+		{Name: "IMG_0002.jpg", Abs: "/Photos/2022/IMG_0002.jpg"},
+	},
+}
+
+func TestRegister(t *testing.T) {
+	Register("test", func(c Config) (Storage, error) {
+		return testTree, nil
+	})
+
+	t.Run("Supported", func(t *testing.T) {
+		assert.True(t, Supported("test"))
+		assert.False(t, Supported("facebook"))
+		assert.Contains(t, Backends(), "test")
+	})
+	t.Run("New", func(t *testing.T) {
+		s, err := New("test", Config{})
+
+		assert.NoError(t, err)
+		assert.IsType(t, testStorage{}, s)
+	})
+	t.Run("Unsupported", func(t *testing.T) {
+		_, err := New("facebook", Config{})
+
+		assert.Error(t, err)
+	})
+	t.Run("Duplicate", func(t *testing.T) {
+		assert.Panics(t, func() {
+			Register("test", func(c Config) (Storage, error) {
+				return testTree, nil
+			})
+		})
+	})
+}
+
+func TestFiles(t *testing.T) {
+	files, err := Files(testTree, "/Photos")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/Photos/IMG_0001.jpg"}, files.Abs())
+
+	_, err = Files(testTree, "/Videos")
+
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestDirectories(t *testing.T) {
+	t.Run("Recursive", func(t *testing.T) {
+		dirs, err := Directories(testTree, "/", true, time.Minute)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/Photos", "/Photos/2022"}, dirs.Abs())
+	})
+	t.Run("NonRecursive", func(t *testing.T) {
+		dirs, err := Directories(testTree, "", false, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/Photos"}, dirs.Abs())
+	})
+}
+
+func TestConfig_RequestTimeout(t *testing.T) {
+	assert.Equal(t, 60*time.Second, Config{}.RequestTimeout())
+	assert.Equal(t, 120*time.Second, Config{Timeout: "high"}.RequestTimeout())
+	assert.Equal(t, time.Duration(0), Config{Timeout: "none"}.RequestTimeout())
+	assert.Equal(t, 60*time.Second, Config{Timeout: "foo"}.RequestTimeout())
+}
+
+func TestCleanName(t *testing.T) {
+	assert.Equal(t, "/", CleanName(""))
+	assert.Equal(t, "/Photos", CleanName("Photos/"))
+	assert.Equal(t, "/Photos/2022", CleanName("/Photos//2022"))
+}
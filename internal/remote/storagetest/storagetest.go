@@ -0,0 +1,176 @@
+/*
+Package storagetest provides conformance tests that every remote.Storage backend must pass.
+
+Copyright (c) 2018 - 2022 PhotoPrism UG. All rights reserved.
+
+	This program is free software: you can redistribute it and/or modify
+	it under Version 3 of the GNU Affero General Public License (the "AGPL"):
+	<https://docs.photoprism.app/license/agpl>
+
+	This program is distributed in the hope that it will be useful,
+	but WITHOUT ANY WARRANTY; without even the implied warranty of
+	MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+	GNU Affero General Public License for more details.
+
+	The AGPL is supplemented by our Trademark and Brand Guidelines,
+	which describe how our Brand Assets may be used:
+	<https://photoprism.app/trademark>
+
+Feel free to send an email to hello@photoprism.app if you have questions,
+want to support our work, or just want to say hello.
+
+Additional information can be found in our Developer Guide:
+<https://docs.photoprism.app/developer-guide/>
+*/
+package storagetest
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/photoprism/photoprism/internal/remote"
+)
+
+// Root is the remote folder used by the conformance tests, it should not exist when Run is called.
+const Root = "/storagetest"
+
+// Run tests that a Storage backend behaves as expected by the sync and share workers.
+func Run(t *testing.T, s remote.Storage) {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	data := []byte("storagetest\n")
+	localName := filepath.Join(tempDir, "upload.jpg")
+
+	if err := os.WriteFile(localName, data, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := Root + "/2022/Summer"
+	remoteName := dir + "/IMG 0001+1.jpg"
+
+	t.Run("Mkdir", func(t *testing.T) {
+		if err := s.Mkdir(dir); err != nil {
+			t.Fatalf("Mkdir(%q): %s", dir, err)
+		}
+
+		if err := s.Mkdir(dir); err != nil {
+			t.Errorf("Mkdir(%q) on existing folder: %s", dir, err)
+		}
+	})
+
+	t.Run("Upload", func(t *testing.T) {
+		if err := s.Upload(localName, remoteName); err != nil {
+			t.Fatalf("Upload(%q): %s", remoteName, err)
+		}
+	})
+
+	t.Run("Stat", func(t *testing.T) {
+		info, err := s.Stat(remoteName)
+
+		if err != nil {
+			t.Fatalf("Stat(%q): %s", remoteName, err)
+		}
+
+		if info.Name != "IMG 0001+1.jpg" || info.Abs != remoteName || info.Size != int64(len(data)) || info.Dir {
+			t.Errorf("Stat(%q) returned %+v", remoteName, info)
+		}
+
+		if info, err = s.Stat(dir); err != nil {
+			t.Errorf("Stat(%q): %s", dir, err)
+		} else if !info.Dir || info.Abs != dir {
+			t.Errorf("Stat(%q) returned %+v", dir, info)
+		}
+
+		if _, err = s.Stat(Root + "/missing.jpg"); !errors.Is(err, remote.ErrNotFound) {
+			t.Errorf("Stat on missing file returned %v, expected %v", err, remote.ErrNotFound)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		list, err := s.List(dir)
+
+		if err != nil {
+			t.Fatalf("List(%q): %s", dir, err)
+		}
+
+		if len(list) != 1 || list[0].Abs != remoteName || list[0].Dir || list[0].Size != int64(len(data)) {
+			t.Errorf("List(%q) returned %+v", dir, list)
+		}
+
+		list, err = s.List(Root)
+
+		if err != nil {
+			t.Fatalf("List(%q): %s", Root, err)
+		}
+
+		if len(list) != 1 || list[0].Abs != Root+"/2022" || list[0].Name != "2022" || !list[0].Dir {
+			t.Errorf("List(%q) returned %+v", Root, list)
+		}
+	})
+
+	t.Run("Directories", func(t *testing.T) {
+		dirs, err := remote.Directories(s, Root, true, 0)
+
+		if err != nil {
+			t.Fatalf("Directories(%q): %s", Root, err)
+		}
+
+		if abs := dirs.Abs(); len(abs) != 2 || abs[0] != Root+"/2022" || abs[1] != dir {
+			t.Errorf("Directories(%q) returned %v", Root, abs)
+		}
+
+		files, err := remote.Files(s, dir)
+
+		if err != nil {
+			t.Fatalf("Files(%q): %s", dir, err)
+		}
+
+		if abs := files.Abs(); len(abs) != 1 || abs[0] != remoteName {
+			t.Errorf("Files(%q) returned %v", dir, abs)
+		}
+	})
+
+	t.Run("Download", func(t *testing.T) {
+		downloadName := filepath.Join(tempDir, "download", "IMG_0001.jpg")
+
+		if err := s.Download(remoteName, downloadName, false); err != nil {
+			t.Fatalf("Download(%q): %s", remoteName, err)
+		}
+
+		if b, err := os.ReadFile(downloadName); err != nil {
+			t.Fatal(err)
+		} else if !bytes.Equal(b, data) {
+			t.Errorf("Download(%q) returned %q, expected %q", remoteName, b, data)
+		}
+
+		if err := s.Download(remoteName, downloadName, false); err == nil {
+			t.Errorf("Download(%q) replaced existing file without force", remoteName)
+		}
+
+		if err := s.Download(remoteName, downloadName, true); err != nil {
+			t.Errorf("Download(%q) with force: %s", remoteName, err)
+		}
+
+		if err := s.Download(Root+"/missing.jpg", filepath.Join(tempDir, "missing.jpg"), false); err == nil {
+			t.Errorf("Download of missing file succeeded")
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if err := s.Delete(remoteName); err != nil {
+			t.Fatalf("Delete(%q): %s", remoteName, err)
+		}
+
+		if _, err := s.Stat(remoteName); !errors.Is(err, remote.ErrNotFound) {
+			t.Errorf("Stat after Delete returned %v, expected %v", err, remote.ErrNotFound)
+		}
+
+		if err := s.Delete(Root); err != nil {
+			t.Errorf("Delete(%q): %s", Root, err)
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package remote
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// PartExt is the file extension of partially transferred files.
+const PartExt = ".part"
+
+// NewLimiter returns a rate limiter for the specified bandwidth in KiB/s, or nil if it is unlimited.
+func NewLimiter(bandwidth int) *rate.Limiter {
+	if bandwidth <= 0 {
+		return nil
+	}
+
+	bytesPerSecond := bandwidth * 1024
+
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), bytesPerSecond)
+}
+
+// limitedReader represents a reader whose throughput is limited by a rate.Limiter.
+type limitedReader struct {
+	r io.Reader
+	l *rate.Limiter
+}
+
+// LimitReader returns a reader whose throughput is limited by l, or r itself if l is nil.
+func LimitReader(r io.Reader, l *rate.Limiter) io.Reader {
+	if l == nil {
+		return r
+	}
+
+	return &limitedReader{r: r, l: l}
+}
+
+// Read implements io.Reader.
+func (r *limitedReader) Read(p []byte) (n int, err error) {
+	if burst := r.l.Burst(); len(p) > burst {
+		p = p[:burst]
+	}
+
+	n, err = r.r.Read(p)
+
+	if n > 0 {
+		if waitErr := r.l.WaitN(context.Background(), n); waitErr != nil && err == nil {
+			err = waitErr
+		}
+	}
+
+	return n, err
+}
+
+// PartName returns the hidden file name in which a specific version of a file is stored while it is being
+// transferred, e.g. ".IMG_0001.jpg.1a2b3c4d.part". Including the version ensures that partial data of
+// different versions is never combined.
+func PartName(fileName string, size int64, date time.Time, etag string) string {
+	h := sha1.Sum([]byte(fmt.Sprintf("%d:%d:%s", size, date.UTC().Unix(), etag)))
+
+	return filepath.Join(filepath.Dir(fileName), "."+filepath.Base(fileName)+"."+hex.EncodeToString(h[:4])+PartExt)
+}
+
+// RemoveParts removes partially transferred versions of a local file.
+func RemoveParts(fileName string) {
+	matches, err := filepath.Glob(filepath.Join(filepath.Dir(fileName), "."+escapeGlob(filepath.Base(fileName))+".*"+PartExt))
+
+	if err != nil {
+		return
+	}
+
+	for _, match := range matches {
+		if err = os.Remove(match); err != nil {
+			log.Debugf("remote: %s (remove partial file)", err)
+		}
+	}
+}
+
+// escapeGlob escapes characters that have a special meaning in glob patterns.
+func escapeGlob(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`).Replace(s)
+}
+
+// OpenFunc opens a remote file for reading, starting at the specified offset.
+type OpenFunc func(offset int64) (io.ReadCloser, error)
+
+// DownloadFile downloads a remote file to a local file name. The data is first written to a hidden part file,
+// so that an interrupted download can be resumed on the next attempt as long as the remote version is unchanged.
+func DownloadFile(info fs.FileInfo, to string, force bool, l *rate.Limiter, open OpenFunc) error {
+	// Skip if file already exists.
+	if _, err := os.Stat(to); err == nil && !force {
+		return fmt.Errorf("download skipped, %s already exists", clean.Log(to))
+	}
+
+	dir := filepath.Dir(to)
+
+	if dirInfo, err := os.Stat(dir); err != nil {
+		// Create local storage path.
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return fmt.Errorf("cannot create folder %s (%s)", clean.Log(dir), err)
+		}
+	} else if !dirInfo.IsDir() {
+		return fmt.Errorf("%s is not a folder", clean.Log(dir))
+	}
+
+	partName := PartName(to, info.Size, info.Date, info.ETag)
+
+	var offset int64
+
+	// Resume previous download?
+	if partInfo, err := os.Stat(partName); err == nil && partInfo.Size() <= info.Size {
+		offset = partInfo.Size()
+	}
+
+	if offset < info.Size || info.Size == 0 {
+		if offset > 0 {
+			log.Infof("remote: resuming download of %s at %d bytes", clean.Log(info.Abs), offset)
+		}
+
+		body, err := open(offset)
+
+		if err != nil {
+			return err
+		}
+
+		defer body.Close()
+
+		f, err := os.OpenFile(partName, os.O_CREATE|os.O_WRONLY, os.ModePerm)
+
+		if err != nil {
+			return err
+		}
+
+		if err = f.Truncate(offset); err != nil {
+			_ = f.Close()
+			return err
+		} else if _, err = f.Seek(offset, io.SeekStart); err != nil {
+			_ = f.Close()
+			return err
+		}
+
+		n, err := io.Copy(f, LimitReader(body, l))
+
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err != nil {
+			return fmt.Errorf("download interrupted after %d bytes (%s)", offset+n, err)
+		} else if info.Size > 0 && offset+n != info.Size {
+			return fmt.Errorf("download incomplete, received %d of %d bytes", offset+n, info.Size)
+		}
+	}
+
+	if err := os.Rename(partName, to); err != nil {
+		return err
+	}
+
+	RemoveParts(to)
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package remote
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+func TestNewLimiter(t *testing.T) {
+	t.Run("Unlimited", func(t *testing.T) {
+		assert.Nil(t, NewLimiter(0))
+		assert.Nil(t, NewLimiter(-1))
+	})
+	t.Run("KiB", func(t *testing.T) {
+		l := NewLimiter(100)
+
+		if assert.NotNil(t, l) {
+			assert.Equal(t, 102400, l.Burst())
+		}
+	})
+}
+
+func TestLimitReader(t *testing.T) {
+	t.Run("Unlimited", func(t *testing.T) {
+		r := strings.NewReader("foo")
+		assert.Equal(t, r, LimitReader(r, nil))
+	})
+	t.Run("Limited", func(t *testing.T) {
+		data := bytes.Repeat([]byte("x"), 3072)
+
+		start := time.Now()
+		b, err := io.ReadAll(LimitReader(bytes.NewReader(data), NewLimiter(1)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, data, b)
+
+		// The first KiB is available immediately, the remaining 2 KiB take 2 seconds.
+		assert.GreaterOrEqual(t, time.Since(start), 1500*time.Millisecond)
+	})
+}
+
+func TestPartName(t *testing.T) {
+	date := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	name := PartName("/photos/IMG_0001.jpg", 4, date, `"etag"`)
+
+	assert.Equal(t, "/photos", filepath.Dir(name))
+	assert.True(t, strings.HasPrefix(filepath.Base(name), ".IMG_0001.jpg."))
+	assert.True(t, strings.HasSuffix(name, PartExt))
+	assert.Equal(t, name, PartName("/photos/IMG_0001.jpg", 4, date, `"etag"`))
+	assert.NotEqual(t, name, PartName("/photos/IMG_0001.jpg", 5, date, `"etag"`))
+	assert.NotEqual(t, name, PartName("/photos/IMG_0001.jpg", 4, date.Add(time.Second), `"etag"`))
+	assert.NotEqual(t, name, PartName("/photos/IMG_0001.jpg", 4, date, `"other"`))
+}
+
+func TestDownloadFile(t *testing.T) {
+	date := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	info := fs.FileInfo{Name: "IMG_0001.jpg", Abs: "/IMG_0001.jpg", Size: 6, Date: date}
+
+	open := func(offset int64) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("abcdef"[offset:])), nil
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "2022", "IMG_0001.jpg")
+
+		assert.NoError(t, DownloadFile(info, fileName, false, nil, open))
+
+		b, err := os.ReadFile(fileName)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "abcdef", string(b))
+		assert.Error(t, DownloadFile(info, fileName, false, nil, open))
+		assert.NoError(t, DownloadFile(info, fileName, true, nil, open))
+	})
+	t.Run("Resume", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "IMG_0001.jpg")
+		partName := PartName(fileName, info.Size, info.Date, info.ETag)
+		staleName := PartName(fileName, 3, info.Date, info.ETag)
+
+		assert.NoError(t, os.WriteFile(partName, []byte("XYZ"), os.ModePerm))
+		assert.NoError(t, os.WriteFile(staleName, []byte("abc"), os.ModePerm))
+		assert.NoError(t, DownloadFile(info, fileName, false, nil, open))
+
+		b, err := os.ReadFile(fileName)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "XYZdef", string(b))
+		assert.NoFileExists(t, partName)
+		assert.NoFileExists(t, staleName)
+	})
+	t.Run("Interrupted", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "IMG_0001.jpg")
+		partName := PartName(fileName, info.Size, info.Date, info.ETag)
+
+		err := DownloadFile(info, fileName, false, nil, func(offset int64) (io.ReadCloser, error) {
+			return io.NopCloser(io.MultiReader(strings.NewReader("abc"), errReader{})), nil
+		})
+
+		assert.Error(t, err)
+		assert.NoFileExists(t, fileName)
+
+		b, err := os.ReadFile(partName)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", string(b))
+
+		assert.NoError(t, DownloadFile(info, fileName, false, nil, open))
+
+		b, err = os.ReadFile(fileName)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "abcdef", string(b))
+	})
+	t.Run("Incomplete", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "IMG_0001.jpg")
+
+		err := DownloadFile(info, fileName, false, nil, func(offset int64) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("abc")), nil
+		})
+
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "received 3 of 6 bytes")
+		}
+
+		assert.NoFileExists(t, fileName)
+	})
+}
+
+// errReader simulates a connection that is lost while reading.
+type errReader struct{}
+
+func (errReader) Read(p []byte) (int, error) {
+	return 0, errors.New("connection lost")
+}
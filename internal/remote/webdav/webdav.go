@@ -26,14 +26,18 @@ package webdav
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/studio-b12/gowebdav"
+	"golang.org/x/time/rate"
 
 	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/remote"
 	"github.com/photoprism/photoprism/pkg/clean"
 	"github.com/photoprism/photoprism/pkg/fs"
 )
@@ -56,21 +60,30 @@ const (
 const Second = time.Second
 
 // MaxRequestDuration is the maximum request duration e.g. for recursive retrieval of large remote directory structures.
-const MaxRequestDuration = 30 * time.Minute
+const MaxRequestDuration = remote.MaxRequestDuration
 
 // Durations maps Timeout options to specific time durations.
 var Durations = map[Timeout]time.Duration{
-	TimeoutHigh:    120 * Second,
-	TimeoutDefault: 60 * Second,
-	TimeoutMedium:  60 * Second,
-	TimeoutLow:     30 * Second,
-	TimeoutNone:    0,
+	TimeoutHigh:    remote.Timeouts[string(TimeoutHigh)],
+	TimeoutDefault: remote.Timeouts[string(TimeoutDefault)],
+	TimeoutMedium:  remote.Timeouts[string(TimeoutMedium)],
+	TimeoutLow:     remote.Timeouts[string(TimeoutLow)],
+	TimeoutNone:    remote.Timeouts[string(TimeoutNone)],
 }
 
-// Client represents a gowebdav.Client wrapper.
+func init() {
+	remote.Register(remote.ServiceWebDAV, func(c remote.Config) (remote.Storage, error) {
+		client := New(c.URL, c.User, c.Pass, Timeout(c.Timeout))
+		client.limiter = remote.NewLimiter(c.Bandwidth)
+		return client, nil
+	})
+}
+
+// Client represents a gowebdav.Client wrapper that implements remote.Storage.
 type Client struct {
 	client  *gowebdav.Client
 	timeout Timeout
+	limiter *rate.Limiter
 }
 
 // New creates a new WebDAV client.
@@ -95,6 +108,77 @@ func (c Client) readDir(path string) ([]os.FileInfo, error) {
 	return c.client.ReadDir(path)
 }
 
+// List returns the files and folders directly in a folder.
+func (c Client) List(dir string) (result fs.FileInfos, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("webdav: %s (panic while listing files)\nstack: %s", r, debug.Stack())
+		}
+	}()
+
+	files, err := c.readDir(dir)
+
+	if err != nil {
+		return result, notFound(err)
+	}
+
+	if dir == "/" {
+		dir = ""
+	}
+
+	for _, file := range files {
+		if !file.Mode().IsRegular() && !file.IsDir() {
+			continue
+		}
+
+		info := fs.NewFileInfo(file, dir)
+		info.ETag = etag(file)
+
+		result = append(result, info)
+	}
+
+	return result, nil
+}
+
+// Stat returns information about a remote file or folder.
+func (c Client) Stat(name string) (result fs.FileInfo, err error) {
+	name = remote.CleanName(name)
+
+	info, err := c.client.Stat(name)
+
+	if err != nil {
+		return result, notFound(err)
+	}
+
+	return fs.FileInfo{
+		Name: path.Base(name),
+		Abs:  name,
+		Size: info.Size(),
+		Date: info.ModTime(),
+		Dir:  info.IsDir(),
+		ETag: etag(info),
+	}, nil
+}
+
+// etag returns the entity tag reported by the server, if any.
+func etag(info os.FileInfo) string {
+	if f, ok := info.(interface{ ETag() string }); ok {
+		return f.ETag()
+	}
+
+	return ""
+}
+
+// notFound returns an error wrapping remote.ErrNotFound if the server responded with status 404.
+func notFound(err error) error {
+	// Depending on the request, the status is either reported as "404" or "404 Not Found - PROPFIND /path".
+	if pathErr, ok := err.(*os.PathError); ok && pathErr.Err != nil && strings.HasPrefix(pathErr.Err.Error(), "404") {
+		return fmt.Errorf("webdav: %s %w", clean.Log(pathErr.Path), remote.ErrNotFound)
+	}
+
+	return err
+}
+
 // Files returns all files in a directory as string slice.
 func (c Client) Files(dir string) (result fs.FileInfos, err error) {
 	defer func() {
@@ -174,7 +258,8 @@ func (c Client) fetchDirs(root string, recursive bool, start time.Time, timeout
 	return result, nil
 }
 
-// Download downloads a single file to the given location.
+// Download downloads a single file to the given location. Interrupted downloads are resumed with
+// HTTP range requests if the server supports them.
 func (c Client) Download(from, to string, force bool) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -183,36 +268,24 @@ func (c Client) Download(from, to string, force bool) (err error) {
 		}
 	}()
 
-	// Skip if file already exists.
-	if _, err := os.Stat(to); err == nil && !force {
-		return fmt.Errorf("webdav: download skipped, %s already exists", clean.Log(to))
-	}
-
-	dir := path.Dir(to)
-	dirInfo, err := os.Stat(dir)
+	info, err := c.Stat(from)
 
 	if err != nil {
-		// Create local storage path.
-		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-			return fmt.Errorf("webdav: cannot create folder %s (%s)", clean.Log(dir), err)
-		}
-	} else if !dirInfo.IsDir() {
-		return fmt.Errorf("webdav: %s is not a folder", clean.Log(dir))
+		log.Errorf("webdav: %s", clean.Log(err.Error()))
+		return fmt.Errorf("webdav: failed downloading %s", clean.Log(from))
 	}
 
-	var bytes []byte
-
-	// Start download.
-	bytes, err = c.client.Read(from)
+	if err = remote.DownloadFile(info, to, force, c.limiter, func(offset int64) (io.ReadCloser, error) {
+		if offset > 0 {
+			return c.client.ReadStreamRange(from, offset, info.Size-offset)
+		}
 
-	// Error?
-	if err != nil {
-		log.Errorf("webdav: %s", clean.Log(err.Error()))
-		return fmt.Errorf("webdav: failed downloading %s", clean.Log(from))
+		return c.client.ReadStream(from)
+	}); err != nil {
+		return fmt.Errorf("webdav: %s", err)
 	}
 
-	// Write data to file and return.
-	return os.WriteFile(to, bytes, os.ModePerm)
+	return nil
 }
 
 // DownloadDir downloads all files from a remote to a local directory.
@@ -264,6 +337,11 @@ func (c Client) CreateDir(dir string) error {
 	return c.client.MkdirAll(dir, os.ModePerm)
 }
 
+// Mkdir recursively creates folders if they don't exist.
+func (c Client) Mkdir(dir string) error {
+	return c.CreateDir(dir)
+}
+
 // Upload uploads a single file to the remote server.
 func (c Client) Upload(from, to string) (err error) {
 	defer func() {
@@ -282,7 +360,8 @@ func (c Client) Upload(from, to string) (err error) {
 		_ = file.Close()
 	}(file)
 
-	return c.client.WriteStream(to, file, os.ModePerm)
+	// WebDAV does not support chunked uploads, so files are always uploaded with a single request.
+	return c.client.WriteStream(to, remote.LimitReader(file, c.limiter), os.ModePerm)
 }
 
 // Delete deletes a single file or directory on a remote server.
@@ -1,12 +1,16 @@
 package webdav
 
 import (
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	xwebdav "golang.org/x/net/webdav"
 
+	"github.com/photoprism/photoprism/internal/remote"
+	"github.com/photoprism/photoprism/internal/remote/storagetest"
 	"github.com/photoprism/photoprism/pkg/fs"
 	"github.com/photoprism/photoprism/pkg/rnd"
 )
@@ -151,3 +155,20 @@ func TestClient_UploadAndDelete(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestStorage(t *testing.T) {
+	srv := httptest.NewServer(&xwebdav.Handler{
+		FileSystem: xwebdav.Dir(t.TempDir()),
+		LockSystem: xwebdav.NewMemLS(),
+	})
+
+	defer srv.Close()
+
+	s, err := remote.New(remote.ServiceWebDAV, remote.Config{URL: srv.URL + "/", Timeout: string(TimeoutLow)})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storagetest.Run(t, s)
+}
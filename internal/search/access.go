@@ -0,0 +1,275 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/pkg/rnd"
+)
+
+// AlbumResource returns the access control resource for the album type.
+func AlbumResource(albumType string) acl.Resource {
+	switch albumType {
+	case entity.AlbumFolder:
+		return acl.ResourceFolders
+	case entity.AlbumMoment:
+		return acl.ResourceMoments
+	case entity.AlbumMonth:
+		return acl.ResourceCalendar
+	case entity.AlbumState:
+		return acl.ResourcePlaces
+	default:
+		return acl.ResourceAlbums
+	}
+}
+
+// LimitedAccess checks if the session may only access its own and shared content of the specified resource.
+func LimitedAccess(resource acl.Resource, sess *entity.Session) bool {
+	if sess == nil {
+		return false
+	}
+
+	return limitedRole(resource, sess.User().AclRole())
+}
+
+// limitedRole checks if the role may only access its own and shared content of the specified resource.
+func limitedRole(resource acl.Resource, role acl.Role) bool {
+	return acl.Resources.DenyAll(resource, role, acl.Permissions{acl.AccessAll, acl.AccessLibrary})
+}
+
+// OwnPhotosWhere returns a where condition that matches photos added by the user or stored in its base path.
+func OwnPhotosWhere(user *entity.User) (where string, values []interface{}) {
+	if user.BasePath == "" {
+		return "photos.created_by = ?", []interface{}{user.UserUID}
+	}
+
+	return "photos.created_by = ? OR photos.photo_path = ? OR photos.photo_path LIKE ?",
+		[]interface{}{user.UserUID, user.BasePath, user.BasePath + "/%"}
+}
+
+// UserPhotosWhere returns a where condition that limits photos to those the session may access,
+// or an empty string if the session has access to the whole library.
+func UserPhotosWhere(resource acl.Resource, sess *entity.Session) (where string, values []interface{}) {
+	if !LimitedAccess(resource, sess) {
+		return "", nil
+	}
+
+	shared := sess.SharedUIDs()
+
+	// Albums, photos, and labels can be shared with a link.
+	where = "photos.photo_uid IN (SELECT photo_uid FROM photos_albums WHERE hidden = FALSE AND missing = FALSE AND album_uid IN (?))" +
+		" OR photos.photo_uid IN (?)" +
+		" OR photos.published_at > ?"
+	values = []interface{}{shared, shared, entity.TimeStamp()}
+
+	if labelWhere, labelValues := LabelSharesWhere(shared); labelWhere != "" {
+		where += " OR " + labelWhere
+		values = append(values, labelValues...)
+	}
+
+	// Visitors can only access shared and published content.
+	if sess.IsVisitor() || sess.NotRegistered() {
+		return where, values
+	}
+
+	own, ownValues := OwnPhotosWhere(sess.User())
+
+	return where + " OR " + own, append(values, ownValues...)
+}
+
+// LabelSharesWhere returns a where condition that matches photos with the shared labels, or an empty string
+// if no labels have been shared. Since a label may be assigned to photos of any user, a label share only includes
+// the photos that the user who created the share link may access.
+func LabelSharesWhere(shared entity.UIDs) (where string, values []interface{}) {
+	var conditions []string
+
+	labelWhere := "photos.id IN (SELECT photos_labels.photo_id FROM photos_labels JOIN labels ON labels.id = photos_labels.label_id WHERE photos_labels.uncertainty < 100 AND labels.label_uid = ?)"
+
+	for _, uid := range shared {
+		if !rnd.IsUID(uid, entity.LabelUID) {
+			continue
+		}
+
+		for _, link := range entity.FindValidLinks("", uid) {
+			creator := entity.FindUserByUID(link.CreatedBy)
+
+			// Ignore links without a registered creator.
+			if creator == nil || !creator.IsRegistered() {
+				continue
+			}
+
+			// Include all photos with the label if the creator may access the whole library.
+			if !limitedRole(acl.ResourcePhotos, creator.AclRole()) {
+				conditions = append(conditions, labelWhere)
+				values = append(values, uid)
+				break
+			}
+
+			own, ownValues := OwnPhotosWhere(creator)
+
+			conditions = append(conditions, "("+labelWhere+" AND ("+own+"))")
+			values = append(append(values, uid), ownValues...)
+		}
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	return strings.Join(conditions, " OR "), values
+}
+
+// UserAlbumsWhere returns a where condition that limits albums of the specified type to those the session may access,
+// or an empty string if the session has access to the whole library.
+func UserAlbumsWhere(albumType string, sess *entity.Session) (where string, values []interface{}) {
+	if !LimitedAccess(AlbumResource(albumType), sess) {
+		return "", nil
+	}
+
+	where = "albums.album_uid IN (?) OR albums.published_at > ?"
+	values = []interface{}{sess.SharedUIDs(), entity.TimeStamp()}
+
+	// Visitors can only access shared and published content.
+	if sess.IsVisitor() || sess.NotRegistered() {
+		return where, values
+	}
+
+	user := sess.User()
+	own, ownValues := OwnPhotosWhere(user)
+
+	where += " OR albums.created_by = ?"
+	values = append(values, user.UserUID)
+
+	// Folders, months, and states are generated automatically, so they are visible if they contain own photos.
+	if albumType == "" || albumType == entity.AlbumFolder {
+		where += " OR albums.album_type = ? AND albums.album_path IN (SELECT photos.photo_path FROM photos WHERE photos.deleted_at IS NULL AND (" + own + "))"
+		values = append(append(values, entity.AlbumFolder), ownValues...)
+
+		if user.BasePath != "" {
+			where += " OR albums.album_type = ? AND (albums.album_path = ? OR albums.album_path LIKE ?)"
+			values = append(values, entity.AlbumFolder, user.BasePath, user.BasePath+"/%")
+		}
+	}
+
+	if albumType == "" || albumType == entity.AlbumMonth {
+		where += " OR albums.album_type = ? AND EXISTS (SELECT 1 FROM photos WHERE photos.photo_year = albums.album_year AND photos.photo_month = albums.album_month AND photos.deleted_at IS NULL AND (" + own + "))"
+		values = append(append(values, entity.AlbumMonth), ownValues...)
+	}
+
+	if albumType == "" || albumType == entity.AlbumState {
+		where += " OR albums.album_type = ? AND EXISTS (SELECT 1 FROM photos JOIN places ON places.id = photos.place_id WHERE places.place_country = albums.album_country AND places.place_state = albums.album_state AND photos.deleted_at IS NULL AND (" + own + "))"
+		values = append(append(values, entity.AlbumState), ownValues...)
+	}
+
+	return where, values
+}
+
+// PhotoAccess checks if the session may access the photo with the specified UID.
+func PhotoAccess(photoUid string, sess *entity.Session) bool {
+	where, values := UserPhotosWhere(acl.ResourcePhotos, sess)
+
+	if where == "" {
+		return true
+	}
+
+	var count int
+
+	if err := UnscopedDb().Table("photos").Where("photos.photo_uid = ?", photoUid).Where(where, values...).Count(&count).Error; err != nil {
+		log.Errorf("search: %s (check photo access)", err)
+		return false
+	}
+
+	return count > 0
+}
+
+// LabelAccess checks if the session may access the label with the specified UID, i.e. if it is assigned
+// to at least one photo the session may access.
+func LabelAccess(labelUid string, sess *entity.Session) bool {
+	where, values := UserPhotosWhere(acl.ResourceLabels, sess)
+
+	if where == "" {
+		return true
+	}
+
+	var count int
+
+	if err := UnscopedDb().Table("labels").Where("labels.label_uid = ?", labelUid).
+		Where("labels.id IN (SELECT photos_labels.label_id FROM photos_labels JOIN photos ON photos.id = photos_labels.photo_id "+
+			"WHERE photos_labels.uncertainty < 100 AND photos.deleted_at IS NULL AND ("+where+"))", values...).
+		Count(&count).Error; err != nil {
+		log.Errorf("search: %s (check label access)", err)
+		return false
+	}
+
+	return count > 0
+}
+
+// AlbumAccess checks if the session may access the album with the specified UID.
+func AlbumAccess(albumUid string, sess *entity.Session) bool {
+	if sess == nil {
+		return true
+	}
+
+	var album entity.Album
+
+	if err := UnscopedDb().Where("album_uid = ?", albumUid).First(&album).Error; err != nil {
+		return false
+	}
+
+	where, values := UserAlbumsWhere(album.AlbumType, sess)
+
+	if where == "" {
+		return true
+	}
+
+	var count int
+
+	if err := UnscopedDb().Table("albums").Where("albums.album_uid = ?", albumUid).Where(where, values...).Count(&count).Error; err != nil {
+		log.Errorf("search: %s (check album access)", err)
+		return false
+	}
+
+	return count > 0
+}
+
+// AlbumPerm returns the permissions the session has for the album with the specified UID.
+func AlbumPerm(albumUid string, sess *entity.Session) uint {
+	if sess == nil {
+		return entity.PermAll
+	}
+
+	var album entity.Album
+
+	if err := UnscopedDb().Where("album_uid = ?", albumUid).First(&album).Error; err != nil {
+		return entity.PermNone
+	}
+
+	user := sess.User()
+
+	// Full access if the user may access the whole library or created the album.
+	if !LimitedAccess(AlbumResource(album.AlbumType), sess) {
+		return entity.PermAll
+	} else if user.IsRegistered() && album.CreatedBy == user.UserUID {
+		return entity.PermAll
+	}
+
+	// Otherwise, the permissions depend on the share level.
+	if user.IsRegistered() {
+		share := entity.FindUserShare(entity.UserShare{UserUID: user.UserUID, ShareUID: album.AlbumUID})
+
+		if share != nil && (share.ExpiresAt == nil || share.ExpiresAt.After(entity.TimeStamp())) {
+			if share.Allow(entity.PermView) {
+				return share.Perm
+			}
+
+			return entity.PermView
+		}
+	}
+
+	if sess.HasShare(album.AlbumUID) {
+		return entity.PermView
+	}
+
+	return entity.PermNone
+}
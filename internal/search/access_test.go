@@ -0,0 +1,318 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+)
+
+// contributorSession returns a session for a user who may only access own and shared content.
+func contributorSession(t *testing.T) *entity.Session {
+	role := acl.Role("contributor")
+
+	grant := acl.Grant{acl.AccessOwn: true, acl.ActionSearch: true, acl.ActionView: true}
+
+	acl.CustomRoles{
+		role: acl.Grants{
+			acl.ResourcePhotos:  grant,
+			acl.ResourceAlbums:  grant,
+			acl.ResourceFolders: grant,
+			acl.ResourceLabels:  grant,
+		},
+	}.Apply(acl.Resources)
+
+	t.Cleanup(func() {
+		for resource := range acl.Resources {
+			delete(acl.Resources[resource], role)
+		}
+
+		delete(acl.ValidRoles, role.String())
+	})
+
+	user := &entity.User{
+		UserUID:  "uqxc08w3d0ej2299",
+		UserName: "contributor",
+		UserRole: role.String(),
+		BasePath: "1990/04",
+	}
+
+	return entity.NewSession(0, 0).SetUser(user)
+}
+
+// visitorSession returns a visitor session that has redeemed a share link for the specified UID.
+func visitorSession(t *testing.T, shareUid string) *entity.Session {
+	return creatorVisitorSession(t, shareUid, entity.UserFixtures.Pointer("alice").UserUID)
+}
+
+// creatorVisitorSession returns a visitor session that has redeemed a share link created by the specified user.
+func creatorVisitorSession(t *testing.T, shareUid, creatorUid string) *entity.Session {
+	link := entity.NewUserLink(shareUid, creatorUid)
+
+	if err := link.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = link.Delete() })
+
+	data := entity.NewSessionData()
+
+	if data.RedeemToken(link.LinkToken) == 0 {
+		t.Fatal("failed redeeming share token")
+	}
+
+	return entity.NewSession(0, 0).SetData(data).SetUser(&entity.Visitor)
+}
+
+func TestAlbumResource(t *testing.T) {
+	assert.Equal(t, acl.ResourceAlbums, AlbumResource(entity.AlbumDefault))
+	assert.Equal(t, acl.ResourceAlbums, AlbumResource(""))
+	assert.Equal(t, acl.ResourceFolders, AlbumResource(entity.AlbumFolder))
+	assert.Equal(t, acl.ResourceMoments, AlbumResource(entity.AlbumMoment))
+	assert.Equal(t, acl.ResourceCalendar, AlbumResource(entity.AlbumMonth))
+	assert.Equal(t, acl.ResourcePlaces, AlbumResource(entity.AlbumState))
+}
+
+func TestLimitedAccess(t *testing.T) {
+	assert.False(t, LimitedAccess(acl.ResourcePhotos, nil))
+	assert.False(t, LimitedAccess(acl.ResourcePhotos, entity.SessionFixtures.Pointer("alice")))
+	assert.True(t, LimitedAccess(acl.ResourcePhotos, entity.SessionFixtures.Pointer("visitor")))
+	assert.True(t, LimitedAccess(acl.ResourcePhotos, contributorSession(t)))
+}
+
+func TestUserPhotosWhere(t *testing.T) {
+	t.Run("Admin", func(t *testing.T) {
+		where, values := UserPhotosWhere(acl.ResourcePhotos, entity.SessionFixtures.Pointer("alice"))
+		assert.Equal(t, "", where)
+		assert.Empty(t, values)
+	})
+	t.Run("Visitor", func(t *testing.T) {
+		where, values := UserPhotosWhere(acl.ResourcePhotos, entity.SessionFixtures.Pointer("visitor"))
+		assert.NotContains(t, where, "created_by")
+		assert.Contains(t, where, "photos.photo_uid IN (?)")
+		assert.NotContains(t, where, "labels.label_uid = ?")
+		assert.Len(t, values, 3)
+	})
+	t.Run("LabelShare", func(t *testing.T) {
+		where, values := UserPhotosWhere(acl.ResourcePhotos, visitorSession(t, "lt9k3pw1wowuy3c4"))
+		assert.Contains(t, where, "labels.label_uid = ?")
+		assert.Len(t, values, 4)
+	})
+	t.Run("Contributor", func(t *testing.T) {
+		where, values := UserPhotosWhere(acl.ResourcePhotos, contributorSession(t))
+		assert.Contains(t, where, "photos.created_by = ?")
+		assert.Contains(t, where, "photos.photo_path LIKE ?")
+		assert.Len(t, values, 6)
+	})
+}
+
+func TestLabelSharesWhere(t *testing.T) {
+	t.Run("NoLabels", func(t *testing.T) {
+		where, values := LabelSharesWhere(entity.UIDs{"at9lxuqxpogaaba8", "pt9jtdre2lvl0y24"})
+		assert.Equal(t, "", where)
+		assert.Empty(t, values)
+	})
+	t.Run("UnknownCreator", func(t *testing.T) {
+		link := entity.NewLink("lt9k3pw1wowuy3c4", false, false)
+
+		if err := link.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		defer func() { _ = link.Delete() }()
+
+		// Links without a registered creator must not grant access.
+		where, _ := LabelSharesWhere(entity.UIDs{"lt9k3pw1wowuy3c4"})
+		assert.Equal(t, "", where)
+	})
+}
+
+func TestPhotoAccess(t *testing.T) {
+	t.Run("Admin", func(t *testing.T) {
+		assert.True(t, PhotoAccess("pt9jtdre2lvl0yh7", entity.SessionFixtures.Pointer("alice")))
+	})
+	t.Run("Visitor", func(t *testing.T) {
+		sess := entity.SessionFixtures.Pointer("visitor")
+		assert.True(t, PhotoAccess("pt9jtdre2lvl0yh7", sess))
+		assert.False(t, PhotoAccess("pt9jtdre2lvl0y24", sess))
+	})
+	t.Run("Contributor", func(t *testing.T) {
+		sess := contributorSession(t)
+		assert.True(t, PhotoAccess("pt9jtdre2lvl0y24", sess))
+		assert.False(t, PhotoAccess("pt9jtdre2lvl0yh7", sess))
+	})
+	t.Run("PhotoShare", func(t *testing.T) {
+		sess := visitorSession(t, "pt9jtdre2lvl0y24")
+		assert.True(t, PhotoAccess("pt9jtdre2lvl0y24", sess))
+		assert.False(t, PhotoAccess("pt9jtdre2lvl0yh9", sess))
+	})
+	t.Run("LabelShare", func(t *testing.T) {
+		sess := visitorSession(t, "lt9k3pw1wowuy3c4")
+		assert.True(t, PhotoAccess("pt9jtdre2lvl0yh9", sess))
+		assert.False(t, PhotoAccess("pt9jtdre2lvl0y24", sess))
+	})
+	t.Run("LimitedLabelShare", func(t *testing.T) {
+		contributorSession(t)
+
+		creator := &entity.User{
+			UserName: "label-contributor",
+			UserRole: "contributor",
+			BasePath: "1990/04",
+		}
+
+		if err := creator.Create(); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() { _ = entity.UnscopedDb().Delete(creator).Error })
+
+		// Label shares only include photos that the creator of the link may access.
+		sess := creatorVisitorSession(t, "lt9k3pw1wowuy3c4", creator.UserUID)
+		assert.False(t, PhotoAccess("pt9jtdre2lvl0yh9", sess))
+	})
+}
+
+func TestLabelAccess(t *testing.T) {
+	t.Run("Admin", func(t *testing.T) {
+		assert.True(t, LabelAccess("lt9k3pw1wowuy3c4", entity.SessionFixtures.Pointer("alice")))
+	})
+	t.Run("Contributor", func(t *testing.T) {
+		assert.False(t, LabelAccess("lt9k3pw1wowuy3c4", contributorSession(t)))
+	})
+	t.Run("LabelShare", func(t *testing.T) {
+		assert.True(t, LabelAccess("lt9k3pw1wowuy3c4", visitorSession(t, "lt9k3pw1wowuy3c4")))
+	})
+}
+
+func TestAlbumAccess(t *testing.T) {
+	t.Run("Admin", func(t *testing.T) {
+		assert.True(t, AlbumAccess("at1lxuqipogaabj8", entity.SessionFixtures.Pointer("alice")))
+	})
+	t.Run("Visitor", func(t *testing.T) {
+		sess := entity.SessionFixtures.Pointer("visitor")
+		assert.True(t, AlbumAccess("at9lxuqxpogaaba8", sess))
+		assert.False(t, AlbumAccess("at1lxuqipogaaba1", sess))
+	})
+	t.Run("Contributor", func(t *testing.T) {
+		sess := contributorSession(t)
+		assert.True(t, AlbumAccess("at1lxuqipogaaba1", sess))
+		assert.False(t, AlbumAccess("at1lxuqipogaabj8", sess))
+		assert.False(t, AlbumAccess("at9lxuqxpogaaba8", sess))
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		assert.False(t, AlbumAccess("at1lxuqipogaaxxx", contributorSession(t)))
+	})
+}
+
+func TestUserPhotos_Contributor(t *testing.T) {
+	sess := contributorSession(t)
+
+	f := form.SearchPhotos{Count: 100}
+
+	photos, _, err := UserPhotos(f, sess)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NotEmpty(t, photos)
+
+	for _, p := range photos {
+		assert.Equal(t, "1990/04", p.PhotoPath)
+	}
+}
+
+func TestUserAlbums_Contributor(t *testing.T) {
+	sess := contributorSession(t)
+
+	f := form.SearchAlbums{Type: entity.AlbumFolder, Count: 100}
+
+	albums, err := UserAlbums(f, sess)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NotEmpty(t, albums)
+
+	for _, a := range albums {
+		assert.Equal(t, "1990/04", a.AlbumPath)
+	}
+}
+
+func TestUserLabels(t *testing.T) {
+	t.Run("Admin", func(t *testing.T) {
+		all, err := Labels(form.SearchLabels{Count: 1000})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		results, err := UserLabels(form.SearchLabels{Count: 1000}, entity.SessionFixtures.Pointer("alice"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, len(all), len(results))
+	})
+	t.Run("Contributor", func(t *testing.T) {
+		all, err := Labels(form.SearchLabels{Count: 1000})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		results, err := UserLabels(form.SearchLabels{Count: 1000}, contributorSession(t))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Less(t, len(results), len(all))
+	})
+	t.Run("Visitor", func(t *testing.T) {
+		_, err := UserLabels(form.SearchLabels{Count: 1000}, entity.SessionFixtures.Pointer("visitor"))
+		assert.Equal(t, ErrForbidden, err)
+	})
+}
+
+func TestAlbumPerm(t *testing.T) {
+	t.Run("Admin", func(t *testing.T) {
+		assert.Equal(t, entity.PermAll, AlbumPerm("at9lxuqxpogaaba8", entity.SessionFixtures.Pointer("alice")))
+	})
+	t.Run("Visitor", func(t *testing.T) {
+		sess := entity.SessionFixtures.Pointer("visitor")
+		assert.Equal(t, entity.PermView, AlbumPerm("at9lxuqxpogaaba8", sess))
+		assert.Equal(t, entity.PermNone, AlbumPerm("at1lxuqipogaaba1", sess))
+	})
+	t.Run("Contributor", func(t *testing.T) {
+		share := entity.NewUserShare("uqxc08w3d0ej2299", "at9lxuqxpogaaba7", entity.ShareLevels[entity.ShareContributor], nil)
+
+		if err := share.Create(); err != nil {
+			t.Fatal(err)
+		}
+
+		defer func() { _ = share.Delete() }()
+
+		sess := contributorSession(t)
+
+		assert.Equal(t, entity.PermUpload, AlbumPerm("at9lxuqxpogaaba7", sess))
+		assert.Equal(t, entity.PermNone, AlbumPerm("at9lxuqxpogaaba8", sess))
+		assert.Equal(t, entity.PermNone, AlbumPerm("at1lxuqipogaaxxx", sess))
+		assert.True(t, AlbumAccess("at9lxuqxpogaaba7", sess))
+
+		// Shared albums are included in the search results.
+		albums, err := UserAlbums(form.SearchAlbums{Type: entity.AlbumDefault, Count: 100}, sess)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, albums, 1)
+		assert.Equal(t, "at9lxuqxpogaaba7", albums[0].AlbumUID)
+	})
+}
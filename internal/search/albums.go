@@ -30,7 +30,7 @@ func UserAlbums(f form.SearchAlbums, sess *entity.Session) (results AlbumResults
 	// Base query.
 	s := UnscopedDb().Table("albums").
 		Select("albums.*, cp.photo_count, cl.link_count, CASE WHEN albums.album_year = 0 THEN 0 ELSE 1 END AS has_year").
-		Joins("LEFT JOIN (SELECT album_uid, count(photo_uid) AS photo_count FROM photos_albums WHERE hidden = 0 AND missing = 0 GROUP BY album_uid) AS cp ON cp.album_uid = albums.album_uid").
+		Joins("LEFT JOIN (SELECT album_uid, count(photo_uid) AS photo_count FROM photos_albums WHERE hidden = FALSE AND missing = FALSE GROUP BY album_uid) AS cp ON cp.album_uid = albums.album_uid").
 		Joins("LEFT JOIN (SELECT share_uid, count(share_uid) AS link_count FROM links GROUP BY share_uid) AS cl ON cl.share_uid = albums.album_uid").
 		Where("albums.deleted_at IS NULL")
 
@@ -40,19 +40,7 @@ func UserAlbums(f form.SearchAlbums, sess *entity.Session) (results AlbumResults
 		aclRole := user.AclRole()
 
 		// Determine resource to check.
-		var aclResource acl.Resource
-		switch f.Type {
-		case entity.AlbumDefault:
-			aclResource = acl.ResourceAlbums
-		case entity.AlbumFolder:
-			aclResource = acl.ResourceFolders
-		case entity.AlbumMoment:
-			aclResource = acl.ResourceMoments
-		case entity.AlbumMonth:
-			aclResource = acl.ResourceCalendar
-		case entity.AlbumState:
-			aclResource = acl.ResourcePlaces
-		}
+		aclResource := AlbumResource(f.Type)
 
 		// Check user permissions.
 		if acl.Resources.DenyAll(aclResource, aclRole, acl.Permissions{acl.AccessAll, acl.AccessLibrary, acl.AccessShared, acl.AccessOwn}) {
@@ -60,15 +48,8 @@ func UserAlbums(f form.SearchAlbums, sess *entity.Session) (results AlbumResults
 		}
 
 		// Limit results by UID, owner and path.
-		if sess.IsVisitor() || sess.NotRegistered() {
-			s = s.Where("albums.album_uid IN (?) OR albums.published_at > ?", sess.SharedUIDs(), entity.TimeStamp())
-		} else if acl.Resources.DenyAll(aclResource, aclRole, acl.Permissions{acl.AccessAll, acl.AccessLibrary}) {
-			if user.BasePath == "" {
-				s = s.Where("albums.album_uid IN (?) OR albums.created_by = ? OR albums.published_at > ?", sess.SharedUIDs(), user.UserUID, entity.TimeStamp())
-			} else {
-				s = s.Where("albums.album_uid IN (?) OR albums.created_by = ? OR albums.published_at > ? OR albums.album_type = ? AND (albums.album_path = ? OR albums.album_path LIKE ?)",
-					sess.SharedUIDs(), user.UserUID, entity.TimeStamp(), entity.AlbumFolder, user.BasePath, user.BasePath+"/%")
-			}
+		if where, values := UserAlbumsWhere(f.Type, sess); where != "" {
+			s = s.Where(where, values...)
 		}
 
 		// Exclude private content?
@@ -148,7 +129,7 @@ func UserAlbums(f form.SearchAlbums, sess *entity.Session) (results AlbumResults
 
 	// Albums with public pictures only?
 	if f.Public {
-		s = s.Where("albums.album_type <> 'folder' OR albums.album_path IN (SELECT photo_path FROM photos WHERE photo_private = 0 AND photo_quality > -1 AND deleted_at IS NULL)")
+		s = s.Where("albums.album_type <> 'folder' OR albums.album_path IN (SELECT photo_path FROM photos WHERE photo_private = FALSE AND photo_quality > -1 AND deleted_at IS NULL)")
 	} else {
 		s = s.Where("albums.album_type <> 'folder' OR albums.album_path IN (SELECT photo_path FROM photos WHERE photo_quality > -1 AND deleted_at IS NULL)")
 	}
@@ -170,7 +151,7 @@ func UserAlbums(f form.SearchAlbums, sess *entity.Session) (results AlbumResults
 	}
 
 	if f.Favorite {
-		s = s.Where("albums.album_favorite = 1")
+		s = s.Where("albums.album_favorite = TRUE")
 	}
 
 	if (f.Year > 0 && f.Year <= txt.YearMax) || f.Year == entity.UnknownYear {
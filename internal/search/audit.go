@@ -0,0 +1,75 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/pkg/txt"
+)
+
+// AuditEvents searches the audit log and returns matching events, newest first.
+func AuditEvents(f form.SearchAudit) (results entity.AuditEvents, err error) {
+	if err = f.ParseQueryString(); err != nil {
+		return results, err
+	}
+
+	s := UnscopedDb().Table(entity.AuditEvent{}.TableName())
+
+	// Limit result count.
+	if f.Count > 0 && f.Count <= MaxResults {
+		s = s.Limit(f.Count).Offset(f.Offset)
+	} else {
+		s = s.Limit(MaxResults).Offset(f.Offset)
+	}
+
+	// Set sort order.
+	switch f.Order {
+	case "oldest":
+		s = s.Order("event_time, id")
+	default:
+		s = s.Order("event_time DESC, id DESC")
+	}
+
+	if f.Level != "" {
+		s = s.Where("event_level IN (?)", strings.Split(strings.ToLower(f.Level), txt.Or))
+	}
+
+	if f.IP != "" {
+		s = s.Where("client_ip = ?", f.IP)
+	}
+
+	if f.Session != "" {
+		s = s.Where("session_ref = ?", f.Session)
+	}
+
+	if f.User != "" {
+		s = s.Where("user_name = ? OR user_uid = ?", f.User, f.User)
+	}
+
+	if f.UID != "" {
+		s = s.Where("resource_uid IN (?)", strings.Split(strings.ToLower(f.UID), txt.Or))
+	}
+
+	if f.Outcome != "" {
+		s = s.Where("outcome LIKE ?", "%"+f.Outcome+"%")
+	}
+
+	if f.Query != "" {
+		s = s.Where("message LIKE ?", "%"+f.Query+"%")
+	}
+
+	if !f.Before.IsZero() {
+		s = s.Where("event_time < ?", f.Before.Format("2006-01-02"))
+	}
+
+	if !f.After.IsZero() {
+		s = s.Where("event_time >= ?", f.After.Format("2006-01-02"))
+	}
+
+	if err = s.Find(&results).Error; err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,75 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/form"
+)
+
+func TestAuditEvents(t *testing.T) {
+	t.Run("All", func(t *testing.T) {
+		results, err := AuditEvents(form.SearchAudit{Count: 100})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.GreaterOrEqual(t, len(results), 3)
+
+		// Newest events first.
+		for i := 1; i < len(results); i++ {
+			assert.False(t, results[i].EventTime.After(results[i-1].EventTime))
+		}
+	})
+	t.Run("ResourceUID", func(t *testing.T) {
+		results, err := AuditEvents(form.SearchAudit{UID: "pt9jtdre2lvl0yh7", Count: 10})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.Len(t, results, 1) {
+			assert.Equal(t, "denied", results[0].Outcome)
+			assert.Equal(t, "bob", results[0].UserName)
+		}
+	})
+	t.Run("QueryString", func(t *testing.T) {
+		results, err := AuditEvents(form.SearchAudit{Query: "level:warning user:bob", Count: 10})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.Len(t, results, 1) {
+			assert.Equal(t, "invalid password", results[0].Outcome)
+		}
+	})
+	t.Run("Dates", func(t *testing.T) {
+		results, err := AuditEvents(form.SearchAudit{
+			IP:     "192.0.2.10",
+			After:  time.Date(2022, 10, 10, 0, 0, 0, 0, time.UTC),
+			Before: time.Date(2022, 10, 11, 0, 0, 0, 0, time.UTC),
+			Count:  10,
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.Len(t, results, 1) {
+			assert.Equal(t, "as6sg6bxpogaaba8", results[0].ResourceUID)
+		}
+	})
+	t.Run("Paging", func(t *testing.T) {
+		results, err := AuditEvents(form.SearchAudit{Order: "oldest", Count: 1, Offset: 1})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, results, 1)
+	})
+}
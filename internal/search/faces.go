@@ -28,21 +28,21 @@ func Faces(f form.SearchFaces) (results FaceResults, err error) {
 			s = s.Joins(`JOIN (
 	        SELECT face_id, MIN(marker_uid) AS marker_uid FROM markers
 	        WHERE face_id <> '' AND subj_uid = '' AND marker_name = '' AND marker_type = 'face' AND marker_src = 'image'
-	          AND marker_invalid = 0 AND face_dist <= 0.64 AND size >= 80 AND score >= 15
+	          AND marker_invalid = FALSE AND face_dist <= 0.64 AND size >= 80 AND score >= 15
 	        GROUP BY face_id) fm
 	        ON faces.id = fm.face_id`)
 		} else if txt.No(f.Unknown) {
 			s = s.Joins(`JOIN (
 	        SELECT face_id, MIN(marker_uid) AS marker_uid FROM markers
 	        WHERE face_id <> '' AND subj_uid <> '' AND marker_name <> '' AND marker_type = 'face' AND marker_src = 'image'
-	          AND marker_invalid = 0 AND face_dist <= 0.64 AND size >= 80 AND score >= 15
+	          AND marker_invalid = FALSE AND face_dist <= 0.64 AND size >= 80 AND score >= 15
 	        GROUP BY face_id) fm
 	        ON faces.id = fm.face_id`)
 		} else {
 			s = s.Joins(`JOIN (
 	        SELECT face_id, MIN(marker_uid) AS marker_uid FROM markers
 	        WHERE face_id <> '' AND marker_type = 'face' AND marker_src = 'image'
-	          AND marker_invalid = 0 AND face_dist <= 0.64 AND size >= 80 AND score >= 15
+	          AND marker_invalid = FALSE AND face_dist <= 0.64 AND size >= 80 AND score >= 15
 	        GROUP BY face_id) fm
 	        ON faces.id = fm.face_id`)
 		}
@@ -91,7 +91,7 @@ func Faces(f form.SearchFaces) (results FaceResults, err error) {
 
 	// Show hidden faces?
 	if !txt.Yes(f.Hidden) {
-		s = s.Where(fmt.Sprintf("%s.face_hidden = 0", facesTable))
+		s = s.Where(fmt.Sprintf("%s.face_hidden = FALSE", facesTable))
 	}
 
 	// Perform query.
@@ -3,14 +3,21 @@ package search
 import (
 	"strings"
 
+	"github.com/photoprism/photoprism/internal/acl"
 	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
 	"github.com/photoprism/photoprism/internal/form"
 	"github.com/photoprism/photoprism/pkg/clean"
 	"github.com/photoprism/photoprism/pkg/txt"
 )
 
-// Labels searches labels based on their name.
+// Labels searches labels based on their name without checking rights or permissions.
 func Labels(f form.SearchLabels) (results []Label, err error) {
+	return UserLabels(f, nil)
+}
+
+// UserLabels searches labels based on their name and user session.
+func UserLabels(f form.SearchLabels, sess *entity.Session) (results []Label, err error) {
 	if err := f.ParseQueryString(); err != nil {
 		return results, err
 	}
@@ -25,6 +32,22 @@ func Labels(f form.SearchLabels) (results []Label, err error) {
 		Where("labels.photo_count > 0").
 		Group("labels.id")
 
+	// Check session permissions and apply as needed.
+	if sess != nil {
+		aclRole := sess.User().AclRole()
+
+		if acl.Resources.Deny(acl.ResourceLabels, aclRole, acl.ActionSearch) {
+			event.AuditErr([]string{sess.IP(), "session %s", "%s %s as %s", "denied"}, sess.RefID, acl.ActionSearch.String(), string(acl.ResourceLabels), aclRole)
+			return results, ErrForbidden
+		}
+
+		// Limit results to labels of own and shared photos.
+		if where, values := UserPhotosWhere(acl.ResourceLabels, sess); where != "" {
+			s = s.Where("labels.id IN (SELECT photos_labels.label_id FROM photos_labels JOIN photos ON photos.id = photos_labels.photo_id "+
+				"WHERE photos_labels.uncertainty < 100 AND photos.deleted_at IS NULL AND ("+where+"))", values...)
+		}
+	}
+
 	// Limit result count.
 	if f.Count > 0 && f.Count <= MaxResults {
 		s = s.Limit(f.Count).Offset(f.Offset)
@@ -78,11 +101,11 @@ func Labels(f form.SearchLabels) (results []Label, err error) {
 	}
 
 	if f.Favorite {
-		s = s.Where("labels.label_favorite = 1")
+		s = s.Where("labels.label_favorite = TRUE")
 	}
 
 	if !f.All {
-		s = s.Where("labels.label_priority >= 0 OR labels.label_favorite = 1")
+		s = s.Where("labels.label_priority >= 0 OR labels.label_favorite = TRUE")
 	}
 
 	if result := s.Scan(&results); result.Error != nil {
@@ -149,6 +149,50 @@ func TestPhotos(t *testing.T) {
 		assert.Equal(t, PhotoResults{}, photos)
 		assert.Equal(t, 0, count)
 	})
+	t.Run("PhotoScope", func(t *testing.T) {
+		var frm form.SearchPhotos
+
+		frm.Scope = "pt9jtdre2lvl0yh7"
+		frm.Count = 10
+		frm.Offset = 0
+
+		photos, _, err := Photos(frm)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if assert.Len(t, photos, 1) {
+			assert.Equal(t, "pt9jtdre2lvl0yh7", photos[0].PhotoUID)
+		}
+	})
+	t.Run("LabelScope", func(t *testing.T) {
+		var frm form.SearchPhotos
+
+		frm.Scope = "lt9k3pw1wowuy3c2"
+		frm.Count = 10
+		frm.Offset = 0
+		frm.Order = "relevance"
+
+		photos, _, err := Photos(frm)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.LessOrEqual(t, 2, len(photos))
+	})
+	t.Run("InvalidScope", func(t *testing.T) {
+		var frm form.SearchPhotos
+
+		frm.Scope = "lt9k3pw1wowuy3xx"
+		frm.Count = 10
+		frm.Offset = 0
+
+		_, _, err := Photos(frm)
+
+		assert.Equal(t, ErrInvalidId, err)
+	})
 	t.Run("form.location true", func(t *testing.T) {
 		var frm form.SearchPhotos
 
@@ -0,0 +1,44 @@
+package search
+
+import (
+	"github.com/photoprism/photoprism/internal/acl"
+	"github.com/photoprism/photoprism/internal/entity"
+)
+
+// UserTrashFiles returns the files in the trash that belong to photos the session may access,
+// most recently deleted first.
+func UserTrashFiles(sess *entity.Session, limit, offset int) (result entity.TrashFiles, err error) {
+	where, values := UserPhotosWhere(acl.ResourcePhotos, sess)
+
+	if where == "" {
+		return entity.ListTrashFiles(limit, offset)
+	}
+
+	return trashFiles(limit, offset, where, values)
+}
+
+// OwnTrashFiles returns the files in the trash that belong to photos added by the user or stored in its base path.
+func OwnTrashFiles(user *entity.User) (result entity.TrashFiles, err error) {
+	if user == nil {
+		return result, nil
+	}
+
+	where, values := OwnPhotosWhere(user)
+
+	return trashFiles(0, 0, where, values)
+}
+
+// trashFiles returns the files in the trash that belong to photos matching the condition.
+func trashFiles(limit, offset int, where string, values []interface{}) (result entity.TrashFiles, err error) {
+	stmt := UnscopedDb().
+		Where("photo_uid IN (SELECT photos.photo_uid FROM photos WHERE ("+where+"))", values...).
+		Order("created_at DESC, id DESC")
+
+	if limit > 0 {
+		stmt = stmt.Limit(limit).Offset(offset)
+	}
+
+	err = stmt.Find(&result).Error
+
+	return result, err
+}
@@ -0,0 +1,38 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/entity"
+)
+
+func TestUserTrashFiles(t *testing.T) {
+	t.Run("Admin", func(t *testing.T) {
+		result, err := UserTrashFiles(entity.SessionFixtures.Pointer("alice"), 10, 0)
+
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, len(result), 2)
+	})
+	t.Run("Visitor", func(t *testing.T) {
+		result, err := UserTrashFiles(entity.SessionFixtures.Pointer("visitor"), 10, 0)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 0)
+	})
+}
+
+func TestOwnTrashFiles(t *testing.T) {
+	t.Run("NoUser", func(t *testing.T) {
+		result, err := OwnTrashFiles(nil)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 0)
+	})
+	t.Run("Alice", func(t *testing.T) {
+		_, err := OwnTrashFiles(entity.UserFixtures.Pointer("alice"))
+
+		assert.NoError(t, err)
+	})
+}
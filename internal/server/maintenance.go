@@ -0,0 +1,27 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/photoprism/photoprism/internal/api"
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/i18n"
+)
+
+// Maintenance rejects requests that may modify data while maintenance mode is enabled, e.g. when a backup is restored.
+var Maintenance = func(conf *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !conf.Maintenance() {
+			return
+		}
+
+		switch c.Request.Method {
+		case MethodGet, MethodHead, MethodOptions, MethodPropfind:
+			return
+		default:
+			api.Abort(c, http.StatusServiceUnavailable, i18n.ErrMaintenance)
+		}
+	}
+}
@@ -70,11 +70,21 @@ func registerRoutes(router *gin.Engine, conf *config.Config) {
 		api.CreateSession(v1)
 		api.GetSession(v1)
 		api.DeleteSession(v1)
+		api.GetUserTokens(v1)
+		api.CreateUserToken(v1)
+		api.DeleteUserToken(v1)
+		api.CreateUserPasscode(v1)
+		api.ActivateUserPasscode(v1)
+		api.DeactivateUserPasscode(v1)
+		api.OIDCLogin(v1)
+		api.OIDCRedirect(v1)
+		api.OIDCPasscode(v1)
 
 		// External Account Management.
 		api.SearchServices(v1)
 		api.GetService(v1)
 		api.GetServiceFolders(v1)
+		api.GetServiceConflicts(v1)
 		api.UploadToService(v1)
 		api.AddService(v1)
 		api.DeleteService(v1)
@@ -101,10 +111,10 @@ func registerRoutes(router *gin.Engine, conf *config.Config) {
 		api.GetPhotoYaml(v1)
 		api.UpdatePhoto(v1)
 		api.GetPhotoDownload(v1)
-		// api.GetPhotoLinks(v1)
-		// api.CreatePhotoLink(v1)
-		// api.UpdatePhotoLink(v1)
-		// api.DeletePhotoLink(v1)
+		api.GetPhotoLinks(v1)
+		api.CreatePhotoLink(v1)
+		api.UpdatePhotoLink(v1)
+		api.DeletePhotoLink(v1)
 		api.ApprovePhoto(v1)
 		api.LikePhoto(v1)
 		api.DislikePhoto(v1)
@@ -131,6 +141,9 @@ func registerRoutes(router *gin.Engine, conf *config.Config) {
 		api.CreateAlbumLink(v1)
 		api.UpdateAlbumLink(v1)
 		api.DeleteAlbumLink(v1)
+		api.GetAlbumUsers(v1)
+		api.ShareAlbumWithUser(v1)
+		api.RevokeAlbumUser(v1)
 		api.LikeAlbum(v1)
 		api.DislikeAlbum(v1)
 		api.CloneAlbums(v1)
@@ -141,10 +154,10 @@ func registerRoutes(router *gin.Engine, conf *config.Config) {
 		api.SearchLabels(v1)
 		api.LabelCover(v1)
 		api.UpdateLabel(v1)
-		// api.GetLabelLinks(v1)
-		// api.CreateLabelLink(v1)
-		// api.UpdateLabelLink(v1)
-		// api.DeleteLabelLink(v1)
+		api.GetLabelLinks(v1)
+		api.CreateLabelLink(v1)
+		api.UpdateLabelLink(v1)
+		api.DeleteLabelLink(v1)
 		api.LikeLabel(v1)
 		api.DislikeLabel(v1)
 
@@ -174,14 +187,39 @@ func registerRoutes(router *gin.Engine, conf *config.Config) {
 		api.BatchAlbumsDelete(v1)
 		api.BatchLabelsDelete(v1)
 
+		// Webhooks.
+		api.GetWebhooks(v1)
+		api.CreateWebhook(v1)
+		api.UpdateWebhook(v1)
+		api.DeleteWebhook(v1)
+		api.GetWebhookDeliveries(v1)
+
+		// Background Jobs.
+		api.GetJobs(v1)
+		api.GetJob(v1)
+		api.CreateJob(v1)
+		api.CancelJob(v1)
+
+		// Backups.
+		api.GetBackups(v1)
+		api.RestoreBackup(v1)
+
+		// Trash.
+		api.GetTrash(v1)
+		api.RestoreTrash(v1)
+		api.EmptyTrash(v1)
+
 		// Technical Endpoints.
 		api.GetSvg(v1)
 		api.GetStatus(v1)
 		api.GetErrors(v1)
 		api.DeleteErrors(v1)
+		api.SearchAudit(v1)
 		api.SendFeedback(v1)
 		api.Connect(v1)
 		api.WebSocket(v1)
+		api.ServerSentEvents(v1)
+		api.CreateEventsToken(v1)
 	}
 
 	// Configure link sharing.
@@ -41,7 +41,7 @@ func Start(ctx context.Context, conf *config.Config) {
 	}
 
 	// Register common middleware.
-	router.Use(Recovery(), Security(conf), Logger())
+	router.Use(Recovery(), Security(conf), Logger(), Maintenance(conf))
 
 	// Initialize package extensions.
 	Ext().Init(router, conf)
@@ -2,3 +2,9 @@ package session
 
 // Header specifies the name of the session HTTP header.
 var Header = "X-Session-ID"
+
+// AuthHeader specifies the name of the HTTP header that may contain a bearer access token.
+var AuthHeader = "Authorization"
+
+// BearerPrefix specifies the authorization header prefix of access tokens.
+var BearerPrefix = "Bearer "
@@ -0,0 +1,154 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/pkg/rnd"
+)
+
+// DeliveryUID is the unique ID prefix of webhook deliveries.
+const DeliveryUID = byte('d')
+
+// HTTP headers sent along with each payload.
+const (
+	HeaderEvent     = "X-PhotoPrism-Event"
+	HeaderDelivery  = "X-PhotoPrism-Delivery"
+	HeaderSignature = "X-PhotoPrism-Signature"
+)
+
+// UserAgent specifies the HTTP user agent of webhook requests.
+var UserAgent = "PhotoPrism/Webhook"
+
+// MaxAttempts specifies how often the delivery of an event is attempted.
+var MaxAttempts = 5
+
+// RetryDelay specifies how long to wait before the first retry, it doubles with each attempt.
+var RetryDelay = 2 * time.Second
+
+// MaxRetryDelay specifies the maximum time to wait between two attempts.
+var MaxRetryDelay = 5 * time.Minute
+
+// Timeout specifies the time limit for each request.
+var Timeout = 15 * time.Second
+
+// Payload represents the JSON request body sent to webhooks.
+type Payload struct {
+	Event string     `json:"event"`
+	Time  time.Time  `json:"time"`
+	Data  event.Data `json:"data"`
+}
+
+// NewPayload creates a new payload for the event topic and data.
+func NewPayload(topic string, data event.Data) Payload {
+	return Payload{
+		Event: topic,
+		Time:  entity.TimeStamp(),
+		Data:  data,
+	}
+}
+
+// Backoff returns the time to wait after the specified number of failed attempts.
+func Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		return 0
+	}
+
+	d := RetryDelay
+
+	for i := 1; i < attempt && d < MaxRetryDelay; i++ {
+		d *= 2
+	}
+
+	if d > MaxRetryDelay {
+		return MaxRetryDelay
+	}
+
+	return d
+}
+
+// Deliver sends the signed payload to the webhook, retries with exponential backoff if it fails,
+// and records each attempt in the delivery log.
+func Deliver(hook *entity.Webhook, p Payload) error {
+	body, err := json.Marshal(p)
+
+	if err != nil {
+		return err
+	}
+
+	deliveryId := rnd.GenerateUID(DeliveryUID)
+
+	for attempt := 1; attempt <= MaxAttempts; attempt++ {
+		start := time.Now()
+		status, sendErr := send(hook, p.Event, deliveryId, body)
+
+		if err = entity.NewWebhookDelivery(hook.WebhookUID, deliveryId, p.Event, attempt, status, sendErr, time.Since(start)).Create(); err != nil {
+			log.Errorf("webhook: %s (log delivery)", err)
+		}
+
+		if sendErr == nil {
+			log.Debugf("webhook: delivered %s to %s", p.Event, hook.String())
+			return nil
+		} else if !retry(status) || attempt == MaxAttempts {
+			return sendErr
+		}
+
+		time.Sleep(Backoff(attempt))
+	}
+
+	return nil
+}
+
+// send performs a single delivery attempt and returns the HTTP status code.
+func send(hook *entity.Webhook, topic, deliveryId string, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, hook.WebhookURL, bytes.NewReader(body))
+
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set(HeaderEvent, topic)
+	req.Header.Set(HeaderDelivery, deliveryId)
+	req.Header.Set(HeaderSignature, "sha256="+hook.Sign(body))
+
+	client := &http.Client{Timeout: Timeout}
+	resp, err := client.Do(req)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+
+	// Drain the body so that the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// retry checks if a failed request should be retried based on the HTTP status code.
+func retry(status int) bool {
+	switch {
+	case status == 0:
+		// Network error or timeout.
+		return true
+	case status == http.StatusRequestTimeout, status == http.StatusTooManyRequests:
+		return true
+	case status >= 500:
+		return true
+	default:
+		return false
+	}
+}
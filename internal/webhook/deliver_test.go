@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+)
+
+func TestBackoff(t *testing.T) {
+	delay, max := RetryDelay, MaxRetryDelay
+
+	RetryDelay = time.Second
+	MaxRetryDelay = 10 * time.Second
+
+	defer func() {
+		RetryDelay, MaxRetryDelay = delay, max
+	}()
+
+	assert.Equal(t, time.Duration(0), Backoff(0))
+	assert.Equal(t, time.Second, Backoff(1))
+	assert.Equal(t, 2*time.Second, Backoff(2))
+	assert.Equal(t, 4*time.Second, Backoff(3))
+	assert.Equal(t, 8*time.Second, Backoff(4))
+	assert.Equal(t, 10*time.Second, Backoff(5))
+	assert.Equal(t, 10*time.Second, Backoff(50))
+}
+
+func TestDeliver(t *testing.T) {
+	t.Run("Signed", func(t *testing.T) {
+		hook := entity.WebhookFixtures.Pointer("bot")
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "photos.created", r.Header.Get(HeaderEvent))
+			assert.True(t, strings.HasPrefix(r.Header.Get(HeaderDelivery), "d"))
+			assert.Equal(t, "sha256="+hook.Sign(body), r.Header.Get(HeaderSignature))
+
+			var p Payload
+
+			if err := json.Unmarshal(body, &p); err != nil {
+				t.Error(err)
+			}
+
+			assert.Equal(t, "photos.created", p.Event)
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		defer srv.Close()
+
+		hook.WebhookURL = srv.URL
+
+		err := Deliver(hook, NewPayload("photos.created", event.Data{"entities": []string{"pt9jtdre2lvl0yh7"}}))
+
+		assert.NoError(t, err)
+	})
+	t.Run("Retry", func(t *testing.T) {
+		var calls int32
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			} else {
+				w.WriteHeader(http.StatusAccepted)
+			}
+		}))
+
+		defer srv.Close()
+
+		hook := entity.WebhookFixtures.Pointer("bot")
+		hook.WebhookURL = srv.URL
+
+		err := Deliver(hook, NewPayload("albums.updated", event.Data{}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+	t.Run("ClientError", func(t *testing.T) {
+		var calls int32
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+
+		defer srv.Close()
+
+		hook := entity.WebhookFixtures.Pointer("bot")
+		hook.WebhookURL = srv.URL
+
+		err := Deliver(hook, NewPayload("albums.updated", event.Data{}))
+
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+	t.Run("GiveUp", func(t *testing.T) {
+		var calls int32
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+
+		defer srv.Close()
+
+		hook := entity.WebhookFixtures.Pointer("bot")
+		hook.WebhookURL = srv.URL
+
+		err := Deliver(hook, NewPayload("albums.updated", event.Data{}))
+
+		assert.Error(t, err)
+		assert.Equal(t, int32(MaxAttempts), atomic.LoadInt32(&calls))
+	})
+}
@@ -0,0 +1,139 @@
+package webhook
+
+import (
+	"sync"
+
+	"github.com/photoprism/photoprism/internal/entity"
+)
+
+// QueueSize specifies how many events can wait for delivery to a webhook before new events are dropped.
+var QueueSize = 100
+
+// QueueWorkers specifies how many events are delivered to a webhook at the same time.
+var QueueWorkers = 2
+
+// queue holds the events waiting for delivery to a webhook.
+type queue struct {
+	hook     entity.Webhook
+	payloads chan Payload
+	quit     chan struct{}
+	dropped  int
+	mutex    sync.Mutex
+}
+
+// queues contains the delivery queues by webhook uid.
+var queues = struct {
+	items map[string]*queue
+	mutex sync.Mutex
+}{
+	items: make(map[string]*queue),
+}
+
+// newQueue creates a delivery queue for the webhook and starts its workers.
+func newQueue(hook entity.Webhook) *queue {
+	q := &queue{
+		hook:     hook,
+		payloads: make(chan Payload, QueueSize),
+		quit:     make(chan struct{}),
+	}
+
+	for i := 0; i < QueueWorkers; i++ {
+		go q.work()
+	}
+
+	return q
+}
+
+// Enqueue adds the payload to the delivery queue of the webhook. It returns false and drops
+// the payload if the queue is full, e.g. because the endpoint is slow or unavailable.
+func Enqueue(hook entity.Webhook, p Payload) bool {
+	queues.mutex.Lock()
+
+	q, ok := queues.items[hook.WebhookUID]
+
+	if !ok {
+		q = newQueue(hook)
+		queues.items[hook.WebhookUID] = q
+	}
+
+	queues.mutex.Unlock()
+
+	return q.add(hook, p)
+}
+
+// add updates the webhook settings and adds the payload to the queue unless it is full.
+func (q *queue) add(hook entity.Webhook, p Payload) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.hook = hook
+
+	select {
+	case q.payloads <- p:
+		if q.dropped > 0 {
+			log.Warnf("webhook: dropped %d events for %s, queue was full", q.dropped, hook.String())
+			q.dropped = 0
+		}
+
+		return true
+	default:
+		if q.dropped == 0 {
+			log.Warnf("webhook: queue for %s is full, dropping %s", hook.String(), p.Event)
+		}
+
+		q.dropped++
+
+		return false
+	}
+}
+
+// current returns the latest webhook settings.
+func (q *queue) current() entity.Webhook {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.hook
+}
+
+// work delivers queued payloads until the queue is closed.
+func (q *queue) work() {
+	for {
+		select {
+		case <-q.quit:
+			return
+		case p := <-q.payloads:
+			hook := q.current()
+
+			if err := Deliver(&hook, p); err != nil {
+				log.Warnf("webhook: %s (deliver %s to %s)", err, p.Event, hook.String())
+			}
+		}
+	}
+}
+
+// closeQueues stops the workers of all queues for webhooks that are not in the list,
+// or of all queues if the list is nil. Events waiting for delivery are discarded.
+func closeQueues(keep entity.Webhooks) {
+	enabled := make(map[string]bool, len(keep))
+
+	for _, hook := range keep {
+		enabled[hook.WebhookUID] = true
+	}
+
+	queues.mutex.Lock()
+	defer queues.mutex.Unlock()
+
+	for uid, q := range queues.items {
+		if enabled[uid] {
+			continue
+		}
+
+		close(q.quit)
+		delete(queues.items, uid)
+
+		if n := len(q.payloads); n > 0 {
+			hook := q.current()
+			log.Infof("webhook: discarded %d events for %s", n, hook.String())
+		}
+	}
+}
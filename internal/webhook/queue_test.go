@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/entity"
+)
+
+func TestEnqueue(t *testing.T) {
+	var calls int32
+
+	release := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	defer srv.Close()
+
+	size, workers := QueueSize, QueueWorkers
+	QueueSize, QueueWorkers = 2, 1
+
+	defer func() {
+		QueueSize, QueueWorkers = size, workers
+	}()
+
+	hook, _, err := entity.NewWebhook(srv.URL, "labels.*", "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer closeQueues(nil)
+
+	p := NewPayload("labels.created", nil)
+
+	// The first payload is taken by the worker, which then blocks until released.
+	assert.True(t, Enqueue(*hook, p))
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// Two more payloads fit into the queue, the next one is dropped.
+	assert.True(t, Enqueue(*hook, p))
+	assert.True(t, Enqueue(*hook, p))
+	assert.False(t, Enqueue(*hook, p))
+
+	close(release)
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 3 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestCloseQueues(t *testing.T) {
+	hook, _, err := entity.NewWebhook("https://example.com/hook", "labels.*", "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	workers := QueueWorkers
+	QueueWorkers = 0
+	defer func() { QueueWorkers = workers }()
+
+	assert.True(t, Enqueue(*hook, NewPayload("labels.created", nil)))
+
+	closeQueues(entity.Webhooks{*hook})
+	queues.mutex.Lock()
+	assert.Contains(t, queues.items, hook.WebhookUID)
+	queues.mutex.Unlock()
+
+	closeQueues(nil)
+	queues.mutex.Lock()
+	assert.NotContains(t, queues.items, hook.WebhookUID)
+	queues.mutex.Unlock()
+}
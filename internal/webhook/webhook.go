@@ -0,0 +1,127 @@
+/*
+Package webhook delivers library events to registered HTTP endpoints.
+
+Copyright (c) 2018 - 2022 PhotoPrism UG. All rights reserved.
+
+	This program is free software: you can redistribute it and/or modify
+	it under Version 3 of the GNU Affero General Public License (the "AGPL"):
+	<https://docs.photoprism.app/license/agpl>
+
+	This program is distributed in the hope that it will be useful,
+	but WITHOUT ANY WARRANTY; without even the implied warranty of
+	MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+	GNU Affero General Public License for more details.
+
+	The AGPL is supplemented by our Trademark and Brand Guidelines,
+	which describe how our Brand Assets may be used:
+	<https://photoprism.app/trademark>
+
+Feel free to send an email to hello@photoprism.app if you have questions,
+want to support our work, or just want to say hello.
+
+Additional information can be found in our Developer Guide:
+<https://docs.photoprism.app/developer-guide/>
+*/
+package webhook
+
+import (
+	"sync"
+	"time"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+)
+
+var log = event.Log
+
+// Topics specifies the event topics that can be delivered to webhooks.
+var Topics = []string{
+	"photos.*",
+	"albums.*",
+	"labels.*",
+	"subjects.*",
+	"people.*",
+	"cameras.*",
+	"lenses.*",
+	"countries.*",
+	"index.*",
+	"import.*",
+	"upload.*",
+	"sync.*",
+}
+
+// CacheExpiration specifies how long the list of enabled webhooks is cached.
+var CacheExpiration = time.Minute
+
+var stop = make(chan bool, 1)
+
+var cache = struct {
+	hooks   entity.Webhooks
+	updated time.Time
+	mutex   sync.Mutex
+}{}
+
+// Start subscribes to library events and delivers them to matching webhooks.
+func Start(conf *config.Config) {
+	UserAgent = conf.UserAgent()
+
+	s := event.Subscribe(Topics...)
+
+	go func() {
+		defer event.Unsubscribe(s)
+
+		for {
+			select {
+			case <-stop:
+				return
+			case msg := <-s.Receiver:
+				Dispatch(msg)
+			}
+		}
+	}()
+}
+
+// Stop stops delivering events to webhooks.
+func Stop() {
+	stop <- true
+	closeQueues(nil)
+}
+
+// Flush resets the list of enabled webhooks so that changes take effect immediately.
+func Flush() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.hooks = nil
+	cache.updated = time.Time{}
+}
+
+// Enabled returns the enabled webhooks.
+func Enabled() entity.Webhooks {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if cache.hooks == nil || time.Since(cache.updated) > CacheExpiration {
+		cache.hooks = entity.FindWebhooks(true)
+		cache.updated = time.Now()
+
+		// Stop delivering events to webhooks that have been disabled or deleted.
+		closeQueues(cache.hooks)
+	}
+
+	return cache.hooks
+}
+
+// Dispatch adds the event message to the delivery queues of all enabled webhooks with a matching topic.
+func Dispatch(msg event.Message) {
+	topic := msg.Topic()
+
+	for _, hook := range Enabled() {
+		if !hook.Matches(topic) {
+			continue
+		}
+
+		Enqueue(hook, NewPayload(topic, msg.Fields))
+	}
+}
@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+)
+
+func TestMain(m *testing.M) {
+	log = logrus.StandardLogger()
+	log.SetLevel(logrus.TraceLevel)
+
+	c := config.TestConfig()
+	defer c.CloseDb()
+
+	RetryDelay = time.Millisecond
+
+	code := m.Run()
+
+	os.Exit(code)
+}
+
+func TestDispatch(t *testing.T) {
+	var calls int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	defer srv.Close()
+
+	hook, _, err := entity.NewWebhook(srv.URL, "labels.created", "")
+
+	if err != nil {
+		t.Fatal(err)
+	} else if err = hook.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = hook.Delete()
+		Flush()
+	}()
+
+	Flush()
+
+	Dispatch(event.Message{Name: "labels.updated", Fields: event.Data{}})
+	Dispatch(event.Message{Name: "labels.created", Fields: event.Data{"entities": []string{"lt9k3pw1wowuy3c3"}}})
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 1 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestEnabled(t *testing.T) {
+	Flush()
+
+	for _, hook := range Enabled() {
+		assert.True(t, hook.WebhookEnabled)
+	}
+}
@@ -0,0 +1,162 @@
+package workers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/internal/photoprism"
+)
+
+// ErrBusy is returned if a job cannot run because another worker is busy.
+var ErrBusy = errors.New("busy")
+
+// ErrJobQueued is returned if a job of the same type is already waiting in the queue.
+var ErrJobQueued = errors.New("job already queued")
+
+// Job represents a background job that can be scheduled.
+type Job func(conf *config.Config) error
+
+// Jobs maps job names to the functions that add them to the queue.
+var Jobs = map[string]Job{
+	config.JobIndex:   IndexJob,
+	config.JobImport:  ImportJob,
+	config.JobFaces:   FacesJob,
+	config.JobMoments: MomentsJob,
+	config.JobPlaces:  PlacesJob,
+	config.JobCleanup: CleanupJob,
+	config.JobBackup:  BackupJob,
+	config.JobThumbs:  ThumbsJob,
+	config.JobTrash:   TrashJob,
+}
+
+// QueueJob adds a job with the specified options to the queue, unless a job of the same type is already queued.
+func QueueJob(jobType string, opt interface{}, createdBy string) (*entity.Job, error) {
+	if queued, err := entity.FindJobs(entity.JobQueued, jobType, 1, 0); err != nil {
+		return nil, err
+	} else if len(queued) > 0 {
+		return nil, ErrJobQueued
+	}
+
+	options, err := json.Marshal(opt)
+
+	if err != nil {
+		return nil, ErrInvalidJobOpts
+	}
+
+	return EnqueueJob(jobType, options, createdBy)
+}
+
+// queueJob adds a scheduled job to the queue.
+func queueJob(jobType string, opt interface{}) error {
+	_, err := QueueJob(jobType, opt, "")
+	return err
+}
+
+// IndexJob queues indexing all originals.
+func IndexJob(conf *config.Config) error {
+	return queueJob(entity.JobTypeIndex, form.IndexOptions{Path: entity.RootPath})
+}
+
+// ImportJob queues importing files from the import folder.
+func ImportJob(conf *config.Config) error {
+	if conf.ReadOnly() || !conf.Settings().Features.Import {
+		return nil
+	}
+
+	return queueJob(entity.JobTypeImport, form.ImportOptions{Move: conf.Settings().Import.Move})
+}
+
+// FacesJob queues face clustering and matching.
+func FacesJob(conf *config.Config) error {
+	return queueJob(entity.JobTypeFaces, form.FacesOptions{})
+}
+
+// MomentsJob queues updating moments based on the indexed pictures.
+func MomentsJob(conf *config.Config) error {
+	return queueJob(entity.JobTypeMoments, nil)
+}
+
+// PlacesJob queues updating the location details of indexed pictures.
+func PlacesJob(conf *config.Config) error {
+	return queueJob(entity.JobTypePlaces, nil)
+}
+
+// CleanupJob queues removing orphaned index entries, sidecar and thumbnail files.
+func CleanupJob(conf *config.Config) error {
+	return queueJob(entity.JobTypeCleanup, nil)
+}
+
+// BackupJob queues creating a backup archive and removing outdated archives.
+func BackupJob(conf *config.Config) error {
+	return queueJob(entity.JobTypeBackup, nil)
+}
+
+// ThumbsJob queues pre-rendering missing thumbnail images.
+func ThumbsJob(conf *config.Config) error {
+	return queueJob(entity.JobTypeThumbs, nil)
+}
+
+// TrashJob queues permanently removing files from the trash whose retention period has expired.
+func TrashJob(conf *config.Config) error {
+	if conf.ReadOnly() || conf.TrashRetention() == 0 {
+		return nil
+	}
+
+	return queueJob(entity.JobTypeTrash, nil)
+}
+
+// runMoments updates moments based on the indexed pictures.
+func runMoments(conf *config.Config) error {
+	return get.Moments().Start()
+}
+
+// runPlaces updates the location details of indexed pictures.
+func runPlaces(conf *config.Config) error {
+	_, err := get.Places().Start()
+
+	return err
+}
+
+// runCleanup removes orphaned index entries, sidecar and thumbnail files.
+func runCleanup(conf *config.Config) error {
+	_, _, _, err := get.CleanUp().Start(photoprism.CleanUpOptions{})
+
+	return err
+}
+
+// runBackup creates a backup archive and removes outdated archives.
+func runBackup(conf *config.Config) error {
+	if err := mutex.BackupWorker.Start(); err != nil {
+		return ErrBusy
+	}
+
+	defer mutex.BackupWorker.Stop()
+
+	if _, err := photoprism.BackupArchive(); err != nil {
+		return fmt.Errorf("backup failed: %s", err)
+	}
+
+	return nil
+}
+
+// runThumbs pre-renders missing thumbnail images.
+func runThumbs(conf *config.Config) error {
+	return get.Thumbs().Start(false, false)
+}
+
+// runTrash permanently removes files from the trash whose retention period has expired.
+func runTrash(conf *config.Config) error {
+	if conf.ReadOnly() || conf.TrashRetention() == 0 {
+		return nil
+	}
+
+	_, _, err := photoprism.EmptyTrash(true)
+
+	return err
+}
@@ -0,0 +1,579 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/leandro-lugaresi/hub"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/event"
+	"github.com/photoprism/photoprism/internal/form"
+	"github.com/photoprism/photoprism/internal/get"
+	"github.com/photoprism/photoprism/internal/i18n"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/internal/photoprism"
+	"github.com/photoprism/photoprism/internal/query"
+	"github.com/photoprism/photoprism/pkg/clean"
+	"github.com/photoprism/photoprism/pkg/fs"
+)
+
+// Errors returned by the job queue.
+var (
+	ErrJobNotFound    = errors.New("job not found")
+	ErrJobFinished    = errors.New("job has already finished")
+	ErrInvalidJobType = errors.New("invalid job type")
+	ErrInvalidJobOpts = errors.New("invalid job options")
+)
+
+// ResumableJobs specifies the types of jobs that are queued again if they were interrupted, e.g. by a restart.
+var ResumableJobs = []string{entity.JobTypeIndex, entity.JobTypeImport}
+
+// QueueWait specifies how long to wait before checking for queued jobs again, e.g. if another worker is busy.
+var QueueWait = 10 * time.Second
+
+// QueueProgressInterval specifies how often the progress of a running job is saved.
+var QueueProgressInterval = time.Second
+
+// QueuePollInterval specifies how often the state of a job is checked while waiting for it to finish.
+var QueuePollInterval = 500 * time.Millisecond
+
+// queue runs persisted background jobs one after another.
+var queue = struct {
+	conf     *config.Config
+	running  *entity.Job
+	canceled bool
+	stopping bool
+	wake     chan bool
+	done     chan bool
+	mutex    sync.Mutex
+}{}
+
+// StartQueue resumes interrupted jobs and runs queued jobs in the background until StopQueue is called.
+func StartQueue(conf *config.Config) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	if queue.done != nil {
+		log.Warnf("jobs: queue already running")
+		return
+	}
+
+	if resumed, failed, err := entity.ResetJobs(ResumableJobs); err != nil {
+		log.Errorf("jobs: %s", err)
+	} else if resumed > 0 || failed > 0 {
+		log.Infof("jobs: resumed %d and failed %d interrupted jobs", resumed, failed)
+	}
+
+	queue.conf = conf
+	queue.stopping = false
+	queue.wake = make(chan bool, 1)
+	queue.done = make(chan bool)
+
+	go runQueue(queue.wake, queue.done)
+}
+
+// StopQueue stops running queued jobs. A job that is currently running is interrupted and
+// resumed on the next start if its type is resumable.
+func StopQueue() {
+	queue.mutex.Lock()
+
+	if queue.done == nil {
+		queue.mutex.Unlock()
+		return
+	}
+
+	done := queue.done
+	queue.stopping = true
+
+	if queue.running != nil {
+		cancelActivity(queue.running.JobType)
+	}
+
+	close(queue.wake)
+	queue.mutex.Unlock()
+
+	log.Info("shutting down job queue")
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		log.Warnf("jobs: running job did not stop in time")
+	}
+
+	queue.mutex.Lock()
+	queue.done = nil
+	queue.mutex.Unlock()
+}
+
+// EnqueueJob validates the options, adds a new job to the queue and returns it.
+func EnqueueJob(jobType string, options json.RawMessage, createdBy string) (*entity.Job, error) {
+	opt, err := jobOptions(jobType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(options) > 0 && string(options) != "null" {
+		if err = json.Unmarshal(options, opt); err != nil {
+			return nil, ErrInvalidJobOpts
+		}
+	}
+
+	job, err := entity.NewJob(jobType, opt, createdBy)
+
+	if err != nil {
+		return nil, err
+	} else if err = job.Create(); err != nil {
+		return nil, err
+	}
+
+	log.Infof("jobs: queued %s job %s", job.JobType, job.String())
+
+	WakeQueue()
+
+	return job, nil
+}
+
+// WakeQueue checks for queued jobs without waiting.
+func WakeQueue() {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	if queue.wake == nil || queue.stopping {
+		return
+	}
+
+	select {
+	case queue.wake <- true:
+	default:
+	}
+}
+
+// CancelJob cancels a queued job, or requests a running job to stop, and returns it.
+func CancelJob(uid string) (*entity.Job, error) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	job := entity.FindJob(uid)
+
+	if job == nil {
+		return nil, ErrJobNotFound
+	} else if job.Finished() {
+		return job, ErrJobFinished
+	}
+
+	// Request running job to stop.
+	if queue.running != nil && queue.running.JobUID == job.JobUID {
+		queue.canceled = true
+		cancelActivity(job.JobType)
+		log.Infof("jobs: canceling %s job %s", job.JobType, job.String())
+		return job, nil
+	}
+
+	// Jobs that are not running in this instance can be canceled directly.
+	if err := job.Cancel(); err != nil {
+		return job, err
+	}
+
+	log.Infof("jobs: canceled %s job %s", job.JobType, job.String())
+
+	return job, nil
+}
+
+// CancelJobs cancels all queued and running jobs of the specified type and returns their number.
+func CancelJobs(jobType string) (n int, err error) {
+	queued, err := entity.FindJobs(entity.JobQueued, jobType, 0, 0)
+
+	if err != nil {
+		return 0, err
+	}
+
+	queue.mutex.Lock()
+
+	// Request running job to stop.
+	if queue.running != nil && queue.running.JobType == jobType {
+		queue.canceled = true
+		cancelActivity(jobType)
+		log.Infof("jobs: canceling %s job %s", jobType, queue.running.String())
+		n++
+	}
+
+	queue.mutex.Unlock()
+
+	for _, job := range queued {
+		if _, err = CancelJob(job.JobUID); err != nil && err != ErrJobFinished {
+			return n, err
+		}
+
+		n++
+	}
+
+	return n, nil
+}
+
+// WaitJob waits until the job has finished or the context is done, and returns its last known state.
+func WaitJob(ctx context.Context, uid string) (*entity.Job, error) {
+	ticker := time.NewTicker(QueuePollInterval)
+	defer ticker.Stop()
+
+	for {
+		job := entity.FindJob(uid)
+
+		if job == nil {
+			return nil, ErrJobNotFound
+		} else if job.Finished() {
+			return job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// jobOptions returns a pointer to the options of the job type.
+func jobOptions(jobType string) (interface{}, error) {
+	switch jobType {
+	case entity.JobTypeIndex:
+		return &form.IndexOptions{Path: "/"}, nil
+	case entity.JobTypeImport:
+		return &form.ImportOptions{}, nil
+	case entity.JobTypeFaces:
+		return &form.FacesOptions{}, nil
+	case entity.JobTypeConvert:
+		return &form.ConvertOptions{}, nil
+	case entity.JobTypeMoments, entity.JobTypePlaces, entity.JobTypeCleanup,
+		entity.JobTypeThumbs, entity.JobTypeTrash, entity.JobTypeBackup:
+		// No options.
+		return &struct{}{}, nil
+	default:
+		return nil, ErrInvalidJobType
+	}
+}
+
+// cancelActivity requests the activity running jobs of this type to stop.
+func cancelActivity(jobType string) {
+	switch jobType {
+	case entity.JobTypeFaces:
+		mutex.FacesWorker.Cancel()
+	case entity.JobTypeBackup:
+		mutex.BackupWorker.Cancel()
+	default:
+		mutex.MainWorker.Cancel()
+	}
+}
+
+// activityBusy checks if the activity required by jobs of this type is busy.
+// Queued jobs are held while a backup is restored.
+func activityBusy(jobType string) bool {
+	if mutex.RestoreWorker.Running() {
+		return true
+	}
+
+	switch jobType {
+	case entity.JobTypeFaces:
+		return mutex.FacesWorker.Running()
+	case entity.JobTypeBackup:
+		return mutex.BackupWorker.Running()
+	default:
+		return mutex.MainWorker.Running()
+	}
+}
+
+// runQueue runs queued jobs one after another until the wake channel is closed.
+func runQueue(wake chan bool, done chan bool) {
+	defer close(done)
+
+	for {
+		wait := time.Minute
+
+		if job := nextJob(); job != nil {
+			runQueuedJob(job)
+			continue
+		} else if entity.NextJob() != nil {
+			// Another worker is busy, try again later.
+			wait = QueueWait
+		}
+
+		select {
+		case _, ok := <-wake:
+			if !ok {
+				return
+			}
+		case <-time.After(wait):
+			queue.mutex.Lock()
+			stopping := queue.stopping
+			queue.mutex.Unlock()
+
+			if stopping {
+				return
+			}
+		}
+	}
+}
+
+// nextJob starts the next queued job if the required activity is not busy.
+func nextJob() *entity.Job {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	if queue.stopping {
+		return nil
+	}
+
+	job := entity.NextJob()
+
+	if job == nil || activityBusy(job.JobType) {
+		return nil
+	}
+
+	if err := job.Start(); err != nil {
+		log.Errorf("jobs: %s", err)
+		return nil
+	}
+
+	queue.running = job
+	queue.canceled = false
+
+	return job
+}
+
+// runQueuedJob runs a job that has been started and updates its state when it is done.
+func runQueuedJob(job *entity.Job) {
+	log.Infof("jobs: running %s job %s", job.JobType, job.String())
+
+	// Track progress based on published events.
+	progress := event.Subscribe("index.folder", "index.indexing", "index.converting", "index.updating", "import.file")
+	tracked := make(chan bool)
+
+	go trackProgress(job, progress, tracked)
+
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%s (panic)\nstack: %s", r, debug.Stack())
+			}
+		}()
+
+		return runJobType(queue.conf, job)
+	}()
+
+	event.Unsubscribe(progress)
+	<-tracked
+
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	queue.running = nil
+
+	switch {
+	case queue.canceled:
+		err = job.Cancel()
+		log.Infof("jobs: %s job %s has been canceled", job.JobType, job.String())
+	case queue.stopping && job.Running():
+		// Keep the running state so that the job can be resumed.
+		err = job.Save()
+	default:
+		if err != nil {
+			log.Errorf("jobs: %s job %s failed (%s)", job.JobType, job.String(), err)
+		} else {
+			log.Infof("jobs: %s job %s completed", job.JobType, job.String())
+		}
+
+		err = job.Finish(err)
+	}
+
+	if err != nil {
+		log.Errorf("jobs: %s", err)
+	}
+}
+
+// trackProgress counts the events published while a job is running and saves the progress at regular intervals.
+func trackProgress(job *entity.Job, s hub.Subscription, done chan bool) {
+	defer close(done)
+
+	count, step := 0, ""
+	saved := time.Now()
+
+	for msg := range s.Receiver {
+		switch msg.Topic() {
+		case "index.folder":
+			step = fmt.Sprintf("%v", msg.Fields["filePath"])
+		case "index.updating":
+			step = fmt.Sprintf("%v", msg.Fields["step"])
+		default:
+			count++
+			step = fmt.Sprintf("%v", msg.Fields["baseName"])
+		}
+
+		if time.Since(saved) >= QueueProgressInterval {
+			if err := job.SetProgress(count, step); err != nil {
+				log.Debugf("jobs: %s (update progress)", err)
+			}
+
+			saved = time.Now()
+		}
+	}
+
+	if err := job.SetProgress(count, ""); err != nil {
+		log.Debugf("jobs: %s (update progress)", err)
+	}
+}
+
+// runJobType runs the job based on its type and options.
+func runJobType(conf *config.Config, job *entity.Job) error {
+	opt, err := jobOptions(job.JobType)
+
+	if err != nil {
+		return err
+	} else if err = job.Options(opt); err != nil {
+		return ErrInvalidJobOpts
+	}
+
+	switch job.JobType {
+	case entity.JobTypeMoments:
+		return runMoments(conf)
+	case entity.JobTypePlaces:
+		return runPlaces(conf)
+	case entity.JobTypeCleanup:
+		return runCleanup(conf)
+	case entity.JobTypeThumbs:
+		return runThumbs(conf)
+	case entity.JobTypeTrash:
+		return runTrash(conf)
+	case entity.JobTypeBackup:
+		return runBackup(conf)
+	}
+
+	switch o := opt.(type) {
+	case *form.IndexOptions:
+		return runIndex(conf, *o)
+	case *form.ImportOptions:
+		return runImport(conf, *o, job.CreatedBy)
+	case *form.FacesOptions:
+		return get.Faces().Start(photoprism.FacesOptions{Force: o.Force})
+	case *form.ConvertOptions:
+		if !conf.SidecarWritable() {
+			return fmt.Errorf("sidecar files are not writable")
+		}
+
+		return get.Convert().Start(filepath.Join(conf.OriginalsPath(), clean.UserPath(o.Path)), o.Force)
+	default:
+		return ErrInvalidJobType
+	}
+}
+
+// runIndex indexes originals in the specified path and updates moments afterwards.
+func runIndex(conf *config.Config, f form.IndexOptions) error {
+	start := time.Now()
+	settings := conf.Settings()
+
+	path := clean.UserPath(f.Path)
+	convert := settings.Index.Convert && conf.SidecarWritable()
+	opt := photoprism.NewIndexOptions(path, f.Rescan, convert, true, false, settings.Index.SkipArchived)
+
+	indexed := get.Index().Start(opt)
+
+	if mutex.MainWorker.Canceled() {
+		return nil
+	}
+
+	prgOpt := photoprism.PurgeOptions{
+		Path:   path,
+		Ignore: indexed,
+	}
+
+	if files, photos, err := get.Purge().Start(prgOpt); err != nil {
+		return err
+	} else if len(files) > 0 || len(photos) > 0 {
+		event.InfoMsg(i18n.MsgRemovedFilesAndPhotos, len(files), len(photos))
+	}
+
+	event.Publish("index.updating", event.Data{"step": "moments"})
+
+	if err := get.Moments().Start(); err != nil {
+		log.Warnf("moments: %s", err)
+	}
+
+	get.FolderCache().Flush()
+
+	event.Publish("index.completed", event.Data{"path": conf.OriginalsPath(), "seconds": int(time.Since(start).Seconds())})
+	event.Publish("config.updated", event.Data{"config": conf.ClientUser(false)})
+
+	return nil
+}
+
+// runImport imports files from the specified import folder and updates moments afterwards.
+func runImport(conf *config.Config, f form.ImportOptions, userUid string) error {
+	if conf.ReadOnly() || !conf.Settings().Features.Import {
+		return fmt.Errorf("import is disabled")
+	}
+
+	start := time.Now()
+	path := filepath.Join(conf.ImportPath(), clean.UserPath(f.Path))
+	dest := conf.ImportDest()
+
+	// Use the upload folder of the user, if any.
+	if u := entity.FindUserByUID(userUid); u != nil && u.UploadPath != "" {
+		dest = u.UploadPath
+	}
+
+	var opt photoprism.ImportOptions
+
+	if f.Move {
+		event.InfoMsg(i18n.MsgMovingFilesFrom, clean.Log(filepath.Base(path)))
+		opt = photoprism.ImportOptionsMove(path, dest)
+	} else {
+		event.InfoMsg(i18n.MsgCopyingFilesFrom, clean.Log(filepath.Base(path)))
+		opt = photoprism.ImportOptionsCopy(path, dest)
+	}
+
+	opt.Albums = f.Albums
+	opt.UserUID = userUid
+
+	imported := get.Import().Start(opt)
+
+	// Delete empty import sub-folder.
+	if path != conf.ImportPath() && fs.DirIsEmpty(path) {
+		if err := os.Remove(path); err != nil {
+			log.Errorf("import: failed deleting empty folder %s: %s", clean.Log(path), err)
+		} else {
+			log.Infof("import: deleted empty folder %s", clean.Log(path))
+		}
+	}
+
+	get.FolderCache().Flush()
+
+	if len(imported) == 0 {
+		log.Infof("import: no new files found to import in %s", clean.Log(path))
+		return nil
+	}
+
+	if err := get.Moments().Start(); err != nil {
+		log.Warnf("moments: %s", err)
+	}
+
+	// Update album, label, and subject cover thumbs.
+	if err := query.UpdateCovers(); err != nil {
+		log.Warnf("index: %s (update covers)", err)
+	}
+
+	elapsed := int(time.Since(start).Seconds())
+
+	event.Publish("import.completed", event.Data{"path": path, "seconds": elapsed})
+	event.Publish("index.completed", event.Data{"path": path, "seconds": elapsed})
+	event.Publish("config.updated", event.Data{"config": conf.ClientUser(false)})
+
+	return nil
+}
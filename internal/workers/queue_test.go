@@ -0,0 +1,129 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/entity"
+	"github.com/photoprism/photoprism/internal/form"
+)
+
+func TestEnqueueJob(t *testing.T) {
+	t.Run("Index", func(t *testing.T) {
+		job, err := EnqueueJob(entity.JobTypeIndex, json.RawMessage(`{"path": "2790/07", "rescan": true}`), "uqxetse3cy5eo9z2")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, job.Queued())
+		assert.Equal(t, "uqxetse3cy5eo9z2", job.CreatedBy)
+
+		var opt form.IndexOptions
+
+		assert.NoError(t, job.Options(&opt))
+		assert.Equal(t, "2790/07", opt.Path)
+		assert.True(t, opt.Rescan)
+
+		canceled, err := CancelJob(job.JobUID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.JobCanceled, canceled.JobState)
+	})
+	t.Run("DefaultOptions", func(t *testing.T) {
+		job, err := EnqueueJob(entity.JobTypeFaces, nil, "")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, `{"force":false}`, job.JobOptions)
+
+		_, err = CancelJob(job.JobUID)
+		assert.NoError(t, err)
+	})
+	t.Run("InvalidType", func(t *testing.T) {
+		job, err := EnqueueJob("foo", nil, "")
+
+		assert.Nil(t, job)
+		assert.Equal(t, ErrInvalidJobType, err)
+	})
+	t.Run("InvalidOptions", func(t *testing.T) {
+		job, err := EnqueueJob(entity.JobTypeImport, json.RawMessage(`{"move": "yes"}`), "")
+
+		assert.Nil(t, job)
+		assert.Equal(t, ErrInvalidJobOpts, err)
+	})
+}
+
+func TestCancelJob(t *testing.T) {
+	t.Run("NotFound", func(t *testing.T) {
+		job, err := CancelJob("qrhn1bzi0fw2x7zz")
+
+		assert.Nil(t, job)
+		assert.Equal(t, ErrJobNotFound, err)
+	})
+	t.Run("Finished", func(t *testing.T) {
+		job, err := CancelJob(entity.JobFixtures.Get("index-completed").JobUID)
+
+		assert.Equal(t, ErrJobFinished, err)
+		assert.Equal(t, entity.JobCompleted, job.JobState)
+	})
+}
+
+func TestQueueJob(t *testing.T) {
+	t.Run("Moments", func(t *testing.T) {
+		job, err := QueueJob(entity.JobTypeMoments, nil, "")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.True(t, job.Queued())
+		assert.Equal(t, "{}", job.JobOptions)
+
+		// Jobs of the same type are only queued once.
+		_, err = QueueJob(entity.JobTypeMoments, nil, "")
+		assert.Equal(t, ErrJobQueued, err)
+
+		n, err := CancelJobs(entity.JobTypeMoments)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+	})
+}
+
+func TestWaitJob(t *testing.T) {
+	t.Run("Finished", func(t *testing.T) {
+		job, err := WaitJob(context.Background(), entity.JobFixtures.Get("index-completed").JobUID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.JobCompleted, job.JobState)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		job, err := WaitJob(context.Background(), "qrhn1bzi0fw2x7zz")
+
+		assert.Nil(t, job)
+		assert.Equal(t, ErrJobNotFound, err)
+	})
+	t.Run("Canceled", func(t *testing.T) {
+		job, err := EnqueueJob(entity.JobTypeFaces, nil, "")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer CancelJob(job.JobUID)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		job, err = WaitJob(ctx, job.JobUID)
+
+		assert.Equal(t, context.Canceled, err)
+		assert.True(t, job.Queued())
+	})
+}
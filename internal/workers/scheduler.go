@@ -0,0 +1,174 @@
+package workers
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/photoprism/photoprism/internal/config"
+	"github.com/photoprism/photoprism/internal/mutex"
+	"github.com/photoprism/photoprism/pkg/clean"
+)
+
+// JobStatus represents the schedule and last run of a job.
+type JobStatus struct {
+	Name     string     `json:"name"`
+	Schedule string     `json:"schedule"`
+	Running  bool       `json:"running"`
+	LastRun  *time.Time `json:"lastRun,omitempty"`
+	Duration float64    `json:"duration,omitempty"`
+	Error    string     `json:"error,omitempty"`
+	NextRun  *time.Time `json:"nextRun,omitempty"`
+	entryId  cron.EntryID
+}
+
+// scheduler runs jobs based on their cron schedule.
+var scheduler = struct {
+	cron  *cron.Cron
+	jobs  map[string]*JobStatus
+	mutex sync.RWMutex
+}{
+	jobs: make(map[string]*JobStatus),
+}
+
+// StartScheduler runs jobs based on their cron schedule until StopScheduler is called.
+func StartScheduler(conf *config.Config) {
+	schedule := conf.Schedule()
+
+	if len(schedule) == 0 {
+		log.Debugf("scheduler: no jobs scheduled")
+		return
+	}
+
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	if scheduler.cron != nil {
+		log.Warnf("scheduler: already running")
+		return
+	}
+
+	c := cron.New()
+
+	for name, spec := range schedule {
+		job, ok := Jobs[name]
+
+		if !ok {
+			continue
+		}
+
+		status := &JobStatus{Name: name, Schedule: spec}
+
+		id, err := c.AddFunc(spec, func() { runJob(conf, status, job) })
+
+		if err != nil {
+			log.Warnf("scheduler: %s (%s)", err, name)
+			continue
+		}
+
+		status.entryId = id
+		scheduler.jobs[name] = status
+
+		log.Infof("scheduler: %s job scheduled for %s", name, clean.LogQuote(spec))
+	}
+
+	scheduler.cron = c
+
+	c.Start()
+}
+
+// StopScheduler stops running jobs based on their schedule and waits for running jobs to complete.
+func StopScheduler() {
+	scheduler.mutex.Lock()
+	c := scheduler.cron
+	scheduler.cron = nil
+	scheduler.mutex.Unlock()
+
+	if c == nil {
+		return
+	}
+
+	log.Info("shutting down scheduler")
+
+	<-c.Stop().Done()
+
+	scheduler.mutex.Lock()
+	scheduler.jobs = make(map[string]*JobStatus)
+	scheduler.mutex.Unlock()
+}
+
+// ScheduledJobs returns the status of scheduled jobs sorted by name.
+func ScheduledJobs() []JobStatus {
+	scheduler.mutex.RLock()
+	defer scheduler.mutex.RUnlock()
+
+	result := make([]JobStatus, 0, len(scheduler.jobs))
+
+	for _, status := range scheduler.jobs {
+		s := *status
+
+		if scheduler.cron != nil {
+			if next := scheduler.cron.Entry(s.entryId).Next; !next.IsZero() {
+				s.NextRun = &next
+			}
+		}
+
+		result = append(result, s)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// runJob runs a scheduled job and updates its status.
+func runJob(conf *config.Config, status *JobStatus, job Job) {
+	scheduler.mutex.Lock()
+
+	if mutex.RestoreWorker.Running() {
+		scheduler.mutex.Unlock()
+		log.Infof("scheduler: skipped %s job, backup is being restored", status.Name)
+		return
+	} else if status.Running {
+		scheduler.mutex.Unlock()
+		log.Infof("scheduler: skipped %s job, still running", status.Name)
+		return
+	}
+
+	start := time.Now()
+	status.Running = true
+	scheduler.mutex.Unlock()
+
+	log.Debugf("scheduler: running %s job", status.Name)
+
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%s (panic)\nstack: %s", r, debug.Stack())
+			}
+		}()
+
+		return job(conf)
+	}()
+
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	status.Running = false
+	status.LastRun = &start
+	status.Duration = time.Since(start).Seconds()
+
+	if err != nil {
+		status.Error = err.Error()
+		log.Warnf("scheduler: %s job failed (%s)", status.Name, err)
+	} else {
+		status.Error = ""
+		log.Infof("scheduler: %s job completed in %s", status.Name, time.Since(start))
+	}
+}
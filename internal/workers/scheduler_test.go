@@ -0,0 +1,110 @@
+package workers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/photoprism/photoprism/internal/config"
+)
+
+func TestStartScheduler(t *testing.T) {
+	conf := config.TestConfig()
+
+	t.Run("NoJobs", func(t *testing.T) {
+		StartScheduler(conf)
+		assert.Empty(t, ScheduledJobs())
+		StopScheduler()
+	})
+	t.Run("Run", func(t *testing.T) {
+		runs := make(chan bool, 10)
+
+		moments, places := Jobs[config.JobMoments], Jobs[config.JobPlaces]
+
+		Jobs[config.JobMoments] = func(conf *config.Config) error {
+			runs <- true
+			return nil
+		}
+		Jobs[config.JobPlaces] = func(conf *config.Config) error {
+			runs <- false
+			return errors.New("failed")
+		}
+
+		conf.Options().MomentsSchedule = "@every 1s"
+		conf.Options().PlacesSchedule = "@every 1s"
+
+		defer func() {
+			Jobs[config.JobMoments], Jobs[config.JobPlaces] = moments, places
+			conf.Options().MomentsSchedule = ""
+			conf.Options().PlacesSchedule = ""
+		}()
+
+		StartScheduler(conf)
+
+		jobs := ScheduledJobs()
+
+		if assert.Len(t, jobs, 2) {
+			assert.Equal(t, config.JobMoments, jobs[0].Name)
+			assert.Equal(t, "@every 1s", jobs[0].Schedule)
+			assert.NotNil(t, jobs[0].NextRun)
+			assert.Nil(t, jobs[0].LastRun)
+			assert.Equal(t, config.JobPlaces, jobs[1].Name)
+		}
+
+		for i := 0; i < 2; i++ {
+			select {
+			case <-runs:
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout")
+			}
+		}
+
+		StopScheduler()
+
+		assert.Empty(t, ScheduledJobs())
+	})
+}
+
+func TestRunJob(t *testing.T) {
+	conf := config.TestConfig()
+
+	t.Run("Error", func(t *testing.T) {
+		status := &JobStatus{Name: "test"}
+
+		runJob(conf, status, func(conf *config.Config) error {
+			return ErrBusy
+		})
+
+		assert.False(t, status.Running)
+		assert.NotNil(t, status.LastRun)
+		assert.Equal(t, "busy", status.Error)
+
+		runJob(conf, status, func(conf *config.Config) error {
+			return nil
+		})
+
+		assert.Equal(t, "", status.Error)
+	})
+	t.Run("Panic", func(t *testing.T) {
+		status := &JobStatus{Name: "test"}
+
+		runJob(conf, status, func(conf *config.Config) error {
+			panic("oops")
+		})
+
+		assert.False(t, status.Running)
+		assert.Contains(t, status.Error, "oops (panic)")
+	})
+	t.Run("StillRunning", func(t *testing.T) {
+		status := &JobStatus{Name: "test", Running: true}
+
+		runJob(conf, status, func(conf *config.Config) error {
+			t.Fatal("must not run")
+			return nil
+		})
+
+		assert.Nil(t, status.LastRun)
+	})
+}
@@ -12,7 +12,6 @@ import (
 	"github.com/photoprism/photoprism/internal/photoprism"
 	"github.com/photoprism/photoprism/internal/query"
 	"github.com/photoprism/photoprism/internal/remote"
-	"github.com/photoprism/photoprism/internal/remote/webdav"
 	"github.com/photoprism/photoprism/internal/search"
 	"github.com/photoprism/photoprism/internal/thumb"
 	"github.com/photoprism/photoprism/pkg/fs"
@@ -63,7 +62,7 @@ func (w *Share) Start() (err error) {
 			return nil
 		}
 
-		if a.AccType != remote.ServiceWebDAV {
+		if !remote.Supported(a.AccType) {
 			continue
 		}
 
@@ -89,7 +88,15 @@ func (w *Share) Start() (err error) {
 			}
 		}
 
-		client := webdav.New(a.AccURL, a.AccUser, a.AccPass, webdav.Timeout(a.AccTimeout))
+		client, err := a.Storage()
+
+		if err != nil {
+			w.logError(err)
+			continue
+		}
+
+		defer remote.Close(client)
+
 		existingDirs := make(map[string]string)
 
 		for _, file := range files {
@@ -100,7 +107,7 @@ func (w *Share) Start() (err error) {
 			dir := filepath.Dir(file.RemoteName)
 
 			if _, ok := existingDirs[dir]; !ok {
-				if err := client.CreateDir(dir); err != nil {
+				if err := client.Mkdir(dir); err != nil {
 					log.Errorf("share: failed creating folder %s", dir)
 					continue
 				}
@@ -147,7 +154,7 @@ func (w *Share) Start() (err error) {
 			return nil
 		}
 
-		if a.AccType != remote.ServiceWebDAV {
+		if !remote.Supported(a.AccType) {
 			continue
 		}
 
@@ -163,7 +170,14 @@ func (w *Share) Start() (err error) {
 			continue
 		}
 
-		client := webdav.New(a.AccURL, a.AccUser, a.AccPass, webdav.Timeout(a.AccTimeout))
+		client, err := a.Storage()
+
+		if err != nil {
+			w.logError(err)
+			continue
+		}
+
+		defer remote.Close(client)
 
 		for _, file := range files {
 			if mutex.ShareWorker.Canceled() {
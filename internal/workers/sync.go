@@ -62,7 +62,7 @@ func (w *Sync) Start() (err error) {
 	accounts, err := search.Accounts(f)
 
 	for _, a := range accounts {
-		if a.AccType != remote.ServiceWebDAV {
+		if !remote.Supported(a.AccType) {
 			continue
 		}
 
@@ -0,0 +1,16 @@
+package workers
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// conflictName returns the file name under which the other version of a conflicting file is kept,
+// e.g. "IMG_0001_conflict_20221018-123000.jpg".
+func conflictName(fileName string, date time.Time) string {
+	ext := filepath.Ext(fileName)
+
+	return fmt.Sprintf("%s_conflict_%s%s", strings.TrimSuffix(fileName, ext), date.UTC().Format("20060102-150405"), ext)
+}
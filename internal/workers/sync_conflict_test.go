@@ -0,0 +1,15 @@
+package workers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConflictName(t *testing.T) {
+	date := time.Date(2022, 10, 18, 12, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, "/Photos/IMG_0001_conflict_20221018-123000.jpg", conflictName("/Photos/IMG_0001.jpg", date))
+	assert.Equal(t, "/Photos/README_conflict_20221018-123000", conflictName("/Photos/README", date))
+}
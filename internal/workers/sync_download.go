@@ -3,6 +3,7 @@ package workers
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/event"
@@ -10,7 +11,7 @@ import (
 	"github.com/photoprism/photoprism/internal/mutex"
 	"github.com/photoprism/photoprism/internal/photoprism"
 	"github.com/photoprism/photoprism/internal/query"
-	"github.com/photoprism/photoprism/internal/remote/webdav"
+	"github.com/photoprism/photoprism/internal/remote"
 	"github.com/photoprism/photoprism/pkg/fs"
 )
 
@@ -76,7 +77,13 @@ func (w *Sync) download(a entity.Service) (complete bool, err error) {
 
 	log.Infof("sync: downloading from %s", a.AccName)
 
-	client := webdav.New(a.AccURL, a.AccUser, a.AccPass, webdav.Timeout(a.AccTimeout))
+	client, err := a.Storage()
+
+	if err != nil {
+		return false, err
+	}
+
+	defer remote.Close(client)
 
 	var baseDir string
 
@@ -89,6 +96,9 @@ func (w *Sync) download(a entity.Service) (complete bool, err error) {
 	done := make(map[string]bool)
 
 	for _, files := range relatedFiles {
+		// Local file names of successful downloads.
+		downloaded := make(map[string]string)
+
 		for i, file := range files {
 			if mutex.SyncWorker.Canceled() {
 				return false, nil
@@ -98,17 +108,50 @@ func (w *Sync) download(a entity.Service) (complete bool, err error) {
 			if a.RetryLimit > 0 && file.Errors > a.RetryLimit {
 				log.Debugf("sync: downloading %s failed more than %d times", file.RemoteName, a.RetryLimit)
 				continue
+			} else if file.Errors > 0 && time.Now().Before(file.RetryAfter()) {
+				log.Debugf("sync: download of %s will be retried after %s", file.RemoteName, file.RetryAfter().Format(time.RFC3339))
+				continue
 			}
 
 			localName := baseDir + file.RemoteName
+			downloadName := localName
+			force := false
 
-			if _, err := os.Stat(localName); err == nil {
+			if info, err := os.Stat(localName); err != nil {
+				file.Conflict = ""
+			} else if !file.Synced() {
 				log.Warnf("sync: download skipped, %s already exists", localName)
 				file.Status = entity.FileSyncExists
 				file.Error = ""
 				file.Errors = 0
+				downloadName = ""
+			} else if !file.LocalChanged(info) {
+				// Only the remote file has changed, so the local file can be replaced.
+				file.Conflict = ""
+				force = true
 			} else {
-				if err := client.Download(file.RemoteName, localName, false); err != nil {
+				// Both files have changed, apply conflict policy.
+				file.Conflict = a.ConflictPolicy()
+
+				switch file.Conflict {
+				case entity.SyncConflictLocal:
+					log.Warnf("sync: conflict, keeping local version of %s", localName)
+					file.Status = entity.FileSyncExists
+					file.SetLocal(info)
+					file.Error = ""
+					file.Errors = 0
+					downloadName = ""
+				case entity.SyncConflictRemote:
+					log.Warnf("sync: conflict, replacing %s with remote version", localName)
+					force = true
+				default:
+					downloadName = conflictName(localName, file.RemoteDate)
+					log.Warnf("sync: conflict, keeping both versions of %s", localName)
+				}
+			}
+
+			if downloadName != "" {
+				if err := client.Download(file.RemoteName, downloadName, force); err != nil {
 					file.Errors++
 					file.Error = err.Error()
 				} else {
@@ -116,6 +159,12 @@ func (w *Sync) download(a entity.Service) (complete bool, err error) {
 					file.Status = entity.FileSyncDownloaded
 					file.Error = ""
 					file.Errors = 0
+
+					if info, err := os.Stat(localName); err == nil {
+						file.SetLocal(info)
+					}
+
+					downloaded[file.RemoteName] = downloadName
 				}
 
 				if mutex.SyncWorker.Canceled() {
@@ -125,17 +174,20 @@ func (w *Sync) download(a entity.Service) (complete bool, err error) {
 
 			if err := entity.Db().Save(&file).Error; err != nil {
 				w.logError(err)
+				delete(downloaded, file.RemoteName)
 			} else {
 				files[i] = file
 			}
 		}
 
 		for _, file := range files {
-			if file.Status != entity.FileSyncDownloaded {
+			fileName, ok := downloaded[file.RemoteName]
+
+			if !ok {
 				continue
 			}
 
-			mf, err := photoprism.NewMediaFile(baseDir + file.RemoteName)
+			mf, err := photoprism.NewMediaFile(fileName)
 
 			if err != nil || !mf.IsMedia() || mf.Empty() {
 				continue
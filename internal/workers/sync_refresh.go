@@ -4,19 +4,24 @@ import (
 	"github.com/photoprism/photoprism/internal/entity"
 	"github.com/photoprism/photoprism/internal/mutex"
 	"github.com/photoprism/photoprism/internal/remote"
-	"github.com/photoprism/photoprism/internal/remote/webdav"
 	"github.com/photoprism/photoprism/pkg/media"
 )
 
 // Updates the local list of remote files so that they can be downloaded in batches
 func (w *Sync) refresh(a entity.Service) (complete bool, err error) {
-	if a.AccType != remote.ServiceWebDAV {
+	if !remote.Supported(a.AccType) {
 		return false, nil
 	}
 
-	client := webdav.New(a.AccURL, a.AccUser, a.AccPass, webdav.Timeout(a.AccTimeout))
+	client, err := a.Storage()
 
-	subDirs, err := client.Directories(a.SyncPath, true, webdav.MaxRequestDuration)
+	if err != nil {
+		return false, err
+	}
+
+	defer remote.Close(client)
+
+	subDirs, err := remote.Directories(client, a.SyncPath, true, remote.MaxRequestDuration)
 
 	if err != nil {
 		log.Error(err)
@@ -30,7 +35,7 @@ func (w *Sync) refresh(a entity.Service) (complete bool, err error) {
 			return false, nil
 		}
 
-		files, err := client.Files(dir)
+		files, err := remote.Files(client, dir)
 
 		if err != nil {
 			log.Error(err)
@@ -45,8 +50,7 @@ func (w *Sync) refresh(a entity.Service) (complete bool, err error) {
 			f := entity.NewFileSync(a.ID, file.Abs)
 
 			f.Status = entity.FileSyncIgnore
-			f.RemoteDate = file.Date
-			f.RemoteSize = file.Size
+			f.SetRemote(file)
 
 			// Select supported types for download
 			content := media.FromName(file.Name)
@@ -70,11 +74,13 @@ func (w *Sync) refresh(a entity.Service) (complete bool, err error) {
 				w.logError(f.Update("Status", entity.FileSyncNew))
 			}
 
-			if f.Status == entity.FileSyncDownloaded && !f.RemoteDate.Equal(file.Date) {
+			// Download again if the remote file has changed since it was synced.
+			if (f.Status == entity.FileSyncDownloaded || f.Synced()) && f.RemoteChanged(file) {
 				w.logError(f.Updates(map[string]interface{}{
 					"Status":     entity.FileSyncNew,
 					"RemoteDate": file.Date,
 					"RemoteSize": file.Size,
+					"RemoteETag": file.ETag,
 				}))
 			}
 		}
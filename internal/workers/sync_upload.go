@@ -1,6 +1,8 @@
 package workers
 
 import (
+	"errors"
+	"os"
 	"path"
 	"path/filepath"
 	"time"
@@ -10,7 +12,7 @@ import (
 	"github.com/photoprism/photoprism/internal/mutex"
 	"github.com/photoprism/photoprism/internal/photoprism"
 	"github.com/photoprism/photoprism/internal/query"
-	"github.com/photoprism/photoprism/internal/remote/webdav"
+	"github.com/photoprism/photoprism/internal/remote"
 	"github.com/photoprism/photoprism/pkg/clean"
 )
 
@@ -31,7 +33,14 @@ func (w *Sync) upload(a entity.Service) (complete bool, err error) {
 		return true, nil
 	}
 
-	client := webdav.New(a.AccURL, a.AccUser, a.AccPass, webdav.Timeout(a.AccTimeout))
+	client, err := a.Storage()
+
+	if err != nil {
+		return false, err
+	}
+
+	defer remote.Close(client)
+
 	existingDirs := make(map[string]string)
 
 	for _, file := range files {
@@ -43,15 +52,86 @@ func (w *Sync) upload(a entity.Service) (complete bool, err error) {
 		remoteName := path.Join(a.SyncPath, file.FileName)
 		remoteDir := filepath.Dir(remoteName)
 
+		// Previous upload failed?
+		pending := entity.FindPendingUpload(a.ID, file.ID)
+
+		if pending != nil {
+			if a.RetryLimit > 0 && pending.Errors > a.RetryLimit {
+				log.Debugf("sync: uploading %s failed more than %d times", clean.Log(file.FileName), a.RetryLimit)
+				continue
+			} else if time.Now().Before(pending.RetryAfter()) {
+				log.Debugf("sync: upload of %s will be retried after %s", clean.Log(file.FileName), pending.RetryAfter().Format(time.RFC3339))
+				continue
+			}
+		}
+
 		if _, ok := existingDirs[remoteDir]; !ok {
-			if err := client.CreateDir(remoteDir); err != nil {
+			if err := client.Mkdir(remoteDir); err != nil {
 				log.Errorf("sync: failed creating remote folder %s", remoteDir)
 				continue // try again next time
 			}
 		}
 
+		conflict := ""
+		replace := false
+
+		// Check if a file with the same name already exists.
+		if info, err := client.Stat(remoteName); err == nil {
+			synced := entity.FindFileSync(a.ID, remoteName)
+			localInfo, localErr := os.Stat(fileName)
+
+			// Compare both versions with the last synced state, if any.
+			if synced != nil && synced.Synced() && localErr == nil {
+				localChanged := synced.LocalChanged(localInfo)
+				remoteChanged := synced.RemoteChanged(info)
+
+				if !localChanged {
+					// Same version, e.g. because it was downloaded before, or only the remote file has changed
+					// so that it will be downloaded again. Link it to the indexed file so it is not selected again.
+					log.Debugf("sync: %s is already synced with %s", clean.Log(file.FileName), a.AccName)
+					w.logError(synced.Update("FileID", file.ID))
+					continue
+				} else if !remoteChanged {
+					// Only the local file has changed, so the remote file can be replaced.
+					log.Infof("sync: replacing %s with local version (%s)", clean.Log(remoteName), a.AccName)
+					replace = true
+				}
+			}
+
+			if !replace {
+				conflict = a.ConflictPolicy()
+			}
+
+			switch {
+			case replace:
+				// Not a conflict, upload the changed file.
+			case conflict == entity.SyncConflictLocal:
+				log.Warnf("sync: conflict, replacing %s with local version (%s)", clean.Log(remoteName), a.AccName)
+			case conflict == entity.SyncConflictRemote:
+				log.Warnf("sync: conflict, keeping remote version of %s (%s)", clean.Log(remoteName), a.AccName)
+
+				fileSync := entity.NewFileSync(a.ID, remoteName)
+				fileSync.Status = entity.FileSyncExists
+				fileSync.Conflict = conflict
+				fileSync.FileID = file.ID
+				fileSync.SetRemote(info)
+
+				w.logError(entity.Db().Save(&fileSync).Error)
+
+				continue
+			default:
+				remoteName = conflictName(remoteName, time.Unix(file.ModTime, 0))
+				log.Warnf("sync: conflict, keeping both versions of %s (%s)", clean.Log(file.FileName), a.AccName)
+			}
+		} else if !errors.Is(err, remote.ErrNotFound) {
+			w.logError(err)
+			w.uploadFailed(a, file, remoteName, pending, err)
+			continue // try again next time
+		}
+
 		if err := client.Upload(fileName, remoteName); err != nil {
 			w.logError(err)
+			w.uploadFailed(a, file, remoteName, pending, err)
 			continue // try again next time
 		}
 
@@ -59,18 +139,61 @@ func (w *Sync) upload(a entity.Service) (complete bool, err error) {
 
 		fileSync := entity.NewFileSync(a.ID, remoteName)
 		fileSync.Status = entity.FileSyncUploaded
+		fileSync.Conflict = conflict
 		fileSync.RemoteDate = time.Now()
 		fileSync.RemoteSize = file.FileSize
 		fileSync.FileID = file.ID
 		fileSync.Error = ""
 		fileSync.Errors = 0
 
+		// Remember the uploaded version to detect changes.
+		if info, err := client.Stat(remoteName); err == nil {
+			fileSync.SetRemote(info)
+		}
+
+		if info, err := os.Stat(fileName); err == nil {
+			fileSync.SetLocal(info)
+		}
+
 		if mutex.SyncWorker.Canceled() {
 			return false, nil
 		}
 
 		w.logError(entity.Db().Save(&fileSync).Error)
+
+		// Remove failed upload if it was stored with a different remote name.
+		if pending != nil && pending.RemoteName != remoteName {
+			w.logError(entity.Db().Delete(pending).Error)
+		}
 	}
 
 	return false, nil
 }
+
+// uploadFailed remembers a failed upload so that it is retried with an increasing delay.
+func (w *Sync) uploadFailed(a entity.Service, file entity.File, remoteName string, pending *entity.FileSync, err error) {
+	fileSync := entity.FindFileSync(a.ID, remoteName)
+
+	// Never overwrite the sync status of other files.
+	if fileSync == nil {
+		fileSync = entity.NewFileSync(a.ID, remoteName)
+		fileSync.Status = entity.FileSyncPending
+		fileSync.FileID = file.ID
+	} else if fileSync.Status != entity.FileSyncPending {
+		return
+	}
+
+	if pending != nil && pending.Errors > fileSync.Errors {
+		fileSync.Errors = pending.Errors
+	}
+
+	fileSync.Errors++
+	fileSync.Error = err.Error()
+
+	w.logError(entity.Db().Save(fileSync).Error)
+
+	// Keep a single record for each pending upload.
+	if pending != nil && pending.RemoteName != remoteName {
+		w.logError(entity.Db().Delete(pending).Error)
+	}
+}
@@ -59,6 +59,11 @@ func Start(conf *config.Config) {
 				mutex.SyncWorker.Cancel()
 				return
 			case <-ticker.C:
+				// Skip while a backup is restored.
+				if mutex.RestoreWorker.Running() {
+					continue
+				}
+
 				RunMeta(conf)
 				RunShare(conf)
 				RunSync(conf)
@@ -13,6 +13,7 @@ type FileInfo struct {
 	Size int64     `json:"size"`
 	Date time.Time `json:"date"`
 	Dir  bool      `json:"dir"`
+	ETag string    `json:"etag,omitempty"`
 }
 
 func NewFileInfo(info os.FileInfo, dir string) FileInfo {
@@ -0,0 +1,218 @@
+package qrcode
+
+// newCode creates the QR code symbol for the data with the specified version.
+func newCode(version int, data []byte) *Code {
+	size := version*4 + 17
+
+	c := &Code{
+		Version: version,
+		Size:    size,
+		modules: make([][]bool, size),
+		reserve: make([][]bool, size),
+	}
+
+	for i := range c.modules {
+		c.modules[i] = make([]bool, size)
+		c.reserve[i] = make([]bool, size)
+	}
+
+	c.drawFunctionPatterns()
+	c.drawCodewords(codewords(version, data))
+
+	// Apply the mask with the lowest penalty score.
+	best, minPenalty := 0, -1
+
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormatBits(mask)
+
+		if p := c.penalty(); minPenalty < 0 || p < minPenalty {
+			best, minPenalty = mask, p
+		}
+
+		// Masks are reversible.
+		c.applyMask(mask)
+	}
+
+	c.applyMask(best)
+	c.drawFormatBits(best)
+
+	return c
+}
+
+// setFunction sets a function module, which is excluded from data placement and masking.
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.reserve[y][x] = true
+}
+
+// drawFunctionPatterns draws the timing, finder, and alignment patterns as well as the version information.
+func (c *Code) drawFunctionPatterns() {
+	size := c.Size
+
+	// Timing patterns.
+	for i := 0; i < size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns, including separators.
+	c.drawFinderPattern(3, 3)
+	c.drawFinderPattern(size-4, 3)
+	c.drawFinderPattern(3, size-4)
+
+	// Alignment patterns, except where they would overlap with finder patterns.
+	pos := alignments[c.Version]
+	n := len(pos)
+
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i == 0 && j == 0 || i == 0 && j == n-1 || i == n-1 && j == 0 {
+				continue
+			}
+
+			c.drawAlignmentPattern(pos[i], pos[j])
+		}
+	}
+
+	// Reserve format information area, drawn later with the selected mask.
+	c.drawFormatBits(0)
+	c.drawVersion()
+}
+
+// drawFinderPattern draws a finder pattern with separator around the center module.
+func (c *Code) drawFinderPattern(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+
+			if xx < 0 || yy < 0 || xx >= c.Size || yy >= c.Size {
+				continue
+			}
+
+			dist := max(abs(dx), abs(dy))
+			c.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// drawAlignmentPattern draws an alignment pattern around the center module.
+func (c *Code) drawAlignmentPattern(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// drawFormatBits draws both copies of the format information for level M and the mask.
+func (c *Code) drawFormatBits(mask int) {
+	// Level M is encoded as 00, so the data only consists of the mask.
+	data := mask
+	rem := data
+
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+
+	bits := (data<<10 | rem) ^ 0x5412
+	size := c.Size
+
+	// First copy next to the top left finder pattern.
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(bits, i))
+	}
+
+	c.setFunction(8, 7, bit(bits, 6))
+	c.setFunction(8, 8, bit(bits, 7))
+	c.setFunction(7, 8, bit(bits, 8))
+
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(bits, i))
+	}
+
+	// Second copy split between the other finder patterns.
+	for i := 0; i < 8; i++ {
+		c.setFunction(size-1-i, 8, bit(bits, i))
+	}
+
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, size-15+i, bit(bits, i))
+	}
+
+	// Always dark.
+	c.setFunction(8, size-8, true)
+}
+
+// drawVersion draws both copies of the version information, which is required from version 7.
+func (c *Code) drawVersion() {
+	if c.Version < 7 {
+		return
+	}
+
+	rem := c.Version
+
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1f25)
+	}
+
+	bits := c.Version<<12 | rem
+
+	for i := 0; i < 18; i++ {
+		a, b := c.Size-11+i%3, i/3
+		c.setFunction(a, b, bit(bits, i))
+		c.setFunction(b, a, bit(bits, i))
+	}
+}
+
+// drawCodewords places the codewords in the zigzag pattern, skipping function modules.
+func (c *Code) drawCodewords(data []byte) {
+	i, n := 0, len(data)*8
+
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		// Skip the vertical timing pattern.
+		if right == 6 {
+			right = 5
+		}
+
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+
+				// Columns are filled upwards and downwards in turns.
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+
+				if !c.reserve[y][x] && i < n {
+					c.modules[y][x] = (data[i>>3]>>uint(7-(i&7)))&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+// bit checks if the i-th bit of x is set.
+func bit(x, i int) bool {
+	return (x>>uint(i))&1 != 0
+}
+
+// abs returns the absolute value of x.
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
+
+// max returns the larger of x and y.
+func max(x, y int) int {
+	if x > y {
+		return x
+	}
+
+	return y
+}
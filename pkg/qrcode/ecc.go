@@ -0,0 +1,52 @@
+package qrcode
+
+// gfMul multiplies two elements of the Galois field GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMul(x, y byte) byte {
+	z := 0
+
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11d)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+
+	return byte(z)
+}
+
+// rsDivisor returns the Reed-Solomon generator polynomial of the specified degree,
+// with coefficients in descending order and the leading term omitted.
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMul(result[j], root)
+
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+
+		root = gfMul(root, 0x02)
+	}
+
+	return result
+}
+
+// rsRemainder returns the Reed-Solomon error correction codewords for the data.
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+
+		for i, coef := range divisor {
+			result[i] ^= gfMul(coef, factor)
+		}
+	}
+
+	return result
+}
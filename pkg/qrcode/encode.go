@@ -0,0 +1,90 @@
+package qrcode
+
+// bitBuffer is an append-only sequence of bits.
+type bitBuffer []bool
+
+// append adds the n lowest bits of the value, most significant bit first.
+func (b *bitBuffer) append(value, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, (value>>uint(i))&1 == 1)
+	}
+}
+
+// bytes returns the bits packed into bytes.
+func (b bitBuffer) bytes() []byte {
+	result := make([]byte, (len(b)+7)/8)
+
+	for i, bit := range b {
+		if bit {
+			result[i/8] |= 1 << uint(7-i%8)
+		}
+	}
+
+	return result
+}
+
+// codewords returns the data codewords followed by the interleaved error correction codewords.
+func codewords(version int, data []byte) []byte {
+	b := versions[version]
+	total := b.dataTotal()
+
+	// Segment header and data in byte mode.
+	var bits bitBuffer
+	bits.append(0x4, 4)
+	bits.append(len(data), countBits(version))
+
+	for _, c := range data {
+		bits.append(int(c), 8)
+	}
+
+	// Terminator and padding.
+	if n := total*8 - len(bits); n < 4 {
+		bits.append(0, n)
+	} else {
+		bits.append(0, 4)
+	}
+
+	bits.append(0, (8-len(bits)%8)%8)
+
+	raw := bits.bytes()
+
+	for pad := byte(0xec); len(raw) < total; pad ^= 0xec ^ 0x11 {
+		raw = append(raw, pad)
+	}
+
+	// Split into blocks and compute error correction codewords.
+	divisor := rsDivisor(b.ecLen)
+	dataBlocks := make([][]byte, 0, b.short+b.long)
+	ecBlocks := make([][]byte, 0, b.short+b.long)
+
+	for i, k := 0, 0; i < b.short+b.long; i++ {
+		n := b.dataLen
+
+		if i >= b.short {
+			n++
+		}
+
+		dataBlocks = append(dataBlocks, raw[k:k+n])
+		ecBlocks = append(ecBlocks, rsRemainder(raw[k:k+n], divisor))
+		k += n
+	}
+
+	// Interleave blocks.
+	result := make([]byte, 0, total+len(ecBlocks)*b.ecLen)
+
+	for i := 0; i <= b.dataLen; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+
+	for i := 0; i < b.ecLen; i++ {
+		for _, block := range ecBlocks {
+			result = append(result, block[i])
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,44 @@
+package qrcode
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// QuietZone is the width of the light border around the symbol in modules.
+const QuietZone = 4
+
+// Image returns the QR code as grayscale image with the specified module size in pixels.
+func (c *Code) Image(scale int) image.Image {
+	if scale < 1 {
+		scale = 1
+	}
+
+	dim := (c.Size + 2*QuietZone) * scale
+	img := image.NewGray(image.Rect(0, 0, dim, dim))
+
+	for y := 0; y < dim; y++ {
+		for x := 0; x < dim; x++ {
+			if c.Dark(x/scale-QuietZone, y/scale-QuietZone) {
+				img.SetGray(x, y, color.Gray{Y: 0})
+			} else {
+				img.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+
+	return img
+}
+
+// PNG returns the QR code as PNG image with the specified module size in pixels.
+func (c *Code) PNG(scale int) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := png.Encode(&buf, c.Image(scale)); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,51 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCode_Image(t *testing.T) {
+	c, err := Encode("otpauth://totp/PhotoPrism:alice?secret=GEZDGNBVGY3TQOJQ")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img := c.Image(4)
+	dim := (c.Size + 2*QuietZone) * 4
+
+	assert.Equal(t, dim, img.Bounds().Dx())
+	assert.Equal(t, dim, img.Bounds().Dy())
+
+	// Quiet zone is light, the top left finder pattern is dark.
+	r, _, _, _ := img.At(0, 0).RGBA()
+	assert.Equal(t, uint32(0xffff), r)
+	r, _, _, _ = img.At(QuietZone*4, QuietZone*4).RGBA()
+	assert.Equal(t, uint32(0), r)
+}
+
+func TestCode_PNG(t *testing.T) {
+	c, err := Encode("Hello")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := c.PNG(2)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(b))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, (c.Size+2*QuietZone)*2, img.Bounds().Dx())
+}
@@ -0,0 +1,134 @@
+package qrcode
+
+// Penalty weights as specified in ISO/IEC 18004, section 7.8.3.
+const (
+	penaltyN1 = 3
+	penaltyN2 = 3
+	penaltyN3 = 40
+	penaltyN4 = 10
+)
+
+// finderCore is the 1:1:3:1:1 module sequence of finder patterns.
+var finderCore = []bool{true, false, true, true, true, false, true}
+
+// masked checks if the module at the position is inverted by the mask pattern.
+func masked(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	case 7:
+		return ((x+y)%2+x*y%3)%2 == 0
+	default:
+		return false
+	}
+}
+
+// applyMask inverts the data modules according to the mask pattern.
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.reserve[y][x] && masked(mask, x, y) {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty returns the penalty score of the symbol, lower is better.
+func (c *Code) penalty() (result int) {
+	size := c.Size
+
+	row := func(y int) func(i int) bool { return func(i int) bool { return c.modules[y][i] } }
+	col := func(x int) func(i int) bool { return func(i int) bool { return c.modules[i][x] } }
+
+	for i := 0; i < size; i++ {
+		result += c.linePenalty(row(i)) + c.linePenalty(col(i))
+	}
+
+	// Blocks of 2x2 modules with the same color.
+	for y := 0; y < size-1; y++ {
+		for x := 0; x < size-1; x++ {
+			if m := c.modules[y][x]; m == c.modules[y][x+1] && m == c.modules[y+1][x] && m == c.modules[y+1][x+1] {
+				result += penaltyN2
+			}
+		}
+	}
+
+	// Balance of dark and light modules.
+	dark := 0
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if c.modules[y][x] {
+				dark++
+			}
+		}
+	}
+
+	// Add points for each full 5% deviation from 50%.
+	total := size * size
+	result += abs(dark*2-total) * 10 / total * penaltyN4
+
+	return result
+}
+
+// linePenalty returns the penalty score for runs of modules with the same color
+// and finder-like patterns in a row or column.
+func (c *Code) linePenalty(module func(i int) bool) (result int) {
+	size := c.Size
+	run := 1
+
+	for i := 1; i <= size; i++ {
+		if i < size && module(i) == module(i-1) {
+			run++
+			continue
+		}
+
+		if run >= 5 {
+			result += penaltyN1 + run - 5
+		}
+
+		run = 1
+	}
+
+	// Finder-like patterns with four light modules on either side,
+	// whereby the quiet zone around the symbol counts as light.
+	light := func(from, to int) bool {
+		for i := from; i < to; i++ {
+			if i >= 0 && i < size && module(i) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	for i := 0; i+len(finderCore) <= size; i++ {
+		match := true
+
+		for j, dark := range finderCore {
+			if module(i+j) != dark {
+				match = false
+				break
+			}
+		}
+
+		if match && (light(i-4, i) || light(i+7, i+11)) {
+			result += penaltyN3
+		}
+	}
+
+	return result
+}
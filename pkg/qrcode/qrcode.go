@@ -0,0 +1,62 @@
+/*
+Package qrcode provides a minimal QR code encoder for short texts such as URLs.
+
+Copyright (c) 2018 - 2022 PhotoPrism UG. All rights reserved.
+
+	This program is free software: you can redistribute it and/or modify
+	it under Version 3 of the GNU Affero General Public License (the "AGPL"):
+	<https://docs.photoprism.app/license/agpl>
+
+	This program is distributed in the hope that it will be useful,
+	but WITHOUT ANY WARRANTY; without even the implied warranty of
+	MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+	GNU Affero General Public License for more details.
+
+	The AGPL is supplemented by our Trademark and Brand Guidelines,
+	which describe how our Brand Assets may be used:
+	<https://photoprism.app/trademark>
+
+Feel free to send an email to hello@photoprism.app if you have questions,
+want to support our work, or just want to say hello.
+
+Additional information can be found in our Developer Guide:
+<https://docs.photoprism.app/developer-guide/>
+*/
+package qrcode
+
+import (
+	"errors"
+)
+
+// ErrTooLong is returned if the text does not fit into a supported QR code version.
+var ErrTooLong = errors.New("qrcode: text too long")
+
+// Code represents an encoded QR code symbol.
+type Code struct {
+	Version int
+	Size    int
+	modules [][]bool
+	reserve [][]bool
+}
+
+// Encode returns the QR code for the text, using byte mode and error correction level M.
+func Encode(text string) (*Code, error) {
+	data := []byte(text)
+
+	for v := 1; v <= MaxVersion; v++ {
+		if len(data) <= capacity(v) {
+			return newCode(v, data), nil
+		}
+	}
+
+	return nil, ErrTooLong
+}
+
+// Dark checks if the module at the specified position is dark.
+func (c *Code) Dark(x, y int) bool {
+	if x < 0 || y < 0 || x >= c.Size || y >= c.Size {
+		return false
+	}
+
+	return c.modules[y][x]
+}
@@ -0,0 +1,98 @@
+package qrcode
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rows returns the modules as strings for comparison.
+func rows(c *Code) []string {
+	result := make([]string, c.Size)
+
+	for y := 0; y < c.Size; y++ {
+		var b strings.Builder
+
+		for x := 0; x < c.Size; x++ {
+			if c.Dark(x, y) {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+
+		result[y] = b.String()
+	}
+
+	return result
+}
+
+func TestEncode(t *testing.T) {
+	t.Run("Hello", func(t *testing.T) {
+		c, err := Encode("Hello")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 1, c.Version)
+		assert.Equal(t, 21, c.Size)
+		assert.Equal(t, []string{
+			"#######..####.#######",
+			"#.....#.....#.#.....#",
+			"#.###.#.#.#.#.#.###.#",
+			"#.###.#.#.#...#.###.#",
+			"#.###.#.##..#.#.###.#",
+			"#.....#.##.#..#.....#",
+			"#######.#.#.#.#######",
+			"........#.#..........",
+			"#.#####..#.#..#####..",
+			"...#....#..####..##.#",
+			"..#####..##.#.##.###.",
+			"..#..#.##.#####..##..",
+			".###..#####.#..#....#",
+			"........#...#..#.#...",
+			"#######..#.#.#..#.##.",
+			"#.....#.#.#....#####.",
+			"#.###.#.#..#.#..#..#.",
+			"#.###.#.##.#####.#...",
+			"#.###.#.##..#.##..#..",
+			"#.....#..#.####.###..",
+			"#######.##..#...#..#.",
+		}, rows(c))
+	})
+	t.Run("Versions", func(t *testing.T) {
+		for v := 1; v <= MaxVersion; v++ {
+			c, err := Encode(strings.Repeat("a", capacity(v)))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, v, c.Version)
+			assert.Equal(t, v*4+17, c.Size)
+		}
+	})
+	t.Run("TooLong", func(t *testing.T) {
+		_, err := Encode(strings.Repeat("a", capacity(MaxVersion)+1))
+		assert.Equal(t, ErrTooLong, err)
+	})
+}
+
+func TestCode_Dark(t *testing.T) {
+	c, err := Encode("Hello")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Finder pattern corners are dark.
+	assert.True(t, c.Dark(0, 0))
+	assert.True(t, c.Dark(c.Size-1, 0))
+	assert.True(t, c.Dark(0, c.Size-1))
+
+	// Outside the symbol.
+	assert.False(t, c.Dark(-1, 0))
+	assert.False(t, c.Dark(0, c.Size))
+}
@@ -0,0 +1,58 @@
+package qrcode
+
+// MaxVersion is the largest supported QR code version.
+const MaxVersion = 10
+
+// blocks specifies the error correction block structure of a version at level M.
+type blocks struct {
+	ecLen   int // Error correction codewords per block.
+	short   int // Number of blocks in group 1.
+	dataLen int // Data codewords per block in group 1.
+	long    int // Number of blocks in group 2, which have one more data codeword.
+}
+
+// versions maps QR code versions to their block structure at error correction level M.
+var versions = map[int]blocks{
+	1:  {10, 1, 16, 0},
+	2:  {16, 1, 28, 0},
+	3:  {26, 1, 44, 0},
+	4:  {18, 2, 32, 0},
+	5:  {24, 2, 43, 0},
+	6:  {16, 4, 27, 0},
+	7:  {18, 4, 31, 0},
+	8:  {22, 2, 38, 2},
+	9:  {22, 3, 36, 2},
+	10: {26, 4, 43, 1},
+}
+
+// alignments maps QR code versions to the center coordinates of their alignment patterns.
+var alignments = map[int][]int{
+	2:  {6, 18},
+	3:  {6, 22},
+	4:  {6, 26},
+	5:  {6, 30},
+	6:  {6, 34},
+	7:  {6, 22, 38},
+	8:  {6, 24, 42},
+	9:  {6, 26, 46},
+	10: {6, 28, 50},
+}
+
+// dataTotal returns the total number of data codewords of a version.
+func (b blocks) dataTotal() int {
+	return b.short*b.dataLen + b.long*(b.dataLen+1)
+}
+
+// countBits returns the length of the character count indicator in byte mode.
+func countBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+
+	return 16
+}
+
+// capacity returns the maximum number of bytes that can be encoded with a version.
+func capacity(version int) int {
+	return (versions[version].dataTotal()*8 - 4 - countBits(version)) / 8
+}
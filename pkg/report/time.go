@@ -0,0 +1,12 @@
+package report
+
+import "time"
+
+// DateTime formats the time for display in reports, or returns an empty string if it is not set.
+func DateTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+
+	return t.Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,75 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Counter returns the time step counter for the specified time.
+func Counter(t time.Time) int64 {
+	return t.Unix() / int64(Period/time.Second)
+}
+
+// CodeAt returns the one-time password for the secret and time step counter.
+func CodeAt(secret string, counter int64) (string, error) {
+	key, err := DecodeSecret(secret)
+
+	if err != nil {
+		return "", err
+	} else if len(key) == 0 {
+		return "", fmt.Errorf("secret is empty")
+	}
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation, see RFC 4226 section 5.3.
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+
+	for i := 0; i < Digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", Digits, value%mod), nil
+}
+
+// Code returns the one-time password for the secret at the specified time.
+func Code(secret string, t time.Time) (string, error) {
+	return CodeAt(secret, Counter(t))
+}
+
+// Validate checks the code against the secret, allowing for a clock skew of one time step,
+// and returns the matching time step counter so that callers can prevent reuse.
+func Validate(code, secret string, t time.Time) (counter int64, ok bool) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+
+	if len(code) != Digits {
+		return 0, false
+	}
+
+	now := Counter(t)
+
+	for i := -Skew; i <= Skew; i++ {
+		expected, err := CodeAt(secret, now+int64(i))
+
+		if err != nil {
+			return 0, false
+		} else if subtle.ConstantTimeCompare([]byte(code), []byte(expected)) == 1 {
+			return now + int64(i), true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,71 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret is the SHA1 test key from RFC 6238, appendix B.
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	t.Run("RFC6238", func(t *testing.T) {
+		vectors := map[int64]string{
+			59:          "287082",
+			1111111109:  "081804",
+			1111111111:  "050471",
+			1234567890:  "005924",
+			2000000000:  "279037",
+			20000000000: "353130",
+		}
+
+		for ts, expected := range vectors {
+			code, err := Code(rfcSecret, time.Unix(ts, 0))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, expected, code)
+		}
+	})
+	t.Run("InvalidSecret", func(t *testing.T) {
+		_, err := Code("!!!", time.Now())
+		assert.Error(t, err)
+		_, err = Code("", time.Now())
+		assert.Error(t, err)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	t.Run("Current", func(t *testing.T) {
+		counter, ok := Validate("050471", rfcSecret, now)
+		assert.True(t, ok)
+		assert.Equal(t, Counter(now), counter)
+	})
+	t.Run("Skew", func(t *testing.T) {
+		code, _ := Code(rfcSecret, now.Add(-Period))
+		counter, ok := Validate(code, rfcSecret, now)
+		assert.True(t, ok)
+		assert.Equal(t, Counter(now)-1, counter)
+		code, _ = Code(rfcSecret, now.Add(Period))
+		_, ok = Validate(" "+code[:3]+" "+code[3:], rfcSecret, now)
+		assert.True(t, ok)
+	})
+	t.Run("Expired", func(t *testing.T) {
+		code, _ := Code(rfcSecret, now.Add(-3*Period))
+		_, ok := Validate(code, rfcSecret, now)
+		assert.False(t, ok)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		_, ok := Validate("123", rfcSecret, now)
+		assert.False(t, ok)
+		_, ok = Validate("050471", "", now)
+		assert.False(t, ok)
+	})
+}
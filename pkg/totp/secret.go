@@ -0,0 +1,30 @@
+package totp
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+)
+
+// SecretLength is the number of random bytes in a new secret.
+const SecretLength = 20
+
+// encoding is the base32 encoding used by authenticator apps.
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewSecret returns a new random secret in base32 encoding.
+func NewSecret() string {
+	b := make([]byte, SecretLength)
+
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return encoding.EncodeToString(b)
+}
+
+// DecodeSecret returns the key bytes of a base32 encoded secret.
+func DecodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.TrimRight(strings.ReplaceAll(secret, " ", ""), "="))
+	return encoding.DecodeString(secret)
+}
@@ -0,0 +1,26 @@
+package totp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSecret(t *testing.T) {
+	s := NewSecret()
+	assert.Len(t, s, 32)
+	assert.NotEqual(t, s, NewSecret())
+
+	key, err := DecodeSecret(s)
+	assert.NoError(t, err)
+	assert.Len(t, key, SecretLength)
+}
+
+func TestDecodeSecret(t *testing.T) {
+	key, err := DecodeSecret("gezd gnbv gy3t qojq gezd gnbv gy3t qojq")
+	assert.NoError(t, err)
+	assert.Equal(t, "12345678901234567890", string(key))
+
+	_, err = DecodeSecret("1!")
+	assert.Error(t, err)
+}
@@ -0,0 +1,24 @@
+package totp
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// URL returns the key URI for enrolling the secret in an authenticator app,
+// see https://github.com/google/google-authenticator/wiki/Key-Uri-Format.
+func URL(issuer, account, secret string) string {
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", issuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprintf("%d", Digits))
+	q.Set("period", fmt.Sprintf("%d", int(Period.Seconds())))
+
+	// Spaces must be encoded as %20, as some apps do not decode "+".
+	label := url.PathEscape(issuer + ":" + account)
+	query := strings.ReplaceAll(q.Encode(), "+", "%20")
+
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, query)
+}
@@ -0,0 +1,16 @@
+package totp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURL(t *testing.T) {
+	assert.Equal(t,
+		"otpauth://totp/PhotoPrism:alice@example.com?algorithm=SHA1&digits=6&issuer=PhotoPrism&period=30&secret=GEZDGNBVGY3TQOJQ",
+		URL("PhotoPrism", "alice@example.com", "GEZDGNBVGY3TQOJQ"))
+	assert.Equal(t,
+		"otpauth://totp/My%20Photos:bob?algorithm=SHA1&digits=6&issuer=My%20Photos&period=30&secret=GEZDGNBVGY3TQOJQ",
+		URL("My Photos", "bob", "GEZDGNBVGY3TQOJQ"))
+}